@@ -193,6 +193,95 @@ func (os objectValues[T, P]) MarshalLogArray(arr zapcore.ArrayEncoder) error {
 	return nil
 }
 
+// Slice constructs a field that carries a slice of any element type. It
+// spares callers from choosing between Ints, Strings, Durations and so on, and
+// is useful in generic code where the element type isn't known in advance:
+//
+//	logger.Info("retrying", zap.Slice("backoffs", backoffs))
+//
+// Slices of the primitive types that have dedicated constructors in this
+// package are encoded exactly as those constructors would, without
+// reflection. Other element types are encoded one element at a time:
+// marshalers are used as such, errors are encoded as Errors would encode
+// them, fmt.Stringers use their String method, and anything else falls back
+// to reflection-based encoding. A nil slice is encoded as null.
+func Slice[T any](key string, s []T) Field {
+	if s == nil {
+		return nilField(key)
+	}
+
+	switch v := any(s).(type) {
+	case []bool:
+		return Bools(key, v)
+	case [][]byte:
+		return ByteStrings(key, v)
+	case []complex128:
+		return Complex128s(key, v)
+	case []complex64:
+		return Complex64s(key, v)
+	case []time.Duration:
+		return Durations(key, v)
+	case []float64:
+		return Float64s(key, v)
+	case []float32:
+		return Float32s(key, v)
+	case []int:
+		return Ints(key, v)
+	case []int64:
+		return Int64s(key, v)
+	case []int32:
+		return Int32s(key, v)
+	case []int16:
+		return Int16s(key, v)
+	case []int8:
+		return Int8s(key, v)
+	case []string:
+		return Strings(key, v)
+	case []time.Time:
+		return Times(key, v)
+	case []uint:
+		return Uints(key, v)
+	case []uint64:
+		return Uint64s(key, v)
+	case []uint32:
+		return Uint32s(key, v)
+	case []uint16:
+		return Uint16s(key, v)
+	case []uint8:
+		return Uint8s(key, v)
+	case []uintptr:
+		return Uintptrs(key, v)
+	case []error:
+		return Errors(key, v)
+	default:
+		return Array(key, anySlice[T](s))
+	}
+}
+
+type anySlice[T any] []T
+
+func (vs anySlice[T]) MarshalLogArray(arr zapcore.ArrayEncoder) error {
+	for _, v := range vs {
+		var err error
+		switch v := any(v).(type) {
+		case zapcore.ObjectMarshaler:
+			err = arr.AppendObject(v)
+		case zapcore.ArrayMarshaler:
+			err = arr.AppendArray(v)
+		case error:
+			err = errArray{v}.MarshalLogArray(arr)
+		case fmt.Stringer:
+			arr.AppendString(v.String())
+		default:
+			err = arr.AppendReflected(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Strings constructs a field that carries a slice of strings.
 func Strings(key string, ss []string) Field {
 	return Array(key, stringArray(ss))
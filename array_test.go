@@ -109,6 +109,36 @@ func TestArrayWrappers(t *testing.T) {
 	}
 }
 
+func TestTimesAndDurationsHonorEncoders(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	})
+	encode := func(fields ...Field) string {
+		buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+		require.NoError(t, err, "Unexpected error encoding fields.")
+		defer buf.Free()
+		return buf.String()
+	}
+
+	ts := []time.Time{time.Unix(0, 0).UTC(), time.Unix(1700000000, 5).UTC()}
+	ds := []time.Duration{time.Second, 1500 * time.Millisecond}
+
+	assert.Equal(t, `{"t":"1970-01-01T00:00:00Z"}`+"\n", encode(Time("t", ts[0])))
+	assert.Equal(t, `{"t":"2023-11-14T22:13:20.000000005Z"}`+"\n", encode(Time("t", ts[1])))
+	assert.Equal(t, `{"t":["1970-01-01T00:00:00Z","2023-11-14T22:13:20.000000005Z"]}`+"\n",
+		encode(Times("t", ts)), "Expected each time to be encoded like Time.")
+
+	assert.Equal(t, `{"d":"1s"}`+"\n", encode(Duration("d", ds[0])))
+	assert.Equal(t, `{"d":"1.5s"}`+"\n", encode(Duration("d", ds[1])))
+	assert.Equal(t, `{"d":["1s","1.5s"]}`+"\n",
+		encode(Durations("d", ds)), "Expected each duration to be encoded like Duration.")
+
+	for _, f := range []Field{Times("k", nil), Times("k", []time.Time{}), Durations("k", nil), Durations("k", []time.Duration{})} {
+		assert.Equal(t, `{"k":[]}`+"\n", encode(f), "Expected nil and empty slices to encode as empty arrays.")
+	}
+}
+
 func TestObjectsAndObjectValues(t *testing.T) {
 	t.Parallel()
 
@@ -314,3 +344,84 @@ func TestStringers(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkIntsArrayMarshaler(b *testing.B) {
+	nums := make([]int, 50)
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Ints("array", nums).AddTo(enc.Clone())
+	}
+}
+
+func BenchmarkIntsSlice(b *testing.B) {
+	nums := make([]int, 50)
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Slice("array", nums).AddTo(enc.Clone())
+	}
+}
+
+type sliceTestID int
+
+func (id sliceTestID) String() string { return fmt.Sprintf("id-%d", int(id)) }
+
+func TestSlice(t *testing.T) {
+	type point struct{ X, Y int }
+
+	tests := []struct {
+		desc     string
+		field    Field
+		expected interface{}
+	}{
+		{"nil", Slice[int]("", nil), nil},
+		{"empty", Slice("", []int{}), []interface{}{}},
+		{"bools", Slice("", []bool{true, false}), []interface{}{true, false}},
+		{"byte strings", Slice("", [][]byte{{1, 2}}), []interface{}{"\x01\x02"}},
+		{"durations", Slice("", []time.Duration{1, 2}), []interface{}{time.Nanosecond, 2 * time.Nanosecond}},
+		{"float64s", Slice("", []float64{1.2, 3.4}), []interface{}{1.2, 3.4}},
+		{"ints", Slice("", []int{1, 2}), []interface{}{1, 2}},
+		{"int64s", Slice("", []int64{1, 2}), []interface{}{int64(1), int64(2)}},
+		{"strings", Slice("", []string{"foo", "bar"}), []interface{}{"foo", "bar"}},
+		{"times", Slice("", []time.Time{time.Unix(0, 0)}), []interface{}{time.Unix(0, 0)}},
+		{"uint8s", Slice("", []uint8{1, 2}), []interface{}{uint8(1), uint8(2)}},
+		{"uintptrs", Slice("", []uintptr{1, 2}), []interface{}{uintptr(1), uintptr(2)}},
+		{
+			"errors",
+			Slice("", []error{errors.New("foo")}),
+			[]interface{}{map[string]interface{}{"error": "foo"}},
+		},
+		{
+			"objects",
+			Slice("", []zapcore.ObjectMarshaler{zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				enc.AddString("foo", "bar")
+				return nil
+			})}),
+			[]interface{}{map[string]interface{}{"foo": "bar"}},
+		},
+		{
+			"stringers",
+			Slice("", []sliceTestID{1, 2}),
+			[]interface{}{"id-1", "id-2"},
+		},
+		{
+			"reflected",
+			Slice("", []point{{1, 2}}),
+			[]interface{}{point{1, 2}},
+		},
+		{
+			"mixed",
+			Slice("", []interface{}{1, "foo", sliceTestID(3)}),
+			[]interface{}{1, "foo", "id-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		enc := zapcore.NewMapObjectEncoder()
+		tt.field.Key = "k"
+		tt.field.AddTo(enc)
+		assert.Equal(t, tt.expected, enc.Fields["k"], "%s: unexpected map contents.", tt.desc)
+		assert.Equal(t, 1, len(enc.Fields), "%s: found extra keys in map: %v", tt.desc, enc.Fields)
+	}
+}
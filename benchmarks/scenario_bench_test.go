@@ -792,6 +792,39 @@ func BenchmarkAccumulatedContext(b *testing.B) {
 	})
 }
 
+func BenchmarkAddingOneField(b *testing.B) {
+	b.Logf("Logging with a single field at each log site.")
+	b.Run("Zap", func(b *testing.B) {
+		logger := newZapLogger(zap.DebugLevel)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				logger.Info(getMessage(0), zap.Int("int", _tenInts[0]))
+			}
+		})
+	})
+	b.Run("Zap.Info1", func(b *testing.B) {
+		logger := newZapLogger(zap.DebugLevel)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				logger.Info1(getMessage(0), zap.Int("int", _tenInts[0]))
+			}
+		})
+	})
+	b.Run("Zap.Check.Write1", func(b *testing.B) {
+		logger := newZapLogger(zap.DebugLevel)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if ce := logger.Check(zap.InfoLevel, getMessage(0)); ce != nil {
+					ce.Write1(zap.Int("int", _tenInts[0]))
+				}
+			}
+		})
+	})
+}
+
 func BenchmarkAddingFields(b *testing.B) {
 	b.Logf("Logging with additional context at each log site.")
 	b.Run("Zap", func(b *testing.B) {
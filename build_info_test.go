@@ -0,0 +1,89 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withBuildInfo(t *testing.T, info *debug.BuildInfo, ok bool) {
+	t.Helper()
+	orig := readBuildInfo
+	t.Cleanup(func() { readBuildInfo = orig })
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, ok }
+}
+
+func TestAddBuildInfo(t *testing.T) {
+	tests := []struct {
+		desc string
+		info *debug.BuildInfo
+		ok   bool
+		want map[string]interface{}
+	}{
+		{
+			desc: "version and revision",
+			info: &debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs", Value: "git"},
+					{Key: "vcs.revision", Value: "abc123"},
+					{Key: "vcs.modified", Value: "false"},
+				},
+			},
+			ok:   true,
+			want: map[string]interface{}{"version": "v1.2.3", "revision": "abc123"},
+		},
+		{
+			desc: "go run",
+			info: &debug.BuildInfo{Main: debug.Module{Path: "example.com/app", Version: "(devel)"}},
+			ok:   true,
+			want: map[string]interface{}{"version": "(devel)"},
+		},
+		{
+			desc: "unavailable",
+			ok:   false,
+			want: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			withBuildInfo(t, tt.info, tt.ok)
+			withLogger(t, DebugLevel, opts(AddBuildInfo()), func(logger *Logger, logs *observer.ObservedLogs) {
+				logger.Info("one")
+				logger.With(String("k", "v")).Info("two")
+
+				entries := logs.AllUntimed()
+				require.Len(t, entries, 2, "Unexpected number of entries.")
+				assert.Equal(t, tt.want, entries[0].ContextMap(), "Unexpected build info fields.")
+				for k, v := range tt.want {
+					assert.Equal(t, v, entries[1].ContextMap()[k], "Expected build info on child loggers.")
+				}
+			})
+		})
+	}
+}
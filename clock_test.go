@@ -21,11 +21,15 @@
 package zap
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/internal/ztest"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 )
 
@@ -45,3 +49,33 @@ func TestWithClock(t *testing.T) {
 		assert.Equal(t, date, logs.All()[0].Time, "Unexpected entry time.")
 	})
 }
+
+func TestWithClockSharedWithSamplerAndRotation(t *testing.T) {
+	clock := ztest.NewMockClock()
+	dir := t.TempDir()
+	ws := zapcore.NewTimeRotatingSyncer(
+		filepath.Join(dir, "%Y%m%d%H.log"), time.Hour,
+		zapcore.TimeRotatingClock(clock),
+	)
+	defer func() { assert.NoError(t, ws.Close(), "Unexpected error closing syncer.") }()
+
+	core := zapcore.NewSamplerWithOptions(
+		zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), ws, DebugLevel),
+		time.Minute, 1 /* first */, 0, /* thereafter */
+	)
+	log := New(core, WithClock(clock))
+
+	log.Info("tick")
+	log.Info("tick") // dropped by the sampler
+	clock.Add(time.Hour)
+	log.Info("tick") // new sampling tick, and a new file
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err, "Failed to list log files.")
+	require.Len(t, files, 2, "Expected a file for each hour.")
+	for _, f := range files {
+		contents, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err, "Failed to read log file.")
+		assert.Equal(t, `{"msg":"tick"}`+"\n", string(contents), "Unexpected contents of %v.", f.Name())
+	}
+}
@@ -72,9 +72,9 @@ type Config struct {
 	DisableStacktrace bool `json:"disableStacktrace" yaml:"disableStacktrace"`
 	// Sampling sets a sampling policy. A nil SamplingConfig disables sampling.
 	Sampling *SamplingConfig `json:"sampling" yaml:"sampling"`
-	// Encoding sets the logger's encoding. Valid values are "json" and
-	// "console", as well as any third-party encodings registered via
-	// RegisterEncoder.
+	// Encoding sets the logger's encoding. Valid values are "json",
+	// "console", "ecs", and "stackdriver", as well as any third-party
+	// encodings registered via RegisterEncoder.
 	Encoding string `json:"encoding" yaml:"encoding"`
 	// EncoderConfig sets options for the chosen encoder. See
 	// zapcore.EncoderConfig for details.
@@ -235,6 +235,102 @@ func NewDevelopmentConfig() Config {
 	}
 }
 
+// NewECSEncoderConfig returns an opinionated EncoderConfig for use with the
+// Elastic Common Schema (ECS) encoder.
+//
+// The ECS encoder uses fixed field names ("@timestamp", "log.level",
+// "message", "error.stack_trace", etc.), so the keys in the returned
+// configuration only document which elements are enabled; see
+// zapcore.NewECSEncoder for details.
+//
+// By default, the following formats are used for different types:
+//
+//   - Time is formatted in ISO8601 format (e.g. "2017-01-01T12:00:00.000Z").
+//   - Duration is formatted as an integer number of nanoseconds, matching
+//     ECS's event.duration.
+func NewECSEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "log.level",
+		NameKey:        "log.logger",
+		CallerKey:      "log.origin",
+		FunctionKey:    "log.origin.function",
+		MessageKey:     "message",
+		StacktraceKey:  "error.stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+		EncodeCaller:   zapcore.FullCallerEncoder,
+	}
+}
+
+// NewECSConfig builds a production logging configuration whose output
+// follows the Elastic Common Schema, for consumption by the Elastic stack.
+//
+// Apart from the encoding, it's identical to [NewProductionConfig]:
+// logging is enabled at InfoLevel and above, logs are written to standard
+// error, stacktraces are included on logs of ErrorLevel and above, and
+// sampling is enabled at 100:100.
+//
+// See [NewECSEncoderConfig] for information on the default encoder
+// configuration.
+func NewECSConfig() Config {
+	cfg := NewProductionConfig()
+	cfg.Encoding = "ecs"
+	cfg.EncoderConfig = NewECSEncoderConfig()
+	return cfg
+}
+
+// NewStackdriverEncoderConfig returns an opinionated EncoderConfig for
+// Google Cloud Logging (formerly Stackdriver).
+//
+// The Stackdriver encoder uses fixed field names ("severity", "time",
+// "message", "logging.googleapis.com/sourceLocation", etc.), so the keys in
+// the returned configuration only document which elements are enabled; see
+// zapcore.NewStackdriverEncoder for details.
+//
+// By default, the following formats are used for different types:
+//
+//   - Time is formatted in RFC3339 format with nanosecond precision
+//     (e.g. "2017-01-01T12:00:00.123456789Z").
+//   - Duration is formatted as a floating-point number of seconds.
+func NewStackdriverEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		CallerKey:      "logging.googleapis.com/sourceLocation",
+		FunctionKey:    "logging.googleapis.com/sourceLocation.function",
+		MessageKey:     "message",
+		StacktraceKey:  "stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.FullCallerEncoder,
+	}
+}
+
+// NewStackdriverConfig builds a production logging configuration whose
+// output follows the structured logging format of Google Cloud Logging, for
+// workloads on GKE, Cloud Run, Cloud Functions, and other platforms whose
+// logging agents parse JSON from standard output or standard error.
+//
+// Apart from the encoding, it's identical to [NewProductionConfig]:
+// logging is enabled at InfoLevel and above, logs are written to standard
+// error, stacktraces are included on logs of ErrorLevel and above, and
+// sampling is enabled at 100:100.
+//
+// See [NewStackdriverEncoderConfig] for information on the default encoder
+// configuration.
+func NewStackdriverConfig() Config {
+	cfg := NewProductionConfig()
+	cfg.Encoding = "stackdriver"
+	cfg.EncoderConfig = NewStackdriverEncoderConfig()
+	return cfg
+}
+
 // Build constructs a logger from the Config and Options.
 func (cfg Config) Build(opts ...Option) (*Logger, error) {
 	enc, err := cfg.buildEncoder()
@@ -262,7 +358,11 @@ func (cfg Config) Build(opts ...Option) (*Logger, error) {
 }
 
 func (cfg Config) buildOptions(errSink zapcore.WriteSyncer) []Option {
-	opts := []Option{ErrorOutput(errSink)}
+	encCfg := cfg.EncoderConfig
+	opts := []Option{ErrorOutput(errSink), optionFunc(func(log *Logger) {
+		log.encoding = cfg.Encoding
+		log.encoderConfig = &encCfg
+	})}
 
 	if cfg.Development {
 		opts = append(opts, Development())
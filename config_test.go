@@ -21,14 +21,17 @@
 package zap
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestConfig(t *testing.T) {
@@ -201,3 +204,118 @@ func TestConfigWithSamplingHook(t *testing.T) {
 	assert.Equal(t, int64(expectDropped), dcount.Load())
 	assert.Equal(t, int64(expectSampled), scount.Load())
 }
+
+func TestECSConfig(t *testing.T) {
+	logOut := filepath.Join(t.TempDir(), "test.log")
+
+	cfg := NewECSConfig()
+	cfg.OutputPaths = []string{logOut}
+	cfg.EncoderConfig.TimeKey = "" // no timestamps in tests
+	logger, err := cfg.Build()
+	require.NoError(t, err, "Unexpected error constructing logger.")
+
+	logger.Named("db").Error("failed", String("k", "v"), Error(errors.New("boom")))
+
+	byteContents, err := os.ReadFile(logOut)
+	require.NoError(t, err, "Couldn't read log contents from temp file.")
+	assert.Regexp(t,
+		`^{"log":{"level":"error","logger":"db","origin":{"file":{"name":"[^"]+/config_test.go","line":\d+},"function":"go.uber.org/zap.TestECSConfig"}},`+
+			`"message":"failed","ecs":{"version":"1.6.0"},"k":"v",`+
+			`"error":{"message":"boom","type":"\*errors.errorString","stack_trace":"go.uber.org/zap.TestECSConfig\\n[^"]+"}}`+"\n$",
+		string(byteContents),
+		"Unexpected log output.",
+	)
+}
+
+func TestStackdriverConfig(t *testing.T) {
+	logOut := filepath.Join(t.TempDir(), "test.log")
+
+	cfg := NewStackdriverConfig()
+	cfg.OutputPaths = []string{logOut}
+	cfg.EncoderConfig.TimeKey = "" // no timestamps in tests
+	logger, err := cfg.Build()
+	require.NoError(t, err, "Unexpected error constructing logger.")
+
+	logger.Named("db").Error("failed", String(zapcore.StackdriverTraceKey, "projects/p/traces/abc"))
+
+	byteContents, err := os.ReadFile(logOut)
+	require.NoError(t, err, "Couldn't read log contents from temp file.")
+	assert.Regexp(t,
+		`^{"severity":"ERROR","logger":"db","message":"failed",`+
+			`"logging.googleapis.com/sourceLocation":{"file":"[^"]+/config_test.go","line":"\d+","function":"go.uber.org/zap.TestStackdriverConfig"},`+
+			`"logging.googleapis.com/trace":"projects/p/traces/abc",`+
+			`"stack_trace":"go.uber.org/zap.TestStackdriverConfig\\n[^"]+"}`+"\n$",
+		string(byteContents),
+		"Unexpected log output.",
+	)
+}
+
+func TestConfigWithSchemaHeader(t *testing.T) {
+	logOut := filepath.Join(t.TempDir(), "test.log")
+
+	cfg := NewProductionConfig()
+	cfg.OutputPaths = []string{logOut}
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.EncoderConfig.FunctionKey = zapcore.OmitKey
+	cfg.Level = NewAtomicLevelAt(WarnLevel)
+	logger, err := cfg.Build(WithSchemaHeader())
+	require.NoError(t, err, "Unexpected error constructing logger.")
+
+	logger.Info("dropped")
+	logger.With(String("k", "v")).Warn("first")
+	logger.Warn("second")
+
+	byteContents, err := os.ReadFile(logOut)
+	require.NoError(t, err, "Couldn't read log contents from temp file.")
+	lines := strings.Split(strings.TrimSpace(string(byteContents)), "\n")
+	require.Len(t, lines, 3, "Expected a header and two entries.")
+	assert.Regexp(t,
+		`^{"level":"info","ts":"[^"]+","msg":"zap schema header","zap_schema":{"version":1,"encoding":"json","time_format":"iso8601",`+
+			`"keys":{"message":"msg","level":"level","time":"ts","name":"logger","caller":"caller","stacktrace":"stacktrace"}}}$`,
+		lines[0],
+		"Unexpected schema header.",
+	)
+	assert.Contains(t, lines[1], `"msg":"first"`, "Expected header before the first entry.")
+	assert.Contains(t, lines[2], `"msg":"second"`, "Expected header only once.")
+}
+
+func TestWithSchemaHeaderWithoutConfig(t *testing.T) {
+	withLogger(t, DebugLevel, opts(WithSchemaHeader()), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Info("entry")
+		entries := logs.AllUntimed()
+		require.Len(t, entries, 2, "Expected a header and an entry.")
+		assert.Equal(t, "zap schema header", entries[0].Message, "Unexpected header message.")
+		assert.Equal(t,
+			map[string]interface{}{"zap_schema": map[string]interface{}{"version": 1}},
+			entries[0].ContextMap(),
+			"Expected only the format version without a Config.",
+		)
+	})
+}
+
+func TestConfigDescribeCores(t *testing.T) {
+	logOut := filepath.Join(t.TempDir(), "test.log")
+
+	cfg := NewProductionConfig()
+	cfg.OutputPaths = []string{"stdout", logOut}
+	logger, err := cfg.Build()
+	require.NoError(t, err, "Unexpected error constructing logger.")
+
+	infos := logger.DescribeCores()
+	require.Len(t, infos, 1, "Expected a single Core.")
+	assert.Equal(t, "json", infos[0].Encoder, "Unexpected encoder.")
+	assert.Equal(t, InfoLevel, infos[0].Level, "Unexpected level.")
+	assert.Equal(t, cfg.Level, infos[0].LevelEnabler, "Expected the Config's AtomicLevel.")
+	assert.Equal(t, os.Stdout.Name()+", "+logOut, infos[0].Syncer, "Unexpected syncer description.")
+
+	cfg.Level.SetLevel(DebugLevel)
+	assert.Equal(t, DebugLevel, logger.DescribeCores()[0].Level, "Expected level changes to be reflected.")
+
+	logger.WithLevelBoost(1, func(l *Logger) {
+		assert.Equal(t, DebugLevel, l.DescribeCores()[0].Level, "Unexpected boosted level.")
+	})
+	cfg.Level.SetLevel(WarnLevel)
+	logger.WithLevelBoost(1, func(l *Logger) {
+		assert.Equal(t, InfoLevel, l.DescribeCores()[0].Level, "Expected boost to be reflected.")
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Context constructs a field that describes the state of a context.Context,
+// to help debug timeouts in request handlers. It's encoded as an object with:
+//
+//   - "deadline": the context's deadline, if it has one;
+//   - "remaining": the time left until the deadline, negative once it's
+//     passed, measured when the field is encoded;
+//   - "error": ctx.Err(), if the context is done;
+//   - "cause": the context's cancellation cause, if it's done and the Go
+//     version supports context.Cause (1.20 and later).
+//
+// A context without a deadline that isn't done, such as
+// context.Background(), is encoded as an empty object; a nil ctx is encoded
+// as null.
+func Context(key string, ctx context.Context) Field {
+	if ctx == nil {
+		return nilField(key)
+	}
+	return Object(key, contextState{ctx})
+}
+
+type contextState struct {
+	ctx context.Context
+}
+
+func (c contextState) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if deadline, ok := c.ctx.Deadline(); ok {
+		enc.AddTime("deadline", deadline)
+		enc.AddDuration("remaining", time.Until(deadline))
+	}
+	if err := c.ctx.Err(); err != nil {
+		enc.AddString("error", err.Error())
+		if cause := contextCause(c.ctx); cause != nil {
+			enc.AddString("cause", cause.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func encodeContextField(t *testing.T, ctx context.Context) interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	Context("ctx", ctx).AddTo(enc)
+	require.Contains(t, enc.Fields, "ctx", "Expected a field under the key.")
+	return enc.Fields["ctx"]
+}
+
+func TestContextField(t *testing.T) {
+	t.Run("background", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{}, encodeContextField(t, context.Background()), "Expected an empty object.")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, encodeContextField(t, nil), "Expected null for a nil context.")
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		deadline := time.Now().Add(time.Hour)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+
+		got := encodeContextField(t, ctx).(map[string]interface{})
+		assert.Equal(t, deadline, got["deadline"], "Unexpected deadline.")
+		remaining := got["remaining"].(time.Duration)
+		assert.True(t, remaining > 0 && remaining <= time.Hour, "Unexpected remaining time %v.", remaining)
+		assert.NotContains(t, got, "error", "Context isn't done yet.")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		got := encodeContextField(t, ctx).(map[string]interface{})
+		assert.Less(t, got["remaining"].(time.Duration), time.Duration(0), "Expected negative remaining time.")
+		assert.Equal(t, context.DeadlineExceeded.Error(), got["error"], "Unexpected error.")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		got := encodeContextField(t, ctx).(map[string]interface{})
+		assert.NotContains(t, got, "deadline", "Expected no deadline.")
+		assert.Equal(t, context.Canceled.Error(), got["error"], "Unexpected error.")
+	})
+}
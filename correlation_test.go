@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var _uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func correlationIDs(t testing.TB, logs *observer.ObservedLogs, key string) []string {
+	var ids []string
+	for _, e := range logs.TakeAll() {
+		id, ok := e.ContextMap()[key].(string)
+		require.True(t, ok, "Entry %q is missing the correlation ID.", e.Message)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestWithCorrelationID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	parent := New(core)
+
+	first := parent.WithOptions(WithCorrelationID("cid"))
+	second := parent.WithOptions(WithCorrelationID("cid"))
+	child := first.With(String("k", "v"))
+
+	first.Info("first")
+	second.Info("second")
+	child.Info("child")
+	parent.Info("parent")
+
+	require.Equal(t, 4, logs.Len(), "Unexpected number of entries.")
+	assert.NotContains(t, logs.All()[3].ContextMap(), "cid", "Parent shouldn't have a correlation ID.")
+	ids := correlationIDs(t, logs.Filter(func(e observer.LoggedEntry) bool {
+		return e.Message != "parent"
+	}), "cid")
+	require.Len(t, ids, 3, "Unexpected number of tagged entries.")
+
+	for _, id := range ids {
+		assert.Regexp(t, _uuidRegexp, id, "Expected a version 4 UUID.")
+	}
+	assert.NotEqual(t, ids[0], ids[1], "Expected loggers to get distinct IDs.")
+	assert.Equal(t, ids[0], ids[2], "Expected child to inherit its parent's ID.")
+}
+
+func TestWithCorrelationIDFunc(t *testing.T) {
+	n := 0
+	gen := func() string {
+		n++
+		return string(rune('a' + n - 1))
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	New(core, WithCorrelationIDFunc("req", gen)).Info("one")
+	New(core, WithCorrelationIDFunc("req", gen)).Info("two")
+
+	assert.Equal(t, []string{"a", "b"}, correlationIDs(t, logs, "req"), "Unexpected IDs.")
+}
+
+func TestNewUUID(t *testing.T) {
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		id := newUUID()
+		assert.Regexp(t, _uuidRegexp, id, "Expected a version 4 UUID.")
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, 100, "Expected unique IDs.")
+}
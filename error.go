@@ -21,6 +21,9 @@
 package zap
 
 import (
+	"errors"
+	"fmt"
+
 	"go.uber.org/zap/internal/pool"
 	"go.uber.org/zap/zapcore"
 )
@@ -48,6 +51,85 @@ func NamedError(key string, err error) Field {
 	return Field{Key: key, Type: zapcore.ErrorType, Interface: err}
 }
 
+// ErrorDetailed constructs a field that, like NamedError, stores
+// err.Error() under the provided key, and also stores the error's concrete
+// Go type under key+".type", such as "*fs.PathError", to help classify
+// errors in dashboards.
+//
+// If err, or any error it wraps, has a code, as reported by a Code() string,
+// Code() int, or ErrorCode() string method, the code is also stored under
+// key+".code". The type is always that of err itself, even if it wraps
+// others. If passed a nil error, the field is encoded as null.
+func ErrorDetailed(key string, err error) Field {
+	if err == nil {
+		return nilField(key)
+	}
+	return Inline(detailedError{key: key, err: err})
+}
+
+type detailedError struct {
+	key string
+	err error
+}
+
+type (
+	stringCoder      interface{ Code() string }
+	intCoder         interface{ Code() int }
+	stringErrorCoder interface{ ErrorCode() string }
+)
+
+func (e detailedError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	NamedError(e.key, e.err).AddTo(enc)
+	enc.AddString(e.key+".type", fmt.Sprintf("%T", e.err))
+
+	var (
+		sc  stringCoder
+		ic  intCoder
+		sec stringErrorCoder
+	)
+	switch {
+	case errors.As(e.err, &sc):
+		enc.AddString(e.key+".code", sc.Code())
+	case errors.As(e.err, &ic):
+		enc.AddInt(e.key+".code", ic.Code())
+	case errors.As(e.err, &sec):
+		enc.AddString(e.key+".code", sec.ErrorCode())
+	}
+	return nil
+}
+
+// DPanicError is the value a Logger configured with DPanicStructured panics
+// with after writing a DPanic entry in development mode.
+type DPanicError struct {
+	// Entry is the logged entry.
+	Entry zapcore.Entry
+
+	fields []Field
+}
+
+// Error returns the entry's message.
+func (e *DPanicError) Error() string {
+	return e.Entry.Message
+}
+
+// Fields returns the fields passed at the log site. Fields added to the
+// Logger with With aren't included, since they've already been encoded.
+func (e *DPanicError) Fields() []Field {
+	return e.fields
+}
+
+// dpanicErrorHook panics with a *DPanicError after an entry is written.
+type dpanicErrorHook struct{}
+
+func (dpanicErrorHook) OnWrite(ce *zapcore.CheckedEntry, fields []Field) {
+	// The CheckedEntry and fields may be reused once this hook returns, so
+	// the panic value holds copies.
+	panic(&DPanicError{
+		Entry:  ce.Entry,
+		fields: append([]Field(nil), fields...),
+	})
+}
+
 type errArray []error
 
 func (errs errArray) MarshalLogArray(arr zapcore.ArrayEncoder) error {
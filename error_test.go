@@ -131,3 +131,72 @@ func (enc brokenArrayObjectEncoder) AddArray(key string, marshaler zapcore.Array
 func (enc brokenArrayObjectEncoder) AppendObject(zapcore.ObjectMarshaler) error {
 	return enc.Err
 }
+
+type detailCodedError struct {
+	code string
+}
+
+func (e detailCodedError) Error() string { return "coded failure" }
+func (e detailCodedError) Code() string  { return e.code }
+
+type detailStatusError struct{}
+
+func (detailStatusError) Error() string { return "status failure" }
+func (detailStatusError) Code() int     { return 503 }
+
+func TestErrorDetailed(t *testing.T) {
+	tests := []struct {
+		desc   string
+		err    error
+		expect map[string]interface{}
+	}{
+		{
+			desc: "plain",
+			err:  errors.New("fail"),
+			expect: map[string]interface{}{
+				"err":      "fail",
+				"err.type": "*errors.errorString",
+			},
+		},
+		{
+			desc: "coded",
+			err:  detailCodedError{"E42"},
+			expect: map[string]interface{}{
+				"err":      "coded failure",
+				"err.type": "zap.detailCodedError",
+				"err.code": "E42",
+			},
+		},
+		{
+			desc: "wrapped coded",
+			err:  fmt.Errorf("charging: %w", detailCodedError{"E42"}),
+			expect: map[string]interface{}{
+				"err":      "charging: coded failure",
+				"err.type": "*fmt.wrapError",
+				"err.code": "E42",
+			},
+		},
+		{
+			desc: "int code",
+			err:  fmt.Errorf("calling: %w", detailStatusError{}),
+			expect: map[string]interface{}{
+				"err":      "calling: status failure",
+				"err.type": "*fmt.wrapError",
+				"err.code": 503,
+			},
+		},
+		{
+			desc:   "nil",
+			err:    nil,
+			expect: map[string]interface{}{"err": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			ErrorDetailed("err", tt.err).AddTo(enc)
+			assert.Equal(t, tt.expect, enc.Fields, "Unexpected fields.")
+		})
+	}
+}
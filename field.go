@@ -21,8 +21,16 @@
 package zap
 
 import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/internal/stacktrace"
@@ -44,6 +52,22 @@ func Skip() Field {
 	return Field{Type: zapcore.SkipType}
 }
 
+// Cond returns f if condition is true, and a no-op field (see Skip)
+// otherwise. It makes conditional fields easy to inline:
+//
+//	logger.Info("request handled", zap.Cond(verbose, zap.Any("request", req)))
+//
+// Unlike an if statement, Cond doesn't defer any work: f, including any
+// arguments passed to its constructor, is evaluated whether or not condition
+// holds. For fields that are expensive to construct, prefer an if statement
+// or a lazily evaluated field such as Stringer or Object.
+func Cond(condition bool, f Field) Field {
+	if condition {
+		return f
+	}
+	return Skip()
+}
+
 // nilField returns a field which will marshal explicitly as nil. See motivation
 // in https://github.com/uber-go/zap/issues/753 . If we ever make breaking
 // changes and add zapcore.NilType and zapcore.ObjectEncoder.AddNil, the
@@ -59,6 +83,37 @@ func Binary(key string, val []byte) Field {
 	return Field{Key: key, Type: zapcore.BinaryType, Interface: val}
 }
 
+// Hex constructs a field that carries a binary blob as a lowercase
+// hexadecimal string. Unlike Binary, the encoding doesn't depend on the
+// encoder, which makes it useful for hashes and keys. A nil slice is encoded
+// as null and an empty slice as the empty string.
+func Hex(key string, val []byte) Field {
+	if val == nil {
+		return nilField(key)
+	}
+	return String(key, hex.EncodeToString(val))
+}
+
+// Base64 constructs a field that carries a binary blob as a string, using
+// standard padded base64 encoding (RFC 4648). Unlike Binary, the encoding
+// doesn't depend on the encoder. A nil slice is encoded as null and an empty
+// slice as the empty string.
+func Base64(key string, val []byte) Field {
+	if val == nil {
+		return nilField(key)
+	}
+	return String(key, base64.StdEncoding.EncodeToString(val))
+}
+
+// Base64URL is like Base64, but uses the URL-safe alphabet (RFC 4648,
+// section 5) with padding.
+func Base64URL(key string, val []byte) Field {
+	if val == nil {
+		return nilField(key)
+	}
+	return String(key, base64.URLEncoding.EncodeToString(val))
+}
+
 // Bool constructs a field that carries a bool.
 func Bool(key string, val bool) Field {
 	var ival int64
@@ -331,7 +386,7 @@ func Reflect(key string, val interface{}) Field {
 // subsequent fields will be added to the new namespace.
 //
 // This helps prevent key collisions when injecting loggers into sub-components
-// or third-party libraries.
+// or third-party libraries. To scope only a group of fields, use Dict.
 func Namespace(key string) Field {
 	return Field{Key: key, Type: zapcore.NamespaceType}
 }
@@ -342,6 +397,13 @@ func Stringer(key string, val fmt.Stringer) Field {
 	return Field{Key: key, Type: zapcore.StringerType, Interface: val}
 }
 
+// TextMarshaler constructs a field with the given key and the output of the
+// value's MarshalText method, logged as a string. The MarshalText method is
+// called lazily.
+func TextMarshaler(key string, val encoding.TextMarshaler) Field {
+	return Field{Key: key, Type: zapcore.TextMarshalerType, Interface: val}
+}
+
 // Time constructs a Field with the given key and value. The encoder
 // controls how the time is serialized.
 func Time(key string, val time.Time) Field {
@@ -360,6 +422,24 @@ func Timep(key string, val *time.Time) Field {
 	return Time(key, *val)
 }
 
+// TimeLayout constructs a field that formats val with the given layout, as
+// in time.Time.Format, rather than with the encoder's EncodeTime. This is
+// useful when one field needs a particular format, such as RFC 3339, while
+// the encoder writes other times differently. Formatting is deferred until
+// the field is encoded.
+func TimeLayout(key string, val time.Time, layout string) Field {
+	return Stringer(key, timeLayout{t: val, layout: layout})
+}
+
+type timeLayout struct {
+	t      time.Time
+	layout string
+}
+
+func (t timeLayout) String() string {
+	return t.t.Format(t.layout)
+}
+
 // Stack constructs a field that stores a stacktrace of the current goroutine
 // under provided key. Keep in mind that taking a stacktrace is eager and
 // expensive (relatively speaking); this function both makes an allocation and
@@ -378,6 +458,40 @@ func StackSkip(key string, skip int) Field {
 	return String(key, stacktrace.Take(skip+1)) // skip StackSkip
 }
 
+// StackSkipFiltered constructs a field similarly to StackSkip, but only
+// includes the frames for which filter returns true. This makes it possible
+// to produce a concise trace of application code, without frames from the
+// runtime, standard library, or vendored dependencies. If filter is nil,
+// DefaultStackFilter is used.
+func StackSkipFiltered(key string, skip int, filter func(runtime.Frame) bool) Field {
+	if filter == nil {
+		filter = DefaultStackFilter
+	}
+	return String(key, stacktrace.TakeFiltered(skip+1, filter)) // skip StackSkipFiltered
+}
+
+// DefaultStackFilter reports whether a frame belongs to application code. It
+// excludes frames from the Go runtime, the testing package, and zap itself,
+// including vendored copies of zap.
+func DefaultStackFilter(frame runtime.Frame) bool {
+	fn := frame.Function
+	if i := strings.LastIndex(fn, "/vendor/"); i >= 0 {
+		fn = fn[i+len("/vendor/"):]
+	}
+	for _, pkg := range _stackFilteredPackages {
+		if strings.HasPrefix(fn, pkg) && len(fn) > len(pkg) {
+			// Match the package exactly (e.g. "go.uber.org/zap" but not
+			// "go.uber.org/zap_test") or one of its subpackages.
+			if c := fn[len(pkg)]; c == '.' || c == '/' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var _stackFilteredPackages = []string{"runtime", "testing", "go.uber.org/zap"}
+
 // Duration constructs a field with the given key and value. The encoder
 // controls how the duration is serialized.
 func Duration(key string, val time.Duration) Field {
@@ -393,6 +507,165 @@ func Durationp(key string, val *time.Duration) Field {
 	return Duration(key, *val)
 }
 
+// DurationBothSuffix is appended to the key of the numeric field written by
+// DurationBoth. It's read when the field is constructed.
+var DurationBothSuffix = "_ms"
+
+// DurationBoth constructs a field that writes a duration twice: under key as
+// a human-readable string, such as "1.5s", and under key plus
+// DurationBothSuffix as a float number of milliseconds, such as 1500. The
+// encoder's DurationEncoder isn't used for either.
+func DurationBoth(key string, val time.Duration) Field {
+	return Inline(durationBoth{
+		key:    key,
+		numKey: key + DurationBothSuffix,
+		val:    val,
+	})
+}
+
+type durationBoth struct {
+	key    string
+	numKey string
+	val    time.Duration
+}
+
+func (d durationBoth) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString(d.key, d.val.String())
+	enc.AddFloat64(d.numKey, float64(d.val)/float64(time.Millisecond))
+	return nil
+}
+
+// TimeRange constructs a field that carries a span of time as an object
+// with the start and end times, encoded with the encoder's TimeEncoder, and
+// the duration end.Sub(start), encoded with its DurationEncoder. The
+// duration is negative if end is before start. A zero start or end time is
+// omitted, along with the duration, so that an open range, such as an
+// audit window that hasn't closed yet, doesn't log a meaningless duration.
+func TimeRange(key string, start, end time.Time) Field {
+	return Object(key, timeRange{start: start, end: end})
+}
+
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func (r timeRange) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if !r.start.IsZero() {
+		enc.AddTime("start", r.start)
+	}
+	if !r.end.IsZero() {
+		enc.AddTime("end", r.end)
+	}
+	if !r.start.IsZero() && !r.end.IsZero() {
+		enc.AddDuration("duration", r.end.Sub(r.start))
+	}
+	return nil
+}
+
+// ByteSizeSuffix is appended to the key of the human-readable string written
+// by ByteSize and ByteSizeDecimal. It's read when the field is constructed.
+var ByteSizeSuffix = "_human"
+
+// ByteSize constructs a field that writes a count of bytes twice: under key
+// as the raw integer, such as 1572864, and under key plus ByteSizeSuffix as
+// a human-readable string in binary (IEC) units, such as "1.5 MiB". Use
+// ByteSizeDecimal for decimal (SI) units.
+func ByteSize(key string, n int64) Field {
+	return Inline(byteSize{
+		key:      key,
+		humanKey: key + ByteSizeSuffix,
+		n:        n,
+		units:    _binaryByteUnits,
+	})
+}
+
+// ByteSizeDecimal is like ByteSize, but formats the human-readable string in
+// decimal (SI) units, such as "1.6 MB".
+func ByteSizeDecimal(key string, n int64) Field {
+	return Inline(byteSize{
+		key:      key,
+		humanKey: key + ByteSizeSuffix,
+		n:        n,
+		units:    _decimalByteUnits,
+	})
+}
+
+type byteUnits struct {
+	base  float64
+	names []string
+}
+
+var (
+	_binaryByteUnits  = &byteUnits{1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}}
+	_decimalByteUnits = &byteUnits{1000, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}}
+)
+
+// format formats n in the largest unit in which its magnitude is at least
+// one, with at most one decimal place.
+func (u *byteUnits) format(n int64) string {
+	sign := ""
+	mag := uint64(n)
+	if n < 0 {
+		sign = "-"
+		mag = uint64(-n) // wraps correctly for math.MinInt64
+	}
+	if float64(mag) < u.base {
+		return sign + strconv.FormatUint(mag, 10) + " " + u.names[0]
+	}
+
+	v, i := float64(mag), 0
+	for i < len(u.names)-1 && math.Round(v*10)/10 >= u.base {
+		v /= u.base
+		i++
+	}
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	return sign + strings.TrimSuffix(s, ".0") + " " + u.names[i]
+}
+
+type byteSize struct {
+	key      string
+	humanKey string
+	n        int64
+	units    *byteUnits
+}
+
+func (b byteSize) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64(b.key, b.n)
+	enc.AddString(b.humanKey, b.units.format(b.n))
+	return nil
+}
+
+// Chan constructs a field that carries the length and capacity of a
+// channel, such as {"len":3,"cap":10}, which helps debug backpressure. ch
+// may be a channel of any element type or direction; a nil channel is
+// encoded as null. If ch isn't a channel, the field is replaced by an error
+// string under key plus "Error", matching how encoders report marshaling
+// failures.
+func Chan(key string, ch interface{}) Field {
+	v := reflect.ValueOf(ch)
+	if !v.IsValid() {
+		return nilField(key)
+	}
+	if v.Kind() != reflect.Chan {
+		return String(key+"Error", fmt.Sprintf("zap.Chan: %T isn't a channel", ch))
+	}
+	if v.IsNil() {
+		return nilField(key)
+	}
+	return Object(key, chanStats{len: v.Len(), cap: v.Cap()})
+}
+
+type chanStats struct {
+	len, cap int
+}
+
+func (c chanStats) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("len", c.len)
+	enc.AddInt("cap", c.cap)
+	return nil
+}
+
 // Object constructs a field with the given key and ObjectMarshaler. It
 // provides a flexible, but still type-safe and efficient, way to add map- or
 // struct-like user-defined types to the logging context. The struct's
@@ -404,6 +677,13 @@ func Object(key string, val zapcore.ObjectMarshaler) Field {
 // Inline constructs a Field that is similar to Object, but it
 // will add the elements of the provided ObjectMarshaler to the
 // current namespace.
+//
+// This flattens the object into the enclosing object or namespace, at any
+// depth, rather than nesting it under a key. Inlined keys aren't
+// deduplicated: if one collides with another key in the same object, the
+// last one written wins, since the map encoder overwrites the earlier value
+// and JSON decoders (including encoding/json) keep the last occurrence of a
+// repeated key.
 func Inline(val zapcore.ObjectMarshaler) Field {
 	return zapcore.Field{
 		Type:      zapcore.InlineMarshalerType,
@@ -413,6 +693,9 @@ func Inline(val zapcore.ObjectMarshaler) Field {
 
 // Dict constructs a field containing the provided key-value pairs.
 // It acts similar to [Object], but with the fields specified as arguments.
+// Its output is identical to adding [Namespace] with the same key followed by
+// the fields, but the scope closes after the last field, so fields added
+// later can't accidentally end up inside it.
 func Dict(key string, val ...Field) Field {
 	return dictField(key, val)
 }
@@ -484,6 +767,18 @@ func (f anyFieldC[T]) Any(key string, val any) Field {
 // Since byte/uint8 and rune/int32 are aliases, Any can't differentiate between
 // them. To minimize surprises, []byte values are treated as binary blobs, byte
 // values are treated as uint8, and runes are always treated as integers.
+//
+// Values that don't match one of the concrete types Any knows about are
+// checked against these interfaces, in order, and logged with the first
+// match:
+//
+//  1. error, logged with NamedError
+//  2. fmt.Stringer, logged with Stringer
+//  3. json.Marshaler, logged with Reflect (the reflection-based encoder calls
+//     MarshalJSON, so the value keeps its JSON representation)
+//  4. encoding.TextMarshaler, logged with TextMarshaler
+//
+// Anything else is logged with Reflect.
 func Any(key string, value interface{}) Field {
 	var c interface{ Any(string, any) Field }
 
@@ -614,6 +909,10 @@ func Any(key string, value interface{}) Field {
 		c = anyFieldC[[]error](Errors)
 	case fmt.Stringer:
 		c = anyFieldC[fmt.Stringer](Stringer)
+	case json.Marshaler:
+		c = anyFieldC[any](Reflect)
+	case encoding.TextMarshaler:
+		c = anyFieldC[encoding.TextMarshaler](TextMarshaler)
 	default:
 		c = anyFieldC[any](Reflect)
 	}
@@ -21,14 +21,17 @@
 package zap
 
 import (
+	"encoding/json"
 	"math"
 	"net"
 	"regexp"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/internal/stacktrace"
 	"go.uber.org/zap/zapcore"
 )
@@ -40,6 +43,17 @@ func (n username) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	return nil
 }
 
+// textOnly is an encoding.TextMarshaler that implements no other interface
+// known to Any.
+type textOnly struct{}
+
+func (textOnly) MarshalText() ([]byte, error) { return []byte("text"), nil }
+
+// jsonAndText implements both json.Marshaler and encoding.TextMarshaler.
+type jsonAndText struct{ textOnly }
+
+func (jsonAndText) MarshalJSON() ([]byte, error) { return []byte(`{"json":true}`), nil }
+
 func assertCanBeReused(t testing.TB, field Field) {
 	var wg sync.WaitGroup
 
@@ -100,6 +114,15 @@ func TestFieldConstructors(t *testing.T) {
 		{"Bool", Field{Key: "k", Type: zapcore.BoolType, Integer: 1}, Bool("k", true)},
 		{"Bool", Field{Key: "k", Type: zapcore.BoolType, Integer: 1}, Bool("k", true)},
 		{"ByteString", Field{Key: "k", Type: zapcore.ByteStringType, Interface: []byte("ab12")}, ByteString("k", []byte("ab12"))},
+		{"Hex", Field{Key: "k", Type: zapcore.StringType, String: "fbff00"}, Hex("k", []byte{0xfb, 0xff, 0x00})},
+		{"Hex:Empty", Field{Key: "k", Type: zapcore.StringType, String: ""}, Hex("k", []byte{})},
+		{"Hex:Nil", nilField("k"), Hex("k", nil)},
+		{"Base64", Field{Key: "k", Type: zapcore.StringType, String: "+/8="}, Base64("k", []byte{0xfb, 0xff})},
+		{"Base64:Empty", Field{Key: "k", Type: zapcore.StringType, String: ""}, Base64("k", []byte{})},
+		{"Base64:Nil", nilField("k"), Base64("k", nil)},
+		{"Base64URL", Field{Key: "k", Type: zapcore.StringType, String: "-_8="}, Base64URL("k", []byte{0xfb, 0xff})},
+		{"Base64URL:Empty", Field{Key: "k", Type: zapcore.StringType, String: ""}, Base64URL("k", []byte{})},
+		{"Base64URL:Nil", nilField("k"), Base64URL("k", nil)},
 		{"Complex128", Field{Key: "k", Type: zapcore.Complex128Type, Interface: 1 + 2i}, Complex128("k", 1+2i)},
 		{"Complex64", Field{Key: "k", Type: zapcore.Complex64Type, Interface: complex64(1 + 2i)}, Complex64("k", 1+2i)},
 		{"Duration", Field{Key: "k", Type: zapcore.DurationType, Integer: 1}, Duration("k", 1)},
@@ -124,12 +147,15 @@ func TestFieldConstructors(t *testing.T) {
 		{"Reflect", Field{Key: "k", Type: zapcore.ReflectType, Interface: ints}, Reflect("k", ints)},
 		{"Reflect", Field{Key: "k", Type: zapcore.ReflectType}, Reflect("k", nil)},
 		{"Stringer", Field{Key: "k", Type: zapcore.StringerType, Interface: addr}, Stringer("k", addr)},
+		{"TextMarshaler", Field{Key: "k", Type: zapcore.TextMarshalerType, Interface: textOnly{}}, TextMarshaler("k", textOnly{})},
 		{"Object", Field{Key: "k", Type: zapcore.ObjectMarshalerType, Interface: name}, Object("k", name)},
 		{"Inline", Field{Type: zapcore.InlineMarshalerType, Interface: name}, Inline(name)},
 		{"Any:ObjectMarshaler", Any("k", name), Object("k", name)},
 		{"Any:ArrayMarshaler", Any("k", bools([]bool{true})), Array("k", bools([]bool{true}))},
 		{"Any:Dict", Any("k", []Field{String("k", "v")}), Dict("k", String("k", "v"))},
 		{"Any:Stringer", Any("k", addr), Stringer("k", addr)},
+		{"Any:JSONMarshaler", Any("k", jsonAndText{}), Reflect("k", jsonAndText{})},
+		{"Any:TextMarshaler", Any("k", textOnly{}), TextMarshaler("k", textOnly{})},
 		{"Any:Bool", Any("k", true), Bool("k", true)},
 		{"Any:Bools", Any("k", []bool{true}), Bools("k", []bool{true})},
 		{"Any:Byte", Any("k", byte(1)), Uint8("k", 1)},
@@ -265,6 +291,109 @@ func TestFieldConstructors(t *testing.T) {
 	}
 }
 
+func TestPointerFieldsEncoding(t *testing.T) {
+	var (
+		boolVal       = true
+		complex128Val = complex(1, 2)
+		complex64Val  = complex64(complex(1, 2))
+		durationVal   = time.Second
+		float64Val    = 1.5
+		float32Val    = float32(1.5)
+		intVal        = 1
+		int64Val      = int64(1)
+		int32Val      = int32(1)
+		int16Val      = int16(1)
+		int8Val       = int8(1)
+		stringVal     = "v"
+		timeVal       = time.Unix(0, 0).UTC()
+		uintVal       = uint(1)
+		uint64Val     = uint64(1)
+		uint32Val     = uint32(1)
+		uint16Val     = uint16(1)
+		uint8Val      = uint8(1)
+		uintptrVal    = uintptr(1)
+	)
+
+	tests := []struct {
+		name  string
+		nil   Field
+		value Field
+		want  string
+	}{
+		{"Boolp", Boolp("k", nil), Boolp("k", &boolVal), `true`},
+		{"Complex128p", Complex128p("k", nil), Complex128p("k", &complex128Val), `"1+2i"`},
+		{"Complex64p", Complex64p("k", nil), Complex64p("k", &complex64Val), `"1+2i"`},
+		{"Durationp", Durationp("k", nil), Durationp("k", &durationVal), `1`},
+		{"Float64p", Float64p("k", nil), Float64p("k", &float64Val), `1.5`},
+		{"Float32p", Float32p("k", nil), Float32p("k", &float32Val), `1.5`},
+		{"Intp", Intp("k", nil), Intp("k", &intVal), `1`},
+		{"Int64p", Int64p("k", nil), Int64p("k", &int64Val), `1`},
+		{"Int32p", Int32p("k", nil), Int32p("k", &int32Val), `1`},
+		{"Int16p", Int16p("k", nil), Int16p("k", &int16Val), `1`},
+		{"Int8p", Int8p("k", nil), Int8p("k", &int8Val), `1`},
+		{"Stringp", Stringp("k", nil), Stringp("k", &stringVal), `"v"`},
+		{"Timep", Timep("k", nil), Timep("k", &timeVal), `"1970-01-01T00:00:00.000Z"`},
+		{"Uintp", Uintp("k", nil), Uintp("k", &uintVal), `1`},
+		{"Uint64p", Uint64p("k", nil), Uint64p("k", &uint64Val), `1`},
+		{"Uint32p", Uint32p("k", nil), Uint32p("k", &uint32Val), `1`},
+		{"Uint16p", Uint16p("k", nil), Uint16p("k", &uint16Val), `1`},
+		{"Uint8p", Uint8p("k", nil), Uint8p("k", &uint8Val), `1`},
+		{"Uintptrp", Uintptrp("k", nil), Uintptrp("k", &uintptrVal), `1`},
+	}
+
+	cfg := NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.EncodeDuration = zapcore.SecondsDurationEncoder
+	enc := zapcore.NewJSONEncoder(cfg)
+
+	encode := func(f Field) string {
+		buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{f})
+		require.NoError(t, err, "Unexpected encoding error.")
+		defer buf.Free()
+		return buf.String()
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, encode(tt.nil), `"k":null`, "Expected nil pointer to encode as null.")
+			assert.Contains(t, encode(tt.value), `"k":`+tt.want, "Expected non-nil pointer to be dereferenced.")
+		})
+	}
+}
+
+func TestBinaryStringFieldsEncoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+		json  string
+	}{
+		{"Hex", Hex("k", []byte{0xfb, 0xff}), `"k":"fbff"`},
+		{"Hex:Empty", Hex("k", []byte{}), `"k":""`},
+		{"Hex:Nil", Hex("k", nil), `"k":null`},
+		{"Base64", Base64("k", []byte{0xfb, 0xff}), `"k":"+/8="`},
+		{"Base64URL", Base64URL("k", []byte{0xfb, 0xff}), `"k":"-_8="`},
+		{"Base64URL:Nil", Base64URL("k", nil), `"k":null`},
+	}
+
+	cfg := zapcore.EncoderConfig{MessageKey: "M"}
+	encoders := map[string]zapcore.Encoder{
+		"json":    zapcore.NewJSONEncoder(cfg),
+		"console": zapcore.NewConsoleEncoder(cfg),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, enc := range encoders {
+				buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{tt.field})
+				require.NoError(t, err, "Unexpected %s encoding error.", name)
+				// The console encoder writes fields as spaced JSON.
+				out := strings.ReplaceAll(buf.String(), `": `, `":`)
+				assert.Contains(t, out, tt.json, "Unexpected %s output.", name)
+				buf.Free()
+			}
+		})
+	}
+}
+
 func TestStackField(t *testing.T) {
 	f := Stack("stacktrace")
 	assert.Equal(t, "stacktrace", f.Key, "Unexpected field key.")
@@ -356,3 +485,393 @@ func TestDictObject(t *testing.T) {
 		})
 	}
 }
+
+func TestDictMatchesNamespace(t *testing.T) {
+	encoders := map[string]func(zapcore.EncoderConfig) zapcore.Encoder{
+		"json":    zapcore.NewJSONEncoder,
+		"console": zapcore.NewConsoleEncoder,
+	}
+	tests := []struct {
+		desc      string
+		scoped    []Field
+		namespace []Field
+	}{
+		{
+			desc:      "flat",
+			scoped:    []Field{String("a", "b"), Dict("ns", String("k", "v"), Int("n", 1))},
+			namespace: []Field{String("a", "b"), Namespace("ns"), String("k", "v"), Int("n", 1)},
+		},
+		{
+			desc: "nested",
+			scoped: []Field{
+				Dict("outer",
+					String("k", "v"),
+					Dict("inner", Bool("deep", true), Dict("innermost", Int("n", 1))),
+				),
+			},
+			namespace: []Field{
+				Namespace("outer"),
+				String("k", "v"),
+				Namespace("inner"),
+				Bool("deep", true),
+				Namespace("innermost"),
+				Int("n", 1),
+			},
+		},
+		{
+			desc:      "empty",
+			scoped:    []Field{Dict("ns")},
+			namespace: []Field{Namespace("ns")},
+		},
+	}
+
+	for name, newEnc := range encoders {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.desc, func(t *testing.T) {
+				enc := newEnc(zapcore.EncoderConfig{MessageKey: "msg"})
+				ent := zapcore.Entry{Message: "m"}
+
+				want, err := enc.EncodeEntry(ent, tt.namespace)
+				require.NoError(t, err, "Unexpected encoding error.")
+				defer want.Free()
+
+				got, err := enc.EncodeEntry(ent, tt.scoped)
+				require.NoError(t, err, "Unexpected encoding error.")
+				defer got.Free()
+
+				assert.Equal(t, want.String(), got.String(), "Dict should match Namespace output.")
+			})
+		}
+	}
+}
+
+func TestDictDoesNotLeak(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{
+		Dict("ns", String("k", "v")),
+		String("after", "outside"),
+	})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+	assert.Equal(t, `{"ns":{"k":"v"},"after":"outside"}`+"\n", buf.String(), "Fields after Dict should stay outside it.")
+	assertCanBeReused(t, Dict("ns", String("k", "v")))
+}
+
+func TestTimeLayout(t *testing.T) {
+	ts := time.Date(2024, 3, 4, 5, 6, 7, 800, time.UTC)
+	cfg := zapcore.EncoderConfig{
+		MessageKey: "msg",
+		TimeKey:    "ts",
+		EncodeTime: zapcore.EpochTimeEncoder,
+	}
+
+	tests := []struct {
+		name     string
+		enc      zapcore.Encoder
+		expected string
+	}{
+		{
+			name:     "json",
+			enc:      zapcore.NewJSONEncoder(cfg),
+			expected: `{"ts":1709528767.0000007,"msg":"m","at":"2024-03-04T05:06:07Z","epoch":1709528767.0000007,"day":"Mon Mar  4"}` + "\n",
+		},
+		{
+			name:     "console",
+			enc:      zapcore.NewConsoleEncoder(cfg),
+			expected: "1.7095287670000007e+09\tm\t" + `{"at": "2024-03-04T05:06:07Z", "epoch": 1709528767.0000007, "day": "Mon Mar  4"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := tt.enc.EncodeEntry(zapcore.Entry{Message: "m", Time: ts}, []Field{
+				TimeLayout("at", ts, time.RFC3339),
+				Time("epoch", ts),
+				TimeLayout("day", ts, "Mon Jan _2"),
+			})
+			require.NoError(t, err, "Unexpected encoding error.")
+			defer buf.Free()
+			assert.Equal(t, tt.expected, buf.String(), "Unexpected output.")
+		})
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	f := TimeLayout("k", ts, time.Kitchen)
+	f.AddTo(enc)
+	assert.Equal(t, "5:06AM", enc.Fields["k"], "Unexpected formatted time.")
+	assertCanBeReused(t, f)
+}
+
+func TestInlineFlattens(t *testing.T) {
+	user := DictObject(String("name", "alice"), Int("id", 7))
+	nested := zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("role", "admin")
+		Inline(user).AddTo(enc)
+		return nil
+	})
+
+	tests := []struct {
+		desc     string
+		fields   []Field
+		expected string
+	}{
+		{
+			desc:     "top level",
+			fields:   []Field{String("a", "b"), Inline(user)},
+			expected: `{"a":"b","name":"alice","id":7}`,
+		},
+		{
+			desc:     "nested flattening",
+			fields:   []Field{Inline(nested)},
+			expected: `{"role":"admin","name":"alice","id":7}`,
+		},
+		{
+			desc:     "inside namespace",
+			fields:   []Field{Namespace("req"), Inline(nested), String("after", "x")},
+			expected: `{"req":{"role":"admin","name":"alice","id":7,"after":"x"}}`,
+		},
+		{
+			desc:     "inside nested object",
+			fields:   []Field{Object("req", nested)},
+			expected: `{"req":{"role":"admin","name":"alice","id":7}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, tt.fields)
+			require.NoError(t, err, "Unexpected encoding error.")
+			defer buf.Free()
+			assert.Equal(t, tt.expected+"\n", buf.String(), "Unexpected output.")
+		})
+	}
+}
+
+func TestInlineCollisions(t *testing.T) {
+	fields := []Field{String("name", "bob"), Inline(DictObject(String("name", "alice")))}
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, fields)
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "Output must be valid JSON.")
+	assert.Equal(t, "alice", decoded["name"], "Expected last key to win when decoded.")
+
+	mapEnc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(mapEnc)
+	}
+	assert.Equal(t, "alice", mapEnc.Fields["name"], "Expected last key to win in map encoder.")
+}
+
+func TestDurationBoth(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	DurationBoth("elapsed", 1500*time.Millisecond).AddTo(enc)
+	assert.Equal(t, map[string]interface{}{
+		"elapsed":    "1.5s",
+		"elapsed_ms": float64(1500),
+	}, enc.Fields, "Unexpected fields.")
+
+	enc = zapcore.NewMapObjectEncoder()
+	DurationBoth("tiny", 250*time.Microsecond).AddTo(enc)
+	assert.Equal(t, "250µs", enc.Fields["tiny"], "Unexpected string value.")
+	assert.Equal(t, 0.25, enc.Fields["tiny_ms"], "Unexpected fractional milliseconds.")
+}
+
+func TestDurationBothSuffix(t *testing.T) {
+	defer func(s string) { DurationBothSuffix = s }(DurationBothSuffix)
+	DurationBothSuffix = "Millis"
+	f := DurationBoth("latency", time.Second)
+	DurationBothSuffix = "_ignored"
+
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	assert.Equal(t, map[string]interface{}{
+		"latency":       "1s",
+		"latencyMillis": float64(1000),
+	}, enc.Fields, "Suffix should be read when the field is constructed.")
+}
+
+func TestTimeRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+
+	tests := []struct {
+		desc       string
+		start, end time.Time
+		expect     map[string]interface{}
+	}{
+		{
+			desc:   "forward",
+			start:  start,
+			end:    end,
+			expect: map[string]interface{}{"start": start, "end": end, "duration": 90 * time.Second},
+		},
+		{
+			desc:   "negative",
+			start:  end,
+			end:    start,
+			expect: map[string]interface{}{"start": end, "end": start, "duration": -90 * time.Second},
+		},
+		{
+			desc:   "open",
+			start:  start,
+			expect: map[string]interface{}{"start": start},
+		},
+		{
+			desc:   "zero",
+			expect: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			TimeRange("window", tt.start, tt.end).AddTo(enc)
+			assert.Equal(t, tt.expect, enc.Fields["window"], "Unexpected time range.")
+		})
+	}
+}
+
+func TestTimeRangeEncoders(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	f := TimeRange("window", start, start.Add(1500*time.Millisecond))
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		EncodeTime:     zapcore.RFC3339TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{f})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+	assert.Equal(t,
+		`{"window":{"start":"1970-01-01T00:00:00Z","end":"1970-01-01T00:00:01Z","duration":"1.5s"}}`+"\n",
+		buf.String(), "Expected the configured time and duration encoders.")
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		n       int64
+		binary  string
+		decimal string
+	}{
+		{0, "0 B", "0 B"},
+		{999, "999 B", "999 B"},
+		{1000, "1000 B", "1 kB"},
+		{1023, "1023 B", "1 kB"},
+		{1024, "1 KiB", "1 kB"},
+		{1536, "1.5 KiB", "1.5 kB"},
+		{1572864, "1.5 MiB", "1.6 MB"},
+		{1048575, "1 MiB", "1 MB"},
+		{-1, "-1 B", "-1 B"},
+		{-1024, "-1 KiB", "-1 kB"},
+		{math.MaxInt64, "8 EiB", "9.2 EB"},
+		{math.MinInt64, "-8 EiB", "-9.2 EB"},
+	}
+
+	for _, tt := range tests {
+		enc := zapcore.NewMapObjectEncoder()
+		ByteSize("size", tt.n).AddTo(enc)
+		assert.Equal(t, map[string]interface{}{
+			"size":       tt.n,
+			"size_human": tt.binary,
+		}, enc.Fields, "Unexpected binary fields for %d.", tt.n)
+
+		enc = zapcore.NewMapObjectEncoder()
+		ByteSizeDecimal("size", tt.n).AddTo(enc)
+		assert.Equal(t, tt.decimal, enc.Fields["size_human"], "Unexpected decimal string for %d.", tt.n)
+	}
+}
+
+func TestByteSizeSuffix(t *testing.T) {
+	defer func(s string) { ByteSizeSuffix = s }(ByteSizeSuffix)
+	ByteSizeSuffix = "Human"
+	f := ByteSize("body", 2048)
+	ByteSizeSuffix = "_ignored"
+
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	assert.Equal(t, map[string]interface{}{
+		"body":      int64(2048),
+		"bodyHuman": "2 KiB",
+	}, enc.Fields, "Suffix should be read when the field is constructed.")
+}
+
+func TestCond(t *testing.T) {
+	assert.Equal(t, String("k", "v"), Cond(true, String("k", "v")), "Expected the field when the condition holds.")
+	assert.Equal(t, Skip(), Cond(false, String("k", "v")), "Expected a no-op field otherwise.")
+
+	for _, enc := range []zapcore.Encoder{
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+		zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+	} {
+		skipped, err := enc.EncodeEntry(
+			zapcore.Entry{Message: "m"},
+			[]Field{String("a", "b"), Cond(false, Int("detail", 1)), Cond(true, Int("n", 2))},
+		)
+		require.NoError(t, err, "Unexpected encoding error.")
+		plain, err := enc.EncodeEntry(
+			zapcore.Entry{Message: "m"},
+			[]Field{String("a", "b"), Int("n", 2)},
+		)
+		require.NoError(t, err, "Unexpected encoding error.")
+		assert.Equal(t, plain.String(), skipped.String(), "Skipped fields should produce no output.")
+		skipped.Free()
+		plain.Free()
+	}
+}
+
+func TestChan(t *testing.T) {
+	buffered := make(chan int, 10)
+	buffered <- 1
+	buffered <- 2
+	var nilChan chan string
+
+	tests := []struct {
+		desc string
+		ch   interface{}
+		want map[string]interface{}
+	}{
+		{
+			desc: "buffered",
+			ch:   buffered,
+			want: map[string]interface{}{"ch": map[string]interface{}{"len": 2, "cap": 10}},
+		},
+		{
+			desc: "unbuffered",
+			ch:   make(chan struct{}),
+			want: map[string]interface{}{"ch": map[string]interface{}{"len": 0, "cap": 0}},
+		},
+		{
+			desc: "receive-only",
+			ch:   (<-chan int)(buffered),
+			want: map[string]interface{}{"ch": map[string]interface{}{"len": 2, "cap": 10}},
+		},
+		{
+			desc: "nil channel",
+			ch:   nilChan,
+			want: map[string]interface{}{"ch": nil},
+		},
+		{
+			desc: "nil interface",
+			ch:   nil,
+			want: map[string]interface{}{"ch": nil},
+		},
+		{
+			desc: "not a channel",
+			ch:   42,
+			want: map[string]interface{}{"chError": "zap.Chan: int isn't a channel"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			Chan("ch", tt.ch).AddTo(enc)
+			assert.Equal(t, tt.want, enc.Fields, "Unexpected fields.")
+		})
+	}
+}
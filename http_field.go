@@ -0,0 +1,253 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// _defaultHTTPHeaders are the headers logged unless the HTTPHeaders option
+// supplies a different allowlist.
+var _defaultHTTPHeaders = []string{
+	"Accept",
+	"Content-Type",
+	"User-Agent",
+	"X-Request-Id",
+}
+
+// _redactedHTTPHeaders carry credentials, so their values are never logged,
+// even if they're allowlisted.
+var _redactedHTTPHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+const _redactedHTTPHeaderValue = "[REDACTED]"
+
+// An HTTPFieldOption configures the fields built by HTTPRequest,
+// HTTPResponse, and Header.
+type HTTPFieldOption interface {
+	apply(*httpFieldConfig)
+}
+
+// httpFieldOptionFunc wraps a func so it satisfies the HTTPFieldOption
+// interface.
+type httpFieldOptionFunc func(*httpFieldConfig)
+
+func (f httpFieldOptionFunc) apply(cfg *httpFieldConfig) {
+	f(cfg)
+}
+
+// HTTPHeaders replaces the allowlist of headers to log. Headers that aren't
+// allowlisted are omitted. Passing no names omits all headers.
+func HTTPHeaders(names ...string) HTTPFieldOption {
+	return httpFieldOptionFunc(func(cfg *httpFieldConfig) {
+		cfg.headers = canonicalHeaderKeys(names)
+		cfg.all = false
+	})
+}
+
+// HTTPRedactHeaders adds to the headers whose values are replaced with
+// "[REDACTED]". Authorization, Cookie, Proxy-Authorization, and Set-Cookie are
+// always redacted. Redacted headers are only logged if they're allowlisted,
+// which shows that they were present without revealing their values.
+func HTTPRedactHeaders(names ...string) HTTPFieldOption {
+	return httpFieldOptionFunc(func(cfg *httpFieldConfig) {
+		cfg.redact = append(cfg.redact[:len(cfg.redact):len(cfg.redact)], canonicalHeaderKeys(names)...)
+	})
+}
+
+type httpFieldConfig struct {
+	headers []string // canonical keys, in logging order
+	all     bool     // log all headers, ignoring the allowlist
+	redact  []string // canonical keys
+}
+
+var _defaultHTTPFieldConfig = httpFieldConfig{
+	headers: _defaultHTTPHeaders,
+	redact:  _redactedHTTPHeaders,
+}
+
+func newHTTPFieldConfig(opts []HTTPFieldOption) *httpFieldConfig {
+	if len(opts) == 0 {
+		return &_defaultHTTPFieldConfig
+	}
+	cfg := &httpFieldConfig{
+		headers: _defaultHTTPHeaders,
+		redact:  _redactedHTTPHeaders,
+	}
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return cfg
+}
+
+// Header constructs a field that logs HTTP headers as an object mapping each
+// header to an array of its values, since headers may repeat. Unlike
+// HTTPRequest and HTTPResponse, it logs all headers by default, in key order;
+// pass HTTPHeaders to log only an allowlist. The values of Authorization,
+// Cookie, Proxy-Authorization, and Set-Cookie are replaced with
+// "[REDACTED]"; see HTTPRedactHeaders to redact others.
+func Header(key string, h http.Header, opts ...HTTPFieldOption) Field {
+	cfg := &httpFieldConfig{
+		all:    true,
+		redact: _redactedHTTPHeaders,
+	}
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return Object(key, httpHeaderValues{h, cfg})
+}
+
+type httpHeaderValues struct {
+	h   http.Header
+	cfg *httpFieldConfig
+}
+
+func (hs httpHeaderValues) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := hs.cfg.headers
+	if hs.cfg.all {
+		keys = make([]string, 0, len(hs.h))
+		for key := range hs.h {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	for _, key := range keys {
+		vals, ok := hs.h[key]
+		if !ok {
+			continue
+		}
+		if hs.cfg.redacted(http.CanonicalHeaderKey(key)) {
+			vals = []string{_redactedHTTPHeaderValue}
+		}
+		if err := enc.AddArray(key, stringArray(vals)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func canonicalHeaderKeys(names []string) []string {
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, http.CanonicalHeaderKey(name))
+	}
+	return keys
+}
+
+// HTTPRequest constructs a field that logs a curated view of an HTTP request:
+// its method, URL path, content length (if known), and allowlisted headers.
+// The query string is omitted, since it often carries tokens. By default, the
+// Accept, Content-Type, User-Agent, and X-Request-Id headers are logged; see
+// HTTPHeaders and HTTPRedactHeaders to change that. A nil request is logged
+// as null.
+func HTTPRequest(key string, r *http.Request, opts ...HTTPFieldOption) Field {
+	if r == nil {
+		return nilField(key)
+	}
+	return Object(key, httpRequest{r, newHTTPFieldConfig(opts)})
+}
+
+// HTTPResponse constructs a field that logs a curated view of an HTTP
+// response: its status code, content length (if known), and allowlisted
+// headers. It accepts the same options as HTTPRequest. A nil response is
+// logged as null.
+func HTTPResponse(key string, r *http.Response, opts ...HTTPFieldOption) Field {
+	if r == nil {
+		return nilField(key)
+	}
+	return Object(key, httpResponse{r, newHTTPFieldConfig(opts)})
+}
+
+type httpRequest struct {
+	r   *http.Request
+	cfg *httpFieldConfig
+}
+
+func (h httpRequest) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("method", h.r.Method)
+	if h.r.URL != nil {
+		enc.AddString("path", h.r.URL.Path)
+	}
+	if h.r.ContentLength >= 0 {
+		enc.AddInt64("contentLength", h.r.ContentLength)
+	}
+	return h.cfg.addHeaders(enc, h.r.Header)
+}
+
+type httpResponse struct {
+	r   *http.Response
+	cfg *httpFieldConfig
+}
+
+func (h httpResponse) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("status", h.r.StatusCode)
+	if h.r.ContentLength >= 0 {
+		enc.AddInt64("contentLength", h.r.ContentLength)
+	}
+	return h.cfg.addHeaders(enc, h.r.Header)
+}
+
+func (cfg *httpFieldConfig) addHeaders(enc zapcore.ObjectEncoder, h http.Header) error {
+	for _, key := range cfg.headers {
+		if _, ok := h[key]; ok {
+			return enc.AddObject("headers", httpHeaders{h, cfg})
+		}
+	}
+	return nil
+}
+
+type httpHeaders struct {
+	h   http.Header
+	cfg *httpFieldConfig
+}
+
+func (hs httpHeaders) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range hs.cfg.headers {
+		vals, ok := hs.h[key]
+		if !ok {
+			continue
+		}
+		if hs.cfg.redacted(key) {
+			enc.AddString(key, _redactedHTTPHeaderValue)
+			continue
+		}
+		enc.AddString(key, strings.Join(vals, ", "))
+	}
+	return nil
+}
+
+func (cfg *httpFieldConfig) redacted(key string) bool {
+	for _, r := range cfg.redact {
+		if r == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPRequest(t *testing.T) {
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/users/42?token=secret", nil)
+		r.ContentLength = 12
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Authorization", "Bearer secret")
+		r.Header.Set("Cookie", "session=secret")
+		r.Header.Set("X-Internal", "hidden")
+		r.Header.Add("Accept", "text/plain")
+		r.Header.Add("Accept", "application/json")
+		return r
+	}
+
+	tests := []struct {
+		desc     string
+		field    Field
+		expected interface{}
+	}{
+		{
+			desc:     "nil",
+			field:    HTTPRequest("req", nil),
+			expected: nil,
+		},
+		{
+			desc:  "default headers",
+			field: HTTPRequest("req", newRequest()),
+			expected: map[string]interface{}{
+				"method":        "POST",
+				"path":          "/users/42",
+				"contentLength": int64(12),
+				"headers": map[string]interface{}{
+					"Accept":       "text/plain, application/json",
+					"Content-Type": "application/json",
+				},
+			},
+		},
+		{
+			desc:  "allowlist",
+			field: HTTPRequest("req", newRequest(), HTTPHeaders("x-internal", "authorization", "cookie", "x-missing")),
+			expected: map[string]interface{}{
+				"method":        "POST",
+				"path":          "/users/42",
+				"contentLength": int64(12),
+				"headers": map[string]interface{}{
+					"X-Internal":    "hidden",
+					"Authorization": "[REDACTED]",
+					"Cookie":        "[REDACTED]",
+				},
+			},
+		},
+		{
+			desc:  "extra redaction",
+			field: HTTPRequest("req", newRequest(), HTTPHeaders("X-Internal", "Content-Type"), HTTPRedactHeaders("x-internal")),
+			expected: map[string]interface{}{
+				"method":        "POST",
+				"path":          "/users/42",
+				"contentLength": int64(12),
+				"headers": map[string]interface{}{
+					"X-Internal":   "[REDACTED]",
+					"Content-Type": "application/json",
+				},
+			},
+		},
+		{
+			desc:  "no headers",
+			field: HTTPRequest("req", newRequest(), HTTPHeaders()),
+			expected: map[string]interface{}{
+				"method":        "POST",
+				"path":          "/users/42",
+				"contentLength": int64(12),
+			},
+		},
+		{
+			desc:  "unknown length",
+			field: HTTPRequest("req", &http.Request{Method: http.MethodGet, ContentLength: -1}),
+			expected: map[string]interface{}{
+				"method": "GET",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			tt.field.AddTo(enc)
+			assert.Equal(t, tt.expected, enc.Fields["req"], "Unexpected request output.")
+			assert.Len(t, enc.Fields, 1, "Found extra keys in map: %v", enc.Fields)
+		})
+	}
+}
+
+func TestHTTPResponse(t *testing.T) {
+	resp := &http.Response{
+		StatusCode:    http.StatusNotFound,
+		ContentLength: -1,
+		Header: http.Header{
+			"Content-Type": {"text/plain"},
+			"Set-Cookie":   {"session=secret"},
+			"Server":       {"hidden"},
+		},
+	}
+
+	tests := []struct {
+		desc     string
+		field    Field
+		expected interface{}
+	}{
+		{
+			desc:     "nil",
+			field:    HTTPResponse("resp", nil),
+			expected: nil,
+		},
+		{
+			desc:  "default headers",
+			field: HTTPResponse("resp", resp),
+			expected: map[string]interface{}{
+				"status": 404,
+				"headers": map[string]interface{}{
+					"Content-Type": "text/plain",
+				},
+			},
+		},
+		{
+			desc:  "allowlist",
+			field: HTTPResponse("resp", resp, HTTPHeaders("Set-Cookie")),
+			expected: map[string]interface{}{
+				"status": 404,
+				"headers": map[string]interface{}{
+					"Set-Cookie": "[REDACTED]",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			tt.field.AddTo(enc)
+			assert.Equal(t, tt.expected, enc.Fields["resp"], "Unexpected response output.")
+			assert.Len(t, enc.Fields, 1, "Found extra keys in map: %v", enc.Fields)
+		})
+	}
+}
+
+func TestHTTPRequestJSON(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("User-Agent", "test")
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{
+		HTTPRequest("req", r),
+		HTTPRequest("nilreq", nil),
+	})
+	if assert.NoError(t, err, "Unexpected encoding error.") {
+		assert.Equal(t,
+			`{"req":{"method":"GET","path":"/","contentLength":0,"headers":{"User-Agent":"test"}},"nilreq":null}`+"\n",
+			buf.String(), "Unexpected JSON output.")
+	}
+	buf.Free()
+}
+
+func TestHeader(t *testing.T) {
+	h := http.Header{}
+	h.Add("Accept", "text/plain")
+	h.Add("Accept", "application/json")
+	h.Set("Authorization", "Bearer secret")
+	h.Set("X-Api-Key", "secret")
+	h.Set("X-Request-Id", "abc")
+
+	tests := []struct {
+		desc     string
+		field    Field
+		expected interface{}
+	}{
+		{
+			desc:  "default",
+			field: Header("h", h),
+			expected: map[string]interface{}{
+				"Accept":        []interface{}{"text/plain", "application/json"},
+				"Authorization": []interface{}{"[REDACTED]"},
+				"X-Api-Key":     []interface{}{"secret"},
+				"X-Request-Id":  []interface{}{"abc"},
+			},
+		},
+		{
+			desc:  "allowlist",
+			field: Header("h", h, HTTPHeaders("accept", "authorization", "missing")),
+			expected: map[string]interface{}{
+				"Accept":        []interface{}{"text/plain", "application/json"},
+				"Authorization": []interface{}{"[REDACTED]"},
+			},
+		},
+		{
+			desc:  "extra redaction",
+			field: Header("h", h, HTTPRedactHeaders("x-api-key")),
+			expected: map[string]interface{}{
+				"Accept":        []interface{}{"text/plain", "application/json"},
+				"Authorization": []interface{}{"[REDACTED]"},
+				"X-Api-Key":     []interface{}{"[REDACTED]"},
+				"X-Request-Id":  []interface{}{"abc"},
+			},
+		},
+		{
+			desc:     "nil",
+			field:    Header("h", nil),
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			tt.field.AddTo(enc)
+			assert.Equal(t, tt.expected, enc.Fields["h"], "Unexpected header output.")
+			assert.Len(t, enc.Fields, 1, "Found extra keys in map: %v", enc.Fields)
+		})
+	}
+}
+
+func TestHeaderJSON(t *testing.T) {
+	h := http.Header{}
+	h.Add("Set-Cookie", "a=1")
+	h.Add("Set-Cookie", "b=2")
+	h.Add("Vary", "Accept")
+	h.Add("Vary", "Origin")
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{Header("h", h)})
+	if assert.NoError(t, err, "Unexpected encoding error.") {
+		assert.Equal(t,
+			`{"h":{"Set-Cookie":["[REDACTED]"],"Vary":["Accept","Origin"]}}`+"\n",
+			buf.String(), "Unexpected JSON output.")
+	}
+	buf.Free()
+}
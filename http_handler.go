@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -138,3 +139,123 @@ func decodePutJSON(body io.Reader) (zapcore.Level, error) {
 	}
 	return *pld.Level, nil
 }
+
+// NewLevelHandler returns a simple JSON endpoint that can report on or change
+// the per-name level overrides held by the given registry.
+//
+// # GET
+//
+// The GET request returns all overrides, sorted by name, like:
+//
+//	{"overrides":[{"name":"db","level":"debug"}]}
+//
+// # PUT
+//
+// The PUT request sets the override for a single name. The payload is
+// expected to be JSON encoded and look like:
+//
+//	{"name":"db","level":"debug"}
+//
+// # DELETE
+//
+// The DELETE request clears the override for a single name. The name may be
+// provided through a JSON payload like {"name":"db"} or a query parameter:
+//
+//	curl -X DELETE localhost:8080/log/level?name=db
+//
+// Successful PUT and DELETE requests respond with the remaining overrides in
+// the same shape as GET.
+func NewLevelHandler(overrides *LevelOverrides) http.Handler {
+	return &levelHandler{overrides: overrides}
+}
+
+type levelHandler struct {
+	overrides *LevelOverrides
+}
+
+type levelOverridePayload struct {
+	Name  string         `json:"name"`
+	Level *zapcore.Level `json:"level,omitempty"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.serveHTTP(w, r); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "internal error: %v", err)
+	}
+}
+
+func (h *levelHandler) serveHTTP(w http.ResponseWriter, r *http.Request) error {
+	type errorResponse struct {
+		Error string `json:"error"`
+	}
+
+	enc := json.NewEncoder(w)
+
+	switch r.Method {
+	case http.MethodGet:
+		return enc.Encode(h.list())
+
+	case http.MethodPut:
+		pld, err := decodeLevelOverride(r.Body)
+		if err == nil && pld.Level == nil {
+			err = errors.New("must specify logging level")
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return enc.Encode(errorResponse{Error: err.Error()})
+		}
+		h.overrides.Set(pld.Name, *pld.Level)
+		return enc.Encode(h.list())
+
+	case http.MethodDelete:
+		var (
+			pld levelOverridePayload
+			err error
+		)
+		if pld.Name = r.URL.Query().Get("name"); pld.Name == "" {
+			pld, err = decodeLevelOverride(r.Body)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return enc.Encode(errorResponse{Error: err.Error()})
+		}
+		h.overrides.Delete(pld.Name)
+		return enc.Encode(h.list())
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return enc.Encode(errorResponse{
+			Error: "Only GET, PUT and DELETE are supported.",
+		})
+	}
+}
+
+func (h *levelHandler) list() interface{} {
+	all := h.overrides.All()
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	overrides := make([]levelOverridePayload, len(names))
+	for i, name := range names {
+		lvl := all[name]
+		overrides[i] = levelOverridePayload{Name: name, Level: &lvl}
+	}
+	return struct {
+		Overrides []levelOverridePayload `json:"overrides"`
+	}{overrides}
+}
+
+func decodeLevelOverride(body io.Reader) (levelOverridePayload, error) {
+	var pld levelOverridePayload
+	if err := json.NewDecoder(body).Decode(&pld); err != nil {
+		return pld, fmt.Errorf("malformed request body: %v", err)
+	}
+	if pld.Name == "" {
+		return pld, errors.New("must specify logger name")
+	}
+	return pld, nil
+}
@@ -23,6 +23,7 @@ package zap_test
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -215,3 +216,141 @@ type brokenHTTPResponseWriter struct {
 func (w *brokenHTTPResponseWriter) Write([]byte) (int, error) {
 	return 0, errors.New("great sadness")
 }
+
+func TestLevelHandler(t *testing.T) {
+	tests := []struct {
+		desc         string
+		method       string
+		query        string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			desc:         "GET",
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"overrides":[{"name":"db","level":"warn"}]}`,
+		},
+		{
+			desc:         "PUT new name",
+			method:       http.MethodPut,
+			body:         `{"name":"api","level":"debug"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"overrides":[{"name":"api","level":"debug"},{"name":"db","level":"warn"}]}`,
+		},
+		{
+			desc:         "PUT existing name",
+			method:       http.MethodPut,
+			body:         `{"name":"db","level":"error"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"overrides":[{"name":"db","level":"error"}]}`,
+		},
+		{
+			desc:         "DELETE JSON",
+			method:       http.MethodDelete,
+			body:         `{"name":"db"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"overrides":[]}`,
+		},
+		{
+			desc:         "DELETE query parameter",
+			method:       http.MethodDelete,
+			query:        "?name=db",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"overrides":[]}`,
+		},
+		{
+			desc:         "DELETE unknown name",
+			method:       http.MethodDelete,
+			body:         `{"name":"api"}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"overrides":[{"name":"db","level":"warn"}]}`,
+		},
+		{
+			desc:         "PUT unrecognized level",
+			method:       http.MethodPut,
+			body:         `{"name":"db","level":"unrecognized"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "PUT unspecified level",
+			method:       http.MethodPut,
+			body:         `{"name":"db"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "PUT unspecified name",
+			method:       http.MethodPut,
+			body:         `{"level":"debug"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "PUT malformed",
+			method:       http.MethodPut,
+			body:         `{"name":"db`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "DELETE unspecified name",
+			method:       http.MethodDelete,
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "POST",
+			method:       http.MethodPost,
+			body:         `{"name":"db","level":"debug"}`,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			overrides := zap.NewLevelOverrides()
+			overrides.Set("db", zap.WarnLevel)
+
+			server := httptest.NewServer(zap.NewLevelHandler(overrides))
+			defer server.Close()
+
+			req, err := http.NewRequest(tt.method, server.URL+tt.query, strings.NewReader(tt.body))
+			require.NoError(t, err, "Error constructing %s request.", tt.method)
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err, "Error making %s request.", req.Method)
+			defer func() {
+				assert.NoError(t, res.Body.Close(), "Error closing response body.")
+			}()
+
+			require.Equal(t, tt.expectedCode, res.StatusCode, "Unexpected status code.")
+			if tt.expectedCode != http.StatusOK {
+				var pld struct {
+					Error string `json:"error"`
+				}
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&pld), "Decoding response body")
+				assert.NotEmpty(t, pld.Error, "Expected an error message")
+				assert.Equal(t, map[string]zapcore.Level{"db": zap.WarnLevel}, overrides.All(),
+					"Overrides changed by a failed request.")
+				return
+			}
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err, "Error reading response body.")
+			assert.JSONEq(t, tt.expectedBody, string(body), "Unexpected response body.")
+		})
+	}
+}
+
+func TestLevelHandlerBrokenWriter(t *testing.T) {
+	t.Parallel()
+
+	request, err := http.NewRequest(http.MethodGet, "http://localhost:1234/log/level", nil)
+	require.NoError(t, err, "Error constructing request.")
+
+	recorder := httptest.NewRecorder()
+	zap.NewLevelHandler(zap.NewLevelOverrides()).ServeHTTP(&brokenHTTPResponseWriter{
+		ResponseWriter: recorder,
+	}, request)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code, "Unexpected status code.")
+}
@@ -144,6 +144,25 @@ func Take(skip int) string {
 	return buffer.String()
 }
 
+// TakeFiltered is like Take, but only includes the frames for which keep
+// returns true.
+func TakeFiltered(skip int, keep func(runtime.Frame) bool) string {
+	stack := Capture(skip+1, Full)
+	defer stack.Free()
+
+	buffer := bufferpool.Get()
+	defer buffer.Free()
+
+	// As in FormatStack, ignore the final runtime.main/runtime.goexit frame.
+	stackfmt := NewFormatter(buffer)
+	for frame, more := stack.Next(); more; frame, more = stack.Next() {
+		if keep(frame) {
+			stackfmt.FormatFrame(frame)
+		}
+	}
+	return buffer.String()
+}
+
 // Formatter formats a stack trace into a readable string representation.
 type Formatter struct {
 	b        *buffer.Buffer
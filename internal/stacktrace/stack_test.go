@@ -22,6 +22,7 @@ package stacktrace
 
 import (
 	"bytes"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -104,3 +105,18 @@ func withStackDepth(depth int, f func()) {
 	}
 	recurse(rune(depth))
 }
+
+func TestTakeFiltered(t *testing.T) {
+	trace := TakeFiltered(0, func(frame runtime.Frame) bool {
+		return !strings.HasPrefix(frame.Function, "testing.")
+	})
+	lines := strings.Split(trace, "\n")
+	require.NotEmpty(t, lines, "Expected stacktrace to have at least one frame.")
+	assert.Contains(
+		t,
+		lines[0],
+		"go.uber.org/zap/internal/stacktrace.TestTakeFiltered",
+		"Expected stacktrace to start with the test.",
+	)
+	assert.NotContains(t, trace, "testing.", "Expected testing frames to be filtered out.")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLevelBoost calls fn with a child Logger whose minimum enabled level is
+// lowered by delta levels (for example, a delta of 1 on an InfoLevel Logger
+// enables DebugLevel). This makes it possible to temporarily raise verbosity
+// while debugging a specific code path.
+//
+// The boost is scoped to fn: it applies only to entries logged through the
+// Logger passed to fn (and Loggers derived from it) while fn runs. Nothing
+// shared is modified, so other goroutines, including those using the same
+// AtomicLevel or Core, are unaffected. Once fn returns, the child Logger
+// reverts to the original level, even if it was retained.
+//
+// The boosted level is computed relative to the Core's current level, so
+// changes to a shared AtomicLevel during fn are respected. Entries enabled
+// only because of the boost are written directly to the Core, bypassing its
+// own level check and any sampling it does. For Cores built with
+// zapcore.NewTee, this means such entries are written to every member Core.
+//
+// A delta of zero or less calls fn with this Logger.
+func (log *Logger) WithLevelBoost(delta int, fn func(*Logger)) {
+	if delta <= 0 {
+		fn(log)
+		return
+	}
+
+	boost := &levelBoostCore{
+		Core:   log.core,
+		delta:  delta,
+		active: new(atomic.Bool),
+	}
+	boost.active.Store(true)
+	defer boost.active.Store(false)
+
+	l := log.clone()
+	l.core = boost
+	fn(l)
+}
+
+// levelBoostCore lowers the level of the wrapped Core while active.
+type levelBoostCore struct {
+	zapcore.Core
+
+	delta  int
+	active *atomic.Bool // shared between Cores derived with With
+}
+
+var (
+	_ zapcore.Core          = (*levelBoostCore)(nil)
+	_ zapcore.CoreDescriber = (*levelBoostCore)(nil)
+)
+
+func (c *levelBoostCore) Level() zapcore.Level {
+	lvl := zapcore.LevelOf(c.Core)
+	if !c.active.Load() || lvl == zapcore.InvalidLevel {
+		return lvl
+	}
+	if boosted := int(lvl) - c.delta; boosted > int(zapcore.DebugLevel) {
+		return zapcore.Level(boosted)
+	}
+	return zapcore.DebugLevel
+}
+
+func (c *levelBoostCore) Enabled(lvl zapcore.Level) bool {
+	return c.Core.Enabled(lvl) || (c.active.Load() && lvl >= c.Level())
+}
+
+func (c *levelBoostCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelBoostCore{
+		Core:   c.Core.With(fields),
+		delta:  c.delta,
+		active: c.active,
+	}
+}
+
+func (c *levelBoostCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return c.Core.Check(ent, ce)
+	}
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *levelBoostCore) Describe() []zapcore.CoreInfo {
+	infos := zapcore.DescribeCores(c.Core)
+	if c.active.Load() {
+		lvl := c.Level()
+		for i := range infos {
+			if lvl < infos[i].Level {
+				infos[i].Level = lvl
+			}
+		}
+	}
+	return infos
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerWithLevelBoost(t *testing.T) {
+	tests := []struct {
+		desc  string
+		level zapcore.Level
+		delta int
+		want  zapcore.Level
+	}{
+		{"no boost", InfoLevel, 0, InfoLevel},
+		{"one level", InfoLevel, 1, DebugLevel},
+		{"clamped", ErrorLevel, 10, DebugLevel},
+		{"partial", ErrorLevel, 1, WarnLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			withLogger(t, tt.level, nil, func(logger *Logger, logs *observer.ObservedLogs) {
+				var boosted *Logger
+				logger.WithLevelBoost(tt.delta, func(l *Logger) {
+					boosted = l
+					assert.Equal(t, tt.want, l.Level(), "Unexpected boosted level.")
+					l.With(String("k", "v")).Log(tt.want, "boosted")
+				})
+				assert.Equal(t, tt.level, logger.Level(), "Expected original Logger to be unaffected.")
+
+				// A retained Logger reverts once the closure returns.
+				assert.Equal(t, tt.level, boosted.Level(), "Expected boost to end with the closure.")
+				if tt.want < tt.level {
+					boosted.Log(tt.want, "after")
+				}
+
+				entries := logs.AllUntimed()
+				if assert.Len(t, entries, 1, "Expected only the boosted entry.") {
+					assert.Equal(t, "boosted", entries[0].Message, "Unexpected message.")
+					assert.Equal(t, []Field{String("k", "v")}, entries[0].Context, "Unexpected context.")
+				}
+			})
+		})
+	}
+}
+
+func TestLoggerWithLevelBoostConcurrent(t *testing.T) {
+	withLogger(t, NewAtomicLevelAt(InfoLevel), nil, func(logger *Logger, logs *observer.ObservedLogs) {
+		start, done := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(done)
+			logger.WithLevelBoost(1, func(l *Logger) {
+				close(start)
+				for i := 0; i < 100; i++ {
+					l.Debug("boosted")
+				}
+			})
+		}()
+
+		<-start
+		var wg sync.WaitGroup
+		runConcurrently(5, 100, &wg, func() { logger.Debug("unboosted") })
+		wg.Wait()
+		<-done
+
+		assert.Equal(t, 100, logs.FilterMessage("boosted").Len(), "Expected boosted entries.")
+		assert.Equal(t, 0, logs.FilterMessage("unboosted").Len(), "Expected other goroutines to be unaffected.")
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelFileOption configures WatchLevelFile.
+type LevelFileOption interface {
+	apply(*levelFileWatcher)
+}
+
+// levelFileOptionFunc wraps a func so it satisfies the LevelFileOption
+// interface.
+type levelFileOptionFunc func(*levelFileWatcher)
+
+func (f levelFileOptionFunc) apply(w *levelFileWatcher) {
+	f(w)
+}
+
+// LevelFilePollInterval sets how often WatchLevelFile reads the file. The
+// default is one second; non-positive intervals are ignored.
+func LevelFilePollInterval(d time.Duration) LevelFileOption {
+	return levelFileOptionFunc(func(w *levelFileWatcher) {
+		if d > 0 {
+			w.interval = d
+		}
+	})
+}
+
+// LevelFileErrorOutput sets the destination for errors reading or parsing
+// the watched file. The default is standard error.
+func LevelFileErrorOutput(ws zapcore.WriteSyncer) LevelFileOption {
+	return levelFileOptionFunc(func(w *levelFileWatcher) {
+		w.errorOutput = ws
+	})
+}
+
+// WatchLevelFile keeps an AtomicLevel in sync with the contents of a file,
+// for operators who can change files on a host but can't reach the HTTP
+// handler served by AtomicLevel. The file holds a single level name, such as
+// "debug" or "WARN", in any form accepted by AtomicLevel's UnmarshalText;
+// surrounding whitespace is ignored.
+//
+// The file is read once before WatchLevelFile returns and then polled in the
+// background. The level is set only when the file's contents change, so
+// changes made to the AtomicLevel by other means persist until the file is
+// next edited. Errors, such as a missing file or an unknown level, leave the
+// level unchanged and are written to the error output (see
+// LevelFileErrorOutput); an error is reported once, not on every poll, until
+// it changes or the file becomes valid again.
+//
+// The returned function stops watching and waits for the background
+// goroutine to exit. It's safe to call more than once.
+func WatchLevelFile(path string, lvl *AtomicLevel, opts ...LevelFileOption) (stop func()) {
+	w := &levelFileWatcher{
+		path:        path,
+		lvl:         lvl,
+		interval:    time.Second,
+		errorOutput: zapcore.Lock(os.Stderr),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(w)
+	}
+
+	w.poll()
+	go w.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(w.stop)
+			<-w.done
+		})
+	}
+}
+
+type levelFileWatcher struct {
+	path        string
+	lvl         *AtomicLevel
+	interval    time.Duration
+	errorOutput zapcore.WriteSyncer
+
+	stop chan struct{} // closed to stop the watcher
+	done chan struct{} // closed when run returns
+
+	contents []byte // last successfully applied contents
+	applied  bool   // whether contents is set
+	lastErr  string // last reported error, if any
+}
+
+func (w *levelFileWatcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+func (w *levelFileWatcher) poll() {
+	contents, err := os.ReadFile(w.path)
+	if err != nil {
+		w.report(err)
+		return
+	}
+	contents = bytes.TrimSpace(contents)
+	if w.applied && bytes.Equal(contents, w.contents) {
+		w.lastErr = ""
+		return
+	}
+
+	var l zapcore.Level
+	if err := l.UnmarshalText(contents); err != nil {
+		w.report(fmt.Errorf("%v: %w", w.path, err))
+		return
+	}
+	w.lvl.SetLevel(l)
+	w.contents = contents
+	w.applied = true
+	w.lastErr = ""
+}
+
+func (w *levelFileWatcher) report(err error) {
+	if msg := err.Error(); msg != w.lastErr {
+		w.lastErr = msg
+		_, _ = fmt.Fprintf(w.errorOutput, "%v WatchLevelFile error: %v\n", time.Now().UTC(), err)
+		_ = w.errorOutput.Sync()
+	}
+}
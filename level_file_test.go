@@ -0,0 +1,165 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/internal/ztest"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLevelFile(t *testing.T, path, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644), "Failed to write level file.")
+}
+
+func TestWatchLevelFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	writeLevelFile(t, path, "warn\n")
+
+	lvl := NewAtomicLevel()
+	errs := &lockedBuffer{}
+	stop := WatchLevelFile(path, &lvl, LevelFilePollInterval(time.Millisecond), LevelFileErrorOutput(errs))
+	defer stop()
+
+	assert.Equal(t, WarnLevel, lvl.Level(), "Expected the file to be read before returning.")
+
+	writeLevelFile(t, path, "  DEBUG ")
+	assert.Eventually(t, func() bool {
+		return lvl.Level() == DebugLevel
+	}, time.Second, time.Millisecond, "Expected level to follow the file.")
+
+	writeLevelFile(t, path, "error")
+	assert.Eventually(t, func() bool {
+		return lvl.Level() == ErrorLevel
+	}, time.Second, time.Millisecond, "Expected level to follow the file.")
+
+	assert.Empty(t, errs.String(), "Unexpected errors.")
+}
+
+func TestWatchLevelFileErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+
+	lvl := NewAtomicLevelAt(InfoLevel)
+	errs := &lockedBuffer{}
+	stop := WatchLevelFile(path, &lvl, LevelFilePollInterval(time.Millisecond), LevelFileErrorOutput(errs))
+	defer stop()
+
+	assert.Equal(t, InfoLevel, lvl.Level(), "A missing file shouldn't change the level.")
+	require.Len(t, errs.Lines(), 1, "Expected a missing file to be reported.")
+	assert.Contains(t, errs.Lines()[0], "WatchLevelFile error:", "Unexpected error output.")
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, errs.Lines(), 1, "Repeated errors should be reported once.")
+
+	writeLevelFile(t, path, "verbose")
+	assert.Eventually(t, func() bool {
+		return len(errs.Lines()) == 2
+	}, time.Second, time.Millisecond, "Expected an invalid level to be reported.")
+	assert.Contains(t, errs.Lines()[1], `unrecognized level: "verbose"`, "Unexpected error output.")
+	assert.Equal(t, InfoLevel, lvl.Level(), "An invalid level shouldn't change the level.")
+
+	writeLevelFile(t, path, "dpanic")
+	assert.Eventually(t, func() bool {
+		return lvl.Level() == DPanicLevel
+	}, time.Second, time.Millisecond, "Expected recovery once the file is valid.")
+}
+
+func TestWatchLevelFileKeepsOtherChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	writeLevelFile(t, path, "warn")
+
+	lvl := NewAtomicLevel()
+	stop := WatchLevelFile(path, &lvl, LevelFilePollInterval(time.Millisecond))
+	defer stop()
+
+	lvl.SetLevel(zapcore.DebugLevel)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, DebugLevel, lvl.Level(), "Unchanged file shouldn't override other level changes.")
+}
+
+func TestWatchLevelFileStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	writeLevelFile(t, path, "warn")
+
+	lvl := NewAtomicLevel()
+	stop := WatchLevelFile(path, &lvl, LevelFilePollInterval(time.Millisecond))
+	stop()
+	stop() // idempotent
+
+	writeLevelFile(t, path, "error")
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, WarnLevel, lvl.Level(), "Level shouldn't change after stopping.")
+}
+
+func TestWatchLevelFileInvalidInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	writeLevelFile(t, path, "warn")
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		lvl := NewAtomicLevel()
+		var stop func()
+		require.NotPanics(t, func() {
+			stop = WatchLevelFile(path, &lvl, LevelFilePollInterval(d))
+		}, "Expected interval %v to be ignored.", d)
+		assert.Equal(t, WarnLevel, lvl.Level(), "Expected the file to be read.")
+		stop()
+	}
+}
+
+// lockedBuffer is a ztest.Buffer that's safe to read while the watcher's
+// goroutine writes to it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf ztest.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Sync() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Sync()
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Lines()
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelOverrides is a registry of logging levels keyed by Logger name. It's
+// safe for concurrent use, so overrides may be changed while a program is
+// running, for example through the http.Handler returned by NewLevelHandler.
+//
+// Overrides apply hierarchically: an override for "db" also applies to
+// Loggers named "db.pool" and "db.pool.conn", unless a more specific override
+// exists. Loggers without a matching override use the level of their Core.
+//
+// Use WithLevelOverrides to apply a registry to a Logger.
+type LevelOverrides struct {
+	mu     sync.RWMutex
+	levels map[string]zapcore.Level
+
+	// min is the lowest overridden level, or InvalidLevel if there are no
+	// overrides. It allows Enabled checks without taking the lock.
+	min atomic.Int32
+}
+
+// NewLevelOverrides builds an empty LevelOverrides.
+func NewLevelOverrides() *LevelOverrides {
+	o := &LevelOverrides{levels: make(map[string]zapcore.Level)}
+	o.min.Store(int32(zapcore.InvalidLevel))
+	return o
+}
+
+// Set overrides the level of Loggers with the given name.
+func (o *LevelOverrides) Set(name string, lvl zapcore.Level) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.levels[name] = lvl
+	o.updateMin()
+}
+
+// Delete removes the override for the given name, if any.
+func (o *LevelOverrides) Delete(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.levels, name)
+	o.updateMin()
+}
+
+// Get returns the override set for exactly the given name.
+func (o *LevelOverrides) Get(name string) (zapcore.Level, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	lvl, ok := o.levels[name]
+	return lvl, ok
+}
+
+// All returns a copy of all overrides, keyed by Logger name.
+func (o *LevelOverrides) All() map[string]zapcore.Level {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	all := make(map[string]zapcore.Level, len(o.levels))
+	for name, lvl := range o.levels {
+		all[name] = lvl
+	}
+	return all
+}
+
+// levelFor returns the override that applies to the named Logger, searching
+// from the full name up through its dot-separated parents.
+func (o *LevelOverrides) levelFor(name string) (zapcore.Level, bool) {
+	if zapcore.Level(o.min.Load()) == zapcore.InvalidLevel {
+		return 0, false
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for {
+		if lvl, ok := o.levels[name]; ok {
+			return lvl, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// updateMin must be called with the write lock held.
+func (o *LevelOverrides) updateMin() {
+	min := zapcore.InvalidLevel
+	for _, lvl := range o.levels {
+		if lvl < min {
+			min = lvl
+		}
+	}
+	o.min.Store(int32(min))
+}
+
+// levelOverrideCore applies a LevelOverrides registry to the wrapped Core.
+type levelOverrideCore struct {
+	zapcore.Core
+
+	overrides *LevelOverrides
+}
+
+var (
+	_ zapcore.Core          = (*levelOverrideCore)(nil)
+	_ zapcore.CoreDescriber = (*levelOverrideCore)(nil)
+)
+
+func (c *levelOverrideCore) Level() zapcore.Level {
+	lvl := zapcore.LevelOf(c.Core)
+	if min := zapcore.Level(c.overrides.min.Load()); min < lvl {
+		return min
+	}
+	return lvl
+}
+
+func (c *levelOverrideCore) Enabled(lvl zapcore.Level) bool {
+	return c.Core.Enabled(lvl) || lvl >= zapcore.Level(c.overrides.min.Load())
+}
+
+func (c *levelOverrideCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelOverrideCore{
+		Core:      c.Core.With(fields),
+		overrides: c.overrides,
+	}
+}
+
+func (c *levelOverrideCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	lvl, ok := c.overrides.levelFor(ent.LoggerName)
+	if !ok {
+		return c.Core.Check(ent, ce)
+	}
+	if ent.Level < lvl {
+		return ce
+	}
+	if c.Core.Enabled(ent.Level) {
+		return c.Core.Check(ent, ce)
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *levelOverrideCore) Describe() []zapcore.CoreInfo {
+	infos := zapcore.DescribeCores(c.Core)
+	min := zapcore.Level(c.overrides.min.Load())
+	for i := range infos {
+		if min < infos[i].Level {
+			infos[i].Level = min
+		}
+	}
+	return infos
+}
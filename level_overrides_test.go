@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelOverrides(t *testing.T) {
+	overrides := NewLevelOverrides()
+	_, ok := overrides.Get("db")
+	assert.False(t, ok, "Unexpected override in empty registry.")
+	assert.Empty(t, overrides.All(), "Expected no overrides.")
+
+	overrides.Set("db", DebugLevel)
+	overrides.Set("db.pool", ErrorLevel)
+	lvl, ok := overrides.Get("db")
+	assert.True(t, ok, "Expected override for db.")
+	assert.Equal(t, DebugLevel, lvl, "Unexpected override for db.")
+	assert.Equal(t, DebugLevel, zapcore.Level(overrides.min.Load()), "Unexpected minimum override.")
+
+	tests := []struct {
+		name string
+		want zapcore.Level
+		ok   bool
+	}{
+		{"db", DebugLevel, true},
+		{"db.query", DebugLevel, true},
+		{"db.pool", ErrorLevel, true},
+		{"db.pool.conn", ErrorLevel, true},
+		{"dbx", 0, false},
+		{"api", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		lvl, ok := overrides.levelFor(tt.name)
+		assert.Equal(t, tt.ok, ok, "Unexpected match for %q.", tt.name)
+		assert.Equal(t, tt.want, lvl, "Unexpected level for %q.", tt.name)
+	}
+
+	overrides.Delete("db")
+	assert.Equal(t, map[string]zapcore.Level{"db.pool": ErrorLevel}, overrides.All(), "Unexpected overrides after Delete.")
+	assert.Equal(t, ErrorLevel, zapcore.Level(overrides.min.Load()), "Unexpected minimum override after Delete.")
+
+	overrides.Delete("db.pool")
+	assert.Equal(t, zapcore.InvalidLevel, zapcore.Level(overrides.min.Load()), "Expected no minimum override.")
+}
+
+func TestLoggerWithLevelOverrides(t *testing.T) {
+	overrides := NewLevelOverrides()
+	overrides.Set("db", DebugLevel)
+	overrides.Set("noisy", ErrorLevel)
+
+	core, logs := observer.New(InfoLevel)
+	logger := New(core, WithLevelOverrides(overrides))
+	assert.Equal(t, DebugLevel, logger.Level(), "Unexpected Logger level.")
+
+	logger.Debug("root debug")
+	logger.Info("root info")
+	logger.Named("db").Named("pool").With(String("k", "v")).Debug("db debug")
+	logger.Named("noisy").Warn("noisy warn")
+	logger.Named("noisy").Error("noisy error")
+
+	// Overrides are looked up on every entry, so changes apply to existing
+	// Loggers immediately.
+	overrides.Delete("db")
+	logger.Named("db").Debug("db debug after delete")
+
+	var got []string
+	for _, e := range logs.AllUntimed() {
+		got = append(got, e.Message)
+	}
+	assert.Equal(t, []string{"root info", "db debug", "noisy error"}, got, "Unexpected entries logged.")
+	assert.Equal(t, "v", logs.FilterMessage("db debug").All()[0].ContextMap()["k"], "Expected context field.")
+}
+
+func TestLevelOverridesDescribe(t *testing.T) {
+	overrides := NewLevelOverrides()
+	overrides.Set("db", DebugLevel)
+
+	core, _ := observer.New(WarnLevel)
+	logger := New(core, WithLevelOverrides(overrides))
+	infos := logger.DescribeCores()
+	if assert.Len(t, infos, 1, "Expected a single core.") {
+		assert.Equal(t, DebugLevel, infos[0].Level, "Expected overridden level.")
+	}
+}
@@ -21,10 +21,13 @@
 package zap
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
 	"go.uber.org/zap/internal/bufferpool"
 	"go.uber.org/zap/internal/stacktrace"
@@ -41,19 +44,41 @@ import (
 type Logger struct {
 	core zapcore.Core
 
-	development bool
-	addCaller   bool
-	onPanic     zapcore.CheckWriteHook // default is WriteThenPanic
-	onFatal     zapcore.CheckWriteHook // default is WriteThenFatal
+	development      bool
+	dpanicStructured bool
+	strictSugar      bool
+	sortSugarMaps    bool
+	migrationAudit   *migrationAuditor // nil unless WithMigrationAudit
+	addCaller        bool
+	callerLevel      zapcore.LevelEnabler   // nil means all levels
+	onPanic          zapcore.CheckWriteHook // default is WriteThenPanic
+	onFatal          zapcore.CheckWriteHook // default is WriteThenFatal
 
 	name        string
 	errorOutput zapcore.WriteSyncer
 
-	addStack zapcore.LevelEnabler
+	addStack   zapcore.LevelEnabler
+	errorStack bool // set by AddStacktraceOnError
 
 	callerSkip int
 
 	clock zapcore.Clock
+
+	contextExtractors []func(context.Context) []Field
+
+	sequences []*sequence // set by WithSequenceNumbers
+
+	messageTransformers []func(zapcore.Level, string) string
+
+	// Set by WithReplaceFields. withFields holds the fields added by With
+	// since withBase, the Core they're added to, was captured.
+	replaceFields bool
+	withBase      zapcore.Core
+	withFields    []Field
+
+	// Set by Config.Build, for WithSchemaHeader.
+	encoding      string
+	encoderConfig *zapcore.EncoderConfig
 }
 
 // New constructs a new Logger from the provided zapcore.Core and Options. If
@@ -171,9 +196,33 @@ func (log *Logger) WithOptions(opts ...Option) *Logger {
 	for _, opt := range opts {
 		opt.apply(c)
 	}
+	// Options may replace the Core, so fields added by With so far can no
+	// longer be rebuilt on top of withBase. They're kept, but are no longer
+	// replaced by later calls to With.
+	c.withBase, c.withFields = nil, nil
 	return c
 }
 
+// ReplaceCore returns a copy of the Logger that writes to the given Core
+// instead of its own. The copy keeps the Logger's name and the settings
+// applied with Options that don't wrap the Core, such as AddCaller,
+// Development, and ErrorOutput. Anything that's part of the old Core isn't
+// carried over: fields added with With, and Options that wrap the Core,
+// such as WrapCore, Hooks, and IncreaseLevel. A nil Core is replaced with a
+// no-op Core. The original Logger is unaffected.
+//
+// To change the Core of Loggers that have already been handed out, build
+// them on a zapcore.SwappableCore and swap its Core instead.
+func (log *Logger) ReplaceCore(core zapcore.Core) *Logger {
+	if core == nil {
+		core = zapcore.NewNopCore()
+	}
+	l := log.clone()
+	l.core = core
+	l.withBase, l.withFields = nil, nil
+	return l
+}
+
 // With creates a child logger and adds structured context to it. Fields added
 // to the child don't affect the parent, and vice versa. Any fields that
 // require evaluation (such as Objects) are evaluated upon invocation of With.
@@ -182,10 +231,46 @@ func (log *Logger) With(fields ...Field) *Logger {
 		return log
 	}
 	l := log.clone()
+	if l.replaceFields {
+		if l.withBase == nil {
+			l.withBase = l.core
+		}
+		l.withFields = mergeWithFields(l.withFields, fields)
+		l.core = l.withBase.With(l.withFields)
+		return l
+	}
 	l.core = l.core.With(fields)
 	return l
 }
 
+// mergeWithFields appends fields to acc, first removing any field in acc
+// with the same key in the same namespace. acc isn't modified.
+func mergeWithFields(acc, fields []Field) []Field {
+	merged := make([]Field, 0, len(acc)+len(fields))
+	merged = append(merged, acc...)
+	for _, f := range fields {
+		if f.Key != "" && f.Type != zapcore.NamespaceType {
+			// Only fields after the last namespace share f's scope.
+			start := 0
+			for i := len(merged) - 1; i >= 0; i-- {
+				if merged[i].Type == zapcore.NamespaceType {
+					start = i + 1
+					break
+				}
+			}
+			kept := merged[:start]
+			for _, m := range merged[start:] {
+				if m.Key != f.Key {
+					kept = append(kept, m)
+				}
+			}
+			merged = kept
+		}
+		merged = append(merged, f)
+	}
+	return merged
+}
+
 // WithLazy creates a child logger and adds structured context to it lazily.
 //
 // The fields are evaluated only if the logger is further chained with [With]
@@ -208,6 +293,36 @@ func (log *Logger) WithLazy(fields ...Field) *Logger {
 	}))
 }
 
+// WithTime returns a child Logger whose entries are all stamped with t
+// instead of the current time, for example when replaying historical events
+// that should be logged at the time they happened. The timestamp is sticky:
+// every entry logged by the child, or by Loggers derived from it, uses t, so
+// derive a new child for each event:
+//
+//	for _, ev := range events {
+//		logger.WithTime(ev.Time).Info("replayed", zap.String("id", ev.ID))
+//	}
+//
+// The parent Logger is unaffected. Only entry timestamps change: Cores that
+// keep time themselves, such as samplers, still use their own clocks.
+func (log *Logger) WithTime(t time.Time) *Logger {
+	l := log.clone()
+	l.clock = fixedClock{Clock: log.clock, t: t}
+	return l
+}
+
+// fixedClock reports a fixed time from Now, and otherwise defers to the
+// wrapped Clock.
+type fixedClock struct {
+	zapcore.Clock
+
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
 // Level reports the minimum enabled level for this logger.
 //
 // For NopLoggers, this is [zapcore.InvalidLevel].
@@ -215,6 +330,15 @@ func (log *Logger) Level() zapcore.Level {
 	return zapcore.LevelOf(log.core)
 }
 
+// DescribeCores describes the Cores that write this Logger's entries: their
+// encoding, level, and destination. See zapcore.DescribeCores for details.
+//
+// This is intended for introspection, such as building an administrative
+// page that shows which outputs an AtomicLevel controls.
+func (log *Logger) DescribeCores() []zapcore.CoreInfo {
+	return zapcore.DescribeCores(log.core)
+}
+
 // Check returns a CheckedEntry if logging a message at the specified level
 // is enabled. It's a completely optional optimization; in high-performance
 // applications, Check can help avoid allocating a slice to hold fields.
@@ -222,6 +346,31 @@ func (log *Logger) Check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 	return log.check(lvl, msg)
 }
 
+// WouldLog reports whether a message at the specified level would currently
+// be written, taking into account level filtering, sampling, and any other
+// decision that the Logger's Core makes when checking an entry.
+//
+// WouldLog checks the entry without writing it, but it isn't free of side
+// effects: a Core's Check method runs exactly as it would for a real log
+// call. In particular, a sampling Core counts the message against its quota
+// and calls its sampling hook, so calling WouldLog and then logging the same
+// message uses up two sampler slots. To decide whether to log and then do
+// so, use Check instead and write the returned CheckedEntry.
+func (log *Logger) WouldLog(lvl zapcore.Level, msg string) bool {
+	if !log.core.Enabled(lvl) {
+		return false
+	}
+	ent := zapcore.Entry{
+		LoggerName: log.name,
+		Time:       log.clock.Now(),
+		Level:      lvl,
+		Message:    msg,
+	}
+	// The CheckedEntry is never written, so it's left to the garbage
+	// collector rather than returned to the pool.
+	return log.core.Check(ent, nil) != nil
+}
+
 // Log logs a message at the specified level. The message includes any fields
 // passed at the log site, as well as any fields accumulated on the logger.
 // Any Fields that require  evaluation (such as Objects) are evaluated upon
@@ -232,6 +381,26 @@ func (log *Logger) Log(lvl zapcore.Level, msg string, fields ...Field) {
 	}
 }
 
+// WriteRaw writes a pre-formatted line at the specified level, bypassing the
+// encoder. It's intended for migrating code that formats its own log lines.
+//
+// The line is checked like any other entry, with the line (minus any
+// trailing newline) as its message, so level routing and sampling still
+// apply. Each Core that accepts it writes the line as is to its WriteSyncer,
+// adding a newline if needed. Encoder-dependent features don't apply: fields
+// accumulated on the logger, timestamps, caller information and stacktraces
+// aren't written. Cores that can't write raw lines (see zapcore.RawWriter)
+// encode the entry as usual instead.
+func (log *Logger) WriteRaw(lvl zapcore.Level, line []byte) {
+	msg := line
+	if n := len(msg); n > 0 && msg[n-1] == '\n' {
+		msg = msg[:n-1]
+	}
+	if ce := log.check(lvl, string(msg)); ce != nil {
+		ce.WriteRaw(line)
+	}
+}
+
 // Debug logs a message at DebugLevel. The message includes any fields passed
 // at the log site, as well as any fields accumulated on the logger.
 func (log *Logger) Debug(msg string, fields ...Field) {
@@ -264,6 +433,37 @@ func (log *Logger) Error(msg string, fields ...Field) {
 	}
 }
 
+// Debug1 is like Debug with exactly one field. Passing a single field to the
+// variadic Debug allocates a slice to hold it; Debug1 doesn't, which matters
+// on hot paths.
+func (log *Logger) Debug1(msg string, f Field) {
+	if ce := log.check(DebugLevel, msg); ce != nil {
+		ce.Write1(f)
+	}
+}
+
+// Info1 is like Info with exactly one field, but doesn't allocate. See Debug1.
+func (log *Logger) Info1(msg string, f Field) {
+	if ce := log.check(InfoLevel, msg); ce != nil {
+		ce.Write1(f)
+	}
+}
+
+// Warn1 is like Warn with exactly one field, but doesn't allocate. See Debug1.
+func (log *Logger) Warn1(msg string, f Field) {
+	if ce := log.check(WarnLevel, msg); ce != nil {
+		ce.Write1(f)
+	}
+}
+
+// Error1 is like Error with exactly one field, but doesn't allocate. See
+// Debug1.
+func (log *Logger) Error1(msg string, f Field) {
+	if ce := log.check(ErrorLevel, msg); ce != nil {
+		ce.Write1(f)
+	}
+}
+
 // DPanic logs a message at DPanicLevel. The message includes any fields
 // passed at the log site, as well as any fields accumulated on the logger.
 //
@@ -297,6 +497,141 @@ func (log *Logger) Fatal(msg string, fields ...Field) {
 	}
 }
 
+// Debugf formats the message with fmt.Sprintf and logs it at DebugLevel. The
+// message is formatted only if DebugLevel is enabled, so disabled calls don't
+// pay for formatting. Prefer Debug with structured fields where possible;
+// Debugf is a convenience for call sites that would otherwise reach for Sugar.
+func (log *Logger) Debugf(template string, args ...interface{}) {
+	if !log.core.Enabled(DebugLevel) {
+		return
+	}
+	if ce := log.check(DebugLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// Infof formats the message with fmt.Sprintf and logs it at InfoLevel. See
+// Debugf.
+func (log *Logger) Infof(template string, args ...interface{}) {
+	if !log.core.Enabled(InfoLevel) {
+		return
+	}
+	if ce := log.check(InfoLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// Warnf formats the message with fmt.Sprintf and logs it at WarnLevel. See
+// Debugf.
+func (log *Logger) Warnf(template string, args ...interface{}) {
+	if !log.core.Enabled(WarnLevel) {
+		return
+	}
+	if ce := log.check(WarnLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// Errorf formats the message with fmt.Sprintf and logs it at ErrorLevel. See
+// Debugf.
+func (log *Logger) Errorf(template string, args ...interface{}) {
+	if !log.core.Enabled(ErrorLevel) {
+		return
+	}
+	if ce := log.check(ErrorLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// DPanicf formats the message with fmt.Sprintf and logs it at DPanicLevel.
+// Like DPanic, it panics in development mode.
+func (log *Logger) DPanicf(template string, args ...interface{}) {
+	if ce := log.check(DPanicLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// Panicf formats the message with fmt.Sprintf and logs it at PanicLevel. Like
+// Panic, it then panics even if logging at PanicLevel is disabled.
+func (log *Logger) Panicf(template string, args ...interface{}) {
+	if ce := log.check(PanicLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// Fatalf formats the message with fmt.Sprintf and logs it at FatalLevel. Like
+// Fatal, it then calls os.Exit(1) even if logging at FatalLevel is disabled.
+func (log *Logger) Fatalf(template string, args ...interface{}) {
+	if ce := log.check(FatalLevel, getMessage(template, args)); ce != nil {
+		ce.Write()
+	}
+}
+
+// LogContext is like Log, but also adds the fields extracted from ctx by any
+// extractors registered with WithContextExtractor. A nil ctx is allowed, and
+// adds no fields.
+func (log *Logger) LogContext(ctx context.Context, lvl zapcore.Level, msg string, fields ...Field) {
+	if ce := log.check(lvl, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// DebugContext is like Debug, but also adds the fields extracted from ctx by
+// any extractors registered with WithContextExtractor.
+func (log *Logger) DebugContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(DebugLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// InfoContext is like Info, but also adds the fields extracted from ctx by
+// any extractors registered with WithContextExtractor.
+func (log *Logger) InfoContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(InfoLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// WarnContext is like Warn, but also adds the fields extracted from ctx by
+// any extractors registered with WithContextExtractor.
+func (log *Logger) WarnContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(WarnLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// ErrorContext is like Error, but also adds the fields extracted from ctx by
+// any extractors registered with WithContextExtractor.
+func (log *Logger) ErrorContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(ErrorLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// DPanicContext is like DPanic, but also adds the fields extracted from ctx
+// by any extractors registered with WithContextExtractor.
+func (log *Logger) DPanicContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(DPanicLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// PanicContext is like Panic, but also adds the fields extracted from ctx by
+// any extractors registered with WithContextExtractor.
+func (log *Logger) PanicContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(PanicLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
+// FatalContext is like Fatal, but also adds the fields extracted from ctx by
+// any extractors registered with WithContextExtractor.
+func (log *Logger) FatalContext(ctx context.Context, msg string, fields ...Field) {
+	if ce := log.check(FatalLevel, msg); ce != nil {
+		ce.Write(log.contextFields(ctx, fields)...)
+	}
+}
+
 // Sync calls the underlying Core's Sync method, flushing any buffered log
 // entries. Applications should take care to call Sync before exiting.
 func (log *Logger) Sync() error {
@@ -319,6 +654,19 @@ func (log *Logger) clone() *Logger {
 	return &clone
 }
 
+// contextFields returns fields followed by the fields extracted from ctx.
+func (log *Logger) contextFields(ctx context.Context, fields []Field) []Field {
+	if ctx == nil || len(log.contextExtractors) == 0 {
+		return fields
+	}
+	all := make([]Field, 0, len(fields)+len(log.contextExtractors))
+	all = append(all, fields...)
+	for _, extract := range log.contextExtractors {
+		all = append(all, extract(ctx)...)
+	}
+	return all
+}
+
 func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 	// Logger.check must always be called directly by a method in the
 	// Logger interface (e.g., Check, Info, Fatal).
@@ -351,7 +699,11 @@ func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 		ce = ce.After(ent, terminalHookOverride(zapcore.WriteThenFatal, log.onFatal))
 	case zapcore.DPanicLevel:
 		if log.development {
-			ce = ce.After(ent, terminalHookOverride(zapcore.WriteThenPanic, log.onPanic))
+			var hook zapcore.CheckWriteHook = zapcore.WriteThenPanic
+			if log.dpanicStructured {
+				hook = dpanicErrorHook{}
+			}
+			ce = ce.After(ent, terminalHookOverride(hook, log.onPanic))
 		}
 	}
 
@@ -365,8 +717,18 @@ func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 	// Thread the error output through to the CheckedEntry.
 	ce.ErrorOutput = log.errorOutput
 
+	// Number the entry once, so that every Core writes the same number.
+	for _, seq := range log.sequences {
+		ce.AddFields(Uint64(seq.key, seq.n.Add(1)))
+	}
+
+	for _, transform := range log.messageTransformers {
+		ce.Message = transform(ce.Level, ce.Message)
+	}
+
+	addCaller := log.addCaller && (log.callerLevel == nil || log.callerLevel.Enabled(ce.Level))
 	addStack := log.addStack.Enabled(ce.Level)
-	if !log.addCaller && !addStack {
+	if !addCaller && !addStack && !log.errorStack {
 		return ce
 	}
 
@@ -380,7 +742,7 @@ func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 	defer stack.Free()
 
 	if stack.Count() == 0 {
-		if log.addCaller {
+		if addCaller {
 			_, _ = fmt.Fprintf(
 				log.errorOutput,
 				"%v Logger.check error: failed to get caller\n",
@@ -393,7 +755,7 @@ func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 
 	frame, more := stack.Next()
 
-	if log.addCaller {
+	if addCaller {
 		ce.Caller = zapcore.EntryCaller{
 			Defined:  frame.PC != 0,
 			PC:       frame.PC,
@@ -401,6 +763,10 @@ func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 			Line:     frame.Line,
 			Function: frame.Function,
 		}
+	} else if log.errorStack {
+		// takeErrorStacktrace starts at the call site, skipped like the
+		// caller, so record it without logging it.
+		ce.Caller = zapcore.EntryCaller{PC: frame.PC, Function: frame.Function}
 	}
 
 	if addStack {
@@ -421,6 +787,53 @@ func (log *Logger) check(lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
 	return ce
 }
 
+// takeErrorStacktrace takes the stack trace for AddStacktraceOnError. It's
+// called while the entry is written, so it skips the frames of the Cores and
+// of the Logger, starting at the call site Logger.check found for the entry,
+// which honors AddCallerSkip.
+func takeErrorStacktrace(ent zapcore.Entry) string {
+	stack := stacktrace.Capture(1, stacktrace.Full)
+	defer stack.Free()
+	return formatErrorStacktrace(stack, ent.Caller)
+}
+
+// formatErrorStacktrace formats the frames of stack from the call site
+// onwards. If the call site isn't known, it starts at the first frame
+// outside zap. It returns an empty string if the frame isn't found.
+func formatErrorStacktrace(stack *stacktrace.Stack, caller zapcore.EntryCaller) string {
+	atCaller := func(frame runtime.Frame) bool {
+		if caller.PC == 0 {
+			return !isLoggingFrame(frame.Function)
+		}
+		return frame.PC == caller.PC && frame.Function == caller.Function
+	}
+
+	frame, more := stack.Next()
+	for more && !atCaller(frame) {
+		frame, more = stack.Next()
+	}
+	if frame.Function == "" || !atCaller(frame) {
+		return ""
+	}
+
+	buffer := bufferpool.Get()
+	defer buffer.Free()
+
+	stackfmt := stacktrace.NewFormatter(buffer)
+	stackfmt.FormatFrame(frame)
+	stackfmt.FormatStack(stack)
+	return buffer.String()
+}
+
+func isLoggingFrame(function string) bool {
+	if i := strings.LastIndex(function, "/vendor/"); i >= 0 {
+		function = function[i+len("/vendor/"):]
+	}
+	return strings.HasPrefix(function, "go.uber.org/zap/zapcore.") ||
+		strings.HasPrefix(function, "go.uber.org/zap.(*Logger).") ||
+		strings.HasPrefix(function, "go.uber.org/zap.(*SugaredLogger).")
+}
+
 func terminalHookOverride(defaultHook, override zapcore.CheckWriteHook) zapcore.CheckWriteHook {
 	// A nil or WriteThenNoop hook will lead to continued execution after
 	// a Panic or Fatal log entry, which is unexpected. For example,
@@ -22,6 +22,7 @@ package zap
 
 import (
 	"errors"
+	"net"
 	"runtime"
 	"strconv"
 	"sync"
@@ -72,6 +73,21 @@ func BenchmarkNoContext(b *testing.B) {
 	})
 }
 
+func BenchmarkFormattedDisabled(b *testing.B) {
+	logger := New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(NewProductionConfig().EncoderConfig),
+		&ztest.Discarder{},
+		ErrorLevel,
+	))
+	b.ResetTimer()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infof("Formatted %s with %d args.", "message", 2)
+		}
+	})
+}
+
 func BenchmarkBoolField(b *testing.B) {
 	withBenchedLogger(b, func(log *Logger) {
 		log.Info("Boolean.", Bool("foo", true))
@@ -182,6 +198,50 @@ func BenchmarkAddCallerHook(b *testing.B) {
 	})
 }
 
+func BenchmarkAddCallerForLevels(b *testing.B) {
+	for _, tt := range []struct {
+		name string
+		opt  Option
+	}{
+		{"AddCaller", AddCaller()},
+		{"AddCallerForLevels", AddCallerForLevels(ErrorLevel)},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			logger := New(
+				zapcore.NewCore(
+					zapcore.NewJSONEncoder(NewProductionConfig().EncoderConfig),
+					&ztest.Discarder{},
+					DebugLevel,
+				),
+				tt.opt,
+			)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					logger.Debug("Caller.")
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkAddGoroutineID(b *testing.B) {
+	logger := New(
+		zapcore.NewCore(
+			zapcore.NewJSONEncoder(NewProductionConfig().EncoderConfig),
+			&ztest.Discarder{},
+			InfoLevel,
+		),
+		AddGoroutineID("goroutine"),
+	)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("Goroutine ID.")
+		}
+	})
+}
+
 func BenchmarkAddCallerAndStacktrace(b *testing.B) {
 	logger := New(
 		zapcore.NewCore(
@@ -302,6 +362,11 @@ func BenchmarkAny(b *testing.B) {
 			typed:  func() Field { return Stringer(key, InfoLevel) },
 			anyArg: InfoLevel,
 		},
+		{
+			name:   "text-marshaler",
+			typed:  func() Field { return TextMarshaler(key, benchTextMarshaler{127, 0, 0, 1}) },
+			anyArg: benchTextMarshaler{127, 0, 0, 1},
+		},
 	}
 
 	for _, tt := range tests {
@@ -359,3 +424,26 @@ func BenchmarkAny(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkSingleField(b *testing.B) {
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		withBenchedLogger(b, func(log *Logger) {
+			log.Info("Single field.", Int("foo", 42))
+		})
+	})
+	b.Run("Info1", func(b *testing.B) {
+		b.ReportAllocs()
+		withBenchedLogger(b, func(log *Logger) {
+			log.Info1("Single field.", Int("foo", 42))
+		})
+	})
+}
+
+// benchTextMarshaler is an encoding.TextMarshaler that isn't also a
+// fmt.Stringer, so Any can't take the Stringer fast path for it.
+type benchTextMarshaler [4]byte
+
+func (m benchTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte(net.IP(m[:]).String()), nil
+}
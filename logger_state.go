@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import "go.uber.org/zap/zapcore"
+
+// LoggerState is a snapshot of a Logger's configuration, taken with
+// Logger.Snapshot and restored with RestoreLogger. It's useful for plugins
+// and tests that temporarily reconfigure logging and must undo their changes
+// afterwards.
+//
+// A LoggerState captures:
+//
+//   - the Logger itself: its Core, name, accumulated fields, and every
+//     setting applied with Options. Loggers are immutable, so this is a
+//     reference, not a copy of the Core.
+//   - the current level of each AtomicLevel that controls one of the Core's
+//     outputs, as reported by DescribeCores.
+//
+// It doesn't capture the state of the Core's encoders and WriteSyncers, the
+// levels of other LevelEnablers, or which Logger is installed globally.
+type LoggerState struct {
+	logger *Logger
+	levels []atomicLevelState
+}
+
+type atomicLevelState struct {
+	atomic AtomicLevel
+	level  zapcore.Level
+}
+
+// Snapshot captures the Logger's configuration. See LoggerState for what's
+// captured.
+func (log *Logger) Snapshot() LoggerState {
+	state := LoggerState{logger: log.clone()}
+	for _, info := range zapcore.DescribeCores(log.core) {
+		lvl, ok := info.LevelEnabler.(AtomicLevel)
+		if !ok || state.hasAtomicLevel(lvl) {
+			continue
+		}
+		state.levels = append(state.levels, atomicLevelState{
+			atomic: lvl,
+			level:  lvl.Level(),
+		})
+	}
+	return state
+}
+
+func (s LoggerState) hasAtomicLevel(lvl AtomicLevel) bool {
+	for _, l := range s.levels {
+		if l.atomic == lvl {
+			return true
+		}
+	}
+	return false
+}
+
+// RestoreLogger resets each AtomicLevel captured in the state to the level it
+// had when the snapshot was taken, and returns a Logger configured as the
+// snapshotted Logger was. To restore the global Logger too, pass the result
+// to ReplaceGlobals.
+//
+// Restoring the zero LoggerState returns a no-op Logger.
+func RestoreLogger(state LoggerState) *Logger {
+	for _, l := range state.levels {
+		l.atomic.SetLevel(l.level)
+	}
+	if state.logger == nil {
+		return NewNop()
+	}
+	return state.logger.clone()
+}
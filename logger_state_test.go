@@ -0,0 +1,69 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerSnapshotRestore(t *testing.T) {
+	lvl := NewAtomicLevelAt(InfoLevel)
+	other := NewAtomicLevelAt(WarnLevel)
+	buf := &ztest.Buffer{}
+	enc := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg", NameKey: "name"})
+	logger := New(zapcore.NewTee(
+		zapcore.NewCore(enc, buf, lvl),
+		zapcore.NewCore(enc, buf, lvl),
+		zapcore.NewCore(enc, buf, other),
+	)).Named("app")
+
+	state := logger.Snapshot()
+	require.Len(t, state.levels, 2, "Expected each AtomicLevel to be captured once.")
+
+	// A plugin reconfigures logging.
+	lvl.SetLevel(ErrorLevel)
+	other.SetLevel(DebugLevel)
+	modified := logger.Named("plugin").WithOptions(WrapCore(func(zapcore.Core) zapcore.Core {
+		return zapcore.NewNopCore()
+	}))
+	modified.Error("dropped")
+	assert.Empty(t, buf.String(), "Expected the modified logger to drop entries.")
+
+	restored := RestoreLogger(state)
+	assert.Equal(t, InfoLevel, lvl.Level(), "Expected level to be restored.")
+	assert.Equal(t, WarnLevel, other.Level(), "Expected level to be restored.")
+	assert.Equal(t, "app", restored.Name(), "Expected name to be restored.")
+
+	restored.Info("restored")
+	assert.Equal(t, []string{"app\trestored", "app\trestored"}, buf.Lines(), "Expected the original core to be restored.")
+}
+
+func TestRestoreZeroLoggerState(t *testing.T) {
+	logger := RestoreLogger(LoggerState{})
+	require.NotNil(t, logger, "Expected a logger.")
+	assert.Equal(t, zapcore.NewNopCore(), logger.Core(), "Expected a no-op logger.")
+}
@@ -21,14 +21,18 @@
 package zap
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"go.uber.org/zap/internal/exit"
+	"go.uber.org/zap/internal/stacktrace"
 	"go.uber.org/zap/internal/ztest"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
@@ -455,6 +459,243 @@ func TestLoggerLeveledMethods(t *testing.T) {
 	})
 }
 
+func TestLoggerFormattedMethods(t *testing.T) {
+	withLogger(t, DebugLevel, opts(AddCaller()), func(logger *Logger, logs *observer.ObservedLogs) {
+		tests := []struct {
+			method        func(string, ...interface{})
+			expectedLevel zapcore.Level
+		}{
+			{logger.Debugf, DebugLevel},
+			{logger.Infof, InfoLevel},
+			{logger.Warnf, WarnLevel},
+			{logger.Errorf, ErrorLevel},
+			{logger.DPanicf, DPanicLevel},
+		}
+		for i, tt := range tests {
+			tt.method("%s-%d", "msg", i)
+			output := logs.AllUntimed()
+			require.Equal(t, i+1, len(output), "Unexpected number of logs.")
+			assert.Equal(t, tt.expectedLevel, output[i].Level, "Unexpected level.")
+			assert.Equal(t, fmt.Sprintf("msg-%d", i), output[i].Message, "Unexpected message.")
+			assert.Empty(t, output[i].Context, "Unexpected context.")
+			assert.Regexp(t, `/logger_test.go:\d+$`, output[i].Caller.String(), "Unexpected caller.")
+		}
+	})
+}
+
+type stringerF func() string
+
+func (f stringerF) String() string { return f() }
+
+func TestLoggerFormattedMethodsDisabled(t *testing.T) {
+	withLogger(t, ErrorLevel, nil, func(logger *Logger, logs *observer.ObservedLogs) {
+		var formatted bool
+		arg := stringerF(func() string {
+			formatted = true
+			return "arg"
+		})
+		logger.Debugf("%v", arg)
+		logger.Infof("%v", arg)
+		logger.Warnf("%v", arg)
+		assert.False(t, formatted, "Shouldn't format messages at disabled levels.")
+		assert.Equal(t, 0, logs.Len(), "Shouldn't write messages at disabled levels.")
+	})
+	withLogger(t, FatalLevel+1, nil, func(logger *Logger, logs *observer.ObservedLogs) {
+		assert.Panics(t, func() { logger.Panicf("%s", "foo") }, "Panicf should always panic.")
+		stub := exit.WithStub(func() { logger.Fatalf("%s", "foo") })
+		assert.True(t, stub.Exited, "Fatalf should always exit.")
+		assert.Equal(t, 0, logs.Len(), "Shouldn't write messages at disabled levels.")
+	})
+}
+
+type traceIDKey struct{}
+
+func extractTraceID(ctx context.Context) []Field {
+	if id, ok := ctx.Value(traceIDKey{}).(string); ok {
+		return []Field{String("trace_id", id)}
+	}
+	return nil
+}
+
+func TestLoggerContextMethods(t *testing.T) {
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "abc")
+	opts := opts(
+		WithContextExtractor(extractTraceID),
+		WithContextExtractor(func(context.Context) []Field { return []Field{Bool("extracted", true)} }),
+		AddCaller(),
+	)
+	withLogger(t, DebugLevel, opts, func(logger *Logger, logs *observer.ObservedLogs) {
+		tests := []struct {
+			method        func(context.Context, string, ...Field)
+			expectedLevel zapcore.Level
+		}{
+			{logger.DebugContext, DebugLevel},
+			{logger.InfoContext, InfoLevel},
+			{logger.WarnContext, WarnLevel},
+			{logger.ErrorContext, ErrorLevel},
+			{logger.DPanicContext, DPanicLevel},
+			{
+				func(ctx context.Context, msg string, fields ...Field) {
+					assert.Panics(t, func() { logger.PanicContext(ctx, msg, fields...) }, "Expected panic.")
+				},
+				PanicLevel,
+			},
+			{
+				func(ctx context.Context, msg string, fields ...Field) {
+					stub := exit.WithStub(func() { logger.FatalContext(ctx, msg, fields...) })
+					assert.True(t, stub.Exited, "Expected Fatal logger call to terminate process.")
+				},
+				FatalLevel,
+			},
+			{
+				func(ctx context.Context, msg string, fields ...Field) {
+					logger.LogContext(ctx, WarnLevel, msg, fields...)
+				},
+				WarnLevel,
+			},
+		}
+		for _, tt := range tests {
+			tt.method(ctx, "msg", Int("n", 1))
+			output := logs.TakeAll()
+			require.Len(t, output, 1, "Unexpected number of logs.")
+			assert.Equal(t, tt.expectedLevel, output[0].Level, "Unexpected level.")
+			assert.Equal(t,
+				[]Field{Int("n", 1), String("trace_id", "abc"), Bool("extracted", true)},
+				output[0].Context,
+				"Expected extracted fields after fields passed at the log site.",
+			)
+		}
+
+		logger.InfoContext(ctx, "caller")
+		entry := logs.TakeAll()[0]
+		assert.Regexp(t, `logger_test.go:\d+$`, entry.Caller.String(), "Unexpected caller.")
+
+		var nilCtx context.Context
+		logger.InfoContext(nilCtx, "nil context", Int("n", 1))
+		assert.Equal(t, []Field{Int("n", 1)}, logs.TakeAll()[0].Context, "Expected no extracted fields with nil context.")
+
+		logger.Info("plain")
+		assert.Empty(t, logs.TakeAll()[0].Context, "Expected non-context methods to be unchanged.")
+	})
+}
+
+func TestLoggerWriteRaw(t *testing.T) {
+	var low, high ztest.Buffer
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	core := zapcore.NewTee(
+		zapcore.NewCore(enc, &low, LevelEnablerFunc(func(l zapcore.Level) bool { return l < WarnLevel })),
+		zapcore.NewCore(enc, &high, WarnLevel),
+	)
+	obs, logs := observer.New(ErrorLevel)
+	logger := New(
+		zapcore.NewSamplerWithOptions(zapcore.NewTee(core, obs), time.Minute, 1, 0),
+		AddCaller(),
+	).With(String("ignored", "field"))
+
+	logger.WriteRaw(DebugLevel, []byte("2024/01/01 legacy debug"))
+	logger.WriteRaw(WarnLevel, []byte("2024/01/01 legacy warn\n"))
+	logger.WriteRaw(ErrorLevel, []byte("2024/01/01 legacy error"))
+	logger.WriteRaw(ErrorLevel, []byte("2024/01/01 legacy error")) // sampled out
+	logger.Info("structured")
+
+	assert.Equal(t, []string{
+		"2024/01/01 legacy debug",
+		`{"msg":"structured","ignored":"field"}`,
+	}, low.Lines(), "Unexpected lines below WarnLevel.")
+	assert.Equal(t, []string{
+		"2024/01/01 legacy warn",
+		"2024/01/01 legacy error",
+	}, high.Lines(), "Unexpected lines at WarnLevel and above.")
+
+	// Cores that can't write raw lines log the line as the message.
+	require.Equal(t, 1, logs.Len(), "Unexpected number of observed entries.")
+	assert.Equal(t, "2024/01/01 legacy error", logs.All()[0].Message, "Unexpected observed message.")
+}
+
+func TestLoggerWouldLog(t *testing.T) {
+	var dropped []string
+	obs, logs := observer.New(InfoLevel)
+	core := zapcore.NewSamplerWithOptions(obs, time.Minute, 2, 0,
+		zapcore.SamplerHook(func(ent zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped > 0 {
+				dropped = append(dropped, ent.Message)
+			}
+		}))
+	logger := New(core)
+
+	assert.False(t, logger.WouldLog(DebugLevel, "debug"), "Expected disabled level not to be logged.")
+	assert.Empty(t, dropped, "Level filtering must not consult the sampler.")
+
+	assert.True(t, logger.WouldLog(InfoLevel, "hot"), "Expected first message to be logged.")
+	assert.True(t, logger.WouldLog(InfoLevel, "hot"), "Expected second message to be logged.")
+	assert.False(t, logger.WouldLog(InfoLevel, "hot"), "Expected third message to be sampled out.")
+	assert.True(t, logger.WouldLog(InfoLevel, "cold"), "Expected other messages to be unaffected.")
+	assert.Zero(t, logs.Len(), "WouldLog must not write entries.")
+
+	// Each WouldLog call consumed a sampler slot, so the message is dropped.
+	logger.Info("hot")
+	assert.Zero(t, logs.Len(), "Expected sampler slots to be consumed by WouldLog.")
+	assert.Equal(t, []string{"hot", "hot"}, dropped, "Unexpected sampler drops.")
+}
+
+func TestLoggerWithReplaceFields(t *testing.T) {
+	withLogger(t, DebugLevel, []Option{WithReplaceFields()}, func(logger *Logger, logs *observer.ObservedLogs) {
+		parent := logger.With(String("request", "r1"), String("step", "start"))
+		child := parent
+		for _, step := range []string{"parse", "validate", "store"} {
+			child = child.With(String("step", step))
+		}
+		child.Info("child")
+		parent.Info("parent")
+
+		entries := logs.AllUntimed()
+		require.Len(t, entries, 2, "Unexpected number of logs.")
+		assert.Equal(t, []Field{String("request", "r1"), String("step", "store")}, entries[0].Context,
+			"Expected a single step field with the latest value.")
+		assert.Equal(t, []Field{String("request", "r1"), String("step", "start")}, entries[1].Context,
+			"Parent context must not change.")
+	})
+}
+
+func TestLoggerWithReplaceFieldsNamespaces(t *testing.T) {
+	var buf ztest.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), &buf, DebugLevel)
+	logger := New(core, WithReplaceFields()).
+		With(String("k", "outer"), Namespace("ns"), String("k", "inner")).
+		With(String("k", "replaced"), String("k", "twice"))
+
+	logger.Info("hi")
+	assert.Equal(t, `{"msg":"hi","k":"outer","ns":{"k":"twice"}}`, buf.Stripped(), "Expected replacement within the namespace only.")
+}
+
+func TestLoggerWithReplaceFieldsAfterOptions(t *testing.T) {
+	withLogger(t, DebugLevel, []Option{WithReplaceFields()}, func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.With(String("step", "a")).
+			WithOptions(AddCallerSkip(0)).
+			With(String("step", "b")).
+			With(String("step", "c")).
+			Info("hi")
+
+		assert.Equal(t, []Field{String("step", "a"), String("step", "c")}, logs.AllUntimed()[0].Context,
+			"Expected fields added before WithOptions to be kept, and later ones replaced.")
+	})
+}
+
+func TestLoggerContextMethodsWithoutExtractors(t *testing.T) {
+	withLogger(t, DebugLevel, nil, func(logger *Logger, logs *observer.ObservedLogs) {
+		child := logger.WithOptions(WithContextExtractor(extractTraceID))
+		ctx := context.WithValue(context.Background(), traceIDKey{}, "abc")
+
+		logger.InfoContext(ctx, "parent")
+		child.InfoContext(ctx, "child")
+
+		output := logs.AllUntimed()
+		require.Len(t, output, 2, "Unexpected number of logs.")
+		assert.Empty(t, output[0].Context, "Expected parent to have no extractors.")
+		assert.Equal(t, []Field{String("trace_id", "abc")}, output[1].Context, "Unexpected child fields.")
+	})
+}
+
 func TestLoggerLogLevels(t *testing.T) {
 	withLogger(t, DebugLevel, nil, func(logger *Logger, logs *observer.ObservedLogs) {
 		levels := []zapcore.Level{
@@ -536,6 +777,47 @@ func TestLoggerDPanic(t *testing.T) {
 	})
 }
 
+func TestLoggerDPanicStructured(t *testing.T) {
+	recoverDPanic := func(f func()) (recovered interface{}) {
+		defer func() { recovered = recover() }()
+		f()
+		return nil
+	}
+
+	withLogger(t, DebugLevel, opts(Development(), DPanicStructured()), func(logger *Logger, logs *observer.ObservedLogs) {
+		fields := []Field{String("k", "v"), Int("n", 1)}
+		recovered := recoverDPanic(func() {
+			logger.With(String("ctx", "c")).DPanic("invariant violated", fields...)
+		})
+
+		err, ok := recovered.(*DPanicError)
+		require.True(t, ok, "Expected to panic with a *DPanicError, got %#v.", recovered)
+		assert.EqualError(t, err, "invariant violated", "Unexpected error message.")
+		assert.Equal(t, DPanicLevel, err.Entry.Level, "Unexpected entry level.")
+		assert.Equal(t, fields, err.Fields(), "Expected the log-site fields.")
+		assert.Equal(t, 1, logs.Len(), "Expected the entry to be written before panicking.")
+
+		// Panic-level entries still panic with the message.
+		assert.PanicsWithValue(t, "boom", func() { logger.Panic("boom") }, "Unexpected Panic value.")
+	})
+
+	// Outside development mode, DPanic doesn't panic.
+	withLogger(t, DebugLevel, opts(DPanicStructured()), func(logger *Logger, logs *observer.ObservedLogs) {
+		assert.NotPanics(t, func() { logger.DPanic("", String("k", "v")) }, "Unexpected panic in production mode.")
+		assert.Equal(t, 1, logs.Len(), "Expected the entry to be written.")
+	})
+
+	// An explicit panic hook takes precedence.
+	withLogger(t, DebugLevel, opts(Development(), DPanicStructured(), WithPanicHook(zapcore.WriteThenGoexit)), func(logger *Logger, _ *observer.ObservedLogs) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			logger.DPanic("")
+		}()
+		<-done
+	})
+}
+
 func TestLoggerNoOpsDisabledLevels(t *testing.T) {
 	withLogger(t, WarnLevel, nil, func(logger *Logger, logs *observer.ObservedLogs) {
 		logger.Info("silence!")
@@ -604,6 +886,36 @@ func TestLoggerWriteFailure(t *testing.T) {
 	assert.True(t, errSink.Called(), "Expected logging an internal error to call Sync the error sink.")
 }
 
+func TestLoggerWriteFailurePerLoggerErrorOutput(t *testing.T) {
+	parentSink := &ztest.Buffer{}
+	logger := New(
+		zapcore.NewCore(
+			zapcore.NewJSONEncoder(NewProductionConfig().EncoderConfig),
+			zapcore.Lock(zapcore.AddSync(ztest.FailWriter{})),
+			DebugLevel,
+		),
+		ErrorOutput(zapcore.Lock(parentSink)),
+	)
+
+	sink1, sink2 := &ztest.Buffer{}, &ztest.Buffer{}
+	clone1 := logger.WithOptions(ErrorOutput(zapcore.Lock(sink1)))
+	clone2 := logger.WithOptions(ErrorOutput(zapcore.Lock(sink2)))
+
+	var wg sync.WaitGroup
+	runConcurrently(5, 10, &wg, func() { clone1.Info("one") })
+	runConcurrently(5, 10, &wg, func() { clone2.Info("two") })
+	wg.Wait()
+
+	assert.Len(t, sink1.Lines(), 50, "Expected every failure of the first clone on its error output.")
+	assert.Len(t, sink2.Lines(), 50, "Expected every failure of the second clone on its error output.")
+	assert.False(t, parentSink.Called(), "Expected parent's error output to be untouched.")
+
+	logger.Info("parent")
+	assert.Len(t, parentSink.Lines(), 1, "Expected parent to keep its error output.")
+	assert.Len(t, sink1.Lines(), 50, "Unexpected write to the first clone's error output.")
+	assert.Len(t, sink2.Lines(), 50, "Unexpected write to the second clone's error output.")
+}
+
 func TestLoggerSync(t *testing.T) {
 	withLogger(t, DebugLevel, nil, func(logger *Logger, _ *observer.ObservedLogs) {
 		assert.NoError(t, logger.Sync(), "Expected syncing a test logger to succeed.")
@@ -656,6 +968,36 @@ func TestLoggerAddCaller(t *testing.T) {
 	}
 }
 
+func TestLoggerAddCallerForLevels(t *testing.T) {
+	tests := []struct {
+		desc        string
+		options     []Option
+		debugCaller bool
+		errorCaller bool
+	}{
+		{"restricted", opts(AddCallerForLevels(WarnLevel)), false, true},
+		{"all levels", opts(AddCallerForLevels(DebugLevel)), true, true},
+		{"AddCaller resets", opts(AddCallerForLevels(WarnLevel), AddCaller()), true, true},
+		{"WithCaller(false) disables", opts(AddCallerForLevels(WarnLevel), WithCaller(false)), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			withLogger(t, DebugLevel, tt.options, func(logger *Logger, logs *observer.ObservedLogs) {
+				logger.Debug("debug")
+				logger.Error("error")
+				output := logs.AllUntimed()
+				require.Len(t, output, 2, "Unexpected number of logs written out.")
+				assert.Equal(t, tt.debugCaller, output[0].Caller.Defined, "Unexpected caller on Debug entry.")
+				assert.Equal(t, tt.errorCaller, output[1].Caller.Defined, "Unexpected caller on Error entry.")
+				if tt.errorCaller {
+					assert.Regexp(t, `.+/logger_test.go:[\d]+$`, output[1].Caller.String(), "Unexpected caller.")
+				}
+			})
+		})
+	}
+}
+
 func TestLoggerAddCallerFunction(t *testing.T) {
 	tests := []struct {
 		options         []Option
@@ -795,6 +1137,198 @@ func TestLoggerHooks(t *testing.T) {
 	assert.Equal(t, int64(2), seen.Load(), "Hook saw an unexpected number of logs.")
 }
 
+type codedError struct {
+	code   string
+	status int
+}
+
+func (e *codedError) Error() string { return e.code }
+
+func TestLoggerWithErrorExpander(t *testing.T) {
+	expand := func(err error) []Field {
+		var coded *codedError
+		if errors.As(err, &coded) {
+			return []Field{String("code", coded.code), Int("status", coded.status)}
+		}
+		return nil
+	}
+	err := fmt.Errorf("lookup: %w", &codedError{code: "not_found", status: 404})
+
+	withLogger(t, DebugLevel, opts(WithErrorExpander(expand)), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Warn("warn", Error(err))
+		logger.Error("error", Error(err))
+		logger.Sugar().Errorw("sugared", "error", err)
+
+		entries := logs.AllUntimed()
+		require.Len(t, entries, 3, "Unexpected number of entries.")
+		assert.Equal(t, []Field{Error(err)}, entries[0].Context, "Expected warn entry to be untouched.")
+		expanded := []Field{Error(err), String("code", "not_found"), Int("status", 404)}
+		assert.Equal(t, expanded, entries[1].Context, "Unexpected fields on error entry.")
+		assert.Equal(t, expanded, entries[2].Context, "Unexpected fields on sugared error entry.")
+	})
+}
+
+func TestLoggerAddGoroutineID(t *testing.T) {
+	withLogger(t, DebugLevel, opts(AddGoroutineID("goroutine")), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Info("main")
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			logger.With(String("k", "v")).Info("other", Int("n", 1))
+		}()
+		<-done
+
+		entries := logs.AllUntimed()
+		require.Len(t, entries, 2, "Unexpected number of entries.")
+		mainID := entries[0].ContextMap()["goroutine"]
+		otherID := entries[1].ContextMap()["goroutine"]
+		assert.Positive(t, mainID, "Expected a goroutine ID.")
+		assert.Positive(t, otherID, "Expected a goroutine ID.")
+		assert.NotEqual(t, mainID, otherID, "Expected distinct goroutine IDs.")
+		assert.Equal(t, []string{"k", "n", "goroutine"}, fieldKeys(entries[1].Context), "Expected goroutine ID after other fields.")
+	})
+}
+
+func TestLoggerWithSequenceNumbers(t *testing.T) {
+	withLogger(t, DebugLevel, opts(WithSequenceNumbers("seq")), func(logger *Logger, logs *observer.ObservedLogs) {
+		const goroutines, perGoroutine = 10, 100
+
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				// Children share the parent's counter.
+				child := logger.With(Int("goroutine", i)).Named("child")
+				var last uint64
+				for j := 0; j < perGoroutine; j++ {
+					child.Info("")
+					entries := logs.FilterField(Int("goroutine", i)).AllUntimed()
+					seq := entries[len(entries)-1].ContextMap()["seq"].(uint64)
+					assert.Greater(t, seq, last, "Expected increasing sequence numbers.")
+					last = seq
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[uint64]bool)
+		for _, e := range logs.AllUntimed() {
+			seen[e.ContextMap()["seq"].(uint64)] = true
+		}
+		require.Len(t, seen, goroutines*perGoroutine, "Expected distinct sequence numbers.")
+		for i := uint64(1); i <= goroutines*perGoroutine; i++ {
+			assert.True(t, seen[i], "Missing sequence number %v.", i)
+		}
+	})
+}
+
+func TestLoggerWithSequenceNumbersScope(t *testing.T) {
+	withLogger(t, DebugLevel, opts(WithSequenceNumbers("seq")), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Info("parent")
+		logger.WithOptions(AddCaller()).Info("child")
+		other := logger.WithOptions(WithSequenceNumbers("other"))
+		other.Info("new counter")
+
+		assert.Equal(t, []map[string]interface{}{
+			{"seq": uint64(1)},
+			{"seq": uint64(2)},
+			{"seq": uint64(3), "other": uint64(1)},
+		}, contextMaps(logs), "Unexpected sequence numbers.")
+	})
+}
+
+func TestLoggerWithSequenceNumbersTee(t *testing.T) {
+	infoCore, infoLogs := observer.New(InfoLevel)
+	errCore, errLogs := observer.New(ErrorLevel)
+	logger := New(zapcore.NewTee(infoCore, errCore), WithSequenceNumbers("seq"))
+
+	logger.Info("info")
+	logger.Check(ErrorLevel, "error").Write(String("k", "v"))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"seq": uint64(1)},
+		{"seq": uint64(2), "k": "v"},
+	}, contextMaps(infoLogs), "Unexpected sequence numbers in info-level Core.")
+	assert.Equal(t, []map[string]interface{}{
+		{"seq": uint64(2), "k": "v"},
+	}, contextMaps(errLogs), "Expected the same sequence number in every Core.")
+}
+
+func contextMaps(logs *observer.ObservedLogs) []map[string]interface{} {
+	var maps []map[string]interface{}
+	for _, e := range logs.AllUntimed() {
+		maps = append(maps, e.ContextMap())
+	}
+	return maps
+}
+
+func TestLoggerReplaceCoreMethod(t *testing.T) {
+	withLogger(t, DebugLevel, opts(AddCaller(), Fields(Int("old", 1))), func(logger *Logger, logs *observer.ObservedLogs) {
+		core, replacedLogs := observer.New(WarnLevel)
+		replaced := logger.Named("app").ReplaceCore(core)
+
+		logger.Info("original")
+		replaced.Info("dropped")
+		replaced.Warn("replaced")
+
+		assert.Equal(t, 1, logs.Len(), "Expected original logger to be unaffected.")
+		entries := replacedLogs.AllUntimed()
+		require.Len(t, entries, 1, "Unexpected entries in replaced core.")
+		assert.Equal(t, "app", entries[0].LoggerName, "Expected name to be kept.")
+		assert.True(t, entries[0].Caller.Defined, "Expected options to be kept.")
+		assert.Empty(t, entries[0].Context, "Fields added to the old core shouldn't be kept.")
+	})
+
+	assert.Equal(t, zapcore.NewNopCore(), NewNop().ReplaceCore(nil).Core(), "Expected nil core to be replaced with a no-op.")
+}
+
+func TestLoggerSwappableCore(t *testing.T) {
+	first, firstLogs := observer.New(DebugLevel)
+	second, secondLogs := observer.New(DebugLevel)
+	swappable := zapcore.NewSwappableCore(first)
+	logger := New(swappable)
+	child := logger.With(String("k", "v"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				child.Info("in flight")
+			}
+		}()
+	}
+	swappable.Swap(second)
+	wg.Wait()
+
+	child.Info("after")
+	assert.Equal(t, 401, firstLogs.Len()+secondLogs.Len(), "Expected every entry to be written once.")
+	all := secondLogs.AllUntimed()
+	assert.Equal(t, "after", all[len(all)-1].Message, "Expected existing loggers to use the new core.")
+	assert.Equal(t, map[string]interface{}{"k": "v"}, all[len(all)-1].ContextMap(), "Expected fields to be kept.")
+}
+
+func fieldKeys(fields []Field) []string {
+	keys := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+func TestGoroutineID(t *testing.T) {
+	ids := make(chan int64, 2)
+	for i := 0; i < 2; i++ {
+		go func() { ids <- goroutineID() }()
+	}
+	a, b := <-ids, <-ids
+	assert.Positive(t, a, "Expected a positive goroutine ID.")
+	assert.NotEqual(t, a, b, "Expected distinct goroutine IDs.")
+	assert.Equal(t, goroutineID(), goroutineID(), "Expected a stable ID within a goroutine.")
+}
+
 func TestLoggerConcurrent(t *testing.T) {
 	withLogger(t, DebugLevel, nil, func(logger *Logger, logs *observer.ObservedLogs) {
 		child := logger.With(String("foo", "bar"))
@@ -1059,3 +1593,144 @@ func infoLog(logger *Logger, msg string, fields ...Field) {
 func infoLogSugared(logger *SugaredLogger, args ...interface{}) {
 	logger.Info(args...)
 }
+
+func TestLoggerSingleField(t *testing.T) {
+	tests := []struct {
+		lvl      zapcore.Level
+		variadic func(*Logger, string, ...Field)
+		single   func(*Logger, string, Field)
+	}{
+		{DebugLevel, (*Logger).Debug, (*Logger).Debug1},
+		{InfoLevel, (*Logger).Info, (*Logger).Info1},
+		{WarnLevel, (*Logger).Warn, (*Logger).Warn1},
+		{ErrorLevel, (*Logger).Error, (*Logger).Error1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lvl.String(), func(t *testing.T) {
+			var variadic, single ztest.Buffer
+			newLogger := func(ws zapcore.WriteSyncer) *Logger {
+				enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+					MessageKey:  "msg",
+					LevelKey:    "level",
+					EncodeLevel: zapcore.LowercaseLevelEncoder,
+				})
+				return New(zapcore.NewCore(enc, ws, DebugLevel)).With(String("ctx", "c"))
+			}
+
+			tt.variadic(newLogger(&variadic), "msg", Int("n", 1))
+			tt.single(newLogger(&single), "msg", Int("n", 1))
+			assert.Equal(t, variadic.Lines(), single.Lines(), "Expected identical output.")
+			assert.Len(t, single.Lines(), 1, "Expected a single line.")
+
+			// Disabled levels log nothing.
+			var disabled ztest.Buffer
+			logger := New(zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), &disabled, tt.lvl+1))
+			tt.single(logger, "msg", Int("n", 1))
+			assert.Empty(t, disabled.Lines(), "Expected no output below the enabled level.")
+		})
+	}
+}
+
+func TestLoggerSingleFieldAllocs(t *testing.T) {
+	if ztest.RaceEnabled {
+		t.Skip("The race detector drops pooled CheckedEntries, which allocates.")
+	}
+
+	logger := New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(NewProductionEncoderConfig()),
+		&ztest.Discarder{},
+		DebugLevel,
+	))
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info1("msg", Int("n", 1))
+	})
+	assert.Zero(t, allocs, "Expected Info1 not to allocate.")
+}
+
+func TestLoggerWithMessageTransformer(t *testing.T) {
+	var seen []string
+	record := func(lvl zapcore.Level, msg string) string {
+		seen = append(seen, lvl.String()+" "+msg)
+		return msg
+	}
+	upper := func(_ zapcore.Level, msg string) string { return strings.ToUpper(msg) }
+
+	withLogger(t, InfoLevel, opts(
+		WithMessageTransformer(nil),
+		WithMessageTransformer(TruncateMessage(5)),
+		WithMessageTransformer(record),
+		WithMessageTransformer(upper),
+	), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Debug("dropped before transforming")
+		logger.Info("hello world")
+		logger.Sugar().Warnf("%d", 42)
+
+		assert.Equal(t, []string{"info hello", "warn 42"}, seen, "Transformers should run in order, only for logged entries.")
+		assert.Equal(t, []string{"HELLO", "42"}, transformedMessages(logs), "Unexpected transformed messages.")
+	})
+
+	withLogger(t, InfoLevel, opts(WithMessageTransformer(nil)), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Info("unchanged")
+		assert.Equal(t, []string{"unchanged"}, transformedMessages(logs), "Expected a nil transformer to be a no-op.")
+	})
+}
+
+func transformedMessages(logs *observer.ObservedLogs) []string {
+	var msgs []string
+	for _, e := range logs.AllUntimed() {
+		msgs = append(msgs, e.Message)
+	}
+	return msgs
+}
+
+func TestTruncateMessage(t *testing.T) {
+	tests := []struct {
+		max  int
+		msg  string
+		want string
+	}{
+		{max: 5, msg: "short", want: "short"},
+		{max: 5, msg: "longer", want: "longe"},
+		{max: 0, msg: "anything", want: ""},
+		{max: -1, msg: "anything", want: ""},
+		{max: -1, msg: "", want: ""},
+		{max: 2, msg: "héllo", want: "h"}, // don't split é
+		{max: 3, msg: "héllo", want: "hé"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, TruncateMessage(tt.max)(InfoLevel, tt.msg), "Unexpected truncation of %q to %d bytes.", tt.msg, tt.max)
+	}
+}
+
+func TestLoggerWithTime(t *testing.T) {
+	clock := ztest.NewMockClock()
+	withLogger(t, DebugLevel, opts(WithClock(clock)), func(logger *Logger, logs *observer.ObservedLogs) {
+		eventTime := time.Date(2001, 2, 3, 4, 5, 6, 7, time.UTC)
+		replay := logger.WithTime(eventTime)
+
+		replay.Info("first")
+		replay.With(String("k", "v")).Named("child").Warn("second")
+		replay.Sugar().Errorw("third")
+		clock.Add(time.Hour)
+		logger.Info("now")
+
+		entries := logs.All()
+		require.Len(t, entries, 4, "Unexpected number of entries.")
+		for _, e := range entries[:3] {
+			assert.Equal(t, eventTime, e.Time, "Expected the explicit time on %q.", e.Message)
+		}
+		assert.Equal(t, clock.Now(), entries[3].Time, "Expected the parent to keep using its clock.")
+	})
+}
+
+func TestFormatErrorStacktraceSingleFrame(t *testing.T) {
+	stack := stacktrace.Capture(0, stacktrace.First)
+	defer stack.Free()
+	require.Equal(t, 1, stack.Count(), "Expected a one-frame stack.")
+
+	got := formatErrorStacktrace(stack, zapcore.EntryCaller{})
+	assert.True(t, strings.HasPrefix(got, "go.uber.org/zap.TestFormatErrorStacktraceSingleFrame\n"),
+		"Expected the only frame to be kept, got:\n%s", got)
+	assert.NotContains(t, got, "\n\n", "Expected a single formatted frame.")
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SortedMap constructs a field that carries a map, encoded as an object whose
+// entries are in sorted key order, so that output is deterministic
+// regardless of the encoder. Values are encoded as with Any, except that
+// nested maps with string keys are sorted too. Encoding a nested map whose
+// keys aren't strings fails with an error naming the offending key.
+func SortedMap(key string, m map[string]any) Field {
+	return Object(key, sortedMap[any](m))
+}
+
+// SortedMapOf is a typed variant of SortedMap for maps with values of any
+// single type.
+func SortedMapOf[V any](key string, m map[string]V) Field {
+	return Object(key, sortedMap[V](m))
+}
+
+type sortedMap[V any] map[string]V
+
+func (m sortedMap[V]) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := addSortedValue(enc, k, m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reflectSortedMap is a map with string keys of a type unknown at compile
+// time.
+type reflectSortedMap reflect.Value
+
+func (m reflectSortedMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	v := reflect.Value(m)
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, k := range keys {
+		if err := addSortedValue(enc, k.String(), v.MapIndex(k).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addSortedValue(enc zapcore.ObjectEncoder, key string, val any) error {
+	if v := reflect.ValueOf(val); v.Kind() == reflect.Map {
+		if kt := v.Type().Key(); kt.Kind() != reflect.String {
+			return fmt.Errorf("can't sort map at key %q: keys of type %v aren't strings", key, kt)
+		}
+		return enc.AddObject(key, reflectSortedMap(v))
+	}
+	Any(key, val).AddTo(enc)
+	return nil
+}
+
+// SyncMap constructs a field that carries the contents of a sync.Map,
+// encoded as an object with one entry per key. Keys are converted to strings
+// with fmt.Sprint, and values are encoded as with Any. Entries are written in
+// the order sync.Map.Range visits them, which isn't deterministic. A nil map
+// is encoded as null.
+//
+// The map is read when the field is encoded, not when it's constructed, and
+// reading it doesn't lock it: as with Range, an entry stored or deleted while
+// the field is encoded may or may not appear, so the output isn't
+// necessarily a consistent snapshot of the map at any single moment.
+func SyncMap(key string, m *sync.Map) Field {
+	if m == nil {
+		return nilField(key)
+	}
+	return Object(key, syncMap{m})
+}
+
+type syncMap struct {
+	m *sync.Map
+}
+
+func (m syncMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	m.m.Range(func(k, v any) bool {
+		Any(fmt.Sprint(k), v).AddTo(enc)
+		return true
+	})
+	return nil
+}
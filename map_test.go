@@ -0,0 +1,104 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func encodeFieldJSON(t *testing.T, f Field) string {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []Field{f})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+	return buf.String()
+}
+
+func TestSortedMap(t *testing.T) {
+	m := map[string]any{
+		"zeta":  1,
+		"alpha": "a",
+		"mid":   map[string]any{"y": true, "b": 2.5, "k": map[string]int{"z": 1, "a": 2}},
+		"beta":  []int{3, 1},
+		"err":   errNamed("boom"),
+	}
+	const want = `{"m":{"alpha":"a","beta":[3,1],"err":"boom","mid":{"b":2.5,"k":{"a":2,"z":1},"y":true},"zeta":1}}` + "\n"
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, want, encodeFieldJSON(t, SortedMap("m", m)), "Unexpected output on run %d.", i)
+	}
+
+	for i := 0; i < 20; i++ {
+		enc := zapcore.NewConsoleEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+		buf, err := enc.EncodeEntry(zapcore.Entry{Message: "m"}, []Field{SortedMap("m", m)})
+		require.NoError(t, err, "Unexpected encoding error.")
+		assert.Equal(t,
+			`m	{"m": {"alpha": "a", "beta": [3, 1], "err": "boom", "mid": {"b": 2.5, "k": {"a": 2, "z": 1}, "y": true}, "zeta": 1}}`+"\n",
+			buf.String(), "Unexpected console output on run %d.", i)
+		buf.Free()
+	}
+}
+
+func TestSortedMapOf(t *testing.T) {
+	assert.Equal(t,
+		`{"m":{"a":2,"b":3,"c":1}}`+"\n",
+		encodeFieldJSON(t, SortedMapOf("m", map[string]int{"c": 1, "a": 2, "b": 3})),
+		"Unexpected output.",
+	)
+	assert.Equal(t, `{"m":{}}`+"\n", encodeFieldJSON(t, SortedMapOf[int]("m", nil)), "Expected nil map as an empty object.")
+}
+
+func TestSortedMapNonStringKeys(t *testing.T) {
+	out := encodeFieldJSON(t, SortedMap("m", map[string]any{"ids": map[int]string{1: "a"}}))
+	assert.Contains(t, out, `"mError":"can't sort map at key \"ids\": keys of type int aren't strings"`,
+		"Expected a clear error for maps with non-string keys.")
+}
+
+type errNamed string
+
+func (e errNamed) Error() string { return string(e) }
+
+func TestSyncMap(t *testing.T) {
+	var m sync.Map
+	m.Store("name", "alice")
+	m.Store(42, time.Second)
+	m.Store(struct{ A, B int }{1, 2}, []int{1, 2})
+
+	enc := zapcore.NewMapObjectEncoder()
+	SyncMap("cache", &m).AddTo(enc)
+	assert.Equal(t, map[string]interface{}{
+		"cache": map[string]interface{}{
+			"name":  "alice",
+			"42":    time.Second,
+			"{1 2}": []interface{}{1, 2},
+		},
+	}, enc.Fields, "Unexpected encoding of populated map.")
+
+	var empty sync.Map
+	assert.Equal(t, `{"cache":{}}`+"\n", encodeFieldJSON(t, SyncMap("cache", &empty)), "Unexpected encoding of empty map.")
+	assert.Equal(t, `{"cache":null}`+"\n", encodeFieldJSON(t, SyncMap("cache", nil)), "Unexpected encoding of nil map.")
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	_auditTemplateKey = "template"
+	_auditFieldsKey   = "fields"
+)
+
+// WithMigrationAudit makes SugaredLoggers built from the Logger write an
+// audit record to w for each entry they log, describing the entry as
+// structured logging would see it. It's a tooling aid for migrating from
+// sugared to structured logging: the records can be diffed against the
+// output of the structured calls that replace the sugared ones.
+//
+// Each record is a line of JSON holding the entry's level, logger name, and
+// message, the printf-style template the message was formatted with (if
+// any), and the fields built from the call's key-value pairs under
+// "fields". For example,
+//
+//	sugar.Infow("failed to fetch URL", "url", url, "attempt", 3)
+//
+// records
+//
+//	{"level":"info","msg":"failed to fetch URL","fields":{"url":"...","attempt":3}}
+//
+// Auditing encodes every sugared entry twice, so it's meant for development
+// and tests rather than production. Errors writing records go to the
+// Logger's ErrorOutput.
+func WithMigrationAudit(w io.Writer) Option {
+	return optionFunc(func(log *Logger) {
+		log.migrationAudit = &migrationAuditor{
+			enc: zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+				LevelKey:       "level",
+				NameKey:        "logger",
+				MessageKey:     "msg",
+				LineEnding:     zapcore.DefaultLineEnding,
+				EncodeLevel:    zapcore.LowercaseLevelEncoder,
+				EncodeDuration: zapcore.StringDurationEncoder,
+				EncodeTime:     zapcore.ISO8601TimeEncoder,
+			}),
+			out: zapcore.Lock(zapcore.AddSync(w)),
+		}
+	})
+}
+
+type migrationAuditor struct {
+	enc zapcore.Encoder
+	out zapcore.WriteSyncer
+}
+
+// record writes the audit record for an entry logged by a SugaredLogger.
+func (a *migrationAuditor) record(log *Logger, ent zapcore.Entry, template string, fields []Field) {
+	rec := make([]Field, 0, len(fields)+2)
+	if template != "" && template != ent.Message {
+		rec = append(rec, String(_auditTemplateKey, template))
+	}
+	if len(fields) > 0 {
+		rec = append(rec, Namespace(_auditFieldsKey))
+		rec = append(rec, fields...)
+	}
+
+	buf, err := a.enc.EncodeEntry(ent, rec)
+	if err == nil {
+		_, err = a.out.Write(buf.Bytes())
+		buf.Free()
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(log.errorOutput, "%v migration audit error: %v\n", time.Now().UTC(), err)
+		_ = log.errorOutput.Sync()
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &rec), "Invalid audit record %q.", line)
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestMigrationAuditInfow(t *testing.T) {
+	var buf bytes.Buffer
+	withSugar(t, DebugLevel, opts(WithMigrationAudit(&buf)), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+		logger.Named("fetcher").Infow("failed to fetch URL", "url", "http://example.com", "attempt", 3)
+
+		assert.Equal(t, []map[string]interface{}{{
+			"level":  "info",
+			"logger": "fetcher",
+			"msg":    "failed to fetch URL",
+			"fields": map[string]interface{}{
+				"url":     "http://example.com",
+				"attempt": float64(3),
+			},
+		}}, auditRecords(t, &buf), "Unexpected audit records.")
+		assert.Equal(t, 1, logs.Len(), "Expected the entry to be logged once.")
+	})
+}
+
+func TestMigrationAuditMethods(t *testing.T) {
+	var buf bytes.Buffer
+	withSugar(t, InfoLevel, opts(WithMigrationAudit(&buf)), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+		logger.Infof("fetched %d URLs", 2)
+		logger.Warn("plain ", "message")
+		logger.Errorln("with", "spaces")
+		logger.Infom("from a map", map[string]interface{}{"k": "v"})
+		logger.Debugw("disabled", "k", "v")
+
+		assert.Equal(t, []map[string]interface{}{
+			{"level": "info", "msg": "fetched 2 URLs", "template": "fetched %d URLs"},
+			{"level": "warn", "msg": "plain message"},
+			{"level": "error", "msg": "with spaces"},
+			{"level": "info", "msg": "from a map", "fields": map[string]interface{}{"k": "v"}},
+		}, auditRecords(t, &buf), "Unexpected audit records.")
+		assert.Equal(t, 4, logs.Len(), "Unexpected number of logged entries.")
+	})
+}
+
+func TestMigrationAuditStructuredUnaffected(t *testing.T) {
+	var buf bytes.Buffer
+	withLogger(t, DebugLevel, opts(WithMigrationAudit(&buf)), func(logger *Logger, logs *observer.ObservedLogs) {
+		logger.Info("structured", String("k", "v"))
+		assert.Empty(t, buf.String(), "Expected no audit records for structured calls.")
+		assert.Equal(t, 1, logs.Len(), "Expected the entry to be logged.")
+	})
+}
+
+func TestMigrationAuditWriteError(t *testing.T) {
+	errSink := &ztest.Buffer{}
+	withSugar(t, DebugLevel, opts(WithMigrationAudit(&ztest.FailWriter{}), ErrorOutput(errSink)), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+		logger.Infow("msg", "k", "v")
+		assert.Contains(t, errSink.String(), "migration audit error: failed", "Expected audit errors on ErrorOutput.")
+		assert.Equal(t, 1, logs.Len(), "Expected the entry to be logged despite the audit error.")
+	})
+}
@@ -21,7 +21,13 @@
 package zap
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -65,11 +71,239 @@ func Fields(fs ...Field) Option {
 	})
 }
 
+// WithEnvFields adds a string field to the Logger for each environment
+// variable whose name starts with prefix, keyed by the rest of the name. For
+// example, with the prefix "LOG_FIELD_", the variable LOG_FIELD_region=us-east
+// adds the field region=us-east. This is convenient for containerized
+// deployments that inject metadata through the environment.
+//
+// The environment is read once, when the option is applied, and the fields
+// are added like those passed to Fields, so they're serialized only once.
+// Fields are added in order of their keys. Variables whose names are exactly
+// prefix are ignored, and an empty prefix adds no fields, since it would
+// otherwise add the entire environment.
+func WithEnvFields(prefix string) Option {
+	return optionFunc(func(log *Logger) {
+		if fs := envFields(prefix, os.Environ()); len(fs) > 0 {
+			log.core = log.core.With(fs)
+		}
+	})
+}
+
+// envFields builds fields from the "key=value" environment entries with the
+// given prefix.
+func envFields(prefix string, environ []string) []Field {
+	if prefix == "" {
+		return nil
+	}
+	var fs []Field
+	for _, kv := range environ {
+		name, val, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		fs = append(fs, String(name[len(prefix):], val))
+	}
+	sort.SliceStable(fs, func(i, j int) bool { return fs[i].Key < fs[j].Key })
+	return fs
+}
+
+// WithCorrelationID tags every entry from the Logger with a random ID under
+// the given key, generated when the option is applied. Loggers derived with
+// With share their parent's ID, so applying the option to a request-scoped
+// logger, for example with WithOptions, tags all of the request's lines.
+//
+// IDs are random (version 4) UUIDs. Use WithCorrelationIDFunc to supply a
+// different generator.
+func WithCorrelationID(key string) Option {
+	return WithCorrelationIDFunc(key, newUUID)
+}
+
+// WithCorrelationIDFunc is like WithCorrelationID, but calls gen to generate
+// the ID.
+func WithCorrelationIDFunc(key string, gen func() string) Option {
+	return optionFunc(func(log *Logger) {
+		log.core = log.core.With([]Field{String(key, gen())})
+	})
+}
+
+// WithErrorExpander configures the Logger to destructure errors logged at
+// ErrorLevel and above into additional structured fields. For every error
+// field on such an entry, the fields returned by expand are added to the
+// entry. See zapcore.NewErrorExpanderCore for details.
+//
+// For example, the following adds the code carried by a custom error type:
+//
+//	zap.WithErrorExpander(func(err error) []zap.Field {
+//	  var coded *CodedError
+//	  if errors.As(err, &coded) {
+//	    return []zap.Field{zap.Int("code", coded.Code)}
+//	  }
+//	  return nil
+//	})
+func WithErrorExpander(expand func(error) []Field) Option {
+	return optionFunc(func(log *Logger) {
+		log.core = zapcore.NewErrorExpanderCore(log.core, expand)
+	})
+}
+
+// AddGoroutineID configures the Logger to annotate each entry with the ID of
+// the goroutine that logged it, under the given key. This can help when
+// debugging concurrency issues.
+//
+// Goroutine IDs are only meaningful while the goroutine runs: the runtime
+// may reuse them, so they're not stable identifiers and shouldn't be used
+// to correlate entries across long periods. Go also doesn't expose them
+// directly, so they're parsed from a stack trace on every entry; this is
+// considerably more expensive than most fields, so use this sparingly.
+func AddGoroutineID(key string) Option {
+	return optionFunc(func(log *Logger) {
+		log.core = zapcore.RegisterFieldHooks(log.core, func(_ zapcore.Entry, fields []Field) []Field {
+			return append(fields[:len(fields):len(fields)], Int64(key, goroutineID()))
+		})
+	})
+}
+
+// AddBuildInfo configures the Logger to annotate each entry with the main
+// module's version, under the key "version", and the VCS revision it was
+// built from, under the key "revision", which helps correlate logs with
+// deployments. Both are read from the binary's build information once, when
+// the option is applied. Values that aren't available are omitted; binaries
+// built without module support have neither, and those built with "go run"
+// or from a working tree typically report the version "(devel)".
+func AddBuildInfo() Option {
+	return optionFunc(func(log *Logger) {
+		if fields := buildInfoFields(); len(fields) > 0 {
+			log.core = log.core.With(fields)
+		}
+	})
+}
+
+// WithSequenceNumbers configures the Logger to annotate each entry with a
+// sequence number under the given key, so that consumers can restore the
+// order of entries that an asynchronous pipeline delivered out of order.
+// Numbers start at 1 and increase by one for each entry logged.
+//
+// Each use of this option creates a new counter. The Logger and every Logger
+// derived from it, with With, Named, or WithOptions, share that counter, so
+// their entries are numbered in a single sequence. Each entry is numbered
+// once, when it's checked, and every Core that writes it, such as each
+// member of a zapcore.NewTee, writes the same number.
+func WithSequenceNumbers(key string) Option {
+	return optionFunc(func(log *Logger) {
+		seqs := log.sequences
+		log.sequences = append(seqs[:len(seqs):len(seqs)], &sequence{key: key})
+	})
+}
+
+// sequence is a counter added by WithSequenceNumbers.
+type sequence struct {
+	key string
+	n   atomic.Uint64
+}
+
+// WithContextExtractor registers a function that extracts fields, such as
+// trace and span IDs, from a context.Context. The Logger's context-aware
+// methods (InfoContext, ErrorContext, etc.) add the extracted fields to each
+// entry; the other methods are unaffected. Extractors run in the order
+// they're registered, and only for entries that will be logged.
+func WithContextExtractor(extract func(context.Context) []Field) Option {
+	return optionFunc(func(log *Logger) {
+		extractors := log.contextExtractors
+		log.contextExtractors = append(extractors[:len(extractors):len(extractors)], extract)
+	})
+}
+
+// WithMessageTransformer registers a function that rewrites the message of
+// each entry before it's encoded, for example to strip control characters,
+// truncate long messages, or redact secrets. Transformers run in the order
+// they're registered, and only for entries that will be logged: they run
+// after the Core's Check, so Cores that inspect messages there, such as
+// samplers, see the original message. Passing a nil transformer is a no-op.
+//
+// See TruncateMessage for a built-in transformer.
+func WithMessageTransformer(transform func(zapcore.Level, string) string) Option {
+	return optionFunc(func(log *Logger) {
+		if transform == nil {
+			return
+		}
+		transformers := log.messageTransformers
+		log.messageTransformers = append(transformers[:len(transformers):len(transformers)], transform)
+	})
+}
+
+// TruncateMessage returns a message transformer, for use with
+// WithMessageTransformer, that shortens messages longer than max bytes to at
+// most max bytes, without splitting a UTF-8 encoded character.
+func TruncateMessage(max int) func(zapcore.Level, string) string {
+	return func(_ zapcore.Level, msg string) string {
+		if len(msg) <= max {
+			return msg
+		}
+		if max <= 0 {
+			return ""
+		}
+		end := max
+		for end > 0 && !utf8.RuneStart(msg[end]) {
+			end--
+		}
+		return msg[:end]
+	}
+}
+
+// WithSchemaHeader configures the Logger to write a one-time header entry
+// to each of its outputs, just before the first entry written to it,
+// describing the format of the output: the encoding and the keys used for
+// each element of an entry. This helps tools that parse log files without
+// out-of-band knowledge of the configuration. The header's message is
+// "zap schema header", and the description is an object under the
+// "zap_schema" key.
+//
+// The header is written exactly once per output for the Logger and any
+// Loggers derived from it, even if the first entries are logged
+// concurrently. Loggers built separately track their headers separately,
+// even if they share a WriteSyncer. The header is written regardless of the
+// Logger's level and sampling, but only once an entry is actually written.
+// The encoding and keys are only known for Loggers built from a Config; for
+// others, the description only includes its format version. See
+// zapcore.NewHeaderCore for details.
+func WithSchemaHeader() Option {
+	return optionFunc(func(log *Logger) {
+		schema := loggerSchema{encoding: log.encoding, cfg: log.encoderConfig}
+		clock := log.clock
+		log.core = zapcore.NewHeaderCore(log.core, func() (zapcore.Entry, []Field) {
+			ent := zapcore.Entry{
+				Level:   InfoLevel,
+				Time:    clock.Now(),
+				Message: _schemaHeaderMessage,
+			}
+			return ent, []Field{Object(_schemaHeaderKey, schema)}
+		})
+	})
+}
+
+// WithFileFooter configures the Logger to append the output of footer to
+// each file its outputs finish writing, for example a checksum or record
+// count that helps verify rotated files. Footers are written by
+// zapcore.TimeRotatingSyncer, exactly once for each file it closes, whether
+// its period ended or it was closed, but not when it's synced. For other
+// outputs, including plain files, which the Logger never closes, this option
+// does nothing. See zapcore.NewFooterCore for details.
+func WithFileFooter(footer func() []byte) Option {
+	return optionFunc(func(log *Logger) {
+		log.core = zapcore.NewFooterCore(log.core, footer)
+	})
+}
+
 // ErrorOutput sets the destination for errors generated by the Logger. Note
 // that this option only affects internal errors; for sample code that sends
 // error-level logs to a different location from info- and debug-level logs,
 // see the package-level AdvancedConfiguration example.
 //
+// Passing this option to Logger.WithOptions changes the error output of the
+// returned Logger only; the parent and any other children keep their own.
+// This is useful for routing internal errors of a noisy component elsewhere.
+//
 // The supplied WriteSyncer must be safe for concurrent use. The Open and
 // zapcore.Lock functions are the simplest ways to protect files with a mutex.
 func ErrorOutput(w zapcore.WriteSyncer) Option {
@@ -86,6 +320,40 @@ func Development() Option {
 	})
 }
 
+// DPanicStructured makes a Logger in development mode panic with a
+// *DPanicError on DPanic, rather than with the entry's message, so that a
+// recover handler can inspect the entry and its fields. It has no effect
+// outside development mode, or if a panic hook is set with WithPanicHook.
+func DPanicStructured() Option {
+	return optionFunc(func(log *Logger) {
+		log.dpanicStructured = true
+	})
+}
+
+// SugaredStrict makes SugaredLoggers built from the Logger strict about the
+// loosely-typed key-value pairs passed to With, WithLazy, and the methods
+// ending in "w". By default, a non-string key or a key without a value is
+// reported in a separate error entry and otherwise ignored, which is easy to
+// miss. In strict mode, these mistakes panic in development. Outside
+// development, the fields being built get a "sugarError" field describing the
+// problem, followed by the offending arguments, so the problem appears in the
+// affected entries themselves.
+func SugaredStrict() Option {
+	return optionFunc(func(log *Logger) {
+		log.strictSugar = true
+	})
+}
+
+// SugaredSortMapKeys makes the methods of SugaredLoggers built from the
+// Logger that end in "m", such as Infom, add the map's entries as fields in
+// key order. By default, they're added in Go's map iteration order, which
+// varies between calls.
+func SugaredSortMapKeys() Option {
+	return optionFunc(func(log *Logger) {
+		log.sortSugarMaps = true
+	})
+}
+
 // AddCaller configures the Logger to annotate each message with the filename,
 // line number, and function name of zap's caller. See also WithCaller.
 func AddCaller() Option {
@@ -98,6 +366,18 @@ func AddCaller() Option {
 func WithCaller(enabled bool) Option {
 	return optionFunc(func(log *Logger) {
 		log.addCaller = enabled
+		log.callerLevel = nil
+	})
+}
+
+// AddCallerForLevels is like AddCaller, but only annotates entries at or
+// above the given level. Capturing the caller has a cost on every log call,
+// so this avoids it for high-volume, low-severity entries, such as Debug
+// logs, while keeping it for the entries where it matters most.
+func AddCallerForLevels(min zapcore.Level) Option {
+	return optionFunc(func(log *Logger) {
+		log.addCaller = true
+		log.callerLevel = min
 	})
 }
 
@@ -119,6 +399,21 @@ func AddStacktrace(lvl zapcore.LevelEnabler) Option {
 	})
 }
 
+// AddStacktraceOnError configures the Logger to record a stack trace for
+// messages that carry an error, added with Error or NamedError either at the
+// log site or with With, regardless of their level. Messages without an
+// error only get a stack trace if AddStacktrace asks for one. Unlike with
+// AddStacktrace, the stack trace is taken when the message is written, after
+// sampling and other filtering, so messages that are dropped cost nothing
+// extra. Like AddStacktrace, it starts at the call site, skipping the frames
+// added with AddCallerSkip.
+func AddStacktraceOnError() Option {
+	return optionFunc(func(log *Logger) {
+		log.errorStack = true
+		log.core = zapcore.NewErrorStacktraceCore(log.core, takeErrorStacktrace)
+	})
+}
+
 // IncreaseLevel increase the level of the logger. It has no effect if
 // the passed in level tries to decrease the level of the logger.
 func IncreaseLevel(lvl zapcore.LevelEnabler) Option {
@@ -136,6 +431,22 @@ func IncreaseLevel(lvl zapcore.LevelEnabler) Option {
 	})
 }
 
+// WithLevelOverrides applies per-name level overrides from the given
+// registry to the Logger. An entry from a Logger whose name (or one of its
+// dot-separated parents) has an override is logged if and only if its level
+// is at or above the override; other entries use the Core's level.
+//
+// Entries enabled only because of an override are written directly to the
+// Core, bypassing its own level check and any sampling it does.
+func WithLevelOverrides(overrides *LevelOverrides) Option {
+	return optionFunc(func(log *Logger) {
+		log.core = &levelOverrideCore{
+			Core:      log.core,
+			overrides: overrides,
+		}
+	})
+}
+
 // WithPanicHook sets a CheckWriteHook to run on Panic/DPanic logs.
 // Zap will call this hook after writing a log statement with a Panic/DPanic level.
 //
@@ -179,8 +490,33 @@ func WithFatalHook(hook zapcore.CheckWriteHook) Option {
 
 // WithClock specifies the clock used by the logger to determine the current
 // time for logged entries. Defaults to the system clock with time.Now.
+//
+// Samplers built with zapcore.NewSamplerWithOptions count entries by their
+// time, so they follow this clock too. To drive a zapcore.TimeRotatingSyncer
+// or the sampler's own entries from the same clock, pass it with
+// zapcore.TimeRotatingClock or zapcore.SamplerClock.
 func WithClock(clock zapcore.Clock) Option {
 	return optionFunc(func(log *Logger) {
 		log.clock = clock
 	})
 }
+
+// WithReplaceFields makes With replace any field already added with With
+// that has the same key, instead of adding a duplicate. This keeps the
+// context of long-lived derived Loggers from growing without bound, for
+// example when a request Logger is repeatedly rebuilt with
+// logger.With(zap.String("step", step)).
+//
+// Keys are matched within the same namespace: after With(zap.Namespace("ns")),
+// new fields only replace fields in "ns". Fields without a key, such as those
+// built with Inline, are never replaced.
+//
+// To replace fields, the Logger re-encodes all the fields it has accumulated
+// on every call to With, so With becomes more expensive as the context grows.
+// Fields added before a later call to WithOptions, including WithLazy, are
+// kept but are no longer replaced.
+func WithReplaceFields() Option {
+	return optionFunc(func(log *Logger) {
+		log.replaceFields = true
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithEnvFields(t *testing.T) {
+	t.Setenv("ZAPTEST_FIELD_region", "us-east")
+	t.Setenv("ZAPTEST_FIELD_zone", "b")
+	t.Setenv("ZAPTEST_FIELD_empty", "")
+	t.Setenv("ZAPTEST_FIELD_", "no key")
+	t.Setenv("ZAPTEST_OTHER", "ignored")
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := New(core, WithEnvFields("ZAPTEST_FIELD_"))
+	logger.Info("hello")
+	logger.With(String("k", "v")).Info("child")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2, "Unexpected number of entries.")
+	assert.Equal(t, []Field{
+		String("empty", ""),
+		String("region", "us-east"),
+		String("zone", "b"),
+	}, entries[0].Context, "Unexpected fields from the environment.")
+	assert.Equal(t, map[string]interface{}{
+		"empty":  "",
+		"region": "us-east",
+		"zone":   "b",
+		"k":      "v",
+	}, entries[1].ContextMap(), "Expected child loggers to keep the fields.")
+}
+
+func TestWithEnvFieldsEmptyPrefix(t *testing.T) {
+	t.Setenv("ZAPTEST_FIELD_region", "us-east")
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	New(core, WithEnvFields("")).Info("hello")
+	require.Equal(t, 1, logs.Len(), "Unexpected number of entries.")
+	assert.Empty(t, logs.All()[0].Context, "Expected an empty prefix to add no fields.")
+}
+
+func TestEnvFields(t *testing.T) {
+	tests := []struct {
+		desc     string
+		prefix   string
+		environ  []string
+		expected []Field
+	}{
+		{
+			desc:   "matching and non-matching",
+			prefix: "LOG_FIELD_",
+			environ: []string{
+				"PATH=/bin",
+				"LOG_FIELD_b=2",
+				"LOG_FIELD_a=1=one",
+				"LOG_FIELDX=3",
+				"log_field_c=4",
+			},
+			expected: []Field{String("a", "1=one"), String("b", "2")},
+		},
+		{
+			desc:     "no matches",
+			prefix:   "LOG_FIELD_",
+			environ:  []string{"PATH=/bin"},
+			expected: nil,
+		},
+		{
+			desc:     "malformed entries",
+			prefix:   "LOG_FIELD_",
+			environ:  []string{"LOG_FIELD_a", "=LOG_FIELD_b"},
+			expected: nil,
+		},
+		{
+			desc:     "empty prefix",
+			prefix:   "",
+			environ:  []string{"PATH=/bin", "LOG_FIELD_a=1"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, envFields(tt.prefix, tt.environ), "Unexpected fields.")
+		})
+	}
+}
+
+func TestWithFileFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	ws := zapcore.NewTimeRotatingSyncer(path, time.Hour)
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	logger := New(zapcore.NewCore(enc, ws, DebugLevel), WithFileFooter(func() []byte {
+		return []byte("# end\n")
+	}))
+
+	logger.Info("first")
+	logger.With(String("k", "v")).Info("second")
+	require.NoError(t, logger.Sync(), "Unexpected sync error.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err, "Failed to read log file.")
+	assert.Equal(t, `{"msg":"first"}`+"\n"+`{"msg":"second","k":"v"}`+"\n# end\n", string(bs),
+		"Expected a single footer when the file is closed.")
+}
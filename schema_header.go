@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"reflect"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	_schemaHeaderMessage = "zap schema header"
+	_schemaHeaderKey     = "zap_schema"
+	_schemaVersion       = 1
+)
+
+// loggerSchema describes a Logger's output format for WithSchemaHeader.
+type loggerSchema struct {
+	encoding string
+	cfg      *zapcore.EncoderConfig
+}
+
+func (s loggerSchema) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("version", _schemaVersion)
+	if s.encoding != "" {
+		enc.AddString("encoding", s.encoding)
+	}
+	if s.cfg == nil {
+		return nil
+	}
+	if name := timeEncoderName(s.cfg.EncodeTime); name != "" {
+		enc.AddString("time_format", name)
+	}
+	return enc.AddObject("keys", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		keys := []struct{ name, key string }{
+			{"message", s.cfg.MessageKey},
+			{"level", s.cfg.LevelKey},
+			{"time", s.cfg.TimeKey},
+			{"name", s.cfg.NameKey},
+			{"caller", s.cfg.CallerKey},
+			{"function", s.cfg.FunctionKey},
+			{"stacktrace", s.cfg.StacktraceKey},
+		}
+		for _, k := range keys {
+			// Omitted keys are disabled, and don't appear in the output.
+			if k.key != "" && k.key != zapcore.OmitKey {
+				enc.AddString(k.name, k.key)
+			}
+		}
+		return nil
+	}))
+}
+
+// _timeEncoderNames names the built-in TimeEncoders, using the names
+// accepted by TimeEncoder.UnmarshalText.
+var _timeEncoderNames = map[uintptr]string{
+	reflect.ValueOf(zapcore.EpochTimeEncoder).Pointer():       "epoch",
+	reflect.ValueOf(zapcore.EpochMillisTimeEncoder).Pointer(): "millis",
+	reflect.ValueOf(zapcore.EpochNanosTimeEncoder).Pointer():  "nanos",
+	reflect.ValueOf(zapcore.ISO8601TimeEncoder).Pointer():     "iso8601",
+	reflect.ValueOf(zapcore.RFC3339TimeEncoder).Pointer():     "rfc3339",
+	reflect.ValueOf(zapcore.RFC3339NanoTimeEncoder).Pointer(): "rfc3339nano",
+}
+
+// timeEncoderName returns the name of a built-in TimeEncoder, or an empty
+// string for custom encoders. The header entry's own timestamp is always an
+// example of the format.
+func timeEncoderName(e zapcore.TimeEncoder) string {
+	if e == nil {
+		return ""
+	}
+	return _timeEncoderNames[reflect.ValueOf(e).Pointer()]
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	_shutdownMu      sync.Mutex
+	_shutdownLoggers []*Logger
+)
+
+// RegisterForShutdown adds a Logger to the set of Loggers flushed by SyncAll.
+// This lets an application flush every Logger it created, including
+// children built for individual components, with a single call on shutdown
+// instead of deferring Sync on each.
+//
+// The registry holds a strong reference to the Logger, so Loggers that are
+// discarded before shutdown must be removed with UnregisterForShutdown.
+// Registering a Logger more than once has no effect. It's safe for
+// concurrent use.
+func RegisterForShutdown(logger *Logger) {
+	_shutdownMu.Lock()
+	defer _shutdownMu.Unlock()
+
+	for _, l := range _shutdownLoggers {
+		if l == logger {
+			return
+		}
+	}
+	_shutdownLoggers = append(_shutdownLoggers, logger)
+}
+
+// UnregisterForShutdown removes a Logger previously added with
+// RegisterForShutdown. It's a no-op if the Logger isn't registered. It's safe
+// for concurrent use.
+func UnregisterForShutdown(logger *Logger) {
+	_shutdownMu.Lock()
+	defer _shutdownMu.Unlock()
+
+	for i, l := range _shutdownLoggers {
+		if l == logger {
+			_shutdownLoggers = append(_shutdownLoggers[:i], _shutdownLoggers[i+1:]...)
+			return
+		}
+	}
+}
+
+// SyncAll calls Sync on every Logger registered with RegisterForShutdown, in
+// registration order. Every Logger is synced even if some fail; the returned
+// error combines all failures. It's safe for concurrent use.
+func SyncAll() error {
+	_shutdownMu.Lock()
+	loggers := append([]*Logger(nil), _shutdownLoggers...)
+	_shutdownMu.Unlock()
+
+	var err error
+	for _, l := range loggers {
+		err = multierr.Append(err, l.Sync())
+	}
+	return err
+}
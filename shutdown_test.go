@@ -0,0 +1,79 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/internal/ztest"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withShutdownRegistry(t testing.TB) {
+	_shutdownMu.Lock()
+	prev := _shutdownLoggers
+	_shutdownLoggers = nil
+	_shutdownMu.Unlock()
+
+	t.Cleanup(func() {
+		_shutdownMu.Lock()
+		_shutdownLoggers = prev
+		_shutdownMu.Unlock()
+	})
+}
+
+func newSyncingLogger(err error) (*Logger, *ztest.Buffer) {
+	sink := &ztest.Buffer{}
+	sink.SetError(err)
+	return New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{}),
+		sink,
+		DebugLevel,
+	)), sink
+}
+
+func TestSyncAll(t *testing.T) {
+	withShutdownRegistry(t)
+
+	err1, err2 := errors.New("sync 1 failed"), errors.New("sync 2 failed")
+	failing1, _ := newSyncingLogger(err1)
+	failing2, _ := newSyncingLogger(err2)
+	healthy, healthySink := newSyncingLogger(nil)
+
+	assert.NoError(t, SyncAll(), "Expected no-op with no registered loggers.")
+
+	RegisterForShutdown(failing1)
+	RegisterForShutdown(healthy)
+	RegisterForShutdown(failing2)
+	RegisterForShutdown(failing1) // duplicate registrations are ignored
+
+	err := SyncAll()
+	assert.Equal(t, []error{err1, err2}, multierr.Errors(err), "Expected errors from every failing logger.")
+	assert.True(t, healthySink.Called(), "Expected healthy logger to be synced.")
+
+	UnregisterForShutdown(failing1)
+	UnregisterForShutdown(failing1) // no-op
+	assert.Equal(t, err2, SyncAll(), "Expected unregistered logger to be skipped.")
+}
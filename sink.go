@@ -55,6 +55,8 @@ type nopCloserSink struct{ zapcore.WriteSyncer }
 
 func (nopCloserSink) Close() error { return nil }
 
+func (s nopCloserSink) Name() string { return zapcore.DescribeWriteSyncer(s.WriteSyncer) }
+
 type sinkRegistry struct {
 	mu        sync.Mutex
 	factories map[string]func(*url.URL) (Sink, error)          // keyed by scheme
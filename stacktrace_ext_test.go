@@ -23,6 +23,7 @@ package zap_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -143,6 +144,92 @@ func TestStacktraceWithCallerSkip(t *testing.T) {
 	})
 }
 
+func TestStackSkipFiltered(t *testing.T) {
+	var f zap.Field
+	func() {
+		f = zap.StackSkipFiltered("stack", 0, nil)
+	}()
+
+	lines := strings.Split(f.String, "\n")
+	require.NotEmpty(t, lines, "Expected stacktrace to have at least one frame.")
+	assert.Contains(t, lines[0], "TestStackSkipFiltered.func", "Should start with the current function")
+	assert.Contains(t, f.String, "zap_test.TestStackSkipFiltered\n", "Should include the test function")
+	assert.NotContains(t, f.String, "testing.", "Should strip out testing frames")
+	assert.NotContains(t, f.String, "runtime.", "Should strip out runtime frames")
+	verifyNoZap(t, f.String)
+}
+
+func TestStackSkipFilteredCustomFilter(t *testing.T) {
+	var seen []string
+	f := zap.StackSkipFiltered("stack", 0, func(frame runtime.Frame) bool {
+		seen = append(seen, frame.Function)
+		return strings.HasPrefix(frame.Function, "testing.")
+	})
+
+	require.NotEmpty(t, seen, "Filter should run for each frame")
+	assert.Contains(t, seen[0], "TestStackSkipFilteredCustomFilter", "Should skip StackSkipFiltered itself")
+	assert.Contains(t, f.String, "testing.tRunner", "Should keep frames accepted by the filter")
+	assert.NotContains(t, f.String, "TestStackSkipFilteredCustomFilter", "Should drop frames rejected by the filter")
+}
+
+func TestDefaultStackFilter(t *testing.T) {
+	tests := []struct {
+		function string
+		want     bool
+	}{
+		{"main.main", true},
+		{"example.com/app/handler.(*Server).ServeHTTP", true},
+		{"go.uber.org/zap_test.TestDefaultStackFilter", true},
+		{"go.uber.org/zapper.Do", true},
+		{"runtimex.Do", true},
+		{"runtime.goexit", false},
+		{"runtime/debug.Stack", false},
+		{"testing.tRunner", false},
+		{"go.uber.org/zap.(*Logger).Info", false},
+		{"go.uber.org/zap/zapcore.(*CheckedEntry).Write", false},
+		{"example.com/app/vendor/go.uber.org/zap.(*Logger).Info", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, zap.DefaultStackFilter(runtime.Frame{Function: tt.function}),
+			"Unexpected result for %q.", tt.function)
+	}
+}
+
+func TestAddStacktraceOnErrorCallerSkip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg", StacktraceKey: "stack"})
+	core := zapcore.NewCore(encoder, zapcore.AddSync(buf), zapcore.DebugLevel)
+
+	// logError stands in for a logging helper that callers use instead of
+	// the Logger.
+	logError := func(logger *zap.Logger, msg string) {
+		logger.Error(msg, zap.Error(errors.New("fail")))
+	}
+	sugarError := func(logger *zap.SugaredLogger, msg string) {
+		logger.Errorw(msg, "error", errors.New("fail"))
+	}
+
+	logger := zap.New(core, zap.AddStacktraceOnError(), zap.AddCallerSkip(1))
+	logError(logger, "skipped")
+	logError(logger.WithOptions(zap.AddCaller()), "skipped with caller")
+	sugarError(logger.Sugar(), "sugared")
+	logError(zap.New(core, zap.AddStacktraceOnError()).WithOptions(zap.AddCallerSkip(1)), "skip added later")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4, "Unexpected number of entries.")
+	for _, line := range lines {
+		var entry struct {
+			Msg   string `json:"msg"`
+			Stack string `json:"stack"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "Unexpected invalid JSON.")
+		assert.True(t, strings.HasPrefix(entry.Stack, "go.uber.org/zap_test.TestAddStacktraceOnErrorCallerSkip\n"),
+			"Expected the stack for %q to skip the helper, got:\n%s", entry.Msg, entry.Stack)
+		assert.NotContains(t, entry.Stack, "TestAddStacktraceOnErrorCallerSkip.func",
+			"Expected the stack for %q to skip the helper.", entry.Msg)
+	}
+}
+
 // withLogger sets up a logger with a real encoder set up, so that any marshal functions are called.
 // The inbuilt observer does not call Marshal for objects/arrays, which we need for some tests.
 func withLogger(t *testing.T, fn func(logger *zap.Logger, out *bytes.Buffer)) {
@@ -208,3 +295,44 @@ func downloadDependencies(t *testing.T) []dependency {
 
 	return deps
 }
+
+func TestAddStacktraceOnError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg", StacktraceKey: "stack"})
+	core := zapcore.NewCore(encoder, zapcore.AddSync(buf), zapcore.DebugLevel)
+	logger := zap.New(core, zap.AddStacktraceOnError())
+
+	stacks := func() map[string]string {
+		out := make(map[string]string)
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry struct {
+				Msg   string `json:"msg"`
+				Stack string `json:"stack"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), "Unexpected invalid JSON.")
+			out[entry.Msg] = entry.Stack
+		}
+		buf.Reset()
+		return out
+	}
+
+	fail := errors.New("fail")
+	logger.Error("no error field")
+	logger.Info("error field", zap.Error(fail))
+	logger.With(zap.NamedError("cause", fail)).Debug("with error")
+	logger.Sugar().Warnw("sugared", "err", fail)
+	logger.Warn1("single", zap.Error(fail))
+
+	got := stacks()
+	assert.Empty(t, got["no error field"], "Expected no stack without an error field.")
+	for _, msg := range []string{"error field", "with error", "sugared", "single"} {
+		require.NotEmpty(t, got[msg], "Expected a stack for %q.", msg)
+		assert.True(t, strings.HasPrefix(got[msg], "go.uber.org/zap_test.TestAddStacktraceOnError\n"),
+			"Expected the stack for %q to start at the call site, got:\n%s", msg, got[msg])
+		verifyNoZap(t, got[msg])
+	}
+
+	// Level-based stacks are left as is.
+	logger.WithOptions(zap.AddStacktrace(zap.ErrorLevel)).Error("level stack", zap.Error(fail))
+	assert.NotEmpty(t, stacks()["level stack"], "Expected the level-based stack.")
+}
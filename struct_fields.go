@@ -0,0 +1,199 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// StructFields constructs a field that adds the exported fields of a struct,
+// or of a pointer to one, directly to the logging context, giving control
+// over what's logged with a struct tag such as `log:"name"`:
+//
+//	type Request struct {
+//		ID       string `log:"request_id"`
+//		Path     string
+//		Password string `log:"-"`
+//	}
+//
+//	logger.Info("handled", zap.StructFields(req, "log"))
+//
+// Each field's key is the name in its tagName tag, or the field's name if
+// the tag is missing or its name is empty; fields tagged "-" are skipped,
+// and fields tagged with the "omitempty" option, as in `log:"path,omitempty"`,
+// are skipped if they hold their zero value. Values are encoded as with Any,
+// except that nested structs that don't implement a marshaling interface
+// are encoded as objects following the same rules. The fields of embedded
+// structs are added as if they belonged to the outer struct, unless the
+// embedded struct has a tag name.
+//
+// A nil pointer adds no fields; passing a value that isn't a struct or a
+// pointer to one adds an error under the key "Error". So does a cyclic
+// value, such as a ring of structs pointing to each other: encoding stops
+// at the first pointer back to a struct that's already being encoded.
+func StructFields(obj interface{}, tagName string) Field {
+	return Inline(structFields{v: reflect.ValueOf(obj), tag: tagName})
+}
+
+type structFields struct {
+	v    reflect.Value
+	tag  string
+	path []uintptr // pointers followed to reach v, to detect cycles
+}
+
+func (s structFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	v := s.v
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		var err error
+		if s, err = s.follow(v); err != nil {
+			return err
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		if !v.IsValid() {
+			return nil
+		}
+		return fmt.Errorf("zap.StructFields: %v isn't a struct", v.Type())
+	}
+	return s.addFields(enc, v)
+}
+
+// structObject encodes a nested struct as an object.
+type structObject structFields
+
+func (s structObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return structFields(s).addFields(enc, s.v)
+}
+
+// follow returns s with the pointer p added to its path, or an error if p
+// is already on the path, which means that the value is cyclic.
+func (s structFields) follow(p reflect.Value) (structFields, error) {
+	ptr := p.Pointer()
+	for _, seen := range s.path {
+		if seen == ptr {
+			return s, fmt.Errorf("zap.StructFields: cycle through %v", p.Type())
+		}
+	}
+	s.path = append(s.path[:len(s.path):len(s.path)], ptr)
+	return s, nil
+}
+
+var (
+	_timeType          = reflect.TypeOf(time.Time{})
+	_objectMarshalerT  = reflect.TypeOf((*zapcore.ObjectMarshaler)(nil)).Elem()
+	_jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	_textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	_stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	_errorType         = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// isPlainStruct reports whether t is a struct that's encoded field by field
+// rather than with one of its own methods.
+func isPlainStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == _timeType {
+		return false
+	}
+	for _, iface := range []reflect.Type{_objectMarshalerT, _jsonMarshalerType, _textMarshalerType, _stringerType, _errorType} {
+		if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s structFields) addFields(enc zapcore.ObjectEncoder, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts, _ := strings.Cut(sf.Tag.Get(s.tag), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		fv := v.Field(i)
+
+		if sf.Anonymous && name == "" {
+			// Spread the fields of embedded structs, as encoding/json does.
+			ft, inner := sf.Type, s
+			if ft.Kind() == reflect.Pointer {
+				if !sf.IsExported() {
+					continue // can't read through unexported pointers
+				}
+				if fv.IsNil() {
+					continue
+				}
+				var err error
+				if inner, err = s.follow(fv); err != nil {
+					return err
+				}
+				ft, fv = ft.Elem(), fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := inner.addFields(enc, fv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+		if err := s.addValue(enc, name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s structFields) addValue(enc zapcore.ObjectEncoder, key string, fv reflect.Value) error {
+	switch {
+	case isPlainStruct(fv.Type()):
+		return enc.AddObject(key, structObject{v: fv, tag: s.tag, path: s.path})
+	case fv.Kind() == reflect.Pointer && isPlainStruct(fv.Type().Elem()):
+		if fv.IsNil() {
+			nilField(key).AddTo(enc)
+			return nil
+		}
+		inner, err := s.follow(fv)
+		if err != nil {
+			return err
+		}
+		return enc.AddObject(key, structObject{v: fv.Elem(), tag: s.tag, path: inner.path})
+	}
+	Any(key, fv.Interface()).AddTo(enc)
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+type structFieldsBase struct {
+	Service string `log:"service"`
+	Secret  string `log:"-"`
+}
+
+type structFieldsAddr struct {
+	City string `log:"city"`
+	Zip  string `log:"-"`
+}
+
+type structFieldsUser struct {
+	structFieldsBase
+	*structFieldsAddr // unexported pointer: skipped, as in encoding/json
+
+	ID       int    `log:"user_id"`
+	Name     string // no tag: field name
+	Password string `log:"-"`
+	Email    string `log:"email,omitempty"`
+	Home     structFieldsAddr
+	Work     *structFieldsAddr `log:"work"`
+	Created  time.Time         `log:"created"`
+	Err      error             `log:"err"`
+	private  string
+}
+
+func structFieldsMap(obj interface{}) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	StructFields(obj, "log").AddTo(enc)
+	return enc.Fields
+}
+
+func TestStructFields(t *testing.T) {
+	created := time.Unix(1700000000, 0).UTC()
+	u := structFieldsUser{
+		structFieldsBase: structFieldsBase{Service: "api", Secret: "s3cr3t"},
+		structFieldsAddr: &structFieldsAddr{City: "hidden"},
+		ID:               42,
+		Name:             "alice",
+		Password:         "hunter2",
+		Home:             structFieldsAddr{City: "Paris", Zip: "75001"},
+		Created:          created,
+		Err:              errors.New("boom"),
+		private:          "private",
+	}
+
+	want := map[string]interface{}{
+		"service": "api",
+		"user_id": int64(42),
+		"Name":    "alice",
+		"Home":    map[string]interface{}{"city": "Paris"},
+		"work":    nil,
+		"created": created,
+		"err":     "boom",
+	}
+	assert.Equal(t, want, structFieldsMap(u), "Unexpected fields from a struct.")
+	assert.Equal(t, want, structFieldsMap(&u), "Unexpected fields from a pointer.")
+
+	u.Email = "alice@example.com"
+	u.Work = &structFieldsAddr{City: "Berlin", Zip: "10115"}
+	got := structFieldsMap(u)
+	assert.Equal(t, "alice@example.com", got["email"], "Expected non-empty omitempty field.")
+	assert.Equal(t, map[string]interface{}{"city": "Berlin"}, got["work"], "Expected nested struct pointer as an object.")
+}
+
+type structFieldsEmbedded struct {
+	StructFieldsExported `log:"inner"`
+	*StructFieldsPtr
+	Outer string
+}
+
+type StructFieldsExported struct {
+	A string `log:"a"`
+}
+
+type StructFieldsPtr struct {
+	B string `log:"b"`
+}
+
+func TestStructFieldsEmbedded(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"inner": map[string]interface{}{"a": "1"},
+		"Outer": "o",
+	}, structFieldsMap(structFieldsEmbedded{StructFieldsExported: StructFieldsExported{A: "1"}, Outer: "o"}),
+		"Expected a tagged embedded struct as an object, and nil embedded pointers skipped.")
+
+	assert.Equal(t, map[string]interface{}{
+		"inner": map[string]interface{}{"a": ""},
+		"b":     "2",
+		"Outer": "",
+	}, structFieldsMap(structFieldsEmbedded{StructFieldsPtr: &StructFieldsPtr{B: "2"}}),
+		"Expected the fields of embedded pointers to be spread.")
+}
+
+func TestStructFieldsInvalid(t *testing.T) {
+	var nilUser *structFieldsUser
+	assert.Empty(t, structFieldsMap(nilUser), "Expected no fields from a nil pointer.")
+	assert.Empty(t, structFieldsMap(nil), "Expected no fields from nil.")
+	assert.Equal(t, map[string]interface{}{
+		"Error": "zap.StructFields: int isn't a struct",
+	}, structFieldsMap(42), "Expected an error for a non-struct.")
+}
+
+func TestStructFieldsOtherTag(t *testing.T) {
+	type tagged struct {
+		A string `log:"-" audit:"a"`
+		B string `log:"b" audit:"-"`
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	StructFields(tagged{A: "1", B: "2"}, "audit").AddTo(enc)
+	assert.Equal(t, map[string]interface{}{"a": "1"}, enc.Fields, "Expected the named tag to be used.")
+}
+
+type structFieldsNode struct {
+	Name string `log:"name"`
+	Next *structFieldsNode
+}
+
+type structFieldsRing struct {
+	*StructFieldsRingLink
+	ID int `log:"id"`
+}
+
+type StructFieldsRingLink struct {
+	Ring *structFieldsRing
+}
+
+func TestStructFieldsCyclic(t *testing.T) {
+	a := &structFieldsNode{Name: "a"}
+	b := &structFieldsNode{Name: "b", Next: a}
+	a.Next = b
+	assert.Equal(t,
+		"zap.StructFields: cycle through *zap.structFieldsNode",
+		structFieldsMap(a)["Error"],
+		"Expected an error for a cyclic value.",
+	)
+
+	r := &structFieldsRing{ID: 1}
+	r.StructFieldsRingLink = &StructFieldsRingLink{Ring: r}
+	assert.Contains(t, structFieldsMap(*r), "Error", "Expected an error for a cycle through an embedded pointer.")
+
+	shared := &structFieldsNode{Name: "shared"}
+	type pair struct{ A, B *structFieldsNode }
+	assert.Equal(t, map[string]interface{}{
+		"A": map[string]interface{}{"name": "shared", "Next": nil},
+		"B": map[string]interface{}{"name": "shared", "Next": nil},
+	}, structFieldsMap(pair{A: shared, B: shared}), "Expected pointers shared outside a cycle to be encoded.")
+}
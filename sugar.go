@@ -22,6 +22,7 @@ package zap
 
 import (
 	"fmt"
+	"sort"
 
 	"go.uber.org/zap/zapcore"
 
@@ -32,6 +33,10 @@ const (
 	_oddNumberErrMsg    = "Ignored key without a value."
 	_nonStringKeyErrMsg = "Ignored key-value pairs with non-string keys."
 	_multipleErrMsg     = "Multiple errors without a key."
+
+	// _strictErrKey holds the problems found in strict mode outside of
+	// development. See SugaredStrict.
+	_strictErrKey = "sugarError"
 )
 
 // A SugaredLogger wraps the base Logger functionality in a slower, but less
@@ -52,6 +57,11 @@ const (
 //	Infow(...any)          Structured logging (read as "info with")
 //	Infof(string, ...any)  Printf-style logging
 //	Infoln(...any)         Println-style logging
+//
+// The "ln" methods build their message exactly as fmt.Sprintln does, minus
+// the trailing newline: operands are always separated by a space, even when
+// they're strings. The plain methods use fmt.Sprint, which only adds spaces
+// between operands when neither is a string.
 type SugaredLogger struct {
 	base *Logger
 }
@@ -288,6 +298,55 @@ func (s *SugaredLogger) Fatalw(msg string, keysAndValues ...interface{}) {
 	s.log(FatalLevel, msg, nil, keysAndValues)
 }
 
+// Logm logs a message with the entries of fields as additional context. Each
+// entry is added as a field as though with Any; see SugaredSortMapKeys for
+// their order.
+func (s *SugaredLogger) Logm(lvl zapcore.Level, msg string, fields map[string]interface{}) {
+	s.logm(lvl, msg, fields)
+}
+
+// Debugm logs a message with the entries of fields as additional context.
+// See Logm for details.
+func (s *SugaredLogger) Debugm(msg string, fields map[string]interface{}) {
+	s.logm(DebugLevel, msg, fields)
+}
+
+// Infom logs a message with the entries of fields as additional context.
+// See Logm for details.
+func (s *SugaredLogger) Infom(msg string, fields map[string]interface{}) {
+	s.logm(InfoLevel, msg, fields)
+}
+
+// Warnm logs a message with the entries of fields as additional context.
+// See Logm for details.
+func (s *SugaredLogger) Warnm(msg string, fields map[string]interface{}) {
+	s.logm(WarnLevel, msg, fields)
+}
+
+// Errorm logs a message with the entries of fields as additional context.
+// See Logm for details.
+func (s *SugaredLogger) Errorm(msg string, fields map[string]interface{}) {
+	s.logm(ErrorLevel, msg, fields)
+}
+
+// DPanicm logs a message with the entries of fields as additional context.
+// In development, the logger then panics. (See DPanicLevel for details.)
+func (s *SugaredLogger) DPanicm(msg string, fields map[string]interface{}) {
+	s.logm(DPanicLevel, msg, fields)
+}
+
+// Panicm logs a message with the entries of fields as additional context,
+// then panics.
+func (s *SugaredLogger) Panicm(msg string, fields map[string]interface{}) {
+	s.logm(PanicLevel, msg, fields)
+}
+
+// Fatalm logs a message with the entries of fields as additional context,
+// then calls os.Exit.
+func (s *SugaredLogger) Fatalm(msg string, fields map[string]interface{}) {
+	s.logm(FatalLevel, msg, fields)
+}
+
 // Logln logs a message at provided level.
 // Spaces are always added between arguments.
 func (s *SugaredLogger) Logln(lvl zapcore.Level, args ...interface{}) {
@@ -352,7 +411,9 @@ func (s *SugaredLogger) log(lvl zapcore.Level, template string, fmtArgs []interf
 
 	msg := getMessage(template, fmtArgs)
 	if ce := s.base.Check(lvl, msg); ce != nil {
-		ce.Write(s.sweetenFields(context)...)
+		fields := s.sweetenFields(context)
+		s.audit(ce, template, fields)
+		ce.Write(fields...)
 	}
 }
 
@@ -364,10 +425,56 @@ func (s *SugaredLogger) logln(lvl zapcore.Level, fmtArgs []interface{}, context
 
 	msg := getMessageln(fmtArgs)
 	if ce := s.base.Check(lvl, msg); ce != nil {
-		ce.Write(s.sweetenFields(context)...)
+		fields := s.sweetenFields(context)
+		s.audit(ce, "", fields)
+		ce.Write(fields...)
+	}
+}
+
+// logm message with the entries of a map as fields.
+func (s *SugaredLogger) logm(lvl zapcore.Level, msg string, fields map[string]interface{}) {
+	if lvl < DPanicLevel && !s.base.Core().Enabled(lvl) {
+		return
+	}
+
+	if ce := s.base.Check(lvl, msg); ce != nil {
+		fs := s.mapFields(fields)
+		s.audit(ce, "", fs)
+		ce.Write(fs...)
 	}
 }
 
+// audit records an entry for WithMigrationAudit, if it's enabled.
+func (s *SugaredLogger) audit(ce *zapcore.CheckedEntry, template string, fields []Field) {
+	if a := s.base.migrationAudit; a != nil {
+		a.record(s.base, ce.Entry, template, fields)
+	}
+}
+
+func (s *SugaredLogger) mapFields(m map[string]interface{}) []Field {
+	if len(m) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, len(m))
+	if !s.base.sortSugarMaps {
+		for k, v := range m {
+			fields = append(fields, Any(k, v))
+		}
+		return fields
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, Any(k, m[k]))
+	}
+	return fields
+}
+
 // getMessage format with Sprint, Sprintf, or neither.
 func getMessage(template string, fmtArgs []interface{}) string {
 	if len(fmtArgs) == 0 {
@@ -427,7 +534,11 @@ func (s *SugaredLogger) sweetenFields(args []interface{}) []Field {
 
 		// Make sure this element isn't a dangling key.
 		if i == len(args)-1 {
-			s.base.Error(_oddNumberErrMsg, Any("ignored", args[i]))
+			if s.base.strictSugar {
+				fields = s.strictViolation(fields, _oddNumberErrMsg, Any("ignored", args[i]), args[i])
+			} else {
+				s.base.Error(_oddNumberErrMsg, Any("ignored", args[i]))
+			}
 			break
 		}
 
@@ -448,11 +559,26 @@ func (s *SugaredLogger) sweetenFields(args []interface{}) []Field {
 
 	// If we encountered any invalid key-value pairs, log an error.
 	if len(invalid) > 0 {
-		s.base.Error(_nonStringKeyErrMsg, Array("invalid", invalid))
+		if s.base.strictSugar {
+			fields = s.strictViolation(fields, _nonStringKeyErrMsg, Array("invalid", invalid), invalid.keys())
+		} else {
+			s.base.Error(_nonStringKeyErrMsg, Array("invalid", invalid))
+		}
 	}
 	return fields
 }
 
+// strictViolation handles malformed key-value pairs in strict mode. In
+// development, it panics; otherwise, it appends an error field describing
+// the problem, followed by detail about the offending arguments, to fields.
+// culprit is included in the panic message.
+func (s *SugaredLogger) strictViolation(fields []Field, msg string, detail Field, culprit interface{}) []Field {
+	if s.base.development {
+		panic(fmt.Sprintf("%s: %v", msg, culprit))
+	}
+	return append(fields, String(_strictErrKey, msg), detail)
+}
+
 type invalidPair struct {
 	position   int
 	key, value interface{}
@@ -467,6 +593,14 @@ func (p invalidPair) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 
 type invalidPairs []invalidPair
 
+func (ps invalidPairs) keys() []interface{} {
+	keys := make([]interface{}, len(ps))
+	for i := range ps {
+		keys[i] = ps[i].key
+	}
+	return keys
+}
+
 func (ps invalidPairs) MarshalLogArray(enc zapcore.ArrayEncoder) error {
 	var err error
 	for i := range ps {
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap/internal/exit"
@@ -287,6 +288,61 @@ func TestSugarFieldsInvalidPairs(t *testing.T) {
 	})
 }
 
+func TestSugarStrict(t *testing.T) {
+	t.Run("error field", func(t *testing.T) {
+		withSugar(t, DebugLevel, opts(SugaredStrict()), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+			logger.With("k", "v", 42, "foo").Info("non-string key")
+			logger.Infow("dangling key", "k", "v", "dangling")
+			logger.With("k", "v").Info("valid")
+
+			assert.Equal(t, []observer.LoggedEntry{
+				{
+					Entry: zapcore.Entry{Level: InfoLevel, Message: "non-string key"},
+					Context: []Field{
+						String("k", "v"),
+						String(_strictErrKey, _nonStringKeyErrMsg),
+						Array("invalid", invalidPairs{{2, 42, "foo"}}),
+					},
+				},
+				{
+					Entry: zapcore.Entry{Level: InfoLevel, Message: "dangling key"},
+					Context: []Field{
+						String("k", "v"),
+						String(_strictErrKey, _oddNumberErrMsg),
+						Any("ignored", "dangling"),
+					},
+				},
+				{
+					Entry:   zapcore.Entry{Level: InfoLevel, Message: "valid"},
+					Context: []Field{String("k", "v")},
+				},
+			}, logs.AllUntimed(), "Expected problems to be reported in the affected entries.")
+		})
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		withSugar(t, DebugLevel, opts(SugaredStrict(), Development()), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+			assert.PanicsWithValue(t, _nonStringKeyErrMsg+": [42 true]", func() {
+				logger.With(42, "foo", true, "bar")
+			}, "Expected non-string keys to panic.")
+			assert.PanicsWithValue(t, _oddNumberErrMsg+": dangling", func() {
+				logger.Infow("msg", "dangling")
+			}, "Expected dangling keys to panic.")
+			assert.NotPanics(t, func() {
+				logger.With("k", "v").Infow("msg", "k2", "v2")
+			}, "Unexpected panic for valid pairs.")
+			assert.Equal(t, 1, logs.Len(), "Expected only the valid entry to be logged.")
+		})
+	})
+
+	t.Run("development without strict mode", func(t *testing.T) {
+		withSugar(t, DebugLevel, opts(Development()), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+			assert.NotPanics(t, func() { logger.With(42, "foo").Info("") }, "Unexpected panic without strict mode.")
+			assert.Equal(t, 2, logs.Len(), "Expected a separate error entry.")
+		})
+	})
+}
+
 func TestSugarStructuredLogging(t *testing.T) {
 	tests := []struct {
 		msg       string
@@ -432,6 +488,26 @@ func TestSugarLnLogging(t *testing.T) {
 	}
 }
 
+func TestSugarLnLoggingSprintlnParity(t *testing.T) {
+	tests := [][]interface{}{
+		{"foo", "bar"},
+		{1, 2},
+		{"foo", 1, "bar"},
+		{1, "foo", 2.5, true},
+		{"multi\nline", nil},
+		{errors.New("fail"), []int{1, 2}},
+		{"trailing newline\n"},
+	}
+
+	for _, args := range tests {
+		withSugar(t, DebugLevel, nil, func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+			logger.Infoln(args...)
+			want := strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+			assert.Equal(t, want, logs.AllUntimed()[0].Message, "Expected message to match fmt.Sprintln for %#v.", args)
+		})
+	}
+}
+
 func TestSugarLnLoggingIgnored(t *testing.T) {
 	withSugar(t, WarnLevel, nil, func(logger *SugaredLogger, logs *observer.ObservedLogs) {
 		logger.Infoln("hello")
@@ -600,3 +676,104 @@ func BenchmarkLnSugarSingleStrArg(b *testing.B) {
 		}
 	})
 }
+
+func TestSugarMapMethods(t *testing.T) {
+	fields := map[string]interface{}{
+		"user":  "alice",
+		"count": 3,
+		"nested": map[string]interface{}{
+			"ok":   true,
+			"tags": []string{"a", "b"},
+		},
+	}
+	methods := map[zapcore.Level]func(*SugaredLogger, string, map[string]interface{}){
+		DebugLevel:  (*SugaredLogger).Debugm,
+		InfoLevel:   (*SugaredLogger).Infom,
+		WarnLevel:   (*SugaredLogger).Warnm,
+		ErrorLevel:  (*SugaredLogger).Errorm,
+		DPanicLevel: (*SugaredLogger).DPanicm,
+	}
+	for lvl, method := range methods {
+		t.Run(lvl.String(), func(t *testing.T) {
+			withSugar(t, DebugLevel, nil, func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+				method(logger, "msg", fields)
+				logger.Logm(lvl, "logm", fields)
+
+				entries := logs.AllUntimed()
+				require.Len(t, entries, 2, "Expected two entries.")
+				for _, ent := range entries {
+					assert.Equal(t, lvl, ent.Level, "Unexpected level.")
+					assert.Equal(t, map[string]interface{}{
+						"user":   "alice",
+						"count":  int64(3),
+						"nested": fields["nested"],
+					}, ent.ContextMap(), "Unexpected fields.")
+				}
+			})
+		})
+	}
+}
+
+func TestSugarMapMethodsEmpty(t *testing.T) {
+	withSugar(t, DebugLevel, nil, func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+		logger.Infom("nil", nil)
+		logger.Infom("empty", map[string]interface{}{})
+		assert.Equal(t, []observer.LoggedEntry{
+			{Entry: zapcore.Entry{Level: InfoLevel, Message: "nil"}, Context: []Field{}},
+			{Entry: zapcore.Entry{Level: InfoLevel, Message: "empty"}, Context: []Field{}},
+		}, logs.AllUntimed(), "Expected entries without fields.")
+	})
+}
+
+func TestSugarMapMethodsSorted(t *testing.T) {
+	fields := map[string]interface{}{"c": 3, "a": 1, "d": 4, "b": 2}
+	withSugar(t, DebugLevel, opts(SugaredSortMapKeys()), func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+		for i := 0; i < 10; i++ {
+			logger.Infom("msg", fields)
+		}
+		for _, ent := range logs.AllUntimed() {
+			assert.Equal(t, []Field{Int("a", 1), Int("b", 2), Int("c", 3), Int("d", 4)}, ent.Context,
+				"Expected fields in key order.")
+		}
+	})
+}
+
+func TestSugarMapMethodsDisabled(t *testing.T) {
+	withSugar(t, ErrorLevel, nil, func(logger *SugaredLogger, logs *observer.ObservedLogs) {
+		logger.Infom("msg", map[string]interface{}{"k": "v"})
+		assert.Zero(t, logs.Len(), "Expected disabled levels to be dropped.")
+	})
+}
+
+func TestSugarDisabledWithFieldsAllocs(t *testing.T) {
+	logger := New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(NewProductionEncoderConfig()),
+		&ztest.Discarder{},
+		ErrorLevel,
+	)).Sugar()
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Infow("msg", "str", "s", "int", 1, "bool", true, "err", errors.New("fail"))
+		logger.Warnw("msg", "str", "s", "int", 1, "bool", true)
+	})
+	// errors.New allocates once; the disabled logger mustn't add to that.
+	assert.Equal(t, float64(1), allocs, "Expected disabled sugared logging not to process fields.")
+}
+
+func BenchmarkSugarDisabledWithFields(b *testing.B) {
+	b.Run("Logger", func(b *testing.B) {
+		withLogger(b, ErrorLevel, nil /* opts */, func(log *Logger, logs *observer.ObservedLogs) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Info("hello world", String("str", "s"), Int("int", i), Bool("bool", true))
+			}
+		})
+	})
+	b.Run("Sugar", func(b *testing.B) {
+		withSugar(b, ErrorLevel, nil /* opts */, func(log *SugaredLogger, logs *observer.ObservedLogs) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Infow("hello world", "str", "s", "int", i, "bool", true)
+			}
+		})
+	})
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package zapcloud ships completed log files to remote storage.
+//
+// The package doesn't depend on any cloud SDK. Instead, it describes the
+// storage it needs with the Uploader interface, which is straightforward to
+// satisfy with the S3, GCS, or Azure clients:
+//
+//	type s3Uploader struct {
+//		client *s3.Client
+//		bucket string
+//	}
+//
+//	func (u s3Uploader) Upload(ctx context.Context, name string, r io.Reader) error {
+//		_, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+//			Bucket: &u.bucket,
+//			Key:    aws.String(filepath.Base(name)),
+//			Body:   r,
+//		})
+//		return err
+//	}
+package zapcloud // import "go.uber.org/zap/zapcloud"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Uploader stores a completed log file remotely. name is the file's local
+// path; implementations choose the remote name.
+type Uploader interface {
+	Upload(ctx context.Context, name string, r io.Reader) error
+}
+
+// Option configures an UploadingSyncer.
+type Option interface {
+	apply(*UploadingSyncer)
+}
+
+type optionFunc func(*UploadingSyncer)
+
+func (f optionFunc) apply(s *UploadingSyncer) {
+	f(s)
+}
+
+// OnError sets a function that's called when a file can't be uploaded or
+// removed after uploading. Files that fail to upload are kept locally and
+// aren't retried. By default, errors are written to standard error.
+func OnError(fn func(name string, err error)) Option {
+	return optionFunc(func(s *UploadingSyncer) {
+		s.onError = fn
+	})
+}
+
+// UploadTimeout limits how long each upload may take. By default, uploads
+// aren't limited.
+func UploadTimeout(d time.Duration) Option {
+	return optionFunc(func(s *UploadingSyncer) {
+		s.timeout = d
+	})
+}
+
+// UploadingSyncer is a WriteSyncer that writes to a local WriteSyncer and
+// uploads the files that it completes. Use NewUploadingSyncer to build one.
+type UploadingSyncer struct {
+	local    zapcore.WriteSyncer
+	uploader Uploader
+	onError  func(name string, err error)
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	pending []string
+	closed  bool
+	wake    chan struct{} // signals the worker that pending changed
+	done    chan struct{} // closed when the worker exits
+}
+
+var _ zapcore.WriteSyncer = (*UploadingSyncer)(nil)
+
+// NewUploadingSyncer builds a WriteSyncer that writes to local and uploads
+// each file that local finishes writing, deleting the local copy once it's
+// uploaded.
+//
+// If local is a *zapcore.TimeRotatingSyncer, or another WriteSyncer with an
+// OnClose method that reports the names of closed files, files are uploaded
+// as they're rotated:
+//
+//	local := zapcore.NewTimeRotatingSyncer("/var/log/app-%Y%m%d%H.log", time.Hour)
+//	ws := zapcloud.NewUploadingSyncer(local, uploader)
+//	defer ws.Close()
+//
+// Other WriteSyncers can report completed files by calling Enqueue.
+//
+// Uploads happen one at a time on a background goroutine, so logging never
+// waits for them. Close stops accepting files, waits for queued uploads to
+// finish, and closes local if it's an io.Closer.
+func NewUploadingSyncer(local zapcore.WriteSyncer, uploader Uploader, opts ...Option) *UploadingSyncer {
+	s := &UploadingSyncer{
+		local:    local,
+		uploader: uploader,
+		onError:  reportError,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	if n, ok := local.(interface{ OnClose(func(string)) }); ok {
+		n.OnClose(s.Enqueue)
+	}
+	go s.run()
+	return s
+}
+
+func reportError(name string, err error) {
+	fmt.Fprintf(os.Stderr, "%v zapcloud: failed to upload %v: %v\n", time.Now().UTC(), name, err)
+}
+
+// Write writes to the local WriteSyncer.
+func (s *UploadingSyncer) Write(bs []byte) (int, error) {
+	return s.local.Write(bs)
+}
+
+// Sync syncs the local WriteSyncer. It doesn't wait for uploads.
+func (s *UploadingSyncer) Sync() error {
+	return s.local.Sync()
+}
+
+// Describe describes the local WriteSyncer. See zapcore.DescribeWriteSyncer.
+func (s *UploadingSyncer) Describe() string {
+	return "uploading " + zapcore.DescribeWriteSyncer(s.local)
+}
+
+// Enqueue queues a completed file for upload. It never blocks. Files
+// enqueued after Close are ignored.
+func (s *UploadingSyncer) Enqueue(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.pending = append(s.pending, name)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close closes the local WriteSyncer, if it's an io.Closer, and then waits
+// for queued uploads to finish; use UploadTimeout to bound the wait. Closing
+// the local WriteSyncer typically completes its current file, which is
+// uploaded too.
+func (s *UploadingSyncer) Close() error {
+	var err error
+	if c, ok := s.local.(io.Closer); ok {
+		err = c.Close()
+	}
+
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.wake)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+	return err
+}
+
+func (s *UploadingSyncer) run() {
+	defer close(s.done)
+
+	for range s.wake {
+		for {
+			name, ok := s.next()
+			if !ok {
+				break
+			}
+			s.upload(name)
+		}
+	}
+	// Drain files enqueued before Close.
+	for {
+		name, ok := s.next()
+		if !ok {
+			return
+		}
+		s.upload(name)
+	}
+}
+
+func (s *UploadingSyncer) next() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return "", false
+	}
+	name := s.pending[0]
+	s.pending = s.pending[1:]
+	return name, true
+}
+
+func (s *UploadingSyncer) upload(name string) {
+	if err := s.uploadFile(name); err != nil {
+		s.onError(name, err)
+		return
+	}
+	if err := os.Remove(name); err != nil {
+		s.onError(name, err)
+	}
+}
+
+func (s *UploadingSyncer) uploadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	return s.uploader.Upload(ctx, name, f)
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcloud
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/internal/ztest"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUploader records uploaded files, optionally waiting on gate before
+// each upload and failing uploads of files named in fail.
+type fakeUploader struct {
+	gate chan struct{}
+	fail map[string]bool
+
+	mu       sync.Mutex
+	uploaded map[string]string
+}
+
+func (u *fakeUploader) Upload(ctx context.Context, name string, r io.Reader) error {
+	if u.gate != nil {
+		select {
+		case <-u.gate:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if u.fail[filepath.Base(name)] {
+		return errors.New("upload failed")
+	}
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.uploaded == nil {
+		u.uploaded = make(map[string]string)
+	}
+	u.uploaded[filepath.Base(name)] = string(bs)
+	return nil
+}
+
+func (u *fakeUploader) files() map[string]string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	files := make(map[string]string, len(u.uploaded))
+	for k, v := range u.uploaded {
+		files[k] = v
+	}
+	return files
+}
+
+func newRotatingSyncer(t *testing.T) (*zapcore.TimeRotatingSyncer, *ztest.MockClock, string) {
+	dir := t.TempDir()
+	clock := ztest.NewMockClock()
+	ws := zapcore.NewTimeRotatingSyncer(
+		filepath.Join(dir, "app-%H%M.log"),
+		time.Minute,
+		zapcore.TimeRotatingClock(clock),
+	)
+	return ws, clock, dir
+}
+
+func write(t *testing.T, ws zapcore.WriteSyncer, s string) {
+	t.Helper()
+	_, err := ws.Write([]byte(s))
+	require.NoError(t, err, "Unexpected write error.")
+}
+
+func TestUploadingSyncer(t *testing.T) {
+	local, clock, dir := newRotatingSyncer(t)
+	uploader := &fakeUploader{}
+	ws := NewUploadingSyncer(local, uploader)
+
+	first := clock.Now().Format("app-1504.log")
+	write(t, ws, "a\n")
+	write(t, ws, "b\n")
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+
+	clock.Add(time.Minute)
+	second := clock.Now().Format("app-1504.log")
+	write(t, ws, "c\n")
+
+	assert.Eventually(t, func() bool {
+		return len(uploader.files()) == 1
+	}, time.Second, time.Millisecond, "Expected rotated file to be uploaded.")
+	assert.Equal(t, "a\nb\n", uploader.files()[first], "Unexpected uploaded content.")
+	assert.NoFileExists(t, filepath.Join(dir, first), "Expected uploaded file to be removed.")
+
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	assert.Equal(t, map[string]string{first: "a\nb\n", second: "c\n"}, uploader.files(), "Expected the last file to be uploaded on close.")
+	assert.NoFileExists(t, filepath.Join(dir, second), "Expected uploaded file to be removed.")
+}
+
+func TestUploadingSyncerDescribe(t *testing.T) {
+	ws := NewUploadingSyncer(zapcore.Lock(os.Stderr), &fakeUploader{})
+	defer ws.Close()
+	assert.Equal(t, "uploading "+os.Stderr.Name(), zapcore.DescribeWriteSyncer(ws), "Unexpected description.")
+}
+
+func TestUploadingSyncerDoesntBlock(t *testing.T) {
+	local, clock, _ := newRotatingSyncer(t)
+	uploader := &fakeUploader{gate: make(chan struct{})}
+	ws := NewUploadingSyncer(local, uploader)
+
+	// With uploads stalled, rotations and writes must still proceed.
+	for i := 0; i < 5; i++ {
+		write(t, ws, "line\n")
+		clock.Add(time.Minute)
+	}
+	assert.Empty(t, uploader.files(), "Expected uploads to be stalled.")
+
+	close(uploader.gate)
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	assert.Len(t, uploader.files(), 5, "Expected every file to be uploaded eventually.")
+}
+
+func TestUploadingSyncerErrors(t *testing.T) {
+	local, clock, dir := newRotatingSyncer(t)
+	failing := clock.Now().Format("app-1504.log")
+	uploader := &fakeUploader{fail: map[string]bool{failing: true}}
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+	ws := NewUploadingSyncer(local, uploader, OnError(func(name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, filepath.Base(name))
+		assert.EqualError(t, err, "upload failed", "Unexpected error.")
+	}))
+
+	write(t, ws, "a\n")
+	clock.Add(time.Minute)
+	write(t, ws, "b\n")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+
+	assert.Equal(t, []string{failing}, failed, "Expected failed upload to be reported.")
+	assert.FileExists(t, filepath.Join(dir, failing), "Expected failed file to be kept.")
+	assert.Len(t, uploader.files(), 1, "Expected other files to be uploaded.")
+}
+
+func TestUploadingSyncerTimeout(t *testing.T) {
+	local, _, _ := newRotatingSyncer(t)
+	uploader := &fakeUploader{gate: make(chan struct{})}
+
+	errs := make(chan error, 1)
+	ws := NewUploadingSyncer(local, uploader, UploadTimeout(time.Millisecond), OnError(func(_ string, err error) {
+		errs <- err
+	}))
+	write(t, ws, "a\n")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	assert.ErrorIs(t, <-errs, context.DeadlineExceeded, "Expected upload to time out.")
+}
+
+func TestUploadingSyncerEnqueue(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "manual.log")
+	require.NoError(t, os.WriteFile(name, []byte("manual\n"), 0o644), "Failed to write file.")
+
+	uploader := &fakeUploader{}
+	ws := NewUploadingSyncer(zapcore.AddSync(io.Discard), uploader)
+	ws.Enqueue(name)
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	ws.Enqueue(name) // ignored after close
+
+	assert.Equal(t, map[string]string{"manual.log": "manual\n"}, uploader.files(), "Expected enqueued file to be uploaded.")
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"errors"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+var errAsyncSyncerClosed = errors.New("async syncer is closed")
+
+// AsyncOption configures an AsyncSyncer.
+type AsyncOption interface {
+	apply(*AsyncSyncer)
+}
+
+type asyncOptionFunc func(*AsyncSyncer)
+
+func (f asyncOptionFunc) apply(s *AsyncSyncer) {
+	f(s)
+}
+
+// AsyncBlockWhenFull makes writes to a full AsyncSyncer wait for room in the
+// queue instead of dropping the line. This trades hot-path latency for
+// completeness: a slow destination eventually slows down logging callers.
+func AsyncBlockWhenFull() AsyncOption {
+	return asyncOptionFunc(func(s *AsyncSyncer) {
+		s.block = true
+	})
+}
+
+// asyncItem is either a line to write or a request to sync.
+type asyncItem struct {
+	line []byte
+	sync chan error
+}
+
+// AsyncSyncer is a WriteSyncer that hands lines off to a background
+// goroutine, which writes them to another WriteSyncer. Use NewAsyncSyncer to
+// build one.
+type AsyncSyncer struct {
+	ws     WriteSyncer
+	onDrop func([]byte)
+	block  bool
+
+	// mu guards sends on queue against closing it.
+	mu     sync.RWMutex
+	closed bool
+	queue  chan asyncItem
+
+	done chan struct{}
+	err  error // errors from the background goroutine after it stops
+}
+
+var _ WriteSyncer = (*AsyncSyncer)(nil)
+
+// NewAsyncSyncer builds a WriteSyncer that enqueues each line for a
+// background goroutine to write to ws, so that logging never waits on slow
+// I/O. Up to bufferLines lines are queued.
+//
+// By default, a line written while the queue is full is dropped, and onDrop,
+// if non-nil, is called with it on the writing goroutine; use it to count or
+// report dropped lines. With the AsyncBlockWhenFull option, such writes wait
+// for room in the queue instead.
+//
+// Errors from writing to ws are reported by the next call to Sync or Close.
+// Both wait for all lines queued before them to be written; Sync then syncs
+// ws. Close additionally stops the background goroutine, after which writes
+// fail. Close must be called to release the goroutine.
+func NewAsyncSyncer(ws WriteSyncer, bufferLines int, onDrop func([]byte), opts ...AsyncOption) *AsyncSyncer {
+	if bufferLines < 0 {
+		bufferLines = 0
+	}
+	s := &AsyncSyncer{
+		ws:     ws,
+		onDrop: onDrop,
+		queue:  make(chan asyncItem, bufferLines),
+		done:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	go s.run()
+	return s
+}
+
+// Write enqueues a copy of bs, so callers may reuse bs as soon as Write
+// returns. It reports success even if the line is dropped.
+func (s *AsyncSyncer) Write(bs []byte) (int, error) {
+	item := asyncItem{line: append([]byte(nil), bs...)}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return 0, errAsyncSyncerClosed
+	}
+	if s.block {
+		s.queue <- item
+		return len(bs), nil
+	}
+	select {
+	case s.queue <- item:
+	default:
+		if s.onDrop != nil {
+			s.onDrop(item.line)
+		}
+	}
+	return len(bs), nil
+}
+
+// Sync waits for all lines queued before it to be written, then syncs the
+// underlying WriteSyncer. It returns any errors encountered since the
+// previous Sync.
+func (s *AsyncSyncer) Sync() error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return errAsyncSyncerClosed
+	}
+	req := make(chan error, 1)
+	s.queue <- asyncItem{sync: req}
+	s.mu.RUnlock()
+
+	return <-req
+}
+
+// Describe describes the wrapped WriteSyncer. See DescribeWriteSyncer.
+func (s *AsyncSyncer) Describe() string {
+	return "async " + DescribeWriteSyncer(s.ws)
+}
+
+// Close writes all queued lines, syncs the underlying WriteSyncer, and stops
+// the background goroutine. It returns any errors encountered since the
+// previous Sync. Calling Close more than once is safe.
+func (s *AsyncSyncer) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	<-s.done
+	return s.err
+}
+
+func (s *AsyncSyncer) run() {
+	defer close(s.done)
+
+	var errs error
+	for item := range s.queue {
+		if item.sync != nil {
+			item.sync <- multierr.Append(errs, s.ws.Sync())
+			errs = nil
+			continue
+		}
+		if _, err := s.ws.Write(item.line); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+	s.err = multierr.Append(errs, s.ws.Sync())
+}
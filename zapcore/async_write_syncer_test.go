@@ -0,0 +1,193 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedSyncer is a WriteSyncer whose writes wait until the gate is opened.
+type gatedSyncer struct {
+	started chan struct{} // receives when a write starts, if there's room
+	gate    chan struct{}
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	synced int
+}
+
+func newGatedSyncer() *gatedSyncer {
+	return &gatedSyncer{
+		started: make(chan struct{}, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (s *gatedSyncer) Write(bs []byte) (int, error) {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.gate
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(bs)
+}
+
+func (s *gatedSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced++
+	return nil
+}
+
+func (s *gatedSyncer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func TestAsyncSyncerDropsWhenFull(t *testing.T) {
+	ws := newGatedSyncer()
+	var dropped []string
+	s := NewAsyncSyncer(ws, 2, func(line []byte) {
+		dropped = append(dropped, string(line))
+	})
+
+	_, err := s.Write([]byte("a\n"))
+	require.NoError(t, err, "Unexpected error writing.")
+	<-ws.started // "a" is dequeued and being written
+
+	for _, line := range []string{"b\n", "c\n", "d\n", "e\n"} {
+		n, err := s.Write([]byte(line))
+		require.NoError(t, err, "Dropped writes shouldn't fail.")
+		assert.Equal(t, len(line), n, "Unexpected number of bytes written.")
+	}
+	assert.Equal(t, []string{"d\n", "e\n"}, dropped, "Expected writes beyond the buffer to be dropped.")
+
+	close(ws.gate)
+	require.NoError(t, s.Close(), "Unexpected error closing.")
+	assert.Equal(t, "a\nb\nc\n", ws.String(), "Unexpected lines written.")
+}
+
+func TestAsyncSyncerBlockWhenFull(t *testing.T) {
+	ws := newGatedSyncer()
+	s := NewAsyncSyncer(ws, 1, func([]byte) {
+		t.Error("Lines shouldn't be dropped when blocking.")
+	}, AsyncBlockWhenFull())
+
+	_, err := s.Write([]byte("a\n"))
+	require.NoError(t, err, "Unexpected error writing.")
+	<-ws.started
+	_, err = s.Write([]byte("b\n")) // fills the queue
+	require.NoError(t, err, "Unexpected error writing.")
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		_, err := s.Write([]byte("c\n"))
+		assert.NoError(t, err, "Unexpected error writing.")
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("Expected write to a full syncer to block.")
+	case <-time.After(ztest.Timeout(10 * time.Millisecond)):
+	}
+
+	close(ws.gate)
+	<-written
+	require.NoError(t, s.Close(), "Unexpected error closing.")
+	assert.Equal(t, "a\nb\nc\n", ws.String(), "Unexpected lines written.")
+}
+
+func TestAsyncSyncerDrain(t *testing.T) {
+	ws := newGatedSyncer()
+	close(ws.gate)
+	s := NewAsyncSyncer(ws, 1000, nil)
+
+	var want bytes.Buffer
+	for i := 0; i < 100; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		want.WriteString(line)
+		_, err := s.Write([]byte(line))
+		require.NoError(t, err, "Unexpected error writing.")
+	}
+	require.NoError(t, s.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, want.String(), ws.String(), "Sync should drain queued lines.")
+	assert.Equal(t, 1, ws.synced, "Sync should sync the underlying WriteSyncer.")
+
+	for i := 100; i < 200; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		want.WriteString(line)
+		_, err := s.Write([]byte(line))
+		require.NoError(t, err, "Unexpected error writing.")
+	}
+	require.NoError(t, s.Close(), "Unexpected error closing.")
+	assert.Equal(t, want.String(), ws.String(), "Close should drain queued lines.")
+	assert.Equal(t, 2, ws.synced, "Close should sync the underlying WriteSyncer.")
+
+	_, err := s.Write([]byte("late\n"))
+	assert.Error(t, err, "Writes after Close should fail.")
+	assert.Error(t, s.Sync(), "Sync after Close should fail.")
+	assert.NoError(t, s.Close(), "Closing twice should be safe.")
+}
+
+func TestAsyncSyncerCopiesLines(t *testing.T) {
+	ws := newGatedSyncer()
+	s := NewAsyncSyncer(ws, 1, nil)
+
+	line := []byte("foo\n")
+	_, err := s.Write(line)
+	require.NoError(t, err, "Unexpected error writing.")
+	copy(line, "bar\n")
+
+	close(ws.gate)
+	require.NoError(t, s.Close(), "Unexpected error closing.")
+	assert.Equal(t, "foo\n", ws.String(), "Expected Write to copy its input.")
+}
+
+func TestAsyncSyncerErrors(t *testing.T) {
+	ws := &ztest.FailWriter{}
+	ws.SetError(errors.New("sync failed"))
+	s := NewAsyncSyncer(ws, 10, nil)
+	assert.Equal(t, "async *ztest.FailWriter", DescribeWriteSyncer(s), "Unexpected description.")
+
+	_, err := s.Write([]byte("foo\n"))
+	require.NoError(t, err, "Write errors are reported asynchronously.")
+	err = s.Sync()
+	assert.ErrorContains(t, err, "failed", "Expected write error from Sync.")
+	assert.ErrorContains(t, err, "sync failed", "Expected sync error from Sync.")
+
+	assert.ErrorContains(t, s.Close(), "sync failed", "Expected sync error from Close.")
+}
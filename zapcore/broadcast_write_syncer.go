@@ -0,0 +1,112 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "sync"
+
+// BroadcastSyncer is a WriteSyncer that writes to another WriteSyncer and
+// also publishes each line to in-process subscribers, for example to serve
+// a live tail of the logs from an admin endpoint. Use NewBroadcastSyncer to
+// build one.
+type BroadcastSyncer struct {
+	ws          WriteSyncer
+	bufferLines int
+
+	// mu guards sends on subscriber channels against closing them.
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]chan []byte
+}
+
+var _ WriteSyncer = (*BroadcastSyncer)(nil)
+
+// NewBroadcastSyncer builds a WriteSyncer that writes each line to ws and
+// publishes a copy of it to every subscriber registered with Subscribe.
+//
+// Each subscriber has its own queue of up to bufferLines lines. Logging never
+// waits on subscribers: when a subscriber's queue is full, lines published to
+// it are dropped until it catches up.
+func NewBroadcastSyncer(ws WriteSyncer, bufferLines int) *BroadcastSyncer {
+	if bufferLines < 0 {
+		bufferLines = 0
+	}
+	return &BroadcastSyncer{
+		ws:          ws,
+		bufferLines: bufferLines,
+		subs:        make(map[int]chan []byte),
+	}
+}
+
+// Write writes bs to the underlying WriteSyncer and publishes a copy of it
+// to all subscribers. All subscribers receive the same copy, so they must
+// not modify it.
+func (s *BroadcastSyncer) Write(bs []byte) (int, error) {
+	n, err := s.ws.Write(bs)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.subs) == 0 {
+		return n, err
+	}
+	line := append([]byte(nil), bs...)
+	for _, ch := range s.subs {
+		select {
+		case ch <- line:
+		default:
+			// Subscriber is behind; drop the line rather than block logging.
+		}
+	}
+	return n, err
+}
+
+// Sync syncs the underlying WriteSyncer.
+func (s *BroadcastSyncer) Sync() error {
+	return s.ws.Sync()
+}
+
+// Describe describes the wrapped WriteSyncer. See DescribeWriteSyncer.
+func (s *BroadcastSyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
+
+// Subscribe registers a new subscriber and returns the channel on which it
+// receives lines written after the call. Calling cancel unregisters the
+// subscriber and closes the channel; it's safe to call more than once.
+func (s *BroadcastSyncer) Subscribe() (lines <-chan []byte, cancel func()) {
+	ch := make(chan []byte, s.bufferLines)
+
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.subs[id] = ch
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, id)
+			close(ch)
+			s.mu.Unlock()
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func drain(ch <-chan []byte) []string {
+	var lines []string
+	for {
+		select {
+		case line, ok := <-ch:
+			if !ok {
+				return lines
+			}
+			lines = append(lines, string(line))
+		default:
+			return lines
+		}
+	}
+}
+
+func TestBroadcastSyncerSubscribers(t *testing.T) {
+	sink := &ztest.Buffer{}
+	ws := NewBroadcastSyncer(sink, 10)
+
+	_, err := ws.Write([]byte("before\n"))
+	require.NoError(t, err, "Unexpected write error.")
+
+	first, cancelFirst := ws.Subscribe()
+	defer cancelFirst()
+	second, cancelSecond := ws.Subscribe()
+	defer cancelSecond()
+
+	line := []byte("hello\n")
+	n, err := ws.Write(line)
+	require.NoError(t, err, "Unexpected write error.")
+	assert.Equal(t, len(line), n, "Unexpected number of bytes written.")
+	copy(line, "HELLO") // zap reuses buffers after writing
+
+	assert.Equal(t, []string{"hello\n"}, drain(first), "Unexpected lines for first subscriber.")
+	assert.Equal(t, []string{"hello\n"}, drain(second), "Unexpected lines for second subscriber.")
+	assert.Equal(t, "before\nhello\n", sink.String(), "Unexpected output in underlying syncer.")
+
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.True(t, sink.Called(), "Expected underlying syncer to be synced.")
+}
+
+func TestBroadcastSyncerCancel(t *testing.T) {
+	ws := NewBroadcastSyncer(&ztest.Discarder{}, 10)
+
+	canceled, cancel := ws.Subscribe()
+	kept, cancelKept := ws.Subscribe()
+	defer cancelKept()
+
+	_, err := ws.Write([]byte("one\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	cancel()
+	cancel() // canceling twice is safe
+	_, err = ws.Write([]byte("two\n"))
+	require.NoError(t, err, "Unexpected write error.")
+
+	assert.Equal(t, []string{"one\n"}, drain(canceled), "Expected no lines after cancellation.")
+	_, ok := <-canceled
+	assert.False(t, ok, "Expected channel to be closed after cancellation.")
+	assert.Equal(t, []string{"one\n", "two\n"}, drain(kept), "Unexpected lines for remaining subscriber.")
+}
+
+func TestBroadcastSyncerSlowSubscriber(t *testing.T) {
+	ws := NewBroadcastSyncer(&ztest.Discarder{}, 2)
+	lines, cancel := ws.Subscribe()
+	defer cancel()
+
+	for _, line := range []string{"a", "b", "c", "d"} {
+		_, err := ws.Write([]byte(line))
+		require.NoError(t, err, "Writes must not block on a full subscriber.")
+	}
+	assert.Equal(t, []string{"a", "b"}, drain(lines), "Expected lines beyond the buffer to be dropped.")
+}
+
+func TestBroadcastSyncerWriteError(t *testing.T) {
+	ws := NewBroadcastSyncer(&ztest.FailWriter{}, 1)
+	lines, cancel := ws.Subscribe()
+	defer cancel()
+
+	_, err := ws.Write([]byte("oops"))
+	assert.Error(t, err, "Expected error from underlying syncer.")
+	assert.Equal(t, []string{"oops"}, drain(lines), "Expected line to be published despite the write error.")
+}
@@ -167,6 +167,11 @@ func (s *BufferedWriteSyncer) Sync() error {
 	return multierr.Append(err, s.WS.Sync())
 }
 
+// Describe describes the buffered WriteSyncer. See DescribeWriteSyncer.
+func (s *BufferedWriteSyncer) Describe() string {
+	return "buffered " + DescribeWriteSyncer(s.WS)
+}
+
 // flushLoop flushes the buffer at the configured interval until Stop is
 // called.
 func (s *BufferedWriteSyncer) flushLoop() {
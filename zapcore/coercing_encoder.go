@@ -0,0 +1,283 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+)
+
+// A CoercionRule converts the field with a given key to another type before
+// it's encoded. See NewCoercingEncoder.
+type CoercionRule struct {
+	// Key is the field's key. Fields in a namespace are matched by their
+	// dotted path: "user_id" only matches top-level fields, and
+	// "request.user_id" matches a "user_id" field in the "request"
+	// namespace.
+	Key string
+	// To is the field's new type. StringType, Int64Type and Float64Type are
+	// supported.
+	To FieldType
+}
+
+// NewCoercingEncoder wraps an Encoder so that fields matching one of the
+// rules are converted to the rule's type before they're encoded, for example
+// to log all IDs as strings for a pipeline with a strict schema. Rules apply
+// both to fields added with With and to fields logged with an entry.
+//
+// Numbers, booleans, times, durations and byte strings can be converted to
+// strings; times use RFC 3339 with nanoseconds. Integers, and strings that
+// parse as integers, can be converted to Int64Type. Numbers, and strings
+// that parse as numbers, can be converted to Float64Type. Fields that can't
+// be converted, including objects and arrays, are encoded unchanged, as are
+// fields nested inside objects.
+//
+// If several rules have the same key, the last one wins.
+func NewCoercingEncoder(enc Encoder, rules []CoercionRule) Encoder {
+	if len(rules) == 0 {
+		return enc
+	}
+	byKey := make(map[string]FieldType, len(rules))
+	for _, r := range rules {
+		byKey[r.Key] = r.To
+	}
+	return &coercingEncoder{Encoder: enc, rules: byKey}
+}
+
+type coercingEncoder struct {
+	Encoder
+
+	rules map[string]FieldType
+	ns    []string // namespaces opened with With
+}
+
+func (e *coercingEncoder) Clone() Encoder {
+	return &coercingEncoder{
+		Encoder: e.Encoder.Clone(),
+		rules:   e.rules,
+		ns:      append([]string(nil), e.ns...),
+	}
+}
+
+func (e *coercingEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	path := e.ns
+	var coerced []Field
+	for i, f := range fields {
+		if f.Type == NamespaceType {
+			path = append(path[:len(path):len(path)], f.Key)
+			continue
+		}
+		to, ok := e.rules[pathKey(path, f.Key)]
+		if !ok {
+			continue
+		}
+		if nf, ok := coerceField(f, to); ok {
+			if coerced == nil {
+				coerced = append([]Field(nil), fields...)
+			}
+			coerced[i] = nf
+		}
+	}
+	if coerced != nil {
+		fields = coerced
+	}
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+// addField adds a field from With to the wrapped Encoder, coercing it if a
+// rule matches.
+func (e *coercingEncoder) addField(f Field) {
+	if to, ok := e.rules[pathKey(e.ns, f.Key)]; ok {
+		if nf, ok := coerceField(f, to); ok {
+			f = nf
+		}
+	}
+	f.AddTo(e.Encoder)
+}
+
+func (e *coercingEncoder) OpenNamespace(key string) {
+	e.ns = append(e.ns, key)
+	e.Encoder.OpenNamespace(key)
+}
+
+func (e *coercingEncoder) AddBool(k string, v bool) {
+	var i int64
+	if v {
+		i = 1
+	}
+	e.addField(Field{Key: k, Type: BoolType, Integer: i})
+}
+
+func (e *coercingEncoder) AddByteString(k string, v []byte) {
+	e.addField(Field{Key: k, Type: ByteStringType, Interface: v})
+}
+
+func (e *coercingEncoder) AddDuration(k string, v time.Duration) {
+	e.addField(Field{Key: k, Type: DurationType, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddFloat64(k string, v float64) {
+	e.addField(Field{Key: k, Type: Float64Type, Integer: int64(math.Float64bits(v))})
+}
+
+func (e *coercingEncoder) AddFloat32(k string, v float32) {
+	e.addField(Field{Key: k, Type: Float32Type, Integer: int64(math.Float32bits(v))})
+}
+
+func (e *coercingEncoder) AddInt(k string, v int) {
+	e.addField(Field{Key: k, Type: Int64Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddInt64(k string, v int64) {
+	e.addField(Field{Key: k, Type: Int64Type, Integer: v})
+}
+
+func (e *coercingEncoder) AddInt32(k string, v int32) {
+	e.addField(Field{Key: k, Type: Int32Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddInt16(k string, v int16) {
+	e.addField(Field{Key: k, Type: Int16Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddInt8(k string, v int8) {
+	e.addField(Field{Key: k, Type: Int8Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddString(k string, v string) {
+	e.addField(Field{Key: k, Type: StringType, String: v})
+}
+
+func (e *coercingEncoder) AddTime(k string, v time.Time) {
+	e.addField(Field{Key: k, Type: TimeFullType, Interface: v})
+}
+
+func (e *coercingEncoder) AddUint(k string, v uint) {
+	e.addField(Field{Key: k, Type: Uint64Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddUint64(k string, v uint64) {
+	e.addField(Field{Key: k, Type: Uint64Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddUint32(k string, v uint32) {
+	e.addField(Field{Key: k, Type: Uint32Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddUint16(k string, v uint16) {
+	e.addField(Field{Key: k, Type: Uint16Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddUint8(k string, v uint8) {
+	e.addField(Field{Key: k, Type: Uint8Type, Integer: int64(v)})
+}
+
+func (e *coercingEncoder) AddUintptr(k string, v uintptr) {
+	e.addField(Field{Key: k, Type: UintptrType, Integer: int64(v)})
+}
+
+// pathKey returns the dotted path of key within the namespaces in path.
+func pathKey(path []string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return strings.Join(path, ".") + "." + key
+}
+
+// coerceField converts f to the given type, reporting whether it could.
+func coerceField(f Field, to FieldType) (Field, bool) {
+	switch to {
+	case StringType:
+		s, ok := fieldString(f)
+		return Field{Key: f.Key, Type: StringType, String: s}, ok
+	case Int64Type:
+		i, ok := fieldInt64(f)
+		return Field{Key: f.Key, Type: Int64Type, Integer: i}, ok
+	case Float64Type:
+		fl, ok := fieldFloat64(f)
+		return Field{Key: f.Key, Type: Float64Type, Integer: int64(math.Float64bits(fl))}, ok
+	}
+	return f, false
+}
+
+func fieldString(f Field) (string, bool) {
+	switch f.Type {
+	case StringType:
+		return f.String, true
+	case ByteStringType:
+		return string(f.Interface.([]byte)), true
+	case BoolType:
+		return strconv.FormatBool(f.Integer == 1), true
+	case Int64Type, Int32Type, Int16Type, Int8Type:
+		return strconv.FormatInt(f.Integer, 10), true
+	case Uint64Type, Uint32Type, Uint16Type, Uint8Type, UintptrType:
+		return strconv.FormatUint(uint64(f.Integer), 10), true
+	case Float64Type:
+		return strconv.FormatFloat(math.Float64frombits(uint64(f.Integer)), 'g', -1, 64), true
+	case Float32Type:
+		return strconv.FormatFloat(float64(math.Float32frombits(uint32(f.Integer))), 'g', -1, 32), true
+	case DurationType:
+		return time.Duration(f.Integer).String(), true
+	case TimeType:
+		t := time.Unix(0, f.Integer)
+		if f.Interface != nil {
+			t = t.In(f.Interface.(*time.Location))
+		}
+		return t.Format(time.RFC3339Nano), true
+	case TimeFullType:
+		return f.Interface.(time.Time).Format(time.RFC3339Nano), true
+	}
+	return "", false
+}
+
+func fieldInt64(f Field) (int64, bool) {
+	switch f.Type {
+	case Int64Type, Int32Type, Int16Type, Int8Type:
+		return f.Integer, true
+	case Uint64Type, Uint32Type, Uint16Type, Uint8Type, UintptrType:
+		return f.Integer, f.Integer >= 0
+	case StringType:
+		i, err := strconv.ParseInt(f.String, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func fieldFloat64(f Field) (float64, bool) {
+	switch f.Type {
+	case Float64Type:
+		return math.Float64frombits(uint64(f.Integer)), true
+	case Float32Type:
+		return float64(math.Float32frombits(uint32(f.Integer))), true
+	case Int64Type, Int32Type, Int16Type, Int8Type:
+		return float64(f.Integer), true
+	case Uint64Type, Uint32Type, Uint16Type, Uint8Type, UintptrType:
+		return float64(uint64(f.Integer)), true
+	case StringType:
+		fl, err := strconv.ParseFloat(f.String, 64)
+		return fl, err == nil
+	}
+	return 0, false
+}
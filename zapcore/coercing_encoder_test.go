@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+func coercingTestCore(rules []CoercionRule) (Core, *ztest.Buffer) {
+	sink := &ztest.Buffer{}
+	enc := NewCoercingEncoder(NewJSONEncoder(EncoderConfig{MessageKey: "msg"}), rules)
+	return NewCore(enc, sink, DebugLevel), sink
+}
+
+func TestCoercingEncoder(t *testing.T) {
+	rules := []CoercionRule{
+		{Key: "user_id", To: StringType},
+		{Key: "request.user_id", To: Int64Type},
+		{Key: "at", To: StringType},
+		{Key: "ratio", To: Float64Type},
+		{Key: "obj", To: StringType},
+	}
+	at := time.Date(2024, 3, 7, 5, 4, 9, 1, time.UTC)
+
+	tests := []struct {
+		desc     string
+		with     []Field
+		fields   []Field
+		expected string
+	}{
+		{
+			desc:     "int to string",
+			fields:   []Field{{Key: "user_id", Type: Int64Type, Integer: 42}, {Key: "other_id", Type: Int64Type, Integer: 7}},
+			expected: `{"msg":"m","user_id":"42","other_id":7}`,
+		},
+		{
+			desc:     "with fields",
+			with:     []Field{{Key: "user_id", Type: Uint32Type, Integer: 42}},
+			expected: `{"msg":"m","user_id":"42"}`,
+		},
+		{
+			desc: "namespace-aware",
+			fields: []Field{
+				{Key: "request", Type: NamespaceType},
+				{Key: "user_id", Type: StringType, String: "42"},
+			},
+			expected: `{"msg":"m","request":{"user_id":42}}`,
+		},
+		{
+			desc: "namespace from with",
+			with: []Field{{Key: "request", Type: NamespaceType}},
+			fields: []Field{
+				{Key: "user_id", Type: StringType, String: "42"},
+			},
+			expected: `{"msg":"m","request":{"user_id":42}}`,
+		},
+		{
+			desc:     "time to string",
+			fields:   []Field{{Key: "at", Type: TimeType, Integer: at.UnixNano(), Interface: time.UTC}},
+			expected: `{"msg":"m","at":"2024-03-07T05:04:09.000000001Z"}`,
+		},
+		{
+			desc:     "string to float",
+			fields:   []Field{{Key: "ratio", Type: StringType, String: "0.5"}},
+			expected: `{"msg":"m","ratio":0.5}`,
+		},
+		{
+			desc: "unconvertible fields are unchanged",
+			fields: []Field{
+				{Key: "ratio", Type: StringType, String: "half"},
+				{Key: "obj", Type: ObjectMarshalerType, Interface: users(1)},
+			},
+			expected: `{"msg":"m","ratio":"half","obj":{"users":1}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			core, sink := coercingTestCore(rules)
+			require.NoError(t, core.With(tt.with).Write(Entry{Message: "m"}, tt.fields), "Unexpected write error.")
+			assert.Equal(t, tt.expected+"\n", sink.String(), "Unexpected output.")
+		})
+	}
+}
+
+func TestCoercingEncoderDoesNotModifyFields(t *testing.T) {
+	core, _ := coercingTestCore([]CoercionRule{{Key: "user_id", To: StringType}})
+	fields := []Field{{Key: "user_id", Type: Int64Type, Integer: 42}}
+	require.NoError(t, core.Write(Entry{}, fields), "Unexpected write error.")
+	assert.Equal(t, Int64Type, fields[0].Type, "Caller's fields must not be modified.")
+}
+
+func TestCoercingEncoderNoRules(t *testing.T) {
+	enc := NewJSONEncoder(EncoderConfig{})
+	assert.Equal(t, enc, NewCoercingEncoder(enc, nil), "Expected encoder to be returned as-is without rules.")
+}
@@ -21,7 +21,12 @@
 package zapcore
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/internal/bufferpool"
@@ -60,7 +65,9 @@ func NewConsoleEncoder(cfg EncoderConfig) Encoder {
 		// Use a default delimiter of '\t' for backwards compatibility
 		cfg.ConsoleSeparator = "\t"
 	}
-	return consoleEncoder{newJSONEncoder(cfg, true)}
+	enc := newJSONEncoder(cfg, true)
+	enc.compactObjects = cfg.ConsoleCompactObjects
+	return consoleEncoder{enc}
 }
 
 func (c consoleEncoder) Clone() Encoder {
@@ -112,7 +119,12 @@ func (c consoleEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer,
 	// Add the message itself.
 	if c.MessageKey != "" {
 		c.addSeparatorIfNecessary(line)
-		line.AppendString(ent.Message)
+		if c.IndentMultilineMessages && strings.IndexByte(ent.Message, '\n') >= 0 {
+			indent := messageIndent(line.Bytes())
+			line.AppendString(strings.ReplaceAll(ent.Message, "\n", "\n"+indent))
+		} else {
+			line.AppendString(ent.Message)
+		}
 	}
 
 	// Add any structured context.
@@ -129,6 +141,32 @@ func (c consoleEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer,
 	return line, nil
 }
 
+// messageIndent returns the whitespace that lines up a continuation line
+// with the end of prefix: tabs are kept, ANSI escape sequences are dropped,
+// and every other character becomes a space.
+func messageIndent(prefix []byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(prefix); {
+		switch b := prefix[i]; {
+		case b == '\t':
+			sb.WriteByte('\t')
+			i++
+		case b == '\x1b' && i+1 < len(prefix) && prefix[i+1] == '[':
+			// Skip a CSI sequence, up to and including its final byte.
+			i += 2
+			for i < len(prefix) && (prefix[i] < 0x40 || prefix[i] > 0x7e) {
+				i++
+			}
+			i++
+		default:
+			_, size := utf8.DecodeRune(prefix[i:])
+			sb.WriteByte(' ')
+			i += size
+		}
+	}
+	return sb.String()
+}
+
 func (c consoleEncoder) writeContext(line *buffer.Buffer, extra []Field) {
 	context := c.jsonEncoder.Clone().(*jsonEncoder)
 	defer func() {
@@ -138,6 +176,9 @@ func (c consoleEncoder) writeContext(line *buffer.Buffer, extra []Field) {
 		putJSONEncoder(context)
 	}()
 
+	if c.SortFields {
+		extra = sortFields(extra)
+	}
 	addFields(context, extra)
 	context.closeOpenNamespaces()
 	if context.buf.Len() == 0 {
@@ -155,3 +196,87 @@ func (c consoleEncoder) addSeparatorIfNecessary(line *buffer.Buffer) {
 		line.AppendString(c.ConsoleSeparator)
 	}
 }
+
+// appendCompactObject encodes an object as JSON and appends it in the compact
+// form used by EncoderConfig.ConsoleCompactObjects. If the JSON can't be
+// converted, for example because a custom ReflectedEncoder produced something
+// else, it's appended unchanged.
+func (enc *jsonEncoder) appendCompactObject(obj ObjectMarshaler) error {
+	sub := _jsonPool.Get()
+	sub.EncoderConfig = enc.EncoderConfig
+	sub.buf = bufferpool.Get()
+	sub.depth = enc.depth
+	defer func() {
+		sub.buf.Free()
+		putJSONEncoder(sub)
+	}()
+
+	err := sub.AppendObject(obj)
+	compact := bufferpool.Get()
+	defer compact.Free()
+	if appendCompactJSON(compact, sub.buf.Bytes()) == nil {
+		enc.buf.AppendBytes(compact.Bytes())
+	} else {
+		enc.buf.AppendBytes(sub.buf.Bytes())
+	}
+	return err
+}
+
+// appendCompactJSON appends a JSON value in a compact, human-friendly form:
+// objects are written as {k=v k2=v2}, arrays as [a b], and strings are
+// unquoted unless they're empty or contain whitespace or syntax characters.
+func appendCompactJSON(buf *buffer.Buffer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return appendCompactValue(buf, dec)
+}
+
+func appendCompactValue(buf *buffer.Buffer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		isObject := t == '{'
+		buf.AppendByte(byte(t))
+		for first := true; dec.More(); first = false {
+			if !first {
+				buf.AppendByte(' ')
+			}
+			if isObject {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				appendCompactString(buf, key.(string))
+				buf.AppendByte('=')
+			}
+			if err := appendCompactValue(buf, dec); err != nil {
+				return err
+			}
+		}
+		end, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		buf.AppendByte(byte(end.(json.Delim)))
+	case string:
+		appendCompactString(buf, t)
+	case json.Number:
+		buf.AppendString(t.String())
+	case bool:
+		buf.AppendBool(t)
+	case nil:
+		buf.AppendString("null")
+	}
+	return nil
+}
+
+func appendCompactString(buf *buffer.Buffer, s string) {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=[]{}") {
+		buf.AppendString(strconv.Quote(s))
+		return
+	}
+	buf.AppendString(s)
+}
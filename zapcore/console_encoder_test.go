@@ -20,6 +20,7 @@
 package zapcore_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -133,3 +134,120 @@ func encoderTestEncoderConfig(separator string) EncoderConfig {
 	testEncoder.ConsoleSeparator = separator
 	return testEncoder
 }
+
+func TestConsoleIndentMultilineMessages(t *testing.T) {
+	tests := []struct {
+		desc      string
+		separator string
+		encLevel  LevelEncoder
+		msg       string
+		want      string
+	}{
+		{
+			desc:      "spaces",
+			separator: " ",
+			msg:       "SELECT *\nFROM users",
+			want:      "info main SELECT *\n          FROM users\n",
+		},
+		{
+			desc: "tabs",
+			msg:  "first\nsecond\nthird",
+			want: "info\tmain\tfirst\n    \t    \tsecond\n    \t    \tthird\n",
+		},
+		{
+			desc:      "color codes",
+			separator: " ",
+			encLevel:  CapitalColorLevelEncoder,
+			msg:       "a\nb",
+			want:      "\x1b[34mINFO\x1b[0m main a\n          b\n",
+		},
+		{
+			desc:      "single line",
+			separator: " ",
+			msg:       "one line",
+			want:      "info main one line\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := encoderTestEncoderConfig(tt.separator)
+			cfg.TimeKey = ""
+			cfg.CallerKey = ""
+			cfg.FunctionKey = ""
+			cfg.IndentMultilineMessages = true
+			if tt.encLevel != nil {
+				cfg.EncodeLevel = tt.encLevel
+			}
+			ent := Entry{Level: InfoLevel, LoggerName: "main", Message: tt.msg}
+
+			buf, err := NewConsoleEncoder(cfg).EncodeEntry(ent, nil)
+			if assert.NoError(t, err, "Unexpected console encoding error.") {
+				assert.Equal(t, tt.want, buf.String(), "Unexpected console output.")
+			}
+			buf.Free()
+
+			buf, err = NewJSONEncoder(cfg).EncodeEntry(ent, nil)
+			if assert.NoError(t, err, "Unexpected JSON encoding error.") {
+				assert.Contains(t, buf.String(), `"msg":"`+strings.ReplaceAll(tt.msg, "\n", `\n`)+`"`,
+					"JSON messages should be unchanged.")
+			}
+			buf.Free()
+		})
+	}
+}
+
+func TestConsoleCompactObjects(t *testing.T) {
+	addr := ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddString("city", "New York")
+		enc.AddInt("zip", 10001)
+		return nil
+	})
+	user := ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddString("name", "alice")
+		enc.AddBool("admin", true)
+		enc.AddString("note", "")
+		if err := enc.AddArray("tags", ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+			arr.AppendString("a")
+			arr.AppendString("b=c")
+			return nil
+		})); err != nil {
+			return err
+		}
+		return enc.AddObject("address", addr)
+	})
+	fields := []Field{
+		{Key: "user", Type: ObjectMarshalerType, Interface: user},
+		{Key: "n", Type: Int64Type, Integer: 1},
+	}
+
+	cfg := testEncoderConfig()
+	cfg.TimeKey = ""
+	cfg.LevelKey = ""
+	cfg.NameKey = ""
+	cfg.ConsoleCompactObjects = true
+	ent := Entry{Message: "msg"}
+
+	buf, err := NewConsoleEncoder(cfg).EncodeEntry(ent, fields)
+	if assert.NoError(t, err, "Unexpected console encoding error.") {
+		assert.Equal(t,
+			`msg	{"user": {name=alice admin=true note="" tags=[a "b=c"] address={city="New York" zip=10001}}, "n": 1}`+"\n",
+			buf.String(), "Expected compact objects in console output.")
+	}
+	buf.Free()
+
+	buf, err = NewJSONEncoder(cfg).EncodeEntry(ent, fields)
+	if assert.NoError(t, err, "Unexpected JSON encoding error.") {
+		assert.Equal(t,
+			`{"msg":"msg","user":{"name":"alice","admin":true,"note":"","tags":["a","b=c"],"address":{"city":"New York","zip":10001}},"n":1}`+"\n",
+			buf.String(), "Expected structured objects in JSON output.")
+	}
+	buf.Free()
+
+	cfg.ConsoleCompactObjects = false
+	buf, err = NewConsoleEncoder(cfg).EncodeEntry(ent, fields[:1])
+	if assert.NoError(t, err, "Unexpected console encoding error.") {
+		assert.Contains(t, buf.String(), `{"user": {"name": "alice"`, "Expected JSON objects by default.")
+	}
+	buf.Free()
+}
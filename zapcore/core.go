@@ -20,6 +20,8 @@
 
 package zapcore
 
+import "go.uber.org/zap/internal/bufferpool"
+
 // Core is a minimal, fast logger interface. It's designed for library authors
 // to wrap in a more user-friendly API.
 type Core interface {
@@ -53,31 +55,45 @@ func (n nopCore) With([]Field) Core                           { return n }
 func (nopCore) Check(_ Entry, ce *CheckedEntry) *CheckedEntry { return ce }
 func (nopCore) Write(Entry, []Field) error                    { return nil }
 func (nopCore) Sync() error                                   { return nil }
+func (nopCore) Describe() []CoreInfo                          { return nil }
 
 // NewCore creates a Core that writes logs to a WriteSyncer.
 func NewCore(enc Encoder, ws WriteSyncer, enab LevelEnabler) Core {
-	return &ioCore{
+	c := &ioCore{
 		LevelEnabler: enab,
 		enc:          enc,
 		out:          ws,
 	}
+	c.root = c
+	return c
 }
 
 type ioCore struct {
 	LevelEnabler
-	enc Encoder
-	out WriteSyncer
+	enc  Encoder
+	out  WriteSyncer
+	root *ioCore // the Core built by NewCore, without fields added with With
 }
 
 var (
 	_ Core           = (*ioCore)(nil)
 	_ leveledEnabler = (*ioCore)(nil)
+	_ RawWriter      = (*ioCore)(nil)
 )
 
 func (c *ioCore) Level() Level {
 	return LevelOf(c.LevelEnabler)
 }
 
+func (c *ioCore) Describe() []CoreInfo {
+	return []CoreInfo{{
+		Encoder:      describeEncoder(c.enc),
+		Level:        LevelOf(c.LevelEnabler),
+		LevelEnabler: c.LevelEnabler,
+		Syncer:       DescribeWriteSyncer(c.out),
+	}}
+}
+
 func (c *ioCore) With(fields []Field) Core {
 	clone := c.clone()
 	addFields(clone.enc, fields)
@@ -109,6 +125,29 @@ func (c *ioCore) Write(ent Entry, fields []Field) error {
 	return nil
 }
 
+// WriteRaw writes line to the WriteSyncer without encoding it, adding a
+// newline if line doesn't end with one.
+func (c *ioCore) WriteRaw(ent Entry, line []byte) error {
+	var err error
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		_, err = c.out.Write(line)
+	} else {
+		buf := bufferpool.Get()
+		buf.Write(line)
+		buf.AppendString(DefaultLineEnding)
+		_, err = c.out.Write(buf.Bytes())
+		buf.Free()
+	}
+	if err != nil {
+		return err
+	}
+	if ent.Level > ErrorLevel {
+		// Since we may be crashing the program, sync the output.
+		_ = c.Sync()
+	}
+	return nil
+}
+
 func (c *ioCore) Sync() error {
 	return c.out.Sync()
 }
@@ -117,6 +156,7 @@ func (c *ioCore) clone() *ioCore {
 	return &ioCore{
 		LevelEnabler: c.LevelEnabler,
 		enc:          c.enc.Clone(),
+		root:         c.root,
 		out:          c.out,
 	}
 }
@@ -0,0 +1,81 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"bytes"
+	"sync/atomic"
+)
+
+// CountingSyncer is a WriteSyncer that counts the lines and bytes written
+// through it, for monitoring log volume. Create one with NewCountingSyncer.
+type CountingSyncer struct {
+	ws    WriteSyncer
+	lines atomic.Uint64
+	bytes atomic.Uint64
+}
+
+var _ WriteSyncer = (*CountingSyncer)(nil)
+
+// NewCountingSyncer wraps a WriteSyncer so that the lines and bytes written
+// to it are counted. Only the bytes the wrapped WriteSyncer reports as
+// written are counted, and a line is counted when its newline is written,
+// so entries encoded with a DefaultLineEnding count as one line each.
+//
+// The counters are updated atomically and may be read at any time. Writes
+// are passed through as is, so the CountingSyncer is safe for concurrent
+// use if the wrapped WriteSyncer is.
+func NewCountingSyncer(ws WriteSyncer) *CountingSyncer {
+	return &CountingSyncer{ws: ws}
+}
+
+// Write writes bs to the wrapped WriteSyncer and counts what was written.
+func (s *CountingSyncer) Write(bs []byte) (int, error) {
+	n, err := s.ws.Write(bs)
+	if n > 0 {
+		if n > len(bs) {
+			n = len(bs)
+		}
+		s.bytes.Add(uint64(n))
+		s.lines.Add(uint64(bytes.Count(bs[:n], []byte{'\n'})))
+	}
+	return n, err
+}
+
+// Sync syncs the wrapped WriteSyncer.
+func (s *CountingSyncer) Sync() error {
+	return s.ws.Sync()
+}
+
+// Describe describes the wrapped WriteSyncer. See DescribeWriteSyncer.
+func (s *CountingSyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
+
+// Lines returns the number of lines written so far.
+func (s *CountingSyncer) Lines() uint64 {
+	return s.lines.Load()
+}
+
+// Bytes returns the number of bytes written so far.
+func (s *CountingSyncer) Bytes() uint64 {
+	return s.bytes.Load()
+}
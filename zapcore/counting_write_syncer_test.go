@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/internal/ztest"
+)
+
+func TestCountingSyncer(t *testing.T) {
+	buf := &ztest.Buffer{}
+	ws := NewCountingSyncer(buf)
+	assert.Zero(t, ws.Lines(), "Expected no lines before writing.")
+	assert.Zero(t, ws.Bytes(), "Expected no bytes before writing.")
+
+	for _, s := range []string{"foo\n", "bar\n", "multi\nline\n", "partial"} {
+		_, err := ws.Write([]byte(s))
+		require.NoError(t, err, "Unexpected error writing.")
+	}
+	require.NoError(t, ws.Sync(), "Unexpected error syncing.")
+
+	assert.Equal(t, uint64(4), ws.Lines(), "Unexpected line count.")
+	assert.Equal(t, uint64(26), ws.Bytes(), "Unexpected byte count.")
+	assert.Equal(t, "foo\nbar\nmulti\nline\npartial", buf.String(), "Expected writes to pass through.")
+	assert.True(t, buf.Called(), "Expected Sync to pass through.")
+}
+
+func TestCountingSyncerWithCore(t *testing.T) {
+	ws := NewCountingSyncer(&ztest.Discarder{})
+	core := NewCore(NewJSONEncoder(EncoderConfig{MessageKey: "msg", LineEnding: DefaultLineEnding}), ws, DebugLevel)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, core.Write(Entry{Message: "msg"}, nil), "Unexpected error writing.")
+	}
+	assert.Equal(t, uint64(3), ws.Lines(), "Expected one line per entry.")
+	assert.NotZero(t, ws.Bytes(), "Expected bytes to be counted.")
+}
+
+func TestCountingSyncerErrors(t *testing.T) {
+	ws := NewCountingSyncer(&ztest.FailWriter{})
+	n, err := ws.Write([]byte("foo\n"))
+	assert.Error(t, err, "Expected the wrapped WriteSyncer's error.")
+	assert.Equal(t, 4, n, "Expected the wrapped WriteSyncer's count.")
+	assert.Equal(t, uint64(1), ws.Lines(), "Expected reported bytes to be counted.")
+
+	ws = NewCountingSyncer(&ztest.ShortWriter{})
+	n, err = ws.Write([]byte("foo\n"))
+	assert.NoError(t, err, "Unexpected error writing.")
+	assert.Equal(t, 3, n, "Expected the wrapped WriteSyncer's count.")
+	assert.Equal(t, uint64(3), ws.Bytes(), "Expected only written bytes to be counted.")
+	assert.Zero(t, ws.Lines(), "Expected the unwritten newline not to be counted.")
+}
+
+func TestCountingSyncerConcurrent(t *testing.T) {
+	ws := NewCountingSyncer(Lock(&ztest.Discarder{}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = ws.Write([]byte("line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(1000), ws.Lines(), "Unexpected line count.")
+	assert.Equal(t, uint64(5000), ws.Bytes(), "Unexpected byte count.")
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+type dedupeCore struct {
+	Core
+
+	window time.Duration
+	state  *dedupeState
+}
+
+type dedupeState struct {
+	mu sync.Mutex
+
+	// The entry that started the current streak.
+	hash    uint64
+	nFields int
+	start   time.Time
+	active  bool
+
+	// The most recent repeat, the Cores that accepted it, and how many
+	// repeats were suppressed.
+	last    Entry
+	cores   multiCore
+	repeats int
+
+	streak uint64      // identifies the current streak for timer
+	timer  *time.Timer // armed while repeats are suppressed
+	err    error       // from summaries written by timer
+}
+
+var (
+	_ Core           = (*dedupeCore)(nil)
+	_ leveledEnabler = (*dedupeCore)(nil)
+)
+
+// NewDedupeConsecutiveCore wraps a Core so that consecutive repeats of an
+// entry are suppressed, like syslog's "last message repeated N times". An
+// entry repeats the previous one if it has the same level, logger name,
+// message, and number of fields, and is logged within window of the first
+// entry in the streak; field values aren't compared, which keeps the check
+// cheap.
+//
+// When a streak of repeats ends, the Core writes a summary entry with the
+// level and logger name of the repeats and the message "<message> (repeated
+// N times)" before writing the entry that ended it. A repeat logged after
+// the window has expired also ends the streak, and is written as the first
+// entry of a new one. If nothing ends the streak, the summary is written
+// once its window expires, and Sync writes the summary of a pending streak,
+// if any. Summaries are written to the Cores that accepted the repeats, so
+// outputs that filtered them out, such as a member of a Tee with a higher
+// level, don't see their summary either. Errors writing a summary when the
+// window expires are returned by the next Sync.
+//
+// Entries are compared using their timestamps rather than the wall clock, so
+// the Core follows the clock configured with WithClock; only the timer that
+// writes the summary of an expired streak runs on the wall clock. Cores
+// derived with With track their streaks separately, since their entries
+// carry different context.
+func NewDedupeConsecutiveCore(next Core, window time.Duration) Core {
+	return &dedupeCore{
+		Core:   next,
+		window: window,
+		state:  &dedupeState{},
+	}
+}
+
+func (c *dedupeCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *dedupeCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *dedupeCore) With(fields []Field) Core {
+	return &dedupeCore{
+		Core:   c.Core.With(fields),
+		window: c.window,
+		state:  &dedupeState{},
+	}
+}
+
+// Check registers the Cores that accept the entry behind a single
+// downstreamWriter, so that the decision to suppress the entry is made once,
+// when it's written with its fields.
+func (c *dedupeCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkDownstream(c.Core, ent, ce, downstreamWriter{write: c.write, writeRaw: c.writeRaw})
+}
+
+func (c *dedupeCore) Sync() error {
+	s := c.state
+	s.mu.Lock()
+	err := s.err
+	s.err = nil
+	err = multierr.Append(err, c.flush())
+	s.mu.Unlock()
+	return multierr.Append(err, c.Core.Sync())
+}
+
+// admit reports whether an entry should be written, writing the summary of
+// the previous streak first if the entry ends it. cores are the Cores that
+// accepted the entry. The caller must hold the state's lock.
+func (c *dedupeCore) admit(ent Entry, nFields int, cores multiCore) (bool, error) {
+	s := c.state
+	h := dedupeHash(ent)
+	if s.active && s.hash == h && s.nFields == nFields && ent.Time.Sub(s.start) < c.window {
+		s.last = ent
+		s.cores = cores
+		s.repeats++
+		if s.timer == nil {
+			streak := s.streak
+			s.timer = time.AfterFunc(c.window-ent.Time.Sub(s.start), func() {
+				c.expire(streak)
+			})
+		}
+		return false, nil
+	}
+
+	err := c.flush()
+	s.streak++
+	s.hash = h
+	s.nFields = nFields
+	s.start = ent.Time
+	s.active = true
+	return true, err
+}
+
+// expire ends a streak whose window has passed, writing its summary.
+func (c *dedupeCore) expire(streak uint64) {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.streak != streak {
+		return // the streak already ended
+	}
+	s.timer = nil
+	s.err = multierr.Append(s.err, c.flush())
+	s.streak++
+	s.active = false
+}
+
+// flush writes the summary of the current streak, if any repeats were
+// suppressed. The caller must hold the state's lock.
+func (c *dedupeCore) flush() error {
+	s := c.state
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.repeats == 0 {
+		return nil
+	}
+	summary := Entry{
+		Level:      s.last.Level,
+		Time:       s.last.Time,
+		LoggerName: s.last.LoggerName,
+		Message:    fmt.Sprintf("%s (repeated %d times)", s.last.Message, s.repeats),
+	}
+	cores := s.cores
+	s.last = Entry{}
+	s.cores = nil
+	s.repeats = 0
+	return cores.Write(summary, nil)
+}
+
+// dedupeHash is an inlined, allocation-free FNV-1a hash of the entry's
+// level, logger name, and message.
+func dedupeHash(ent Entry) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	h = (h ^ uint64(byte(ent.Level))) * prime64
+	for i := 0; i < len(ent.LoggerName); i++ {
+		h = (h ^ uint64(ent.LoggerName[i])) * prime64
+	}
+	h = (h ^ 0) * prime64 // separates the logger name from the message
+	for i := 0; i < len(ent.Message); i++ {
+		h = (h ^ uint64(ent.Message[i])) * prime64
+	}
+	return h
+}
+
+// write writes an entry to the Cores that agreed to log it, unless it
+// duplicates the previous entries.
+func (c *dedupeCore) write(cores multiCore, ent Entry, fields []Field) error {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ok, err := c.admit(ent, len(fields), cores)
+	if !ok {
+		return err
+	}
+	return multierr.Append(err, cores.Write(ent, fields))
+}
+
+// writeRaw is like write, for pre-formatted lines.
+func (c *dedupeCore) writeRaw(cores multiCore, ent Entry, line []byte) error {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ok, err := c.admit(ent, 0, cores)
+	if !ok {
+		return err
+	}
+	return multierr.Append(err, writeRawTo(cores, ent, line))
+}
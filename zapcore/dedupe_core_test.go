@@ -0,0 +1,240 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dedupeMessages(logs *observer.ObservedLogs) []string {
+	var msgs []string
+	for _, e := range logs.AllUntimed() {
+		msgs = append(msgs, e.Entry.Level.String()+" "+e.Message)
+	}
+	return msgs
+}
+
+func writeDedupeEntry(core Core, ent Entry, fields ...Field) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+func TestDedupeConsecutiveCore(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	core := NewDedupeConsecutiveCore(obs, time.Minute)
+	assert.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ent := func(lvl Level, msg string, offset time.Duration) Entry {
+		return Entry{Level: lvl, Message: msg, Time: start.Add(offset)}
+	}
+
+	// A burst of identical errors is written once.
+	for i := 0; i < 5; i++ {
+		writeDedupeEntry(core, ent(ErrorLevel, "boom", time.Duration(i)*time.Second), makeInt64Field("i", i))
+	}
+	assert.Equal(t, []string{"error boom"}, dedupeMessages(logs), "Expected repeats to be suppressed.")
+
+	// A different entry ends the streak.
+	writeDedupeEntry(core, ent(InfoLevel, "recovered", 10*time.Second))
+	assert.Equal(t, []string{
+		"error boom",
+		"error boom (repeated 4 times)",
+		"info recovered",
+	}, dedupeMessages(logs), "Expected a summary before the next entry.")
+	summary := logs.All()[1]
+	assert.Equal(t, start.Add(4*time.Second), summary.Time, "Summary should carry the time of the last repeat.")
+	assert.Empty(t, summary.Context, "Summary shouldn't have fields.")
+
+	// A single entry doesn't produce a summary.
+	writeDedupeEntry(core, ent(InfoLevel, "other", 11*time.Second))
+	assert.Len(t, logs.AllUntimed(), 4, "Unexpected summary for an entry without repeats.")
+
+	// Entries below the level are dropped without affecting the streak.
+	writeDedupeEntry(core, ent(DebugLevel, "other", 12*time.Second))
+	writeDedupeEntry(core, ent(InfoLevel, "other", 13*time.Second))
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{
+		"error boom",
+		"error boom (repeated 4 times)",
+		"info recovered",
+		"info other",
+		"info other (repeated 1 times)",
+	}, dedupeMessages(logs), "Expected Sync to write the pending summary.")
+
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Len(t, logs.AllUntimed(), 5, "Sync without repeats shouldn't write a summary.")
+}
+
+func TestDedupeConsecutiveCoreComparison(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := Entry{Level: WarnLevel, LoggerName: "db", Message: "slow", Time: start}
+
+	tests := []struct {
+		desc   string
+		next   Entry
+		fields []Field
+		repeat bool
+	}{
+		{"identical", base, []Field{makeInt64Field("k", 1)}, true},
+		{"different field values", base, []Field{makeInt64Field("k", 2)}, true},
+		{"different field count", base, nil, false},
+		{"different level", Entry{Level: ErrorLevel, LoggerName: "db", Message: "slow", Time: start}, []Field{makeInt64Field("k", 1)}, false},
+		{"different logger", Entry{Level: WarnLevel, LoggerName: "d", Message: "bslow", Time: start}, []Field{makeInt64Field("k", 1)}, false},
+		{"different message", Entry{Level: WarnLevel, LoggerName: "db", Message: "slower", Time: start}, []Field{makeInt64Field("k", 1)}, false},
+		{"window expired", Entry{Level: WarnLevel, LoggerName: "db", Message: "slow", Time: start.Add(time.Second)}, []Field{makeInt64Field("k", 1)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			obs, logs := observer.New(DebugLevel)
+			core := NewDedupeConsecutiveCore(obs, time.Second)
+			writeDedupeEntry(core, base, makeInt64Field("k", 1))
+			writeDedupeEntry(core, tt.next, tt.fields...)
+			if tt.repeat {
+				assert.Equal(t, 1, logs.Len(), "Expected the second entry to be suppressed.")
+			} else {
+				assert.Equal(t, 2, logs.Len(), "Expected both entries to be written.")
+			}
+		})
+	}
+}
+
+func TestDedupeConsecutiveCoreWindowExpiry(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+	core := NewDedupeConsecutiveCore(obs, time.Second)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, offset := range []time.Duration{0, 300, 600, 900, 1000, 1200} {
+		writeDedupeEntry(core, Entry{Message: "tick", Time: start.Add(offset * time.Millisecond)})
+	}
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{
+		"info tick",
+		"info tick (repeated 3 times)",
+		"info tick",
+		"info tick (repeated 1 times)",
+	}, dedupeMessages(logs), "Expected the window to start a new streak.")
+}
+
+func TestDedupeConsecutiveCoreWith(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+	core := NewDedupeConsecutiveCore(obs, time.Minute)
+	child := core.With([]Field{makeInt64Field("ctx", 1)})
+	ent := Entry{Message: "m", Time: time.Now()}
+
+	writeDedupeEntry(core, ent)
+	writeDedupeEntry(child, ent)
+	writeDedupeEntry(child, ent)
+	require.NoError(t, child.Sync(), "Unexpected error syncing.")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 3, "Expected parent and child to track streaks separately.")
+	assert.Empty(t, entries[0].Context, "Unexpected context on parent entry.")
+	assert.Equal(t, []Field{makeInt64Field("ctx", 1)}, entries[1].Context, "Unexpected context on child entry.")
+	assert.Equal(t, "m (repeated 1 times)", entries[2].Message, "Unexpected summary.")
+	assert.Equal(t, []Field{makeInt64Field("ctx", 1)}, entries[2].Context, "Summary should carry the child's context.")
+}
+
+func TestDedupeConsecutiveCoreHonorsWrappedCheck(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+	// The sampler inside drops every entry after the first, so nothing is left
+	// to deduplicate.
+	sampled := NewSamplerWithOptions(obs, time.Minute, 1, 0)
+	core := NewDedupeConsecutiveCore(sampled, time.Minute)
+
+	now := time.Now()
+	writeDedupeEntry(core, Entry{Message: "m", Time: now})
+	writeDedupeEntry(core, Entry{Message: "m", Time: now})
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{"info m"}, dedupeMessages(logs), "Expected sampled entries to be dropped before deduplication.")
+}
+
+func TestDedupeConsecutiveCoreConcurrent(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+	core := NewDedupeConsecutiveCore(obs, time.Hour)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				writeDedupeEntry(core, Entry{Message: "m", Time: now})
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{"info m", "info m (repeated 799 times)"}, dedupeMessages(logs), "Unexpected entries.")
+}
+
+func TestDedupeConsecutiveCoreSummaryRespectsFilters(t *testing.T) {
+	infoObs, infoLogs := observer.New(InfoLevel)
+	errorObs, errorLogs := observer.New(ErrorLevel)
+	core := NewDedupeConsecutiveCore(NewTee(infoObs, errorObs), time.Minute)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		writeDedupeEntry(core, Entry{Level: InfoLevel, Message: "retrying", Time: start.Add(time.Duration(i) * time.Second)})
+	}
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+
+	assert.Equal(t, []string{
+		"info retrying",
+		"info retrying (repeated 2 times)",
+	}, dedupeMessages(infoLogs), "Expected a summary where the repeats were accepted.")
+	assert.Zero(t, errorLogs.Len(), "Expected no summary where the repeats were filtered out.")
+}
+
+func TestDedupeConsecutiveCoreFlushesExpiredWindow(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	core := NewDedupeConsecutiveCore(obs, 20*time.Millisecond)
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		writeDedupeEntry(core, Entry{Level: ErrorLevel, Message: "boom", Time: now})
+	}
+	assert.Equal(t, []string{"error boom"}, dedupeMessages(logs), "Expected repeats to be suppressed.")
+
+	assert.Eventually(t, func() bool { return logs.Len() == 2 }, time.Second, time.Millisecond,
+		"Expected a summary once the window expires.")
+	assert.Equal(t, []string{
+		"error boom",
+		"error boom (repeated 2 times)",
+	}, dedupeMessages(logs), "Unexpected summary.")
+
+	// The expired streak is over, so the next entry is written.
+	writeDedupeEntry(core, Entry{Level: ErrorLevel, Message: "boom", Time: now})
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, 3, logs.Len(), "Expected a new streak after the window expired.")
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"fmt"
+	"io"
+)
+
+// CoreInfo describes a Core that encodes and writes entries. It's intended
+// for introspection, such as an administrative page listing a process's
+// logging outputs.
+type CoreInfo struct {
+	// Encoder names the Core's encoding, such as "json" or "console". For
+	// encoders not built into zap, it's the encoder's Go type.
+	Encoder string
+
+	// Level is the minimum enabled level of the Core.
+	Level Level
+
+	// LevelEnabler decides which levels the Core logs. Comparing it to an
+	// AtomicLevel reveals which Cores that AtomicLevel controls.
+	LevelEnabler LevelEnabler
+
+	// Syncer describes the Core's destination; see DescribeWriteSyncer.
+	Syncer string
+}
+
+// CoreDescriber is implemented by Cores that can describe themselves for
+// DescribeCores. Cores wrapping other Cores should typically return the
+// descriptions of the Cores they wrap.
+type CoreDescriber interface {
+	Describe() []CoreInfo
+}
+
+// DescribeCores describes the Cores that ultimately write entries logged to
+// the given Core, in order. Built-in Cores, including those built by
+// NewCore, NewTee, and the Core wrappers in this package, implement
+// CoreDescriber. Other Cores may implement it too; Cores that don't are
+// described by their Go type and level alone.
+func DescribeCores(core Core) []CoreInfo {
+	if d, ok := core.(CoreDescriber); ok {
+		return d.Describe()
+	}
+	return []CoreInfo{{
+		Encoder:      fmt.Sprintf("%T", core),
+		Level:        LevelOf(core),
+		LevelEnabler: core,
+		Syncer:       "unknown",
+	}}
+}
+
+func describeEncoder(enc Encoder) string {
+	switch e := enc.(type) {
+	case *jsonEncoder:
+		return "json"
+	case *prettyJSONEncoder:
+		return "pretty json"
+	case consoleEncoder:
+		return "console"
+	case ecsEncoder:
+		return "ecs"
+	case stackdriverEncoder:
+		return "stackdriver"
+	case emfEncoder:
+		return "emf"
+	case *msgpackEncoder:
+		return "msgpack"
+	case *protoEncoder:
+		return "protobuf"
+	case *xmlEncoder:
+		return "xml"
+	case *prefixedEncoder:
+		return describeEncoder(e.Encoder)
+	case *sizeObservingEncoder:
+		return describeEncoder(e.Encoder)
+	case *coercingEncoder:
+		return describeEncoder(e.Encoder)
+	}
+	return fmt.Sprintf("%T", enc)
+}
+
+// WriteSyncerDescriber is implemented by WriteSyncers that can describe
+// themselves for DescribeWriteSyncer. WriteSyncers wrapping other
+// WriteSyncers should typically build on the descriptions of the
+// WriteSyncers they wrap.
+type WriteSyncerDescriber interface {
+	Describe() string
+}
+
+// DescribeWriteSyncer returns a human-readable description of a WriteSyncer,
+// such as a file's name. WriteSyncers built by this package implement
+// WriteSyncerDescriber and are unwrapped: for example, the description of a
+// locked file is the file's name, and the descriptions of the WriteSyncers
+// combined by NewMultiWriteSyncer are joined with commas. Other
+// WriteSyncers with a Name method, like *os.File, are described by their
+// name; the rest are described by their Go type.
+func DescribeWriteSyncer(ws WriteSyncer) string {
+	if d, ok := ws.(WriteSyncerDescriber); ok {
+		return d.Describe()
+	}
+	return describeWriter(ws)
+}
+
+func describeWriter(w io.Writer) string {
+	if n, ok := w.(interface{ Name() string }); ok { // e.g. *os.File
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", w)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type describedCore struct{ Core }
+
+func (describedCore) Describe() []CoreInfo {
+	return []CoreInfo{{Encoder: "custom", Level: WarnLevel, Syncer: "somewhere"}}
+}
+
+type namedBuffer struct{ bytes.Buffer }
+
+func (*namedBuffer) Name() string { return "in-memory buffer" }
+
+func TestDescribeCores(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	require.NoError(t, err, "Failed to create file.")
+	defer f.Close()
+
+	jsonCore := NewCore(NewJSONEncoder(testEncoderConfig()), Lock(f), InfoLevel)
+	consoleCore := NewCore(
+		NewConsoleEncoder(testEncoderConfig()),
+		NewMultiWriteSyncer(AddSync(&namedBuffer{}), AddSync(&bytes.Buffer{})),
+		DebugLevel,
+	)
+	increased, err := NewIncreaseLevelCore(consoleCore, ErrorLevel)
+	require.NoError(t, err, "Failed to increase level.")
+	obs, _ := observer.New(DebugLevel)
+
+	core := NewTee(
+		RegisterHooks(NewSamplerWithOptions(jsonCore, time.Second, 1, 1)),
+		increased,
+		describedCore{jsonCore},
+		NewDedupeConsecutiveCore(NewReorderingCore(NewLazyWith(obs, nil), time.Second), time.Second),
+		NewNopCore(),
+	)
+
+	assert.Equal(t, []CoreInfo{
+		{Encoder: "json", Level: InfoLevel, LevelEnabler: InfoLevel, Syncer: f.Name()},
+		{Encoder: "console", Level: ErrorLevel, LevelEnabler: ErrorLevel, Syncer: "in-memory buffer, *bytes.Buffer"},
+		{Encoder: "custom", Level: WarnLevel, Syncer: "somewhere"},
+		{Encoder: "*observer.contextObserver", Level: DebugLevel, LevelEnabler: obs.With(nil), Syncer: "unknown"},
+	}, DescribeCores(core), "Unexpected core descriptions.")
+}
+
+func TestDescribeWriteSyncer(t *testing.T) {
+	tests := []struct {
+		ws   WriteSyncer
+		want string
+	}{
+		{Lock(os.Stderr), os.Stderr.Name()},
+		{AddSync(&namedBuffer{}), "in-memory buffer"},
+		{&BufferedWriteSyncer{WS: AddSync(&bytes.Buffer{})}, "buffered *bytes.Buffer"},
+		{NewMultiWriteSyncer(os.Stdout, os.Stderr), os.Stdout.Name() + ", " + os.Stderr.Name()},
+		{NewFailoverSyncer(os.Stdout, os.Stderr), os.Stdout.Name() + " (failover: " + os.Stderr.Name() + ")"},
+		{NewTimeRotatingSyncer("app-%Y%m%d.log", time.Hour), "time-rotating app-%Y%m%d.log"},
+		{NewCountingSyncer(NewTimeoutSyncer(Lock(os.Stderr), time.Second)), os.Stderr.Name()},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DescribeWriteSyncer(tt.ws), "Unexpected description.")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "go.uber.org/multierr"
+
+// checkDownstream lets core decide which of its Cores will log the entry,
+// then registers them with ce behind a single downstreamWriter built from
+// w. This lets a Core wrapper act once per entry, when it's written with its
+// fields, while keeping the filtering done by core's Check (for example,
+// the per-Core levels of a Tee) and any CheckWriteHook it sets intact.
+func checkDownstream(core Core, ent Entry, ce *CheckedEntry, w downstreamWriter) *CheckedEntry {
+	downstream := core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	if len(downstream.cores) > 0 {
+		w.cores = make(multiCore, len(downstream.cores))
+		copy(w.cores, downstream.cores)
+		ce = ce.AddCore(ent, &w)
+	}
+	if downstream.after != nil {
+		ce = ce.After(ent, downstream.after)
+	}
+	putCheckedEntry(downstream)
+	return ce
+}
+
+// downstreamWriter is a Core that writes an entry to the Cores that agreed to
+// log it, through the functions of the wrapper that registered it.
+type downstreamWriter struct {
+	cores multiCore
+
+	// write writes an entry to cores.
+	write func(cores multiCore, ent Entry, fields []Field) error
+	// writeRaw, if set, writes a pre-formatted line to cores; see
+	// CheckedEntry.WriteRaw. If it's nil, lines are written like entries
+	// without fields.
+	writeRaw func(cores multiCore, ent Entry, line []byte) error
+}
+
+var _ RawWriter = (*downstreamWriter)(nil)
+
+func (w *downstreamWriter) Enabled(lvl Level) bool {
+	return w.cores.Enabled(lvl)
+}
+
+func (w *downstreamWriter) With(fields []Field) Core {
+	clone := *w
+	clone.cores = w.cores.With(fields).(multiCore)
+	return &clone
+}
+
+func (w *downstreamWriter) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return w.cores.Check(ent, ce)
+}
+
+func (w *downstreamWriter) Write(ent Entry, fields []Field) error {
+	return w.write(w.cores, ent, fields)
+}
+
+func (w *downstreamWriter) WriteRaw(ent Entry, line []byte) error {
+	if w.writeRaw == nil {
+		return w.write(w.cores, ent, nil)
+	}
+	return w.writeRaw(w.cores, ent, line)
+}
+
+func (w *downstreamWriter) Sync() error {
+	return w.cores.Sync()
+}
+
+// writeRawTo writes line to each of cores, encoding the entry without fields
+// for Cores that don't implement RawWriter.
+func writeRawTo(cores multiCore, ent Entry, line []byte) error {
+	var err error
+	for _, core := range cores {
+		if rw, ok := core.(RawWriter); ok {
+			err = multierr.Append(err, rw.WriteRaw(ent, line))
+		} else {
+			err = multierr.Append(err, core.Write(ent, nil))
+		}
+	}
+	return err
+}
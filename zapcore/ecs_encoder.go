@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+)
+
+// ECSVersion is the version of the Elastic Common Schema that the ECS encoder
+// targets. It's written to every entry under "ecs.version".
+const ECSVersion = "1.6.0"
+
+type ecsEncoder struct {
+	*jsonEncoder
+}
+
+// NewECSEncoder creates a JSON encoder whose output follows the Elastic
+// Common Schema (ECS). The standard entry fields are written under their ECS
+// names and nested into objects as ECS requires:
+//
+//	{
+//	  "@timestamp": ...,
+//	  "log": {
+//	    "level": ...,
+//	    "logger": ...,
+//	    "origin": {"file": {"name": ..., "line": ...}, "function": ...}
+//	  },
+//	  "message": ...,
+//	  "ecs": {"version": "1.6.0"},
+//	  ...
+//	  "error": {"message": ..., "type": ..., "stack_trace": ...}
+//	}
+//
+// Since the ECS field names are fixed, the keys in the supplied EncoderConfig
+// are only used to enable or disable each element: as with the console
+// encoder, any element whose key is set to the empty string is omitted. The
+// EncodeTime, EncodeLevel, EncodeName and EncodeDuration functions are
+// honored; EncodeCaller is not, since ECS splits the caller into separate
+// file, line, and function fields.
+//
+// Structured context is written in the same format as the JSON encoder, with
+// fields whose keys share a dotted prefix nested into objects, as with
+// EncoderConfig.AutoNestDottedKeys: "http.request.method" is written as
+// {"http":{"request":{"method":...}}}. The error logged with zap.Error (or
+// any top-level field keyed "error") is written as error.message and
+// error.type, in the same object as the stack trace and any fields with
+// keys like "error.code". Context fields added with With are already
+// encoded, so they're written as-is and should use nested objects
+// themselves.
+func NewECSEncoder(cfg EncoderConfig) Encoder {
+	return ecsEncoder{newJSONEncoder(cfg, false)}
+}
+
+func (enc ecsEncoder) Clone() Encoder {
+	return ecsEncoder{enc.jsonEncoder.Clone().(*jsonEncoder)}
+}
+
+func (enc ecsEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	if enc.SortFields {
+		fields = sortFields(fields)
+	}
+	fields, errFields := splitECSErrorFields(fields)
+	fields = nestDottedKeys(fields)
+	stack := ent.Stack != "" && enc.StacktraceKey != ""
+
+	return enc.encodeFixedKeyEntry(fields, func(final *jsonEncoder) {
+		if final.TimeKey != "" && !ent.Time.IsZero() {
+			final.AddTime("@timestamp", ent.Time)
+		}
+		addECSLog(final, ent)
+		if final.MessageKey != "" {
+			final.AddString("message", ent.Message)
+		}
+		final.addKey("ecs")
+		final.buf.AppendByte('{')
+		final.AddString("version", ECSVersion)
+		final.buf.AppendByte('}')
+	}, func(final *jsonEncoder) {
+		if len(errFields) == 0 && !stack {
+			return
+		}
+		final.addKey("error")
+		final.buf.AppendByte('{')
+		addFields(final, nestDottedKeys(errFields))
+		if stack {
+			final.AddString("stack_trace", ent.Stack)
+		}
+		final.buf.AppendByte('}')
+	}), nil
+}
+
+// addECSLog adds the "log" object, which holds the entry's level, logger
+// name, and caller.
+func addECSLog(final *jsonEncoder, ent Entry) {
+	final.addKey("log")
+	final.buf.AppendByte('{')
+	if final.LevelKey != "" {
+		final.addKey("level")
+		cur := final.buf.Len()
+		if final.EncodeLevel != nil {
+			final.EncodeLevel(ent.Level, final)
+		}
+		if cur == final.buf.Len() {
+			// User-supplied EncodeLevel was missing or a no-op. Fall back to
+			// strings to keep output JSON valid.
+			final.AppendString(ent.Level.String())
+		}
+	}
+	if ent.LoggerName != "" && final.NameKey != "" {
+		final.addLoggerName("logger", ent.LoggerName)
+	}
+	if ent.Caller.Defined && (final.CallerKey != "" || final.FunctionKey != "") {
+		final.addKey("origin")
+		final.buf.AppendByte('{')
+		if final.CallerKey != "" {
+			final.addKey("file")
+			final.buf.AppendByte('{')
+			final.AddString("name", ent.Caller.File)
+			final.AddInt("line", ent.Caller.Line)
+			final.buf.AppendByte('}')
+		}
+		if final.FunctionKey != "" && ent.Caller.Function != "" {
+			final.AddString("function", ent.Caller.Function)
+		}
+		final.buf.AppendByte('}')
+	}
+	final.buf.AppendByte('}')
+}
+
+// splitECSErrorFields removes the fields that belong in the ECS error object
+// from the top level of fields: an error or string keyed "error", which
+// becomes error.message and error.type, and fields with keys like
+// "error.code", which are returned without the prefix.
+func splitECSErrorFields(fields []Field) (rest, errFields []Field) {
+	if !hasECSErrorField(fields) {
+		return fields, nil
+	}
+	rest = make([]Field, 0, len(fields))
+	top := true
+	for _, f := range fields {
+		if f.Type == NamespaceType {
+			top = false
+		}
+		if !top || !isECSErrorField(f) {
+			rest = append(rest, f)
+			continue
+		}
+		switch {
+		case f.Key == "error" && f.Type == ErrorType:
+			f = Field{Type: InlineMarshalerType, Interface: ecsError{f.Interface.(error)}}
+		case f.Key == "error":
+			f.Key = "message"
+		default:
+			f.Key = strings.TrimPrefix(f.Key, "error.")
+		}
+		errFields = append(errFields, f)
+	}
+	return rest, errFields
+}
+
+func hasECSErrorField(fields []Field) bool {
+	for _, f := range fields {
+		if f.Type == NamespaceType {
+			return false
+		}
+		if isECSErrorField(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func isECSErrorField(f Field) bool {
+	switch f.Type {
+	case ErrorType, StringType:
+		if f.Key == "error" {
+			return true
+		}
+	case NamespaceType, InlineMarshalerType, SkipType:
+		return false
+	}
+	return strings.HasPrefix(f.Key, "error.") && len(f.Key) > len("error.")
+}
+
+// ecsError writes an error's message and type into the ECS error object.
+type ecsError struct{ err error }
+
+func (e ecsError) MarshalLogObject(enc ObjectEncoder) error {
+	if err := encodeError("message", e.err, enc); err != nil {
+		return err
+	}
+	enc.AddString("type", fmt.Sprintf("%T", e.err))
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+func ecsTestEncoderConfig() EncoderConfig {
+	cfg := testEncoderConfig()
+	cfg.EncodeTime = RFC3339TimeEncoder
+	return cfg
+}
+
+func TestECSEncodeEntry(t *testing.T) {
+	enc := NewECSEncoder(ecsTestEncoderConfig())
+	enc.AddString("service", "api")
+
+	buf, err := enc.EncodeEntry(_testEntry, []Field{
+		{Key: "count", Type: Int64Type, Integer: 3},
+	})
+	require.NoError(t, err, "Unexpected ECS encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t,
+		`{"@timestamp":"1970-01-01T00:00:00Z",`+
+			`"log":{"level":"info","logger":"main","origin":{"file":{"name":"foo.go","line":42},"function":"foo.Foo"}},`+
+			`"message":"hello","ecs":{"version":"1.6.0"},`+
+			`"service":"api","count":3,`+
+			`"error":{"stack_trace":"fake-stack"}}`+"\n",
+		buf.String(),
+		"Unexpected ECS output.",
+	)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "ECS output must be valid JSON.")
+	assert.Equal(t, map[string]interface{}{
+		"level":  "info",
+		"logger": "main",
+		"origin": map[string]interface{}{
+			"file":     map[string]interface{}{"name": "foo.go", "line": float64(42)},
+			"function": "foo.Foo",
+		},
+	}, decoded["log"], "Unexpected nested log object.")
+	assert.Equal(t, map[string]interface{}{"stack_trace": "fake-stack"}, decoded["error"], "Unexpected nested error object.")
+}
+
+func TestECSEncoderNestsFields(t *testing.T) {
+	enc := NewECSEncoder(ecsTestEncoderConfig())
+	buf, err := enc.EncodeEntry(_testEntry, []Field{
+		{Key: "http.request.method", Type: StringType, String: "GET"},
+		{Key: "error", Type: ErrorType, Interface: errors.New("boom")},
+		{Key: "http.response.status_code", Type: Int64Type, Integer: 500},
+		{Key: "error.code", Type: StringType, String: "E42"},
+		{Key: "user", Type: StringType, String: "alice"},
+	})
+	require.NoError(t, err, "Unexpected ECS encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t,
+		`{"@timestamp":"1970-01-01T00:00:00Z",`+
+			`"log":{"level":"info","logger":"main","origin":{"file":{"name":"foo.go","line":42},"function":"foo.Foo"}},`+
+			`"message":"hello","ecs":{"version":"1.6.0"},`+
+			`"http":{"request":{"method":"GET"},"response":{"status_code":500}},"user":"alice",`+
+			`"error":{"message":"boom","type":"*errors.errorString","code":"E42","stack_trace":"fake-stack"}}`+"\n",
+		buf.String(),
+		"Unexpected ECS output.",
+	)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "ECS output must be valid JSON.")
+	assert.Equal(t, map[string]interface{}{
+		"message":     "boom",
+		"type":        "*errors.errorString",
+		"code":        "E42",
+		"stack_trace": "fake-stack",
+	}, decoded["error"], "Expected a single error object.")
+}
+
+func TestECSEncoderErrorWithoutStack(t *testing.T) {
+	enc := NewECSEncoder(ecsTestEncoderConfig())
+	buf, err := enc.EncodeEntry(Entry{Level: ErrorLevel, Time: _epoch, Message: "m"}, []Field{
+		{Key: "error", Type: StringType, String: "plain"},
+		{Key: "ns", Type: NamespaceType},
+		{Key: "error", Type: StringType, String: "namespaced"},
+	})
+	require.NoError(t, err, "Unexpected ECS encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t,
+		`{"@timestamp":"1970-01-01T00:00:00Z","log":{"level":"error"},`+
+			`"message":"m","ecs":{"version":"1.6.0"},`+
+			`"ns":{"error":"namespaced"},"error":{"message":"plain"}}`+"\n",
+		buf.String(),
+		"Expected only top-level error fields to move into the error object.",
+	)
+}
+
+func TestECSEncoderOmitsDisabledElements(t *testing.T) {
+	tests := []struct {
+		desc     string
+		cfg      func(*EncoderConfig)
+		ent      Entry
+		expected string
+	}{
+		{
+			desc: "no caller or stack",
+			cfg:  func(*EncoderConfig) {},
+			ent:  Entry{Level: WarnLevel, Time: _epoch, Message: "m"},
+			expected: `{"@timestamp":"1970-01-01T00:00:00Z","log":{"level":"warn"},` +
+				`"message":"m","ecs":{"version":"1.6.0"}}` + "\n",
+		},
+		{
+			desc: "empty keys",
+			cfg: func(cfg *EncoderConfig) {
+				cfg.TimeKey = ""
+				cfg.NameKey = ""
+				cfg.CallerKey = ""
+				cfg.FunctionKey = ""
+				cfg.StacktraceKey = ""
+			},
+			ent:      _testEntry,
+			expected: `{"log":{"level":"info"},"message":"hello","ecs":{"version":"1.6.0"}}` + "\n",
+		},
+		{
+			desc: "function only",
+			cfg: func(cfg *EncoderConfig) {
+				cfg.CallerKey = ""
+			},
+			ent: Entry{Level: InfoLevel, Message: "m", Caller: _testEntry.Caller},
+			expected: `{"log":{"level":"info","origin":{"function":"foo.Foo"}},` +
+				`"message":"m","ecs":{"version":"1.6.0"}}` + "\n",
+		},
+		{
+			desc: "no-op level encoder",
+			cfg: func(cfg *EncoderConfig) {
+				cfg.EncodeLevel = func(Level, PrimitiveArrayEncoder) {}
+			},
+			ent:      Entry{Level: ErrorLevel, Message: "m"},
+			expected: `{"log":{"level":"error"},"message":"m","ecs":{"version":"1.6.0"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := ecsTestEncoderConfig()
+			tt.cfg(&cfg)
+
+			buf, err := NewECSEncoder(cfg).EncodeEntry(tt.ent, nil)
+			require.NoError(t, err, "Unexpected ECS encoding error.")
+			defer buf.Free()
+
+			assert.Equal(t, tt.expected, buf.String(), "Unexpected ECS output.")
+		})
+	}
+}
+
+func TestECSEncoderClone(t *testing.T) {
+	parent := NewECSEncoder(ecsTestEncoderConfig())
+	parent.AddString("parent", "p")
+
+	child := parent.Clone()
+	child.AddString("child", "c")
+
+	ent := Entry{Level: InfoLevel, Message: "m"}
+	parentBuf, err := parent.EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	defer parentBuf.Free()
+	childBuf, err := child.EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	defer childBuf.Free()
+
+	assert.Contains(t, parentBuf.String(), `"parent":"p"}`, "Expected parent context.")
+	assert.NotContains(t, parentBuf.String(), "child", "Clone leaked context to parent.")
+	assert.Contains(t, childBuf.String(), `"parent":"p","child":"c"}`, "Expected inherited context.")
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "go.uber.org/zap/buffer"
+
+// A MetricDef declares a field that the EMF encoder publishes as a CloudWatch
+// metric.
+type MetricDef struct {
+	// Name is the key of the numeric field holding the metric's value.
+	Name string
+	// Unit is the CloudWatch unit of the metric, such as "Count",
+	// "Milliseconds" or "Percent". It's omitted if empty, in which case
+	// CloudWatch uses "None".
+	Unit string
+}
+
+type emfEncoder struct {
+	*jsonEncoder
+
+	namespace string
+	metrics   []MetricDef
+}
+
+// NewEMFEncoder creates a JSON encoder that emits CloudWatch Embedded Metric
+// Format (EMF). When an entry carries fields named after any of the declared
+// metrics, the encoder prepends an "_aws" metadata object so that CloudWatch
+// extracts those fields as metrics in the given namespace:
+//
+//	{
+//	  "_aws": {
+//	    "Timestamp": 1574109732004,
+//	    "CloudWatchMetrics": [{
+//	      "Namespace": "checkout",
+//	      "Dimensions": [[]],
+//	      "Metrics": [{"Name": "latency", "Unit": "Milliseconds"}]
+//	    }]
+//	  },
+//	  "level": "info",
+//	  ...
+//	  "latency": 42
+//	}
+//
+// All fields, including metric fields, are also written as regular JSON, as
+// EMF requires. Entries without metric fields are encoded exactly as the
+// JSON encoder would encode them.
+//
+// Only numeric fields passed to the entry itself are treated as metrics.
+// Fields added with With, and fields nested in a namespace, are always
+// written as ordinary fields.
+func NewEMFEncoder(cfg EncoderConfig, namespace string, metrics []MetricDef) Encoder {
+	return emfEncoder{
+		jsonEncoder: newJSONEncoder(cfg, false),
+		namespace:   namespace,
+		metrics:     append([]MetricDef(nil), metrics...),
+	}
+}
+
+func (enc emfEncoder) Clone() Encoder {
+	return emfEncoder{
+		jsonEncoder: enc.jsonEncoder.Clone().(*jsonEncoder),
+		namespace:   enc.namespace,
+		metrics:     enc.metrics,
+	}
+}
+
+func (enc emfEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	buf, err := enc.jsonEncoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	present := enc.presentMetrics(fields)
+	if len(present) == 0 {
+		return buf, nil
+	}
+
+	final := enc.clone()
+	final.buf.AppendByte('{')
+	final.addKey("_aws")
+	final.buf.AppendByte('{')
+	final.AddInt64("Timestamp", ent.Time.UnixNano()/1e6)
+	final.addKey("CloudWatchMetrics")
+	final.buf.AppendString(`[{`)
+	final.AddString("Namespace", enc.namespace)
+	final.addKey("Dimensions")
+	final.buf.AppendString(`[[]]`)
+	final.addKey("Metrics")
+	final.buf.AppendByte('[')
+	for _, m := range present {
+		final.addElementSeparator()
+		final.buf.AppendByte('{')
+		final.AddString("Name", m.Name)
+		if m.Unit != "" {
+			final.AddString("Unit", m.Unit)
+		}
+		final.buf.AppendByte('}')
+	}
+	final.buf.AppendString(`]}]}`)
+
+	// Splice the regular JSON object, minus its opening brace, after the
+	// metadata.
+	rest := buf.Bytes()[1:]
+	if len(rest) > 0 && rest[0] != '}' {
+		final.buf.AppendByte(',')
+	}
+	final.buf.Write(rest)
+	buf.Free()
+
+	ret := final.buf
+	putJSONEncoder(final)
+	return ret, nil
+}
+
+// presentMetrics returns the declared metrics that have a numeric field among
+// the top-level entry fields, in declaration order.
+func (enc emfEncoder) presentMetrics(fields []Field) []MetricDef {
+	if enc.openNamespaces > 0 {
+		return nil
+	}
+	var present []MetricDef
+	for _, m := range enc.metrics {
+		for _, f := range fields {
+			if f.Type == NamespaceType {
+				break
+			}
+			if f.Key == m.Name && isNumericField(f) {
+				present = append(present, m)
+				break
+			}
+		}
+	}
+	return present
+}
+
+func isNumericField(f Field) bool {
+	switch f.Type {
+	case Float64Type, Float32Type,
+		Int64Type, Int32Type, Int16Type, Int8Type,
+		Uint64Type, Uint32Type, Uint16Type, Uint8Type, UintptrType:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+var _emfTestMetrics = []MetricDef{
+	{Name: "requests", Unit: "Count"},
+	{Name: "cpu", Unit: "Percent"},
+	{Name: "queueDepth"},
+}
+
+func emfTestEncoder() Encoder {
+	cfg := EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: LowercaseLevelEncoder}
+	return NewEMFEncoder(cfg, "checkout", _emfTestMetrics)
+}
+
+func encodeEMF(t *testing.T, enc Encoder, fields []Field) string {
+	ent := Entry{Level: InfoLevel, Message: "hello", Time: time.Unix(1574109732, 4e6)}
+	buf, err := enc.EncodeEntry(ent, fields)
+	require.NoError(t, err, "Unexpected EMF encoding error.")
+	defer buf.Free()
+	return buf.String()
+}
+
+func TestEMFEncoderMetrics(t *testing.T) {
+	out := encodeEMF(t, emfTestEncoder(), []Field{
+		{Key: "cpu", Type: Float64Type, Integer: int64(math.Float64bits(72.5))},
+		{Key: "user", Type: StringType, String: "alice"},
+		{Key: "requests", Type: Int64Type, Integer: 3},
+	})
+
+	assert.Equal(t,
+		`{"_aws":{"Timestamp":1574109732004,"CloudWatchMetrics":[{"Namespace":"checkout","Dimensions":[[]],`+
+			`"Metrics":[{"Name":"requests","Unit":"Count"},{"Name":"cpu","Unit":"Percent"}]}]},`+
+			`"level":"info","msg":"hello","cpu":72.5,"user":"alice","requests":3}`+"\n",
+		out,
+		"Unexpected EMF output.",
+	)
+
+	var decoded struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []MetricDef
+			}
+		} `json:"_aws"`
+		Requests int64   `json:"requests"`
+		CPU      float64 `json:"cpu"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &decoded), "EMF output must be valid JSON.")
+	require.Len(t, decoded.AWS.CloudWatchMetrics, 1, "Expected a single metric directive.")
+	directive := decoded.AWS.CloudWatchMetrics[0]
+	assert.Equal(t, "checkout", directive.Namespace, "Unexpected namespace.")
+	assert.Equal(t, [][]string{{}}, directive.Dimensions, "Unexpected dimensions.")
+	assert.Equal(t, _emfTestMetrics[:2], directive.Metrics, "Unexpected metric definitions.")
+	assert.Equal(t, int64(3), decoded.Requests, "Counter value must be written as a regular field.")
+	assert.Equal(t, 72.5, decoded.CPU, "Gauge value must be written as a regular field.")
+}
+
+func TestEMFEncoderWithoutMetrics(t *testing.T) {
+	fields := []Field{
+		{Key: "user", Type: StringType, String: "alice"},
+		// Metric names with non-numeric values aren't metrics.
+		{Key: "requests", Type: StringType, String: "many"},
+		// Nor are metric fields nested in a namespace.
+		{Key: "ns", Type: NamespaceType},
+		{Key: "cpu", Type: Int64Type, Integer: 1},
+	}
+	assert.Equal(t,
+		`{"level":"info","msg":"hello","user":"alice","requests":"many","ns":{"cpu":1}}`+"\n",
+		encodeEMF(t, emfTestEncoder(), fields),
+		"Expected plain JSON without metric fields.",
+	)
+}
+
+func TestEMFEncoderUnitOmitted(t *testing.T) {
+	assert.Contains(t,
+		encodeEMF(t, emfTestEncoder(), []Field{{Key: "queueDepth", Type: Uint32Type, Integer: 7}}),
+		`"Metrics":[{"Name":"queueDepth"}]`,
+		"Expected metric without a unit.",
+	)
+}
+
+func TestEMFEncoderClone(t *testing.T) {
+	enc := emfTestEncoder()
+	enc.AddString("service", "api")
+	clone := enc.Clone()
+	clone.AddString("version", "1")
+
+	assert.Equal(t,
+		`{"level":"info","msg":"hello","service":"api"}`+"\n",
+		encodeEMF(t, enc, nil),
+		"Clone must not modify the original encoder.",
+	)
+	assert.Equal(t,
+		`{"_aws":{"Timestamp":1574109732004,"CloudWatchMetrics":[{"Namespace":"checkout","Dimensions":[[]],`+
+			`"Metrics":[{"Name":"requests","Unit":"Count"}]}]},`+
+			`"level":"info","msg":"hello","service":"api","version":"1","requests":1}`+"\n",
+		encodeEMF(t, clone, []Field{{Key: "requests", Type: Int64Type, Integer: 1}}),
+		"Unexpected output from clone.",
+	)
+}
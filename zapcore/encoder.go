@@ -21,11 +21,18 @@
 package zapcore
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+	"go.uber.org/zap/internal/color"
 )
 
 // DefaultLineEnding defines the default line ending when writing logs.
@@ -74,6 +81,48 @@ func CapitalColorLevelEncoder(l Level, enc PrimitiveArrayEncoder) {
 	enc.AppendString(s)
 }
 
+// A Color is an ANSI terminal foreground color, for use with
+// NewColorLevelEncoder.
+type Color uint8
+
+// Foreground colors.
+const (
+	ColorBlack Color = iota + 30
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+// NewColorLevelEncoder returns a LevelEncoder that serializes a Level to an
+// all-caps string colored as specified by colors, like
+// CapitalColorLevelEncoder but with a custom palette. Levels missing from
+// colors, including custom levels, are colored red.
+//
+// Following the NO_COLOR convention (https://no-color.org), if the NO_COLOR
+// environment variable is set to a non-empty value when the encoder is
+// built, it omits colors entirely and behaves like CapitalLevelEncoder.
+func NewColorLevelEncoder(colors map[Level]Color) LevelEncoder {
+	if os.Getenv("NO_COLOR") != "" {
+		return CapitalLevelEncoder
+	}
+
+	strs := make(map[Level]string, len(colors))
+	for l, c := range colors {
+		strs[l] = color.Color(c).Add(l.CapitalString())
+	}
+	return func(l Level, enc PrimitiveArrayEncoder) {
+		s, ok := strs[l]
+		if !ok {
+			s = _unknownLevelColor.Add(l.CapitalString())
+		}
+		enc.AppendString(s)
+	}
+}
+
 // UnmarshalText unmarshals text to a LevelEncoder. "capital" is unmarshaled to
 // CapitalLevelEncoder, "coloredCapital" is unmarshaled to CapitalColorLevelEncoder,
 // "colored" is unmarshaled to LowercaseColorLevelEncoder, and anything else
@@ -253,9 +302,60 @@ func StringDurationEncoder(d time.Duration, enc PrimitiveArrayEncoder) {
 	enc.AppendString(d.String())
 }
 
+// ISO8601DurationEncoder serializes a time.Duration to an ISO 8601 duration
+// string built from hours, minutes, and seconds, such as "PT1H2M3.5S".
+// Sub-second components are written as a decimal fraction of seconds with
+// trailing zeros removed, zero components are omitted, and a zero duration is
+// written as "PT0S". Negative durations are written with a leading minus sign,
+// as in "-PT1M30S".
+func ISO8601DurationEncoder(d time.Duration, enc PrimitiveArrayEncoder) {
+	enc.AppendString(formatISO8601Duration(d))
+}
+
+func formatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var buf [32]byte
+	b := buf[:0]
+	u := uint64(d)
+	if d < 0 {
+		b = append(b, '-')
+		u = -u // correct even for math.MinInt64
+	}
+	b = append(b, 'P', 'T')
+
+	hours := u / uint64(time.Hour)
+	u -= hours * uint64(time.Hour)
+	minutes := u / uint64(time.Minute)
+	u -= minutes * uint64(time.Minute)
+	seconds, nanos := u/uint64(time.Second), u%uint64(time.Second)
+
+	if hours > 0 {
+		b = strconv.AppendUint(b, hours, 10)
+		b = append(b, 'H')
+	}
+	if minutes > 0 {
+		b = strconv.AppendUint(b, minutes, 10)
+		b = append(b, 'M')
+	}
+	if seconds > 0 || nanos > 0 {
+		b = strconv.AppendUint(b, seconds, 10)
+		if nanos > 0 {
+			var fracBuf [10]byte
+			frac := strconv.AppendUint(fracBuf[:0], nanos+uint64(time.Second), 10)[1:] // zero-padded to 9 digits
+			b = append(b, '.')
+			b = append(b, bytes.TrimRight(frac, "0")...)
+		}
+		b = append(b, 'S')
+	}
+	return string(b)
+}
+
 // UnmarshalText unmarshals text to a DurationEncoder. "string" is unmarshaled
-// to StringDurationEncoder, and anything else is unmarshaled to
-// NanosDurationEncoder.
+// to StringDurationEncoder, "iso8601" to ISO8601DurationEncoder, and anything
+// else is unmarshaled to NanosDurationEncoder.
 func (e *DurationEncoder) UnmarshalText(text []byte) error {
 	switch string(text) {
 	case "string":
@@ -264,6 +364,8 @@ func (e *DurationEncoder) UnmarshalText(text []byte) error {
 		*e = NanosDurationEncoder
 	case "ms":
 		*e = MillisDurationEncoder
+	case "iso8601", "ISO8601":
+		*e = ISO8601DurationEncoder
 	default:
 		*e = SecondsDurationEncoder
 	}
@@ -290,6 +392,30 @@ func ShortCallerEncoder(caller EntryCaller, enc PrimitiveArrayEncoder) {
 	enc.AppendString(caller.TrimmedPath())
 }
 
+// TrimmedPathEncoder returns a CallerEncoder that serializes a caller's path
+// relative to root, such as a module's root directory, in file:line format.
+// For example, with the root "/src/app", a caller in
+// "/src/app/internal/db/query.go" is serialized as "internal/db/query.go:42".
+//
+// Binaries built with -trimpath report callers by module path rather than
+// directory, so root can also be a module path, like "example.com/app".
+// Callers outside root are serialized as by ShortCallerEncoder.
+func TrimmedPathEncoder(root string) CallerEncoder {
+	prefix := strings.TrimSuffix(filepath.ToSlash(root), "/") + "/"
+	return func(caller EntryCaller, enc PrimitiveArrayEncoder) {
+		if !caller.Defined || !strings.HasPrefix(caller.File, prefix) {
+			ShortCallerEncoder(caller, enc)
+			return
+		}
+		buf := bufferpool.Get()
+		buf.AppendString(caller.File[len(prefix):])
+		buf.AppendByte(':')
+		buf.AppendInt(int64(caller.Line))
+		enc.AppendString(buf.String())
+		buf.Free()
+	}
+}
+
 // UnmarshalText unmarshals text to a CallerEncoder. "full" is unmarshaled to
 // FullCallerEncoder and anything else is unmarshaled to ShortCallerEncoder.
 func (e *CallerEncoder) UnmarshalText(text []byte) error {
@@ -314,6 +440,32 @@ func FullNameEncoder(loggerName string, enc PrimitiveArrayEncoder) {
 	enc.AppendString(loggerName)
 }
 
+// TruncateNameEncoder returns a NameEncoder that keeps only the last
+// maxSegments period-separated segments of long logger names, replacing the
+// rest with a leading ellipsis. For example, with a limit of three, the name
+// "a.b.c.d.e" is serialized as "...c.d.e". Names with at most maxSegments
+// segments are serialized as-is, as are all names if maxSegments isn't
+// positive.
+func TruncateNameEncoder(maxSegments int) NameEncoder {
+	return func(loggerName string, enc PrimitiveArrayEncoder) {
+		if maxSegments <= 0 {
+			enc.AppendString(loggerName)
+			return
+		}
+		seen := 0
+		for i := len(loggerName) - 1; i >= 0; i-- {
+			if loggerName[i] != '.' {
+				continue
+			}
+			if seen++; seen == maxSegments {
+				enc.AppendString("..." + loggerName[i+1:])
+				return
+			}
+		}
+		enc.AppendString(loggerName)
+	}
+}
+
 // UnmarshalText unmarshals text to a NameEncoder. Currently, everything is
 // unmarshaled to FullNameEncoder.
 func (e *NameEncoder) UnmarshalText(text []byte) error {
@@ -326,6 +478,76 @@ func (e *NameEncoder) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// A StacktraceEncoder serializes an entry's stack trace, as captured by the
+// Logger's AddStacktrace option. The stack has one frame per pair of lines:
+// the function name, then a tab-indented "file:line".
+//
+// This function must make exactly one call to an ArrayEncoder's Append*
+// method.
+type StacktraceEncoder func(string, ArrayEncoder)
+
+// FullStacktraceEncoder serializes the stack trace as-is, as a multi-line
+// string.
+func FullStacktraceEncoder(stack string, enc ArrayEncoder) {
+	enc.AppendString(stack)
+}
+
+// StructuredStacktraceEncoder serializes the stack trace as an array of
+// objects with "function", "file", and "line" keys, one per frame, which is
+// easier for log processing tools to consume than the multi-line string.
+func StructuredStacktraceEncoder(stack string, enc ArrayEncoder) {
+	// Errors are impossible: stackFrames never fails.
+	_ = enc.AppendArray(stackFrames(stack))
+}
+
+// UnmarshalText unmarshals text to a StacktraceEncoder. "structured" is
+// unmarshaled to StructuredStacktraceEncoder, and anything else is
+// unmarshaled to FullStacktraceEncoder.
+func (e *StacktraceEncoder) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "structured":
+		*e = StructuredStacktraceEncoder
+	default:
+		*e = FullStacktraceEncoder
+	}
+	return nil
+}
+
+// stackFrames parses a stack trace in the format produced by the Logger.
+type stackFrames string
+
+func (s stackFrames) MarshalLogArray(arr ArrayEncoder) error {
+	rest := strings.TrimRight(string(s), "\n")
+	for rest != "" {
+		var fn, loc string
+		fn, rest, _ = strings.Cut(rest, "\n")
+		loc, rest, _ = strings.Cut(rest, "\n")
+		frame := stackFrame{function: fn, file: strings.TrimPrefix(loc, "\t")}
+		if i := strings.LastIndexByte(frame.file, ':'); i >= 0 {
+			if line, err := strconv.Atoi(frame.file[i+1:]); err == nil {
+				frame.file, frame.line = frame.file[:i], line
+			}
+		}
+		if err := arr.AppendObject(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type stackFrame struct {
+	function string
+	file     string
+	line     int
+}
+
+func (f stackFrame) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddString("function", f.function)
+	enc.AddString("file", f.file)
+	enc.AddInt("line", f.line)
+	return nil
+}
+
 // An EncoderConfig allows users to configure the concrete encoders supplied by
 // zapcore.
 type EncoderConfig struct {
@@ -350,12 +572,53 @@ type EncoderConfig struct {
 	// Unlike the other primitive type encoders, EncodeName is optional. The
 	// zero value falls back to FullNameEncoder.
 	EncodeName NameEncoder `json:"nameEncoder" yaml:"nameEncoder"`
+	// EncodeStacktrace is also optional; the zero value falls back to
+	// FullStacktraceEncoder. Supported by the JSON encoder; the console
+	// encoder always writes stack traces as-is, on their own lines.
+	EncodeStacktrace StacktraceEncoder `json:"stacktraceEncoder" yaml:"stacktraceEncoder"`
 	// Configure the encoder for interface{} type objects.
 	// If not provided, objects are encoded using json.Encoder
 	NewReflectedEncoder func(io.Writer) ReflectedEncoder `json:"-" yaml:"-"`
 	// Configures the field separator used by the console encoder. Defaults
 	// to tab.
 	ConsoleSeparator string `json:"consoleSeparator" yaml:"consoleSeparator"`
+	// Limits how deeply objects and arrays added by marshalers may nest. An
+	// object or array beyond this depth is replaced by the marker
+	// {"...":"max depth exceeded"}, which protects against marshalers that
+	// recurse indefinitely. Zero means no limit. Supported by the JSON,
+	// console, ECS, and MessagePack encoders.
+	MaxObjectDepth int `json:"maxObjectDepth" yaml:"maxObjectDepth"`
+	// Sorts each entry's fields by key before encoding them, which makes
+	// output deterministic for golden tests and diffs. Sorting is stable and
+	// happens within each namespace; fields never move across a Namespace
+	// field. Context fields added with With keep their order and precede the
+	// entry's fields, and the contents of marshaled objects aren't sorted.
+	// Sorting adds per-entry cost, so it's off by default. Supported by the
+	// JSON, console, ECS, MessagePack, and XML encoders.
+	SortFields bool `json:"sortFields" yaml:"sortFields"`
+	// Nests fields with dotted keys into objects, so that fields logged as
+	// "http.method" and "http.path" are written as
+	// {"http":{"method":...,"path":...}}. The object is written where its
+	// first field would have been. If a key is both a field's entire key and
+	// the prefix of other keys, as with "http" and "http.method", the longer
+	// keys are written flat, unchanged. Nesting applies to the fields of each
+	// entry, separately within each namespace; context fields added with
+	// With are already encoded, so they're written as-is. Supported by the
+	// JSON encoder; the ECS encoder always nests dotted keys.
+	AutoNestDottedKeys bool `json:"autoNestDottedKeys" yaml:"autoNestDottedKeys"`
+	// Indents the continuation lines of multi-line messages, such as SQL
+	// queries, so that they line up under the first line of the message
+	// rather than starting at the left margin. Tabs in the entry's metadata
+	// are kept, so alignment holds for any tab width, and ANSI color codes
+	// take no space. Only the console encoder indents messages; other
+	// encoders write them unchanged.
+	IndentMultilineMessages bool `json:"indentMultilineMessages" yaml:"indentMultilineMessages"`
+	// Makes the console encoder write objects compactly, as {k=v k2=v2},
+	// rather than as JSON. Arrays are written as [a b], and strings are
+	// quoted only if they're empty or contain spaces or syntax characters.
+	// The other fields of the context are still written as JSON, and other
+	// encoders ignore this setting.
+	ConsoleCompactObjects bool `json:"consoleCompactObjects" yaml:"consoleCompactObjects"`
 }
 
 // ObjectEncoder is a strongly-typed, encoding-agnostic interface for adding a
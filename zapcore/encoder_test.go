@@ -22,6 +22,7 @@ package zapcore_test
 
 import (
 	"encoding/json"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -584,6 +585,47 @@ func TestLevelEncoders(t *testing.T) {
 	}
 }
 
+func TestColorLevelEncoder(t *testing.T) {
+	const traceLevel = DebugLevel - 1
+
+	t.Setenv("NO_COLOR", "")
+	le := NewColorLevelEncoder(map[Level]Color{
+		InfoLevel:  ColorGreen,
+		traceLevel: ColorCyan,
+	})
+
+	tests := []struct {
+		lvl      Level
+		expected string
+	}{
+		{InfoLevel, "\x1b[32mINFO\x1b[0m"},
+		{traceLevel, "\x1b[36mLEVEL(-2)\x1b[0m"},
+		{WarnLevel, "\x1b[31mWARN\x1b[0m"}, // unlisted levels fall back to red
+	}
+	for _, tt := range tests {
+		assertAppended(
+			t,
+			tt.expected,
+			func(arr ArrayEncoder) { le(tt.lvl, arr) },
+			"Unexpected output serializing %v.", tt.lvl,
+		)
+	}
+}
+
+func TestColorLevelEncoderNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	le := NewColorLevelEncoder(map[Level]Color{InfoLevel: ColorGreen})
+
+	for _, lvl := range []Level{InfoLevel, ErrorLevel} {
+		assertAppended(
+			t,
+			lvl.CapitalString(),
+			func(arr ArrayEncoder) { le(lvl, arr) },
+			"Expected colors to be omitted for %v.", lvl,
+		)
+	}
+}
+
 func TestTimeEncoders(t *testing.T) {
 	moment := time.Unix(100, 50005000).UTC()
 	tests := []struct {
@@ -659,6 +701,8 @@ func TestDurationEncoders(t *testing.T) {
 		{"string", "1.0000005s"},
 		{"nanos", int64(1000000500)},
 		{"ms", int64(1000)},
+		{"iso8601", "PT1.0000005S"},
+		{"ISO8601", "PT1.0000005S"},
 		{"", 1.0000005},
 		{"something-random", 1.0000005},
 	}
@@ -675,6 +719,39 @@ func TestDurationEncoders(t *testing.T) {
 	}
 }
 
+func TestISO8601DurationEncoder(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "PT0S"},
+		{time.Nanosecond, "PT0.000000001S"},
+		{time.Millisecond, "PT0.001S"},
+		{1500 * time.Millisecond, "PT1.5S"},
+		{3 * time.Second, "PT3S"},
+		{time.Minute, "PT1M"},
+		{time.Minute + 250*time.Millisecond, "PT1M0.25S"},
+		{time.Hour, "PT1H"},
+		{time.Hour + 3*time.Second, "PT1H3S"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "PT1H2M3S"},
+		{time.Hour + 2*time.Minute + 3*time.Second + 40*time.Millisecond, "PT1H2M3.04S"},
+		{49 * time.Hour, "PT49H"},
+		{-90 * time.Second, "-PT1M30S"},
+		{-time.Millisecond, "-PT0.001S"},
+		{math.MaxInt64, "PT2562047H47M16.854775807S"},
+		{math.MinInt64, "-PT2562047H47M16.854775808S"},
+	}
+
+	for _, tt := range tests {
+		assertAppended(
+			t,
+			tt.expected,
+			func(arr ArrayEncoder) { ISO8601DurationEncoder(tt.d, arr) },
+			"Unexpected output serializing %v.", tt.d,
+		)
+	}
+}
+
 func TestCallerEncoders(t *testing.T) {
 	caller := EntryCaller{Defined: true, File: "/home/jack/src/github.com/foo/foo.go", Line: 42}
 	tests := []struct {
@@ -699,6 +776,41 @@ func TestCallerEncoders(t *testing.T) {
 	}
 }
 
+func TestTrimmedPathEncoder(t *testing.T) {
+	tests := []struct {
+		desc     string
+		root     string
+		file     string
+		expected string
+	}{
+		{"nested package", "/src/app", "/src/app/internal/db/query.go", "internal/db/query.go:42"},
+		{"root package", "/src/app", "/src/app/main.go", "main.go:42"},
+		{"trailing slash", "/src/app/", "/src/app/internal/db/query.go", "internal/db/query.go:42"},
+		{"module path", "example.com/app", "example.com/app/internal/db/query.go", "internal/db/query.go:42"},
+		{"outside root", "/src/app", "/go/pkg/mod/example.com/lib/x/y.go", "x/y.go:42"},
+		{"sibling with shared prefix", "/src/app", "/src/application/db/query.go", "db/query.go:42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			caller := EntryCaller{Defined: true, File: tt.file, Line: 42}
+			assertAppended(
+				t,
+				tt.expected,
+				func(arr ArrayEncoder) { TrimmedPathEncoder(tt.root)(caller, arr) },
+				"Unexpected output serializing %q relative to %q.", tt.file, tt.root,
+			)
+		})
+	}
+
+	assertAppended(
+		t,
+		"undefined",
+		func(arr ArrayEncoder) { TrimmedPathEncoder("/src/app")(EntryCaller{}, arr) },
+		"Unexpected output serializing an undefined caller.",
+	)
+}
+
 func TestNameEncoders(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -721,6 +833,72 @@ func TestNameEncoders(t *testing.T) {
 	}
 }
 
+func TestStacktraceEncoders(t *testing.T) {
+	stack := "main.main\n\t/src/main.go:12\nruntime.main\n\t/go/src/runtime/proc.go:250"
+	structured := []interface{}{
+		map[string]interface{}{"function": "main.main", "file": "/src/main.go", "line": 12},
+		map[string]interface{}{"function": "runtime.main", "file": "/go/src/runtime/proc.go", "line": 250},
+	}
+
+	tests := []struct {
+		name     string
+		stack    string
+		expected interface{}
+	}{
+		{"", stack, stack},
+		{"full", stack, stack},
+		{"something-random", stack, stack},
+		{"structured", stack, structured},
+		{"structured", stack + "\n", structured},
+		{"structured", "weird\n\tno-line", []interface{}{
+			map[string]interface{}{"function": "weird", "file": "no-line", "line": 0},
+		}},
+		{"structured", "only-function", []interface{}{
+			map[string]interface{}{"function": "only-function", "file": "", "line": 0},
+		}},
+	}
+
+	for _, tt := range tests {
+		var se StacktraceEncoder
+		require.NoError(t, se.UnmarshalText([]byte(tt.name)), "Unexpected error unmarshaling %q.", tt.name)
+		assertAppended(
+			t,
+			tt.expected,
+			func(arr ArrayEncoder) { se(tt.stack, arr) },
+			"Unexpected output serializing stack %q with %q.", tt.stack, tt.name,
+		)
+	}
+}
+
+func TestTruncateNameEncoder(t *testing.T) {
+	tests := []struct {
+		max      int
+		name     string
+		expected string
+	}{
+		{3, "", ""},
+		{3, "a", "a"},
+		{3, "a.b", "a.b"},
+		{3, "a.b.c", "a.b.c"},
+		{3, "a.b.c.d", "...b.c.d"},
+		{3, "a.b.c.d.e", "...c.d.e"},
+		{1, "a.b", "...b"},
+		{1, "a", "a"},
+		{2, "long.name.with.segments", "...with.segments"},
+		{0, "a.b.c.d", "a.b.c.d"},
+		{-1, "a.b.c.d", "a.b.c.d"},
+	}
+
+	for _, tt := range tests {
+		assertAppended(
+			t,
+			tt.expected,
+			func(arr ArrayEncoder) { TruncateNameEncoder(tt.max)(tt.name, arr) },
+			"Unexpected output truncating %q to %d segments.", tt.name, tt.max,
+		)
+	}
+}
+
 func assertAppended(t testing.TB, expected interface{}, f func(ArrayEncoder), msgAndArgs ...interface{}) {
 	mem := NewMapObjectEncoder()
 	err := mem.AddArray("k", ArrayMarshalerFunc(func(arr ArrayEncoder) error {
@@ -213,6 +213,8 @@ type CheckedEntry struct {
 	dirty       bool // best-effort detection of pool misuse
 	after       CheckWriteHook
 	cores       []Core
+	fields      []Field  // added with AddFields
+	field       [1]Field // backing storage for Write1
 }
 
 func (ce *CheckedEntry) reset() {
@@ -225,12 +227,51 @@ func (ce *CheckedEntry) reset() {
 		ce.cores[i] = nil
 	}
 	ce.cores = ce.cores[:0]
+	for i := range ce.fields {
+		ce.fields[i] = Field{}
+	}
+	ce.fields = ce.fields[:0]
+	ce.field[0] = Field{}
 }
 
 // Write writes the entry to the stored Cores, returns any errors, and returns
 // the CheckedEntry reference to a pool for immediate re-use. Finally, it
 // executes any required CheckWriteAction.
 func (ce *CheckedEntry) Write(fields ...Field) {
+	ce.write(fields, nil, false /* raw */)
+}
+
+// Write1 is like Write with a single field, but it doesn't allocate a slice
+// to hold the field: the field is stored in the pooled CheckedEntry instead.
+func (ce *CheckedEntry) Write1(f Field) {
+	if ce == nil {
+		return
+	}
+	if ce.dirty {
+		// Report the unsafe re-use without touching the entry's storage.
+		ce.write(nil, nil, false /* raw */)
+		return
+	}
+	ce.field[0] = f
+	// Cap the slice so that Cores appending to it can't clobber the entry.
+	ce.write(ce.field[:1:1], nil, false /* raw */)
+}
+
+// RawWriter is implemented by Cores that can write a pre-formatted line
+// without encoding it. See CheckedEntry.WriteRaw.
+type RawWriter interface {
+	WriteRaw(ent Entry, line []byte) error
+}
+
+// WriteRaw is like Write, but asks the stored Cores to write line as is,
+// bypassing their encoders. Cores that implement RawWriter, such as those
+// built with NewCore, write the line directly to their WriteSyncer; other
+// Cores encode the entry as usual, without fields.
+func (ce *CheckedEntry) WriteRaw(line []byte) {
+	ce.write(nil, line, true /* raw */)
+}
+
+func (ce *CheckedEntry) write(fields []Field, line []byte, raw bool) {
 	if ce == nil {
 		return
 	}
@@ -253,9 +294,18 @@ func (ce *CheckedEntry) Write(fields ...Field) {
 	}
 	ce.dirty = true
 
+	if len(ce.fields) > 0 {
+		all := make([]Field, 0, len(fields)+len(ce.fields))
+		fields = append(append(all, fields...), ce.fields...)
+	}
+
 	var err error
 	for i := range ce.cores {
-		err = multierr.Append(err, ce.cores[i].Write(ce.Entry, fields))
+		if rw, ok := ce.cores[i].(RawWriter); ok && raw {
+			err = multierr.Append(err, rw.WriteRaw(ce.Entry, line))
+		} else {
+			err = multierr.Append(err, ce.cores[i].Write(ce.Entry, fields))
+		}
 	}
 	if err != nil && ce.ErrorOutput != nil {
 		_, _ = fmt.Fprintf(
@@ -286,6 +336,18 @@ func (ce *CheckedEntry) AddCore(ent Entry, core Core) *CheckedEntry {
 	return ce
 }
 
+// AddFields adds fields to write to every Core that agreed to log this
+// CheckedEntry, after the fields passed to Write. It's intended for Loggers
+// annotating an entry once for all their Cores, and is safe to call on nil
+// CheckedEntry references. Fields aren't added to lines written with
+// WriteRaw.
+func (ce *CheckedEntry) AddFields(fields ...Field) {
+	if ce == nil {
+		return
+	}
+	ce.fields = append(ce.fields, fields...)
+}
+
 // Should sets this CheckedEntry's CheckWriteAction, which controls whether a
 // Core will panic or fatal after writing this log entry. Like AddCore, it's
 // safe to call on nil CheckedEntry references.
@@ -138,6 +138,51 @@ func TestCheckedEntryWrite(t *testing.T) {
 		ce.Write()
 		assert.True(t, hook.called, "Expected to call custom action after Write.")
 	})
+
+	t.Run("Write1 nil is safe", func(t *testing.T) {
+		var ce *CheckedEntry
+		assert.NotPanics(t, func() { ce.Write1(Field{}) }, "Unexpected panic writing nil CheckedEntry.")
+	})
+
+	t.Run("Write1", func(t *testing.T) {
+		var ce *CheckedEntry
+		hook := &fieldsHook{}
+		ce = ce.After(Entry{}, hook)
+		f := Field{Key: "k", Type: StringType, String: "v"}
+		ce.Write1(f)
+		assert.Equal(t, []Field{f}, hook.fields, "Unexpected fields passed to hook.")
+		assert.Equal(t, 1, cap(hook.fieldsCap), "Appending to the fields must not clobber the entry.")
+	})
+
+	t.Run("AddFields nil is safe", func(t *testing.T) {
+		var ce *CheckedEntry
+		assert.NotPanics(t, func() { ce.AddFields(Field{}) }, "Unexpected panic adding fields to nil CheckedEntry.")
+	})
+
+	t.Run("AddFields", func(t *testing.T) {
+		var ce *CheckedEntry
+		hook := &fieldsHook{}
+		ce = ce.After(Entry{}, hook)
+		added := Field{Key: "seq", Type: Uint64Type, Integer: 1}
+		ce.AddFields(added)
+		f := Field{Key: "k", Type: StringType, String: "v"}
+		ce.Write(f)
+		assert.Equal(t, []Field{f, added}, hook.fields, "Expected added fields after the written ones.")
+
+		ce = getCheckedEntry()
+		assert.Empty(t, ce.fields, "Expected added fields to be reset.")
+		putCheckedEntry(ce)
+	})
+}
+
+type fieldsHook struct {
+	fields    []Field
+	fieldsCap []Field
+}
+
+func (h *fieldsHook) OnWrite(_ *CheckedEntry, fields []Field) {
+	h.fields = append([]Field(nil), fields...)
+	h.fieldsCap = fields
 }
 
 type customHook struct {
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+type errorExpanderCore struct {
+	Core
+	hooks []func(Entry, []Field) []Field
+}
+
+var (
+	_ Core           = (*errorExpanderCore)(nil)
+	_ leveledEnabler = (*errorExpanderCore)(nil)
+)
+
+// NewErrorExpanderCore wraps a Core so that errors logged at ErrorLevel and
+// above are destructured into additional structured fields. For each field
+// carrying an error (as built by zap.Error or zap.NamedError), the fields
+// returned by expand are appended to the entry's fields.
+//
+// This lets applications teach zap about their domain errors; for example,
+// an error type carrying an error code and HTTP status may be expanded into
+// "code" and "status" fields. expand may return nil for errors it doesn't
+// recognize.
+//
+// Entries below ErrorLevel are passed through untouched, and fields added to
+// the logger's context with With are not expanded.
+func NewErrorExpanderCore(core Core, expand func(error) []Field) Core {
+	expandFields := func(_ Entry, fields []Field) []Field {
+		return expandErrorFields(fields, expand)
+	}
+	return &errorExpanderCore{
+		Core:  core,
+		hooks: []func(Entry, []Field) []Field{expandFields},
+	}
+}
+
+func (c *errorExpanderCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *errorExpanderCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *errorExpanderCore) With(fields []Field) Core {
+	return &errorExpanderCore{
+		Core:  c.Core.With(fields),
+		hooks: c.hooks,
+	}
+}
+
+func (c *errorExpanderCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	if ent.Level < ErrorLevel {
+		return c.Core.Check(ent, ce)
+	}
+	return checkWithFieldHooks(c.Core, ent, ce, c.hooks)
+}
+
+func expandErrorFields(fields []Field, expand func(error) []Field) []Field {
+	expanded := fields
+	for i := range fields {
+		if fields[i].Type != ErrorType {
+			continue
+		}
+		err, ok := fields[i].Interface.(error)
+		if !ok {
+			continue
+		}
+		extra := expand(err)
+		if len(extra) == 0 {
+			continue
+		}
+		if len(expanded) == len(fields) {
+			// Copy so that we don't modify the caller's slice.
+			expanded = append(make([]Field, 0, len(fields)+len(extra)), fields...)
+		}
+		expanded = append(expanded, extra...)
+	}
+	return expanded
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpError struct {
+	code   string
+	status int
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("%v (HTTP %v)", e.code, e.status)
+}
+
+func expandHTTPError(err error) []Field {
+	var herr *httpError
+	if !errors.As(err, &herr) {
+		return nil
+	}
+	return []Field{
+		{Key: "code", Type: StringType, String: herr.code},
+		{Key: "status", Type: Int64Type, Integer: int64(herr.status)},
+	}
+}
+
+func TestErrorExpanderCore(t *testing.T) {
+	herr := fmt.Errorf("load user: %w", &httpError{code: "not_found", status: 404})
+	errField := Field{Key: "error", Type: ErrorType, Interface: herr}
+	plainField := Field{Key: "error", Type: ErrorType, Interface: errors.New("boom")}
+
+	tests := []struct {
+		desc   string
+		level  Level
+		fields []Field
+		want   []Field
+	}{
+		{
+			desc:   "below error level",
+			level:  WarnLevel,
+			fields: []Field{errField},
+			want:   []Field{errField},
+		},
+		{
+			desc:   "domain error",
+			level:  ErrorLevel,
+			fields: []Field{errField},
+			want: []Field{
+				errField,
+				{Key: "code", Type: StringType, String: "not_found"},
+				{Key: "status", Type: Int64Type, Integer: 404},
+			},
+		},
+		{
+			desc:   "unrecognized error",
+			level:  DPanicLevel,
+			fields: []Field{plainField},
+			want:   []Field{plainField},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			core, logs := observer.New(InfoLevel)
+			expander := NewErrorExpanderCore(core, expandHTTPError)
+			assert.Equal(t, InfoLevel, LevelOf(expander), "Unexpected level.")
+
+			fields := append([]Field(nil), tt.fields...)
+			ce := expander.Check(Entry{Level: tt.level, Message: "msg"}, nil)
+			require.NotNil(t, ce, "Expected entry to be logged.")
+			ce.Write(fields...)
+
+			require.Equal(t, 1, logs.Len(), "Expected exactly one entry.")
+			assert.Equal(t, tt.want, logs.All()[0].Context, "Unexpected fields.")
+			assert.Equal(t, tt.fields, fields, "Caller's fields must not be modified.")
+		})
+	}
+}
+
+func TestErrorExpanderCoreRespectsTeeLevels(t *testing.T) {
+	errCore, errLogs := observer.New(ErrorLevel)
+	fatalCore, fatalLogs := observer.New(FatalLevel)
+	expander := NewErrorExpanderCore(NewTee(errCore, fatalCore), expandHTTPError)
+
+	ce := expander.With([]Field{{Key: "k", Type: StringType, String: "v"}}).
+		Check(Entry{Level: ErrorLevel, Message: "msg"}, nil)
+	require.NotNil(t, ce, "Expected entry to be logged.")
+	ce.Write(Field{Key: "error", Type: ErrorType, Interface: &httpError{code: "c", status: 500}})
+
+	require.Equal(t, 1, errLogs.Len(), "Expected error-level core to log.")
+	assert.Len(t, errLogs.All()[0].Context, 4, "Expected context and expanded fields.")
+	assert.Equal(t, 0, fatalLogs.Len(), "Fatal-level core must not log error entries.")
+
+	assert.Nil(t, expander.Check(Entry{Level: WarnLevel}, nil), "Expected warn entry to be dropped.")
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+type errorStacktraceCore struct {
+	Core
+
+	take     func(Entry) string
+	hasError bool // whether With added an error field
+}
+
+var (
+	_ Core           = (*errorStacktraceCore)(nil)
+	_ leveledEnabler = (*errorStacktraceCore)(nil)
+)
+
+// NewErrorStacktraceCore wraps a Core so that entries carrying an error,
+// that is, a field of ErrorType logged with the entry or added with With,
+// get a stack trace regardless of their level. The stack trace is taken by
+// calling take with the entry when it's written, once for all the Cores that
+// accept it. Entries that already have a stack trace are left as they are.
+//
+// Most users should use zap.AddStacktraceOnError, which takes stack traces
+// starting at the logging call site, rather than this function.
+func NewErrorStacktraceCore(core Core, take func(Entry) string) Core {
+	return &errorStacktraceCore{Core: core, take: take}
+}
+
+func (c *errorStacktraceCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *errorStacktraceCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *errorStacktraceCore) With(fields []Field) Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.hasError = c.hasError || hasErrorField(fields)
+	return &clone
+}
+
+// Check registers the Cores that accept the entry behind a single
+// downstreamWriter, so that the stack trace is taken at most once.
+func (c *errorStacktraceCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkDownstream(c.Core, ent, ce, downstreamWriter{write: c.write})
+}
+
+func hasErrorField(fields []Field) bool {
+	for i := range fields {
+		if fields[i].Type == ErrorType {
+			return true
+		}
+	}
+	return false
+}
+
+// write adds a stack trace to an entry with an error before writing it to
+// the Cores that agreed to log it.
+func (c *errorStacktraceCore) write(cores multiCore, ent Entry, fields []Field) error {
+	if ent.Stack == "" && (c.hasError || hasErrorField(fields)) {
+		ent.Stack = c.take(ent)
+	}
+	return cores.Write(ent, fields)
+}
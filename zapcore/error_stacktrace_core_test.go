@@ -0,0 +1,74 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"errors"
+	"testing"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorStacktraceCore(t *testing.T) {
+	first, firstLogs := observer.New(InfoLevel)
+	second, secondLogs := observer.New(WarnLevel)
+	var takes int
+	core := NewErrorStacktraceCore(NewTee(first, second), func(Entry) string {
+		takes++
+		return "fake-stack"
+	})
+	assert.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+
+	errField := Field{Key: "error", Type: ErrorType, Interface: errors.New("fail")}
+	writeEntry := func(core Core, ent Entry, fields ...Field) {
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write(fields...)
+		}
+	}
+
+	writeEntry(core, Entry{Level: ErrorLevel, Message: "no error"})
+	writeEntry(core, Entry{Level: WarnLevel, Message: "error"}, errField)
+	writeEntry(core.With([]Field{errField}), Entry{Level: InfoLevel, Message: "with error"})
+	writeEntry(core, Entry{Level: ErrorLevel, Message: "has stack", Stack: "level-stack"}, errField)
+	writeEntry(core, Entry{Level: DebugLevel, Message: "disabled"}, errField)
+
+	assert.Equal(t, 2, takes, "Expected one stack per written entry with an error.")
+	stacks := func(logs *observer.ObservedLogs) map[string]string {
+		out := make(map[string]string)
+		for _, e := range logs.AllUntimed() {
+			out[e.Message] = e.Stack
+		}
+		return out
+	}
+	assert.Equal(t, map[string]string{
+		"no error":   "",
+		"error":      "fake-stack",
+		"with error": "fake-stack",
+		"has stack":  "level-stack",
+	}, stacks(firstLogs), "Unexpected stacks.")
+	require.Equal(t, 3, secondLogs.Len(), "Unexpected number of entries in the second core.")
+	assert.Equal(t, "fake-stack", stacks(secondLogs)["error"], "Expected the shared stack in every core.")
+}
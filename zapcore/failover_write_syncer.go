@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	_defaultFailoverProbeInterval    = time.Second
+	_defaultFailoverMaxProbeInterval = time.Minute
+)
+
+// FailoverOption configures a WriteSyncer built by NewFailoverSyncer.
+type FailoverOption interface {
+	apply(*failoverWriteSyncer)
+}
+
+type failoverOptionFunc func(*failoverWriteSyncer)
+
+func (f failoverOptionFunc) apply(s *failoverWriteSyncer) {
+	f(s)
+}
+
+// FailoverBackoff configures how often a failover WriteSyncer probes its
+// primary after failing over to the secondary. The first probe happens after
+// initial; each failed probe doubles the delay, up to max.
+//
+// Defaults to probing after one second, backing off to at most one minute.
+func FailoverBackoff(initial, max time.Duration) FailoverOption {
+	return failoverOptionFunc(func(s *failoverWriteSyncer) {
+		s.initialBackoff = initial
+		s.maxBackoff = max
+	})
+}
+
+// FailoverClock sets the source of time used to schedule probes of the
+// primary. Defaults to the system clock.
+func FailoverClock(clock Clock) FailoverOption {
+	return failoverOptionFunc(func(s *failoverWriteSyncer) {
+		s.clock = clock
+	})
+}
+
+type failoverWriteSyncer struct {
+	primary, secondary WriteSyncer
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	clock          Clock
+
+	mu         sync.Mutex
+	failedOver bool
+	backoff    time.Duration
+	nextProbe  time.Time
+}
+
+// NewFailoverSyncer creates a WriteSyncer that writes to primary and, if a
+// write to primary fails, transparently switches to secondary. The write that
+// triggered the failover is retried on secondary, so it isn't lost.
+//
+// While failed over, writes periodically probe the primary (with exponential
+// backoff; see FailoverBackoff). The probe is the write itself: if it
+// succeeds, the WriteSyncer switches back to the primary, and if it fails,
+// the write is retried on the secondary.
+//
+// A typical use is a network WriteSyncer with a local-file fallback.
+//
+// Sync flushes whichever WriteSyncer is currently active. The secondary is
+// also flushed when switching back to the primary.
+//
+// The returned WriteSyncer is safe for concurrent use, and serializes writes
+// to both primary and secondary.
+func NewFailoverSyncer(primary, secondary WriteSyncer, opts ...FailoverOption) WriteSyncer {
+	s := &failoverWriteSyncer{
+		primary:        primary,
+		secondary:      secondary,
+		initialBackoff: _defaultFailoverProbeInterval,
+		maxBackoff:     _defaultFailoverMaxProbeInterval,
+		clock:          DefaultClock,
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	if s.maxBackoff < s.initialBackoff {
+		s.maxBackoff = s.initialBackoff
+	}
+	return s
+}
+
+func (s *failoverWriteSyncer) Write(bs []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failedOver {
+		now := s.clock.Now()
+		if now.Before(s.nextProbe) {
+			return s.secondary.Write(bs)
+		}
+
+		if n, err := s.primary.Write(bs); err == nil {
+			s.failedOver = false
+			// Flush anything we wrote to the secondary while the primary was
+			// unavailable. The primary is healthy, so the caller doesn't need
+			// to hear about the secondary's problems.
+			_ = s.secondary.Sync()
+			return n, nil
+		}
+
+		s.backoff *= 2
+		if s.backoff > s.maxBackoff {
+			s.backoff = s.maxBackoff
+		}
+		s.nextProbe = now.Add(s.backoff)
+		return s.secondary.Write(bs)
+	}
+
+	n, err := s.primary.Write(bs)
+	if err == nil {
+		return n, nil
+	}
+
+	s.failedOver = true
+	s.backoff = s.initialBackoff
+	s.nextProbe = s.clock.Now().Add(s.backoff)
+	return s.secondary.Write(bs)
+}
+
+func (s *failoverWriteSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failedOver {
+		return s.secondary.Sync()
+	}
+	return s.primary.Sync()
+}
+
+func (s *failoverWriteSyncer) Describe() string {
+	return DescribeWriteSyncer(s.primary) + " (failover: " + DescribeWriteSyncer(s.secondary) + ")"
+}
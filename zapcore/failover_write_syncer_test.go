@@ -0,0 +1,149 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+// flakyWriter is a WriteSyncer whose writes fail while it's marked down.
+type flakyWriter struct {
+	ztest.Buffer
+
+	mu       sync.Mutex
+	down     bool
+	attempts int
+}
+
+func (w *flakyWriter) setDown(down bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.down = down
+}
+
+func (w *flakyWriter) Write(bs []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.attempts++
+	if w.down {
+		return 0, errors.New("primary unavailable")
+	}
+	return w.Buffer.Write(bs)
+}
+
+func TestFailoverSyncerHealthyPrimary(t *testing.T) {
+	primary := &flakyWriter{}
+	secondary := &ztest.Buffer{}
+	ws := NewFailoverSyncer(primary, secondary)
+
+	n, err := ws.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, n, "Unexpected number of bytes written.")
+	require.NoError(t, ws.Sync())
+
+	assert.Equal(t, []string{"foo"}, primary.Lines(), "Expected write to primary.")
+	assert.True(t, primary.Called(), "Expected primary to be synced.")
+	assert.Empty(t, secondary.String(), "Unexpected write to secondary.")
+	assert.False(t, secondary.Called(), "Unexpected sync of secondary.")
+}
+
+func TestFailoverSyncerFlappingPrimary(t *testing.T) {
+	clock := ztest.NewMockClock()
+	primary := &flakyWriter{}
+	secondary := &ztest.Buffer{}
+	ws := NewFailoverSyncer(primary, secondary,
+		FailoverClock(clock),
+		FailoverBackoff(time.Second, 4*time.Second),
+	)
+
+	write := func(s string) {
+		_, err := ws.Write([]byte(s + "\n"))
+		require.NoError(t, err, "Unexpected write error.")
+	}
+
+	write("1")
+	primary.setDown(true)
+	write("2") // fails over, retried on secondary
+	write("3") // before the first probe: straight to secondary
+	assert.Equal(t, 2, primary.attempts, "Didn't expect to probe the primary yet.")
+
+	clock.Add(time.Second)
+	write("4") // probe fails, backoff doubles to 2s
+	assert.Equal(t, 3, primary.attempts, "Expected to probe the primary.")
+
+	clock.Add(time.Second)
+	write("5") // still backing off
+	assert.Equal(t, 3, primary.attempts, "Expected backoff to double.")
+
+	clock.Add(time.Second)
+	write("6") // probe fails, backoff capped at 4s
+
+	clock.Add(4 * time.Second)
+	primary.setDown(false)
+	write("7") // probe succeeds, switch back
+	write("8")
+
+	assert.Equal(t, []string{"1", "7", "8"}, primary.Lines(), "Unexpected writes to primary.")
+	assert.Equal(t, []string{"2", "3", "4", "5", "6"}, secondary.Lines(), "Unexpected writes to secondary.")
+	assert.True(t, secondary.Called(), "Expected secondary to be synced on switching back.")
+
+	// Failing again restarts the backoff from the initial interval.
+	primary.setDown(true)
+	write("9")
+	clock.Add(time.Second)
+	primary.setDown(false)
+	write("10")
+	assert.Equal(t, []string{"1", "7", "8", "10"}, primary.Lines(), "Expected backoff to reset.")
+	assert.Equal(t, []string{"2", "3", "4", "5", "6", "9"}, secondary.Lines(), "Unexpected writes to secondary.")
+}
+
+func TestFailoverSyncerSyncsActive(t *testing.T) {
+	primary := &flakyWriter{}
+	secondary := &ztest.Buffer{}
+	secondary.SetError(errors.New("sync failed"))
+	ws := NewFailoverSyncer(primary, secondary)
+
+	primary.setDown(true)
+	_, err := ws.Write([]byte("foo\n"))
+	require.NoError(t, err)
+
+	assert.EqualError(t, ws.Sync(), "sync failed", "Expected to sync the secondary.")
+	assert.False(t, primary.Called(), "Didn't expect to sync the primary.")
+}
+
+func TestFailoverSyncerBothFail(t *testing.T) {
+	primary := &flakyWriter{}
+	primary.setDown(true)
+	ws := NewFailoverSyncer(primary, &ztest.FailWriter{})
+
+	_, err := ws.Write([]byte("foo\n"))
+	assert.Error(t, err, "Expected the secondary's error.")
+}
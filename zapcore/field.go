@@ -22,9 +22,11 @@ package zapcore
 
 import (
 	"bytes"
+	"encoding"
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -96,6 +98,9 @@ const (
 	// InlineMarshalerType indicates that the field carries an ObjectMarshaler
 	// that should be inlined.
 	InlineMarshalerType
+	// TextMarshalerType indicates that the field carries an
+	// encoding.TextMarshaler.
+	TextMarshalerType
 )
 
 // A Field is a marshaling operation used to add a key-value pair to a logger's
@@ -174,6 +179,8 @@ func (f Field) AddTo(enc ObjectEncoder) {
 		err = encodeStringer(f.Key, f.Interface, enc)
 	case ErrorType:
 		err = encodeError(f.Key, f.Interface.(error), enc)
+	case TextMarshalerType:
+		err = encodeTextMarshaler(f.Key, f.Interface, enc)
 	case SkipType:
 		break
 	default:
@@ -211,6 +218,142 @@ func addFields(enc ObjectEncoder, fields []Field) {
 	}
 }
 
+// sortFields orders fields by key within each namespace, keeping duplicate
+// keys in their original order. Namespace fields stay where they are, so
+// fields never move across a namespace boundary. Fields that are already
+// sorted are returned as-is; otherwise, the caller's slice is copied rather
+// than reordered in place.
+func sortFields(fields []Field) []Field {
+	if fieldsSorted(fields) {
+		return fields
+	}
+	sorted := make([]Field, len(fields))
+	copy(sorted, fields)
+	start := 0
+	for i := 0; i <= len(sorted); i++ {
+		if i < len(sorted) && sorted[i].Type != NamespaceType {
+			continue
+		}
+		// Entries rarely carry more than a handful of fields, so a stable
+		// insertion sort beats sort.SliceStable and doesn't allocate.
+		for j := start + 1; j < i; j++ {
+			for k := j; k > start && sorted[k].Key < sorted[k-1].Key; k-- {
+				sorted[k], sorted[k-1] = sorted[k-1], sorted[k]
+			}
+		}
+		start = i + 1
+	}
+	return sorted
+}
+
+func fieldsSorted(fields []Field) bool {
+	for i := 1; i < len(fields); i++ {
+		if fields[i].Type == NamespaceType || fields[i-1].Type == NamespaceType {
+			continue
+		}
+		if fields[i].Key < fields[i-1].Key {
+			return false
+		}
+	}
+	return true
+}
+
+// nestDottedKeys groups fields whose keys share a dotted prefix, like
+// "http.method" and "http.path", into an object field named after the prefix
+// and placed where the prefix first appears. Grouping is repeated within each
+// object, and happens separately within each namespace. If a key is both a
+// field's entire key and a prefix of other keys in the same scope, those
+// longer keys are left as-is. Fields without dotted keys are returned as-is.
+func nestDottedKeys(fields []Field) []Field {
+	if !hasDottedKey(fields) {
+		return fields
+	}
+	nested := make([]Field, 0, len(fields))
+	for len(fields) > 0 {
+		end := 0
+		for end < len(fields) && fields[end].Type != NamespaceType {
+			end++
+		}
+		nested = appendNestedScope(nested, fields[:end])
+		if end < len(fields) {
+			nested = append(nested, fields[end]) // the namespace
+			end++
+		}
+		fields = fields[end:]
+	}
+	return nested
+}
+
+func hasDottedKey(fields []Field) bool {
+	for _, f := range fields {
+		if _, _, ok := cutDottedKey(f); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cutDottedKey splits a field's key at its first dot.
+func cutDottedKey(f Field) (prefix, rest string, ok bool) {
+	switch f.Type {
+	case NamespaceType, InlineMarshalerType, SkipType:
+		return "", "", false
+	}
+	idx := strings.IndexByte(f.Key, '.')
+	if idx <= 0 || idx == len(f.Key)-1 {
+		return "", "", false
+	}
+	return f.Key[:idx], f.Key[idx+1:], true
+}
+
+// appendNestedScope appends the fields of a single namespace to nested,
+// grouping them by dotted prefix.
+func appendNestedScope(nested, scope []Field) []Field {
+	type group struct {
+		prefix string
+		fields *dottedFields
+	}
+	var groups []group
+
+	isKey := func(key string) bool {
+		for _, f := range scope {
+			if f.Key == key {
+				return true
+			}
+		}
+		return false
+	}
+
+fields:
+	for _, f := range scope {
+		prefix, rest, ok := cutDottedKey(f)
+		if !ok || isKey(prefix) {
+			nested = append(nested, f)
+			continue
+		}
+		f.Key = rest
+		for _, g := range groups {
+			if g.prefix == prefix {
+				*g.fields = append(*g.fields, f)
+				continue fields
+			}
+		}
+		g := group{prefix: prefix, fields: &dottedFields{f}}
+		groups = append(groups, g)
+		nested = append(nested, Field{Key: prefix, Type: ObjectMarshalerType, Interface: g.fields})
+	}
+	return nested
+}
+
+// dottedFields are the fields grouped under a dotted prefix by
+// nestDottedKeys, with the prefix removed from their keys.
+type dottedFields []Field
+
+func (fs *dottedFields) MarshalLogObject(enc ObjectEncoder) error {
+	addFields(enc, nestDottedKeys(*fs))
+	return nil
+}
+
 func encodeStringer(key string, stringer interface{}, enc ObjectEncoder) (retErr error) {
 	// Try to capture panics (from nil references or otherwise) when calling
 	// the String() method, similar to https://golang.org/src/fmt/print.go#L540
@@ -231,3 +374,24 @@ func encodeStringer(key string, stringer interface{}, enc ObjectEncoder) (retErr
 	enc.AddString(key, stringer.(fmt.Stringer).String())
 	return nil
 }
+
+func encodeTextMarshaler(key string, marshaler interface{}, enc ObjectEncoder) (retErr error) {
+	// As with Stringers, capture panics from nil references.
+	defer func() {
+		if err := recover(); err != nil {
+			if v := reflect.ValueOf(marshaler); v.Kind() == reflect.Ptr && v.IsNil() {
+				enc.AddString(key, "<nil>")
+				return
+			}
+
+			retErr = fmt.Errorf("PANIC=%v", err)
+		}
+	}()
+
+	text, err := marshaler.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return err
+	}
+	enc.AddByteString(key, text)
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+type fieldHooked struct {
+	Core
+	funcs []func(Entry, []Field) []Field
+}
+
+var (
+	_ Core           = (*fieldHooked)(nil)
+	_ leveledEnabler = (*fieldHooked)(nil)
+)
+
+// RegisterFieldHooks wraps a Core and runs a collection of user-defined
+// callback hooks each time a message is logged. Each hook receives the entry
+// and its fields, and returns the fields to write instead; this allows
+// adding fields computed at the time of logging, such as a sequence number.
+// Hooks run in order, each receiving the fields returned by the previous one.
+//
+// Hooks run when the entry is written, on the goroutine that writes it, and
+// run once for every Core that accepts the entry. Hooks must not modify the
+// slice they're given; to add fields, append to a copy. Fields added to the
+// Core with With aren't passed to hooks.
+func RegisterFieldHooks(core Core, hooks ...func(Entry, []Field) []Field) Core {
+	funcs := append([]func(Entry, []Field) []Field{}, hooks...)
+	return &fieldHooked{
+		Core:  core,
+		funcs: funcs,
+	}
+}
+
+func (h *fieldHooked) Level() Level {
+	return LevelOf(h.Core)
+}
+
+func (h *fieldHooked) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkWithFieldHooks(h.Core, ent, ce, h.funcs)
+}
+
+func (h *fieldHooked) Describe() []CoreInfo {
+	return DescribeCores(h.Core)
+}
+
+func (h *fieldHooked) With(fields []Field) Core {
+	return &fieldHooked{
+		Core:  h.Core.With(fields),
+		funcs: h.funcs,
+	}
+}
+
+// checkWithFieldHooks lets core decide which of its Cores will log the
+// entry, then registers each of them with ce so that writes to them first
+// run the given hooks. This keeps the filtering done by core's Check (for
+// example, the per-Core levels of a Tee) intact.
+func checkWithFieldHooks(core Core, ent Entry, ce *CheckedEntry, funcs []func(Entry, []Field) []Field) *CheckedEntry {
+	downstream := core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	for _, c := range downstream.cores {
+		ce = ce.AddCore(ent, &fieldHookedWriter{core: c, funcs: funcs})
+	}
+	if downstream.after != nil {
+		ce = ce.After(ent, downstream.after)
+	}
+	putCheckedEntry(downstream)
+	return ce
+}
+
+// fieldHookedWriter is a Core that runs field hooks before writing to a Core
+// that has already agreed to log an entry.
+type fieldHookedWriter struct {
+	core  Core
+	funcs []func(Entry, []Field) []Field
+}
+
+func (w *fieldHookedWriter) Enabled(lvl Level) bool {
+	return w.core.Enabled(lvl)
+}
+
+func (w *fieldHookedWriter) With(fields []Field) Core {
+	return &fieldHookedWriter{core: w.core.With(fields), funcs: w.funcs}
+}
+
+func (w *fieldHookedWriter) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return w.core.Check(ent, ce)
+}
+
+func (w *fieldHookedWriter) Write(ent Entry, fields []Field) error {
+	for _, f := range w.funcs {
+		fields = f(ent, fields)
+	}
+	return w.core.Write(ent, fields)
+}
+
+// WriteRaw writes line as is: there are no fields for hooks to change.
+func (w *fieldHookedWriter) WriteRaw(ent Entry, line []byte) error {
+	if rw, ok := w.core.(RawWriter); ok {
+		return rw.WriteRaw(ent, line)
+	}
+	return w.Write(ent, nil)
+}
+
+func (w *fieldHookedWriter) Sync() error {
+	return w.core.Sync()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldHooks(t *testing.T) {
+	infoCore, infoLogs := observer.New(InfoLevel)
+	errCore, errLogs := observer.New(ErrorLevel)
+
+	var seq int
+	next := func(_ Entry, fields []Field) []Field {
+		seq++
+		return append(fields[:len(fields):len(fields)], makeInt64Field("seq", seq))
+	}
+	levelName := func(ent Entry, fields []Field) []Field {
+		return append(fields[:len(fields):len(fields)], Field{Key: "lvl", Type: StringType, String: ent.Level.String()})
+	}
+
+	core := RegisterFieldHooks(NewTee(infoCore, errCore), next, levelName)
+	require.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+	core = core.With([]Field{makeInt64Field("ctx", 1)})
+
+	assert.Nil(t, core.Check(Entry{Level: DebugLevel}, nil), "Expected debug entry to be dropped.")
+
+	fields := []Field{makeInt64Field("f", 2)}
+	core.Check(Entry{Level: InfoLevel, Message: "info"}, nil).Write(fields...)
+	assert.Equal(t, []Field{makeInt64Field("f", 2)}, fields, "Hooks must not modify the caller's fields.")
+	assert.Equal(t, 0, errLogs.Len(), "Expected error-level Core to skip info entry.")
+	assert.Equal(t, []observer.LoggedEntry{{
+		Entry: Entry{Level: InfoLevel, Message: "info"},
+		Context: []Field{
+			makeInt64Field("ctx", 1),
+			makeInt64Field("f", 2),
+			makeInt64Field("seq", 1),
+			{Key: "lvl", Type: StringType, String: "info"},
+		},
+	}}, infoLogs.TakeAll(), "Unexpected info entry.")
+
+	// Hooks run once for each Core that accepts the entry.
+	core.Check(Entry{Level: ErrorLevel, Message: "err"}, nil).Write()
+	require.Equal(t, 1, infoLogs.Len(), "Expected info-level Core to log error entry.")
+	require.Equal(t, 1, errLogs.Len(), "Expected error-level Core to log error entry.")
+	assert.Equal(t, makeInt64Field("seq", 2), infoLogs.All()[0].Context[1], "Unexpected sequence number.")
+	assert.Equal(t, makeInt64Field("seq", 3), errLogs.All()[0].Context[1], "Unexpected sequence number.")
+}
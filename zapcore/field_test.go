@@ -94,6 +94,20 @@ func (eobj *errObj) Error() string {
 	return eobj.errMsg
 }
 
+type textObj struct {
+	kind int
+}
+
+func (o *textObj) MarshalText() ([]byte, error) {
+	switch o.kind {
+	case 1:
+		panic("panic in MarshalText() method")
+	case 2:
+		return nil, errors.New("can't marshal")
+	}
+	return []byte("text obj"), nil
+}
+
 func TestUnknownFieldType(t *testing.T) {
 	unknown := Field{Key: "k", String: "foo"}
 	assert.Equal(t, UnknownType, unknown.Type, "Expected zero value of FieldType to be UnknownType.")
@@ -118,6 +132,8 @@ func TestFieldAddingError(t *testing.T) {
 		{t: StringerType, iface: &obj{2}, want: empty, err: "PANIC=panic with error"},
 		{t: StringerType, iface: &obj{3}, want: empty, err: "PANIC=<nil>"},
 		{t: ErrorType, iface: &errObj{kind: 1}, want: empty, err: "PANIC=panic in Error() method"},
+		{t: TextMarshalerType, iface: &textObj{kind: 1}, want: empty, err: "PANIC=panic in MarshalText() method"},
+		{t: TextMarshalerType, iface: &textObj{kind: 2}, want: empty, err: "can't marshal"},
 	}
 	for _, tt := range tests {
 		f := Field{Key: "k", Interface: tt.iface, Type: tt.t}
@@ -166,6 +182,8 @@ func TestFields(t *testing.T) {
 		{t: StringerType, iface: (*url.URL)(nil), want: "<nil>"},
 		{t: StringerType, iface: (*users)(nil), want: "<nil>"},
 		{t: ErrorType, iface: (*errObj)(nil), want: "<nil>"},
+		{t: TextMarshalerType, iface: &textObj{}, want: "text obj"},
+		{t: TextMarshalerType, iface: (*textObj)(nil), want: "<nil>"},
 	}
 
 	for _, tt := range tests {
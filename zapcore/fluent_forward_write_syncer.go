@@ -0,0 +1,214 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"encoding/binary"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+)
+
+// _fluentEventTimeExt is the MessagePack extension type for Fluentd's
+// EventTime.
+const _fluentEventTimeExt byte = 0
+
+type fluentForwardWriteSyncer struct {
+	ws    WriteSyncer
+	tag   string
+	clock Clock
+}
+
+// NewFluentForwardSyncer wraps a WriteSyncer so that each write is framed as
+// a Fluentd forward protocol message, [tag, time, record]. Each write must
+// be a single MessagePack map, as produced by NewMsgpackEncoder. The time,
+// encoded as an EventTime with nanosecond precision, is taken from the
+// record: it's the first top-level value encoded with the MessagePack
+// timestamp extension type, which is the entry's time if the encoder's
+// EncodeTime is nil. This keeps the entry's time even if writes are
+// buffered or made asynchronously. Records without such a value get the
+// time of the write.
+//
+// A typical destination is a TCP connection to a Fluentd or Fluent Bit
+// forward input, wrapped with AddSync and Lock. Each message is written to
+// ws with a single call to Write.
+func NewFluentForwardSyncer(ws WriteSyncer, tag string) WriteSyncer {
+	return &fluentForwardWriteSyncer{
+		ws:    ws,
+		tag:   tag,
+		clock: DefaultClock,
+	}
+}
+
+func (s *fluentForwardWriteSyncer) Write(record []byte) (int, error) {
+	buf := bufferpool.Get()
+	defer buf.Free()
+
+	appendMsgpackArrayHeader(buf, 3)
+	appendMsgpackString(buf, s.tag)
+	t, ok := firstMsgpackTimestamp(record)
+	if !ok {
+		t = s.clock.Now()
+	}
+	appendFluentEventTime(buf, t)
+	buf.Write(record)
+
+	if _, err := s.ws.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(record), nil
+}
+
+func (s *fluentForwardWriteSyncer) Sync() error {
+	return s.ws.Sync()
+}
+
+func (s *fluentForwardWriteSyncer) Describe() string {
+	return "fluent forward " + DescribeWriteSyncer(s.ws)
+}
+
+func appendFluentEventTime(buf *buffer.Buffer, t time.Time) {
+	buf.AppendByte(0xd7) // fixext 8
+	buf.AppendByte(_fluentEventTimeExt)
+	appendBigEndian(buf, uint64(t.Unix()), 4)
+	appendBigEndian(buf, uint64(t.Nanosecond()), 4)
+}
+
+// firstMsgpackTimestamp returns the first value in the MessagePack map
+// record that's encoded with the timestamp extension type.
+func firstMsgpackTimestamp(record []byte) (time.Time, bool) {
+	var n int
+	switch {
+	case len(record) >= 1 && record[0] >= 0x80 && record[0] <= 0x8f: // fixmap
+		n, record = int(record[0]&0x0f), record[1:]
+	case len(record) >= 3 && record[0] == 0xde: // map 16
+		n, record = int(binary.BigEndian.Uint16(record[1:])), record[3:]
+	case len(record) >= 5 && record[0] == 0xdf: // map 32
+		n, record = int(binary.BigEndian.Uint32(record[1:])), record[5:]
+	default:
+		return time.Time{}, false
+	}
+
+	for ; n > 0; n-- {
+		var ok bool
+		if record, ok = skipMsgpackValues(record, 1); !ok { // key
+			return time.Time{}, false
+		}
+		if t, ok := readMsgpackTimestamp(record); ok {
+			return t, true
+		}
+		if record, ok = skipMsgpackValues(record, 1); !ok {
+			return time.Time{}, false
+		}
+	}
+	return time.Time{}, false
+}
+
+// readMsgpackTimestamp decodes a value encoded by appendMsgpackTimestamp.
+func readMsgpackTimestamp(b []byte) (time.Time, bool) {
+	const ext = byte(_msgpackTimestampExt)
+	switch {
+	case len(b) >= 6 && b[0] == 0xd6 && b[1] == ext: // fixext 4
+		return time.Unix(int64(binary.BigEndian.Uint32(b[2:])), 0), true
+	case len(b) >= 10 && b[0] == 0xd7 && b[1] == ext: // fixext 8
+		v := binary.BigEndian.Uint64(b[2:])
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34)), true
+	case len(b) >= 15 && b[0] == 0xc7 && b[1] == 12 && b[2] == ext: // ext 8
+		nsec := binary.BigEndian.Uint32(b[3:])
+		return time.Unix(int64(binary.BigEndian.Uint64(b[7:])), int64(nsec)), true
+	}
+	return time.Time{}, false
+}
+
+// skipMsgpackValues skips n MessagePack values, including any values nested
+// in them, and returns the remaining bytes. It reports false if b is
+// truncated or malformed.
+func skipMsgpackValues(b []byte, n int) ([]byte, bool) {
+	// length reads a big-endian length of the given width after the first
+	// byte, noting if b is too short.
+	var short bool
+	length := func(width int) int {
+		if len(b) < 1+width {
+			short = true
+			return 0
+		}
+		var v int
+		for _, c := range b[1 : 1+width] {
+			v = v<<8 | int(c)
+		}
+		return v
+	}
+
+	for ; n > 0; n-- {
+		if len(b) == 0 {
+			return nil, false
+		}
+		var size, nested int
+		switch c := b[0]; {
+		case c <= 0x7f || c >= 0xe0 || c == 0xc0 || c == 0xc2 || c == 0xc3:
+			size = 1 // fixint, nil, and bool
+		case c <= 0x8f: // fixmap
+			size, nested = 1, 2*int(c&0x0f)
+		case c <= 0x9f: // fixarray
+			size, nested = 1, int(c&0x0f)
+		case c <= 0xbf: // fixstr
+			size = 1 + int(c&0x1f)
+		case c == 0xc4 || c == 0xd9: // bin 8, str 8
+			size = 2 + length(1)
+		case c == 0xc5 || c == 0xda: // bin 16, str 16
+			size = 3 + length(2)
+		case c == 0xc6 || c == 0xdb: // bin 32, str 32
+			size = 5 + length(4)
+		case c == 0xc7: // ext 8
+			size = 3 + length(1)
+		case c == 0xc8: // ext 16
+			size = 4 + length(2)
+		case c == 0xc9: // ext 32
+			size = 6 + length(4)
+		case c == 0xcc || c == 0xd0: // uint 8, int 8
+			size = 2
+		case c == 0xcd || c == 0xd1: // uint 16, int 16
+			size = 3
+		case c == 0xca || c == 0xce || c == 0xd2: // float 32, uint 32, int 32
+			size = 5
+		case c == 0xcb || c == 0xcf || c == 0xd3: // float 64, uint 64, int 64
+			size = 9
+		case c >= 0xd4 && c <= 0xd8: // fixext 1, 2, 4, 8, and 16
+			size = 2 + 1<<(c-0xd4)
+		case c == 0xdc: // array 16
+			size, nested = 3, length(2)
+		case c == 0xdd: // array 32
+			size, nested = 5, length(4)
+		case c == 0xde: // map 16
+			size, nested = 3, 2*length(2)
+		case c == 0xdf: // map 32
+			size, nested = 5, 2*length(4)
+		default:
+			return nil, false
+		}
+		if short || size > len(b) {
+			return nil, false
+		}
+		b, n = b[size:], n+nested
+	}
+	return b, true
+}
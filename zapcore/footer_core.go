@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "sync"
+
+type footerCore struct {
+	Core
+	state *footerState // shared with Cores derived with With
+}
+
+type footerState struct {
+	footer func() []byte
+
+	mu      sync.Mutex
+	roots   map[*ioCore]struct{}             // Cores built by NewCore already seen
+	syncers map[*TimeRotatingSyncer]struct{} // syncers given the footer
+}
+
+var (
+	_ Core           = (*footerCore)(nil)
+	_ leveledEnabler = (*footerCore)(nil)
+)
+
+// NewFooterCore wraps a Core so that the output of footer is appended to each
+// file its outputs finish writing. Footers are written by the
+// TimeRotatingSyncers that Cores built by NewCore write to, directly or
+// through Lock or NewMultiWriteSyncer, exactly as if they had been added with
+// TimeRotatingFooter: once for each file a syncer closes. Other outputs,
+// including plain files, don't get a footer.
+//
+// The footer is added to a syncer when the first entry is written to it,
+// once for the returned Core and the Cores derived from it with With, even
+// if several of them write to the same syncer.
+func NewFooterCore(core Core, footer func() []byte) Core {
+	return &footerCore{
+		Core: core,
+		state: &footerState{
+			footer:  footer,
+			roots:   make(map[*ioCore]struct{}),
+			syncers: make(map[*TimeRotatingSyncer]struct{}),
+		},
+	}
+}
+
+func (c *footerCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *footerCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *footerCore) With(fields []Field) Core {
+	return &footerCore{
+		Core:  c.Core.With(fields),
+		state: c.state,
+	}
+}
+
+// Check registers the Cores that accept the entry behind a single
+// downstreamWriter, so that the footer is added before the entry opens a
+// file.
+func (c *footerCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkDownstream(c.Core, ent, ce, downstreamWriter{write: c.state.write})
+}
+
+// write adds the footer to the syncers of the Cores that agreed to log an
+// entry, if they don't have it yet, before writing the entry.
+func (s *footerState) write(cores multiCore, ent Entry, fields []Field) error {
+	for _, core := range cores {
+		if ioc, ok := core.(*ioCore); ok {
+			s.add(ioc.root)
+		}
+	}
+	return cores.Write(ent, fields)
+}
+
+func (s *footerState) add(root *ioCore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.roots[root]; ok {
+		return
+	}
+	s.roots[root] = struct{}{}
+	for _, ts := range timeRotatingSyncers(root.out) {
+		if _, ok := s.syncers[ts]; !ok {
+			s.syncers[ts] = struct{}{}
+			ts.addFooter(s.footer)
+		}
+	}
+}
+
+// timeRotatingSyncers returns the TimeRotatingSyncers that ws writes to,
+// looking through Lock and NewMultiWriteSyncer.
+func timeRotatingSyncers(ws WriteSyncer) []*TimeRotatingSyncer {
+	switch w := ws.(type) {
+	case *TimeRotatingSyncer:
+		return []*TimeRotatingSyncer{w}
+	case *lockedWriteSyncer:
+		return timeRotatingSyncers(w.ws)
+	case multiWriteSyncer:
+		var all []*TimeRotatingSyncer
+		for _, each := range w {
+			all = append(all, timeRotatingSyncers(each)...)
+		}
+		return all
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFooterCore(t *testing.T) {
+	dir := t.TempDir()
+	clock := ztest.NewMockClock()
+	now := clock.Now()
+	clock.Add(now.Truncate(time.Hour).Add(time.Hour - time.Second).Sub(now))
+	first := clock.Now().Truncate(time.Hour)
+
+	ws := NewTimeRotatingSyncer(filepath.Join(dir, "app-%H.log"), time.Hour, TimeRotatingClock(clock))
+	enc := NewJSONEncoder(EncoderConfig{MessageKey: "msg"})
+	var footers int
+	core := NewFooterCore(
+		// Both Cores write to ws, which should still get a single footer.
+		NewTee(NewCore(enc, Lock(ws), InfoLevel), NewCore(enc, ws, WarnLevel)),
+		func() []byte {
+			footers++
+			return []byte("# footer " + strconv.Itoa(footers) + "\n")
+		},
+	)
+	require.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+
+	write := func(core Core, lvl Level, msg string) {
+		ce := core.Check(Entry{Level: lvl, Message: msg}, nil)
+		require.NotNil(t, ce, "Expected %q to be logged.", msg)
+		ce.Write()
+	}
+	read := func(ts time.Time) string {
+		bs, err := os.ReadFile(filepath.Join(dir, ts.Format("app-15.log")))
+		require.NoError(t, err, "Failed to read log file.")
+		return string(bs)
+	}
+
+	write(core, InfoLevel, "a")
+	write(core.With([]Field{makeInt64Field("k", 1)}), WarnLevel, "b")
+	require.NoError(t, core.Sync(), "Unexpected sync error.")
+	assert.NotContains(t, read(first), "# footer", "Sync must not write a footer.")
+
+	clock.Add(time.Second)
+	write(core, InfoLevel, "c")
+	assert.Equal(t,
+		`{"msg":"a"}`+"\n"+`{"msg":"b","k":1}`+"\n"+`{"msg":"b","k":1}`+"\n# footer 1\n",
+		read(first), "Expected one footer when rotating.")
+
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	assert.Equal(t, `{"msg":"c"}`+"\n# footer 2\n", read(first.Add(time.Hour)),
+		"Expected one footer on close.")
+}
+
+func TestFooterCoreOtherOutputs(t *testing.T) {
+	buf := &ztest.Buffer{}
+	core := NewFooterCore(NewCore(NewJSONEncoder(EncoderConfig{MessageKey: "msg"}), buf, InfoLevel), func() []byte {
+		t.Error("Unexpected footer.")
+		return nil
+	})
+	core.Check(Entry{Level: InfoLevel, Message: "entry"}, nil).Write()
+	require.NoError(t, core.Sync(), "Unexpected sync error.")
+	assert.Equal(t, []string{`{"msg":"entry"}`}, buf.Lines(), "Expected no footer for other outputs.")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "sync"
+
+type headerCore struct {
+	Core
+	state *headerState // shared with Cores derived with With
+}
+
+type headerState struct {
+	header func() (Entry, []Field)
+
+	mu     sync.Mutex
+	onces  map[*ioCore]*sync.Once // by the Core built by NewCore
+	others sync.Once              // for Cores not built by NewCore
+}
+
+var (
+	_ Core           = (*headerCore)(nil)
+	_ leveledEnabler = (*headerCore)(nil)
+)
+
+// NewHeaderCore wraps a Core so that a header entry, built by calling header,
+// is written to each output just before the first entry written to it. If
+// the wrapped Core is a Tee, each member Core gets the header before the
+// first entry it writes. The header is written only when an entry is
+// written, not when it's merely checked, and it isn't subject to level
+// filtering or sampling.
+//
+// Cores built by NewCore get the header encoded without any fields added
+// with With, exactly once for the returned Core and the Cores derived from
+// it with With, even when their first entries are written concurrently.
+// Other Cores get the header, with their fields, once between them. Cores
+// returned by separate calls to NewHeaderCore track their headers
+// separately, so wrapping the same Core twice writes the header twice.
+// Errors writing the header are ignored; the header isn't retried.
+func NewHeaderCore(core Core, header func() (Entry, []Field)) Core {
+	return &headerCore{
+		Core: core,
+		state: &headerState{
+			header: header,
+			onces:  make(map[*ioCore]*sync.Once),
+		},
+	}
+}
+
+func (c *headerCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *headerCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *headerCore) With(fields []Field) Core {
+	return &headerCore{
+		Core:  c.Core.With(fields),
+		state: c.state,
+	}
+}
+
+// Check registers the Cores that accept the entry behind a single
+// downstreamWriter, so that the header is written only if the entry is.
+func (c *headerCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkDownstream(c.Core, ent, ce, downstreamWriter{write: c.state.write})
+}
+
+// write writes the header to the Cores that agreed to log an entry, if they
+// haven't written it yet, before writing the entry.
+func (s *headerState) write(cores multiCore, ent Entry, fields []Field) error {
+	var others multiCore
+	for _, core := range cores {
+		ioc, ok := core.(*ioCore)
+		if !ok {
+			others = append(others, core)
+			continue
+		}
+		s.once(ioc.root).Do(func() {
+			hent, hfields := s.header()
+			_ = ioc.root.Write(hent, hfields)
+		})
+	}
+	if len(others) > 0 {
+		s.others.Do(func() {
+			hent, hfields := s.header()
+			_ = others.Write(hent, hfields)
+		})
+	}
+	return cores.Write(ent, fields)
+}
+
+// once returns the sync.Once guarding the header written by root.
+func (s *headerState) once(root *ioCore) *sync.Once {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.onces[root]
+	if !ok {
+		o = new(sync.Once)
+		s.onces[root] = o
+	}
+	return o
+}
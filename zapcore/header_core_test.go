@@ -0,0 +1,136 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHeaderTestCore(ws WriteSyncer, enab LevelEnabler) Core {
+	return NewCore(NewJSONEncoder(EncoderConfig{MessageKey: "msg"}), ws, enab)
+}
+
+func TestHeaderCore(t *testing.T) {
+	buf := &ztest.Buffer{}
+	var calls int
+	core := NewHeaderCore(newHeaderTestCore(Lock(buf), InfoLevel), func() (Entry, []Field) {
+		calls++
+		return Entry{Level: DebugLevel, Message: "header"}, []Field{makeInt64Field("version", 1)}
+	})
+	require.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+
+	assert.Nil(t, core.Check(Entry{Level: DebugLevel}, nil), "Expected debug entry to be dropped.")
+	assert.NotNil(t, core.Check(Entry{Level: InfoLevel}, nil), "Expected info entry to be accepted.")
+	assert.Empty(t, buf.String(), "Expected no header before an entry is written.")
+
+	child := core.With([]Field{makeInt64Field("ctx", 1)})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child.Check(Entry{Level: InfoLevel, Message: "entry"}, nil).Write()
+		}()
+	}
+	wg.Wait()
+	core.Check(Entry{Level: WarnLevel, Message: "entry"}, nil).Write()
+
+	lines := buf.Lines()
+	require.Len(t, lines, 12, "Expected one header and all entries.")
+	assert.Equal(t, 1, calls, "Expected header to be built once.")
+	assert.Equal(t, `{"msg":"header","version":1}`, lines[0], "Expected header first, without context added by With.")
+	for _, line := range lines[1:] {
+		assert.NotContains(t, line, "header", "Expected only one header.")
+	}
+}
+
+func TestHeaderCoreTee(t *testing.T) {
+	infoBuf, errBuf := &ztest.Buffer{}, &ztest.Buffer{}
+	core := NewHeaderCore(NewTee(
+		newHeaderTestCore(infoBuf, InfoLevel),
+		newHeaderTestCore(errBuf, ErrorLevel),
+	), func() (Entry, []Field) {
+		return Entry{Message: "header"}, nil
+	})
+
+	core.Check(Entry{Level: InfoLevel, Message: "info"}, nil).Write()
+	assert.Equal(t, []string{`{"msg":"header"}`, `{"msg":"info"}`}, infoBuf.Lines(), "Unexpected entries in info-level Core.")
+	assert.Empty(t, errBuf.Lines(), "Expected no header before the first entry written to the error-level Core.")
+
+	core.Check(Entry{Level: ErrorLevel, Message: "error"}, nil).Write()
+	assert.Equal(t,
+		[]string{`{"msg":"header"}`, `{"msg":"info"}`, `{"msg":"error"}`},
+		infoBuf.Lines(),
+		"Expected header only once in info-level Core.",
+	)
+	assert.Equal(t, []string{`{"msg":"header"}`, `{"msg":"error"}`}, errBuf.Lines(), "Unexpected entries in error-level Core.")
+}
+
+func TestHeaderCoreSeparateWrappers(t *testing.T) {
+	buf := &ztest.Buffer{}
+	header := func() (Entry, []Field) { return Entry{Message: "header"}, nil }
+	core := newHeaderTestCore(buf, InfoLevel)
+	first := NewHeaderCore(core, header)
+	second := NewHeaderCore(core, header)
+
+	first.Check(Entry{Level: InfoLevel, Message: "first"}, nil).Write()
+	first.With([]Field{makeInt64Field("k", 1)}).Check(Entry{Level: InfoLevel, Message: "child"}, nil).Write()
+	second.Check(Entry{Level: InfoLevel, Message: "second"}, nil).Write()
+	assert.Equal(t,
+		[]string{`{"msg":"header"}`, `{"msg":"first"}`, `{"msg":"child","k":1}`, `{"msg":"header"}`, `{"msg":"second"}`},
+		buf.Lines(),
+		"Expected header once per call to NewHeaderCore.",
+	)
+}
+
+func TestHeaderCoreOtherCores(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	core := NewHeaderCore(obs, func() (Entry, []Field) {
+		return Entry{Message: "header"}, nil
+	})
+
+	child := core.With([]Field{makeInt64Field("ctx", 1)})
+	child.Check(Entry{Level: InfoLevel, Message: "first"}, nil).Write()
+	core.Check(Entry{Level: InfoLevel, Message: "second"}, nil).Write()
+	assert.Equal(t, []string{"header", "first", "second"}, messages(logs), "Expected header only once.")
+	assert.Equal(t,
+		[]Field{makeInt64Field("ctx", 1)},
+		logs.All()[0].Context,
+		"Expected header with the fields of the Core writing it.",
+	)
+}
+
+func messages(logs *observer.ObservedLogs) []string {
+	var msgs []string
+	for _, e := range logs.All() {
+		msgs = append(msgs, e.Message)
+	}
+	return msgs
+}
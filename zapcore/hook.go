@@ -59,6 +59,10 @@ func (h *hooked) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
 	return ce
 }
 
+func (h *hooked) Describe() []CoreInfo {
+	return DescribeCores(h.Core)
+}
+
 func (h *hooked) With(fields []Field) Core {
 	return &hooked{
 		Core:  h.Core.With(fields),
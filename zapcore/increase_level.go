@@ -54,6 +54,17 @@ func (c *levelFilterCore) Level() Level {
 	return LevelOf(c.level)
 }
 
+func (c *levelFilterCore) Describe() []CoreInfo {
+	infos := DescribeCores(c.core)
+	for i := range infos {
+		if lvl := LevelOf(c.level); lvl > infos[i].Level {
+			infos[i].Level = lvl
+			infos[i].LevelEnabler = c.level
+		}
+	}
+	return infos
+}
+
 func (c *levelFilterCore) With(fields []Field) Core {
 	return &levelFilterCore{c.core.With(fields), c.level}
 }
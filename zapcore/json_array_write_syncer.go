@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	errJSONArraySyncerClosed = errors.New("JSON array syncer is closed")
+
+	_jsonArrayOpen  = []byte("[\n")
+	_jsonArraySep   = []byte(",\n")
+	_jsonArrayClose = []byte("\n]\n")
+	_jsonArrayEmpty = []byte("[]\n")
+)
+
+// JSONArraySyncer is a WriteSyncer that joins the entries written to it into
+// a single JSON array, for tools that expect one JSON document rather than
+// newline-delimited JSON. Use NewJSONArraySyncer to build one.
+type JSONArraySyncer struct {
+	ws WriteSyncer
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+var _ WriteSyncer = (*JSONArraySyncer)(nil)
+
+// NewJSONArraySyncer builds a WriteSyncer that writes the entries written to
+// it, typically by a JSON encoder, to ws as the elements of a JSON array:
+//
+//	[
+//	{"level":"info","msg":"first"},
+//	{"level":"info","msg":"second"}
+//	]
+//
+// Each write must hold exactly one JSON value; a trailing line ending is
+// stripped. The array is only terminated by Close, so the output isn't a
+// valid JSON document until then. If the process crashes before Close, the
+// output is a prefix of the array that lacks its closing bracket, which many
+// tools can recover by appending "]". Closing a JSONArraySyncer that was
+// never written to writes an empty array.
+func NewJSONArraySyncer(ws WriteSyncer) *JSONArraySyncer {
+	return &JSONArraySyncer{ws: ws}
+}
+
+// Write writes bs to the underlying WriteSyncer as the next element of the
+// array. It reports the length of bs on success.
+func (s *JSONArraySyncer) Write(bs []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, errJSONArraySyncerClosed
+	}
+
+	sep := _jsonArraySep
+	if !s.started {
+		sep = _jsonArrayOpen
+	}
+	if _, err := s.ws.Write(sep); err != nil {
+		return 0, err
+	}
+	s.started = true
+	if _, err := s.ws.Write(bytes.TrimRight(bs, "\r\n")); err != nil {
+		return 0, err
+	}
+	return len(bs), nil
+}
+
+// Sync syncs the underlying WriteSyncer. It doesn't terminate the array.
+func (s *JSONArraySyncer) Sync() error {
+	return s.ws.Sync()
+}
+
+// Describe describes the wrapped WriteSyncer. See DescribeWriteSyncer.
+func (s *JSONArraySyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
+
+// Close terminates the array and syncs the underlying WriteSyncer. Writes
+// after Close fail. Calling Close more than once is a no-op.
+func (s *JSONArraySyncer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	end := _jsonArrayClose
+	if !s.started {
+		end = _jsonArrayEmpty
+	}
+	_, err := s.ws.Write(end)
+	return multierr.Append(err, s.ws.Sync())
+}
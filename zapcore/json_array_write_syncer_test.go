@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONArraySyncer(t *testing.T) {
+	sink := &ztest.Buffer{}
+	ws := NewJSONArraySyncer(sink)
+	core := NewCore(NewJSONEncoder(EncoderConfig{MessageKey: "msg"}), ws, DebugLevel)
+
+	for _, msg := range []string{"first", "second", "third"} {
+		require.NoError(t, core.Write(Entry{Message: msg}, nil), "Unexpected write error.")
+	}
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.True(t, sink.Called(), "Expected underlying syncer to be synced.")
+	require.NoError(t, ws.Close(), "Unexpected close error.")
+	require.NoError(t, ws.Close(), "Closing twice must be a no-op.")
+
+	assert.Equal(t,
+		"[\n"+`{"msg":"first"}`+",\n"+`{"msg":"second"}`+",\n"+`{"msg":"third"}`+"\n]\n",
+		sink.String(),
+		"Unexpected output.",
+	)
+
+	var entries []map[string]string
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &entries), "Output must be a single JSON array.")
+	assert.Equal(t, []map[string]string{{"msg": "first"}, {"msg": "second"}, {"msg": "third"}}, entries, "Unexpected entries.")
+
+	_, err := ws.Write([]byte(`{}`))
+	assert.Error(t, err, "Expected writes after Close to fail.")
+}
+
+func TestJSONArraySyncerEmpty(t *testing.T) {
+	sink := &ztest.Buffer{}
+	ws := NewJSONArraySyncer(sink)
+	require.NoError(t, ws.Close(), "Unexpected close error.")
+
+	var entries []interface{}
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &entries), "Output must be a JSON array.")
+	assert.Empty(t, entries, "Expected an empty array.")
+	assert.Equal(t, "[]\n", sink.String(), "Unexpected output.")
+}
+
+func TestJSONArraySyncerWriteError(t *testing.T) {
+	ws := NewJSONArraySyncer(&ztest.FailWriter{})
+	_, err := ws.Write([]byte(`{}`))
+	assert.Error(t, err, "Expected error from underlying syncer.")
+	assert.Error(t, ws.Close(), "Expected error terminating the array.")
+}
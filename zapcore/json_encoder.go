@@ -39,15 +39,20 @@ var _jsonPool = pool.New(func() *jsonEncoder {
 })
 
 func putJSONEncoder(enc *jsonEncoder) {
-	if enc.reflectBuf != nil {
+	if enc.reflected != nil {
+		putReflectedEncoder(enc.reflected)
+	} else if enc.reflectBuf != nil {
 		enc.reflectBuf.Free()
 	}
 	enc.EncoderConfig = nil
 	enc.buf = nil
 	enc.spaced = false
+	enc.compactObjects = false
 	enc.openNamespaces = 0
+	enc.depth = 0
 	enc.reflectBuf = nil
 	enc.reflectEnc = nil
+	enc.reflected = nil
 	_jsonPool.Put(enc)
 }
 
@@ -55,11 +60,14 @@ type jsonEncoder struct {
 	*EncoderConfig
 	buf            *buffer.Buffer
 	spaced         bool // include spaces after colons and commas
+	compactObjects bool // write objects as {k=v k2=v2}, for the console encoder
 	openNamespaces int
+	depth          int // nesting of objects and arrays added by marshalers
 
 	// for encoding generic values by reflection
 	reflectBuf *buffer.Buffer
 	reflectEnc ReflectedEncoder
+	reflected  *pooledReflectedEncoder // owns reflectBuf and reflectEnc, if set
 }
 
 // NewJSONEncoder creates a fast, low-allocation JSON encoder. The encoder
@@ -85,11 +93,6 @@ func newJSONEncoder(cfg EncoderConfig, spaced bool) *jsonEncoder {
 		cfg.LineEnding = DefaultLineEnding
 	}
 
-	// If no EncoderConfig.NewReflectedEncoder is provided by the user, then use default
-	if cfg.NewReflectedEncoder == nil {
-		cfg.NewReflectedEncoder = defaultReflectedEncoder
-	}
-
 	return &jsonEncoder{
 		EncoderConfig: &cfg,
 		buf:           bufferpool.Get(),
@@ -153,6 +156,14 @@ func (enc *jsonEncoder) AddInt64(key string, val int64) {
 
 func (enc *jsonEncoder) resetReflectBuf() {
 	if enc.reflectBuf == nil {
+		// Without a user-provided EncoderConfig.NewReflectedEncoder, reuse a
+		// pooled default encoder and its buffer.
+		if enc.NewReflectedEncoder == nil {
+			enc.reflected = getReflectedEncoder()
+			enc.reflectBuf = enc.reflected.buf
+			enc.reflectEnc = enc.reflected.enc
+			return
+		}
 		enc.reflectBuf = bufferpool.Get()
 		enc.reflectEnc = enc.NewReflectedEncoder(enc.reflectBuf)
 	} else {
@@ -162,6 +173,13 @@ func (enc *jsonEncoder) resetReflectBuf() {
 
 var nullLiteralBytes = []byte("null")
 
+// The member of the marker that replaces objects and arrays nested beyond
+// MaxObjectDepth.
+const (
+	_maxDepthKey     = "..."
+	_maxDepthMessage = "max depth exceeded"
+)
+
 // Only invoke the standard JSON encoder if there is actually something to
 // encode; otherwise write JSON null literal directly.
 func (enc *jsonEncoder) encodeReflected(obj interface{}) ([]byte, error) {
@@ -209,26 +227,53 @@ func (enc *jsonEncoder) AddUint64(key string, val uint64) {
 
 func (enc *jsonEncoder) AppendArray(arr ArrayMarshaler) error {
 	enc.addElementSeparator()
+	if enc.maxDepthExceeded() {
+		enc.appendMaxDepthMarker()
+		return nil
+	}
+	enc.depth++
 	enc.buf.AppendByte('[')
 	err := arr.MarshalLogArray(enc)
 	enc.buf.AppendByte(']')
+	enc.depth--
 	return err
 }
 
 func (enc *jsonEncoder) AppendObject(obj ObjectMarshaler) error {
+	enc.addElementSeparator()
+	if enc.maxDepthExceeded() {
+		enc.appendMaxDepthMarker()
+		return nil
+	}
+	if enc.compactObjects {
+		return enc.appendCompactObject(obj)
+	}
 	// Close ONLY new openNamespaces that are created during
 	// AppendObject().
 	old := enc.openNamespaces
 	enc.openNamespaces = 0
-	enc.addElementSeparator()
+	enc.depth++
 	enc.buf.AppendByte('{')
 	err := obj.MarshalLogObject(enc)
 	enc.buf.AppendByte('}')
 	enc.closeOpenNamespaces()
+	enc.depth--
 	enc.openNamespaces = old
 	return err
 }
 
+func (enc *jsonEncoder) maxDepthExceeded() bool {
+	return enc.MaxObjectDepth > 0 && enc.depth >= enc.MaxObjectDepth
+}
+
+// appendMaxDepthMarker appends the object that stands in for objects and
+// arrays nested beyond MaxObjectDepth.
+func (enc *jsonEncoder) appendMaxDepthMarker() {
+	enc.buf.AppendByte('{')
+	enc.AddString(_maxDepthKey, _maxDepthMessage)
+	enc.buf.AppendByte('}')
+}
+
 func (enc *jsonEncoder) AppendBool(val bool) {
 	enc.addElementSeparator()
 	enc.buf.AppendBool(val)
@@ -353,6 +398,7 @@ func (enc *jsonEncoder) clone() *jsonEncoder {
 	clone := _jsonPool.Get()
 	clone.EncoderConfig = enc.EncoderConfig
 	clone.spaced = enc.spaced
+	clone.compactObjects = enc.compactObjects
 	clone.openNamespaces = enc.openNamespaces
 	clone.buf = bufferpool.Get()
 	return clone
@@ -417,10 +463,25 @@ func (enc *jsonEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer,
 		final.addElementSeparator()
 		final.buf.Write(enc.buf.Bytes())
 	}
+	if final.SortFields {
+		fields = sortFields(fields)
+	}
+	if final.AutoNestDottedKeys {
+		fields = nestDottedKeys(fields)
+	}
 	addFields(final, fields)
 	final.closeOpenNamespaces()
 	if ent.Stack != "" && final.StacktraceKey != "" {
-		final.AddString(final.StacktraceKey, ent.Stack)
+		final.addKey(final.StacktraceKey)
+		cur := final.buf.Len()
+		if final.EncodeStacktrace != nil {
+			final.EncodeStacktrace(ent.Stack, final)
+		}
+		if cur == final.buf.Len() {
+			// No EncodeStacktrace, or it was a no-op. Fall back to strings to
+			// keep output JSON valid.
+			final.AppendString(ent.Stack)
+		}
 	}
 	final.buf.AppendByte('}')
 	final.buf.AppendString(final.LineEnding)
@@ -430,6 +491,50 @@ func (enc *jsonEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer,
 	return ret, nil
 }
 
+// encodeFixedKeyEntry encodes an entry for the encoders built on the JSON
+// encoder that write an entry's metadata under fixed keys, like the ECS and
+// Stackdriver encoders. head writes the metadata, then the encoder's context
+// and fields follow as they do in EncodeEntry, and tail, if it isn't nil,
+// writes anything that goes after the fields. Callers sort fields first if
+// the EncoderConfig asks for it.
+func (enc *jsonEncoder) encodeFixedKeyEntry(fields []Field, head, tail func(*jsonEncoder)) *buffer.Buffer {
+	final := enc.clone()
+	final.buf.AppendByte('{')
+	head(final)
+	if enc.buf.Len() > 0 {
+		final.addElementSeparator()
+		final.buf.Write(enc.buf.Bytes())
+	}
+	addFields(final, fields)
+	final.closeOpenNamespaces()
+	if tail != nil {
+		tail(final)
+	}
+	final.buf.AppendByte('}')
+	final.buf.AppendString(final.LineEnding)
+
+	ret := final.buf
+	putJSONEncoder(final)
+	return ret
+}
+
+// addLoggerName adds the logger's name under key, using EncodeName if it's
+// set and falling back to the plain name if it's a no-op.
+func (enc *jsonEncoder) addLoggerName(key, name string) {
+	enc.addKey(key)
+	cur := enc.buf.Len()
+	nameEncoder := enc.EncodeName
+	if nameEncoder == nil {
+		nameEncoder = FullNameEncoder
+	}
+	nameEncoder(name, enc)
+	if cur == enc.buf.Len() {
+		// User-supplied EncodeName was a no-op. Fall back to strings to keep
+		// output JSON valid.
+		enc.AppendString(name)
+	}
+}
+
 func (enc *jsonEncoder) truncate() {
 	enc.buf.Reset()
 }
@@ -130,3 +130,53 @@ func BenchmarkStandardJSON(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkJSONSortFields(b *testing.B) {
+	fields := []Field{
+		{Key: "str", Type: StringType, String: "foo"},
+		{Key: "int64", Type: Int64Type, Integer: 1},
+		{Key: "bool", Type: BoolType, Integer: 1},
+		{Key: "dur", Type: DurationType, Integer: int64(time.Second)},
+		{Key: "another", Type: StringType, String: "bar"},
+		{Key: "ns", Type: NamespaceType},
+		{Key: "z", Type: Int64Type, Integer: 2},
+		{Key: "a", Type: Int64Type, Integer: 3},
+	}
+	for _, sortFields := range []bool{false, true} {
+		b.Run(fmt.Sprintf("sort=%v", sortFields), func(b *testing.B) {
+			cfg := testEncoderConfig()
+			cfg.SortFields = sortFields
+			enc := NewJSONEncoder(cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf, _ := enc.EncodeEntry(Entry{Message: "fake"}, fields)
+				buf.Free()
+			}
+		})
+	}
+}
+
+func BenchmarkJSONReflectedFields(b *testing.B) {
+	type point struct {
+		X, Y int
+		Tag  string
+	}
+	fields := []Field{
+		{Key: "a", Type: ReflectType, Interface: point{1, 2, "a"}},
+		{Key: "b", Type: ReflectType, Interface: []int{1, 2, 3}},
+		{Key: "c", Type: ReflectType, Interface: map[string]int{"x": 1}},
+	}
+	enc := NewJSONEncoder(testEncoderConfig())
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf, err := enc.EncodeEntry(Entry{Message: "fake"}, fields)
+			if err != nil {
+				b.Fatal(err)
+			}
+			buf.Free()
+		}
+	})
+}
@@ -21,13 +21,17 @@
 package zapcore_test
 
 import (
+	"encoding/json"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/internal/ztest"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -280,3 +284,233 @@ func TestJSONCustomReflectedEncoder(t *testing.T) {
 		})
 	}
 }
+
+// recursiveMarshaler marshals itself as a child indefinitely, alternating
+// between objects and arrays.
+type recursiveMarshaler struct{}
+
+func (r recursiveMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("k", "v")
+	return enc.AddArray("children", r)
+}
+
+func (r recursiveMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return enc.AppendObject(r)
+}
+
+func TestJSONMaxObjectDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		newEnc   func(zapcore.EncoderConfig) zapcore.Encoder
+		expected string
+	}{
+		{
+			name:     "json",
+			newEnc:   zapcore.NewJSONEncoder,
+			expected: `{"msg":"m","r":{"k":"v","children":[{"k":"v","children":{"...":"max depth exceeded"}}]},"after":1}` + "\n",
+		},
+		{
+			name:     "console",
+			newEnc:   zapcore.NewConsoleEncoder,
+			expected: `m	{"r": {"k": "v", "children": [{"k": "v", "children": {"...": "max depth exceeded"}}]}, "after": 1}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := tt.newEnc(zapcore.EncoderConfig{MessageKey: "msg", MaxObjectDepth: 3})
+			buf, err := enc.EncodeEntry(zapcore.Entry{Message: "m"}, []zapcore.Field{
+				zap.Object("r", recursiveMarshaler{}),
+				zap.Int("after", 1),
+			})
+			if assert.NoError(t, err, "Unexpected encoding error.") {
+				assert.Equal(t, tt.expected, buf.String(), "Unexpected output.")
+			}
+			buf.Free()
+		})
+	}
+}
+
+func TestJSONSortFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		newEnc   func(zapcore.EncoderConfig) zapcore.Encoder
+		expected string
+	}{
+		{
+			name:     "json",
+			newEnc:   zapcore.NewJSONEncoder,
+			expected: `{"msg":"m","ctx":"c","b":1,"c":"first","c":"second","ns":{"a":true,"z":{"y":1,"x":2}}}` + "\n",
+		},
+		{
+			name:     "console",
+			newEnc:   zapcore.NewConsoleEncoder,
+			expected: `m	{"ctx": "c", "b": 1, "c": "first", "c": "second", "ns": {"a": true, "z": {"y": 1, "x": 2}}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := tt.newEnc(zapcore.EncoderConfig{MessageKey: "msg", SortFields: true})
+			enc.AddString("ctx", "c")
+			fields := []zapcore.Field{
+				zap.String("c", "first"),
+				zap.Int("b", 1),
+				zap.String("c", "second"),
+				zap.Namespace("ns"),
+				zap.Object("z", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					// Keys inside marshaled objects keep their order.
+					enc.AddInt("y", 1)
+					enc.AddInt("x", 2)
+					return nil
+				})),
+				zap.Bool("a", true),
+			}
+			buf, err := enc.EncodeEntry(zapcore.Entry{Message: "m"}, fields)
+			if assert.NoError(t, err, "Unexpected encoding error.") {
+				assert.Equal(t, tt.expected, buf.String(), "Unexpected output.")
+			}
+			buf.Free()
+			assert.Equal(t, "c", fields[0].Key, "Caller's fields must not be reordered.")
+			assert.Equal(t, "first", fields[0].String, "Caller's fields must not be reordered.")
+		})
+	}
+}
+
+func TestJSONAutoNestDottedKeys(t *testing.T) {
+	tests := []struct {
+		desc     string
+		fields   []zapcore.Field
+		expected string
+	}{
+		{
+			desc: "overlapping prefixes",
+			fields: []zapcore.Field{
+				zap.String("http.method", "GET"),
+				zap.String("user", "alice"),
+				zap.String("http.request.path", "/"),
+				zap.Int("http.request.size", 10),
+				zap.Int("http.status", 200),
+				zap.String("db.query", "SELECT 1"),
+			},
+			expected: `{"http":{"method":"GET","request":{"path":"/","size":10},"status":200},"user":"alice","db":{"query":"SELECT 1"}}`,
+		},
+		{
+			desc: "leaf and prefix",
+			fields: []zapcore.Field{
+				zap.String("http.method", "GET"),
+				zap.String("http", "HTTP/1.1"),
+				zap.String("a.b", "leaf"),
+				zap.String("a.b.c", "deep"),
+				zap.String("a.x", "x"),
+			},
+			expected: `{"http.method":"GET","http":"HTTP/1.1","a":{"b":"leaf","b.c":"deep","x":"x"}}`,
+		},
+		{
+			desc: "namespaces",
+			fields: []zapcore.Field{
+				zap.String("a.b", "outer"),
+				zap.Namespace("ns"),
+				zap.String("a.c", "inner"),
+			},
+			expected: `{"a":{"b":"outer"},"ns":{"a":{"c":"inner"}}}`,
+		},
+		{
+			desc: "not dotted",
+			fields: []zapcore.Field{
+				zap.String(".a", "1"),
+				zap.String("a.", "2"),
+				zap.String("plain", "3"),
+			},
+			expected: `{".a":"1","a.":"2","plain":"3"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{AutoNestDottedKeys: true})
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, tt.fields)
+			require.NoError(t, err, "Unexpected encoding error.")
+			defer buf.Free()
+			assert.Equal(t, tt.expected+"\n", buf.String(), "Unexpected output.")
+		})
+	}
+}
+
+func TestJSONAutoNestDottedKeysDisabled(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zapcore.Field{zap.String("http.method", "GET")})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+	assert.Equal(t, `{"http.method":"GET"}`+"\n", buf.String(), "Dotted keys should be flat by default.")
+}
+
+func TestJSONSortFieldsDisabled(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zapcore.Field{zap.Int("b", 1), zap.Int("a", 2)})
+	if assert.NoError(t, err, "Unexpected encoding error.") {
+		assert.Equal(t, `{"b":1,"a":2}`+"\n", buf.String(), "Fields should keep their order by default.")
+	}
+	buf.Free()
+}
+
+func TestJSONMaxObjectDepthUnlimited(t *testing.T) {
+	// Without a limit, nesting that's deep but finite is encoded in full.
+	var nested zapcore.ObjectMarshalerFunc
+	depth := 0
+	nested = func(enc zapcore.ObjectEncoder) error {
+		if depth++; depth > 100 {
+			return nil
+		}
+		return enc.AddObject("o", nested)
+	}
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zapcore.Field{zap.Object("o", nested)})
+	if assert.NoError(t, err, "Unexpected encoding error.") {
+		assert.Equal(t, 101, strings.Count(buf.String(), `"o":`), "Unexpected nesting.")
+	}
+	buf.Free()
+}
+
+func TestJSONEncodeStacktrace(t *testing.T) {
+	logStack := func(se zapcore.StacktraceEncoder) map[string]interface{} {
+		var out ztest.Buffer
+		cfg := zapcore.EncoderConfig{MessageKey: "msg", StacktraceKey: "trace", EncodeStacktrace: se}
+		logger := zap.New(
+			zapcore.NewCore(zapcore.NewJSONEncoder(cfg), &out, zapcore.DebugLevel),
+			zap.AddStacktrace(zapcore.ErrorLevel),
+		)
+		logger.Error("oops")
+
+		lines := out.Lines()
+		require.Len(t, lines, 1, "Expected a single line.")
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry), "Output isn't valid JSON.")
+		return entry
+	}
+
+	t.Run("default", func(t *testing.T) {
+		trace, ok := logStack(nil)["trace"].(string)
+		require.True(t, ok, "Expected the stack trace to be a string by default.")
+		assert.Contains(t, trace, "TestJSONEncodeStacktrace", "Expected the test in the stack trace.")
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		frames, ok := logStack(zapcore.StructuredStacktraceEncoder)["trace"].([]interface{})
+		require.True(t, ok, "Expected the stack trace to be an array.")
+		require.NotEmpty(t, frames, "Expected at least one frame.")
+
+		first, ok := frames[0].(map[string]interface{})
+		require.True(t, ok, "Expected frames to be objects.")
+		assert.Contains(t, first["function"], "TestJSONEncodeStacktrace", "Unexpected first frame.")
+		assert.True(t, strings.HasSuffix(first["file"].(string), "json_encoder_test.go"), "Unexpected file %v.", first["file"])
+		assert.Greater(t, first["line"], float64(0), "Expected a line number.")
+	})
+
+	t.Run("no-op", func(t *testing.T) {
+		trace, ok := logStack(func(string, zapcore.ArrayEncoder) {})["trace"].(string)
+		require.True(t, ok, "Expected a no-op encoder to fall back to a string.")
+		assert.Contains(t, trace, "TestJSONEncodeStacktrace", "Expected the test in the stack trace.")
+	})
+}
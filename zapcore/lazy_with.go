@@ -43,6 +43,11 @@ func (d *lazyWithCore) initOnce() {
 	})
 }
 
+func (d *lazyWithCore) Describe() []CoreInfo {
+	d.initOnce()
+	return DescribeCores(d.Core)
+}
+
 func (d *lazyWithCore) With(fields []Field) Core {
 	d.initOnce()
 	return d.Core.With(fields)
@@ -24,6 +24,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var errUnmarshalNilLevel = errors.New("can't unmarshal a nil *Level")
@@ -61,7 +62,8 @@ const (
 )
 
 // ParseLevel parses a level based on the lower-case or all-caps ASCII
-// representation of the log level. If the provided ASCII representation is
+// representation of the log level, including the names of custom levels
+// registered with RegisterLevel. If the provided ASCII representation is
 // invalid an error is returned.
 //
 // This is particularly useful when dealing with text input to configure log
@@ -127,6 +129,9 @@ func (l Level) String() string {
 	case FatalLevel:
 		return "fatal"
 	default:
+		if name, ok := customLevelName(l); ok {
+			return name
+		}
 		return fmt.Sprintf("Level(%d)", l)
 	}
 }
@@ -151,6 +156,9 @@ func (l Level) CapitalString() string {
 	case FatalLevel:
 		return "FATAL"
 	default:
+		if name, ok := customLevelName(l); ok {
+			return strings.ToUpper(name)
+		}
 		return fmt.Sprintf("LEVEL(%d)", l)
 	}
 }
@@ -178,6 +186,17 @@ func (l *Level) UnmarshalText(text []byte) error {
 }
 
 func (l *Level) unmarshalText(text []byte) bool {
+	if l.unmarshalBuiltinText(text) {
+		return true
+	}
+	if lvl, ok := customLevel(string(text)); ok {
+		*l = lvl
+		return true
+	}
+	return false
+}
+
+func (l *Level) unmarshalBuiltinText(text []byte) bool {
 	switch string(text) {
 	case "debug":
 		*l = DebugLevel
@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var _customLevels = struct {
+	sync.RWMutex
+
+	names  map[Level]string // lower-case names
+	levels map[string]Level // by lower-case name
+}{
+	names:  make(map[Level]string),
+	levels: make(map[string]Level),
+}
+
+// RegisterLevel names a custom level, so that it's encoded as name by the
+// level encoders (as name in lower case, or in all caps by the capital
+// encoders) and parsed from name by ParseLevel and Level.UnmarshalText, and
+// thus from configuration files and flags.
+//
+// Zap's own levels are consecutive, so custom levels must lie outside them:
+// below DebugLevel, such as a TraceLevel of DebugLevel-1, or above
+// FatalLevel. For example,
+//
+//	const TraceLevel = zapcore.DebugLevel - 1
+//
+//	func init() {
+//		if err := zapcore.RegisterLevel(TraceLevel, "trace"); err != nil {
+//			panic(err)
+//		}
+//	}
+//
+// Names are case-insensitive. Registering one of zap's levels, a name that's
+// already taken, or a second name for the same level returns an error;
+// registering the same level and name again is a no-op. Levels are typically
+// registered in an init function, but RegisterLevel is safe for concurrent
+// use.
+func RegisterLevel(level Level, name string) error {
+	lower := strings.ToLower(name)
+	if lower == "" {
+		return errors.New("level name must not be empty")
+	}
+	if level >= _minLevel && level <= _maxLevel {
+		return fmt.Errorf("can't rename built-in level %v", level)
+	}
+	var builtin Level
+	if builtin.unmarshalBuiltinText([]byte(lower)) {
+		return fmt.Errorf("level name %q is already used by %v", name, builtin)
+	}
+
+	_customLevels.Lock()
+	defer _customLevels.Unlock()
+	if existing, ok := _customLevels.names[level]; ok {
+		if existing == lower {
+			return nil
+		}
+		return fmt.Errorf("level %d is already named %q", level, existing)
+	}
+	if other, ok := _customLevels.levels[lower]; ok {
+		return fmt.Errorf("level name %q is already used by level %d", name, other)
+	}
+	_customLevels.names[level] = lower
+	_customLevels.levels[lower] = level
+	return nil
+}
+
+// customLevelName returns the lower-case name registered for a level.
+func customLevelName(l Level) (string, bool) {
+	_customLevels.RLock()
+	defer _customLevels.RUnlock()
+	name, ok := _customLevels.names[l]
+	return name, ok
+}
+
+// customLevel returns the level registered under a lower-case name.
+func customLevel(name string) (Level, bool) {
+	_customLevels.RLock()
+	defer _customLevels.RUnlock()
+	l, ok := _customLevels.levels[name]
+	return l, ok
+}
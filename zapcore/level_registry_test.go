@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterLevel(t *testing.T) {
+	const verboseLevel, auditLevel = Level(-3), Level(10)
+	require.NoError(t, RegisterLevel(verboseLevel, "Verbose"), "Unexpected error registering a level below DebugLevel.")
+	require.NoError(t, RegisterLevel(auditLevel, "audit"), "Unexpected error registering a level above FatalLevel.")
+	require.NoError(t, RegisterLevel(auditLevel, "AUDIT"), "Registering the same name again should be a no-op.")
+
+	assert.Equal(t, "verbose", verboseLevel.String(), "Unexpected lower-case name.")
+	assert.Equal(t, "AUDIT", auditLevel.CapitalString(), "Unexpected capitalized name.")
+
+	enc := NewMapObjectEncoder()
+	require.NoError(t, enc.AddArray("levels", ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+		LowercaseLevelEncoder(verboseLevel, arr)
+		CapitalLevelEncoder(auditLevel, arr)
+		return nil
+	})), "Unexpected error encoding levels.")
+	assert.Equal(t, []interface{}{"verbose", "AUDIT"}, enc.Fields["levels"], "Unexpected encoded levels.")
+
+	for text, want := range map[string]Level{
+		"verbose": verboseLevel,
+		"VERBOSE": verboseLevel,
+		"Audit":   auditLevel,
+		"info":    InfoLevel,
+	} {
+		lvl, err := ParseLevel(text)
+		require.NoError(t, err, "Unexpected error parsing %q.", text)
+		assert.Equal(t, want, lvl, "Unexpected level parsed from %q.", text)
+	}
+
+	var lvl Level
+	require.NoError(t, lvl.UnmarshalText([]byte("audit")), "Unexpected error unmarshaling a custom level.")
+	assert.Equal(t, auditLevel, lvl, "Unexpected unmarshaled level.")
+	text, err := auditLevel.MarshalText()
+	require.NoError(t, err, "Unexpected error marshaling a custom level.")
+	assert.Equal(t, "audit", string(text), "Unexpected marshaled level.")
+}
+
+func TestRegisterLevelErrors(t *testing.T) {
+	const customLevel = Level(11)
+	require.NoError(t, RegisterLevel(customLevel, "security"), "Unexpected error registering a level.")
+
+	tests := []struct {
+		desc  string
+		level Level
+		name  string
+		want  string
+	}{
+		{"empty name", Level(12), "", "level name must not be empty"},
+		{"built-in level", InfoLevel, "notice", "can't rename built-in level info"},
+		{"built-in name", Level(12), "Warning", `level name "Warning" is already used by warn`},
+		{"renamed level", customLevel, "other", `level 11 is already named "security"`},
+		{"taken name", Level(12), "SECURITY", `level name "SECURITY" is already used by level 11`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.EqualError(t, RegisterLevel(tt.level, tt.name), tt.want, "Unexpected error.")
+		})
+	}
+	assert.Equal(t, "Level(12)", Level(12).String(), "Failed registrations shouldn't name the level.")
+}
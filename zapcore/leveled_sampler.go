@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "time"
+
+// SamplingPolicy is the sampling policy for a single level of a Core built
+// by NewLeveledSampler. Its fields have the same meaning as the tick, first,
+// and thereafter arguments of NewSamplerWithOptions.
+type SamplingPolicy struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+type levelPolicy struct {
+	sampled           bool
+	tick              time.Duration
+	first, thereafter uint64
+}
+
+type leveledSampler struct {
+	sampler
+
+	policies [_numLevels]levelPolicy
+}
+
+var (
+	_ Core           = (*leveledSampler)(nil)
+	_ leveledEnabler = (*leveledSampler)(nil)
+)
+
+// NewLeveledSampler creates a Core that samples incoming entries like
+// NewSamplerWithOptions, but with a separate policy for each level. Entries
+// at levels without a policy aren't sampled at all. For example,
+//
+//	core = NewLeveledSampler(core, map[Level]SamplingPolicy{
+//	  DebugLevel: {Tick: time.Second, First: 10, Thereafter: 0},
+//	  InfoLevel:  {Tick: time.Second, First: 100, Thereafter: 100},
+//	})
+//
+// aggressively samples debug and info entries while writing every warning
+// and error.
+//
+// The same SamplerOptions as NewSamplerWithOptions are supported; hooks are
+// only called for entries at sampled levels.
+func NewLeveledSampler(core Core, policies map[Level]SamplingPolicy, opts ...SamplerOption) Core {
+	s := &leveledSampler{sampler: *newSampler(core, 0, 0, 0, opts)}
+	for lvl, p := range policies {
+		if lvl < _minLevel || lvl > _maxLevel {
+			continue
+		}
+		s.policies[lvl-_minLevel] = levelPolicy{
+			sampled:    true,
+			tick:       p.Tick,
+			first:      uint64(p.First),
+			thereafter: uint64(p.Thereafter),
+		}
+	}
+	return s
+}
+
+func (s *leveledSampler) Describe() []CoreInfo {
+	return DescribeCores(s.Core)
+}
+
+func (s *leveledSampler) With(fields []Field) Core {
+	return &leveledSampler{
+		sampler:  *s.sampler.With(fields).(*sampler),
+		policies: s.policies,
+	}
+}
+
+func (s *leveledSampler) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	if !s.Enabled(ent.Level) {
+		return ce
+	}
+
+	if ent.Level >= _minLevel && ent.Level <= _maxLevel {
+		if p := &s.policies[ent.Level-_minLevel]; p.sampled && s.drop(ent, p.tick, p.first, p.thereafter) {
+			return ce
+		}
+	}
+	return s.Core.Check(ent, ce)
+}
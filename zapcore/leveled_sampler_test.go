@@ -0,0 +1,112 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+	"time"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeveledSampler(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+	var dropped, sampled int
+	core := NewLeveledSampler(obs, map[Level]SamplingPolicy{
+		DebugLevel: {Tick: time.Minute, First: 1},
+		InfoLevel:  {Tick: time.Minute, First: 2, Thereafter: 3},
+	}, SamplerHook(func(_ Entry, dec SamplingDecision) {
+		if dec&LogDropped > 0 {
+			dropped++
+		} else {
+			sampled++
+		}
+	}))
+	assert.Equal(t, DebugLevel, LevelOf(core), "Unexpected level.")
+
+	for i := 1; i < 10; i++ {
+		writeSequence(core, i, InfoLevel)
+	}
+	assertSequence(t, logs.TakeAll(), InfoLevel, 1, 2, 5, 8)
+
+	for i := 1; i < 10; i++ {
+		writeSequence(core, i, DebugLevel)
+	}
+	assertSequence(t, logs.TakeAll(), DebugLevel, 1)
+
+	// Levels without a policy aren't sampled.
+	for i := 1; i < 10; i++ {
+		writeSequence(core, i, ErrorLevel)
+	}
+	assertSequence(t, logs.TakeAll(), ErrorLevel, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	assert.Equal(t, 5, sampled, "Unexpected number of sampled entries.")
+	assert.Equal(t, 13, dropped, "Unexpected number of dropped entries.")
+}
+
+func TestLeveledSamplerTicking(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+	core := NewLeveledSampler(obs, map[Level]SamplingPolicy{
+		InfoLevel: {Tick: time.Millisecond, First: 1},
+		WarnLevel: {Tick: time.Hour, First: 1},
+	})
+
+	for i := 1; i <= 3; i++ {
+		writeSequence(core, i, InfoLevel)
+		writeSequence(core, i, WarnLevel)
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, 3, len(logs.FilterLevelExact(InfoLevel).All()), "Expected each info tick to reset.")
+	assert.Equal(t, 1, len(logs.FilterLevelExact(WarnLevel).All()), "Expected warn counter not to reset.")
+}
+
+func TestLeveledSamplerDisabledLevels(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	core := NewLeveledSampler(obs, map[Level]SamplingPolicy{
+		DebugLevel: {Tick: time.Minute, First: 1},
+	})
+	writeSequence(core, 1, DebugLevel)
+	assert.Equal(t, 0, logs.Len(), "Expected disabled levels to be dropped.")
+}
+
+func TestLeveledSamplerDropSummary(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	core := NewLeveledSampler(obs, map[Level]SamplingPolicy{
+		InfoLevel: {Tick: time.Second, First: 1},
+	}, SamplerDropSummary(10))
+
+	for i := 1; i <= 3; i++ {
+		writeSequence(core, i, InfoLevel)
+	}
+	logs.TakeAll()
+
+	require.NoError(t, core.Sync(), "Unexpected sync error.")
+	summaries := logs.TakeAll()
+	require.Len(t, summaries, 1, "Expected a summary on sync.")
+	assert.Equal(t, "sampler dropped entries", summaries[0].Message, "Unexpected summary message.")
+	assert.Equal(t, uint64(2), summaries[0].ContextMap()["dropped"], "Unexpected drop count.")
+	assert.False(t, summaries[0].Time.IsZero(), "Expected the summary to be timestamped.")
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+// MeteredOption configures a Core built by NewMeteredCore.
+type MeteredOption interface {
+	apply(*meteredCore)
+}
+
+type meteredOptionFunc func(*meteredCore)
+
+func (f meteredOptionFunc) apply(c *meteredCore) {
+	f(c)
+}
+
+// MeteredWrites registers a function that's called after each write of an
+// entry, with the entry's level and the error returned by the write, if any.
+// Unlike the observer passed to NewMeteredCore, it isn't called for entries
+// that are checked but never written, and it's called once for each Core
+// that writes the entry: an entry written to both members of a Tee is
+// reported twice.
+//
+// Observing writes costs a small allocation for each Core that accepts an
+// entry.
+func MeteredWrites(observe func(lvl Level, err error)) MeteredOption {
+	return meteredOptionFunc(func(c *meteredCore) {
+		c.written = observe
+	})
+}
+
+type meteredCore struct {
+	Core
+
+	checked func(Level)
+	written func(Level, error)
+}
+
+var (
+	_ Core           = (*meteredCore)(nil)
+	_ leveledEnabler = (*meteredCore)(nil)
+)
+
+// NewMeteredCore wraps a Core and calls observe with the level of each entry
+// that passes the Core's Check, for example to maintain a metric of entries
+// logged by level. Entries are passed on to the Core unchanged.
+//
+// observe is called once per entry, when it's checked, even if the entry is
+// accepted by several Cores (for example, the members of a Tee). Entries
+// dropped by the Core, including those dropped by sampling, aren't observed.
+// Checking an entry doesn't guarantee it's written: it may be discarded by
+// a caller that checks entries without writing them, or its write may fail.
+// Use the MeteredWrites option to observe writes as well.
+//
+// Without MeteredWrites, the wrapper doesn't allocate, and entries below the
+// Core's level are rejected without calling observe. observe must be safe
+// for concurrent use.
+func NewMeteredCore(next Core, observe func(Level), opts ...MeteredOption) Core {
+	c := &meteredCore{
+		Core:    next,
+		checked: observe,
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+func (c *meteredCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *meteredCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *meteredCore) With(fields []Field) Core {
+	return &meteredCore{
+		Core:    c.Core.With(fields),
+		checked: c.checked,
+		written: c.written,
+	}
+}
+
+func (c *meteredCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	if c.written != nil {
+		return c.checkWritten(ent, ce)
+	}
+
+	n := numCores(ce)
+	ce = c.Core.Check(ent, ce)
+	if numCores(ce) > n && c.checked != nil {
+		c.checked(ent.Level)
+	}
+	return ce
+}
+
+// numCores reports the number of Cores registered with a possibly nil
+// CheckedEntry.
+func numCores(ce *CheckedEntry) int {
+	if ce == nil {
+		return 0
+	}
+	return len(ce.cores)
+}
+
+// checkWritten is like checkWithFieldHooks, registering each Core that
+// accepts the entry with ce so that its writes are observed.
+func (c *meteredCore) checkWritten(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	downstream := c.Core.Check(ent, nil)
+	if downstream == nil {
+		return ce
+	}
+	if len(downstream.cores) > 0 && c.checked != nil {
+		c.checked(ent.Level)
+	}
+	for _, dc := range downstream.cores {
+		ce = ce.AddCore(ent, &meteredWriter{core: dc, written: c.written})
+	}
+	if downstream.after != nil {
+		ce = ce.After(ent, downstream.after)
+	}
+	putCheckedEntry(downstream)
+	return ce
+}
+
+// meteredWriter is a Core that observes writes to a Core that has already
+// agreed to log an entry.
+type meteredWriter struct {
+	core    Core
+	written func(Level, error)
+}
+
+func (w *meteredWriter) Enabled(lvl Level) bool {
+	return w.core.Enabled(lvl)
+}
+
+func (w *meteredWriter) With(fields []Field) Core {
+	return &meteredWriter{core: w.core.With(fields), written: w.written}
+}
+
+func (w *meteredWriter) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return w.core.Check(ent, ce)
+}
+
+func (w *meteredWriter) Write(ent Entry, fields []Field) error {
+	err := w.core.Write(ent, fields)
+	w.written(ent.Level, err)
+	return err
+}
+
+func (w *meteredWriter) WriteRaw(ent Entry, line []byte) error {
+	rw, ok := w.core.(RawWriter)
+	if !ok {
+		return w.Write(ent, nil)
+	}
+	err := rw.WriteRaw(ent, line)
+	w.written(ent.Level, err)
+	return err
+}
+
+func (w *meteredWriter) Sync() error {
+	return w.core.Sync()
+}
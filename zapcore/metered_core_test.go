@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// levelCounts counts observed entries by level.
+type levelCounts struct {
+	mu     sync.Mutex
+	counts map[Level]int
+	errors int
+}
+
+func (c *levelCounts) observe(lvl Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[Level]int)
+	}
+	c.counts[lvl]++
+}
+
+func (c *levelCounts) observeWrite(lvl Level, err error) {
+	c.observe(lvl)
+	if err != nil {
+		c.mu.Lock()
+		c.errors++
+		c.mu.Unlock()
+	}
+}
+
+func writeEntry(core Core, lvl Level) {
+	if ce := core.Check(Entry{Level: lvl, Time: time.Now()}, nil); ce != nil {
+		ce.Write()
+	}
+}
+
+func TestMeteredCore(t *testing.T) {
+	info, infoLogs := observer.New(InfoLevel)
+	warn, warnLogs := observer.New(WarnLevel)
+
+	var checked levelCounts
+	core := NewMeteredCore(NewTee(info, warn), checked.observe).With([]Field{makeInt64Field("k", 1)})
+	assert.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+
+	writeEntry(core, DebugLevel)
+	writeEntry(core, InfoLevel)
+	writeEntry(core, WarnLevel)
+	writeEntry(core, WarnLevel)
+
+	// Entries accepted by both members of the Tee are observed once.
+	assert.Equal(t, map[Level]int{InfoLevel: 1, WarnLevel: 2}, checked.counts, "Unexpected checked counts.")
+	assert.Equal(t, 3, infoLogs.Len(), "Unexpected entries written to the info core.")
+	assert.Equal(t, 2, warnLogs.Len(), "Unexpected entries written to the warn core.")
+	for _, ent := range append(infoLogs.All(), warnLogs.All()...) {
+		assert.Equal(t, []Field{makeInt64Field("k", 1)}, ent.Context, "Fields should be passed on unchanged.")
+	}
+}
+
+func TestMeteredCoreSampled(t *testing.T) {
+	obs, logs := observer.New(DebugLevel)
+
+	var checked levelCounts
+	core := NewMeteredCore(NewSamplerWithOptions(obs, time.Minute, 2, 0), checked.observe)
+	for i := 0; i < 5; i++ {
+		writeEntry(core, InfoLevel)
+	}
+	assert.Equal(t, map[Level]int{InfoLevel: 2}, checked.counts, "Sampled entries shouldn't be observed.")
+	assert.Equal(t, 2, logs.Len(), "Unexpected number of entries written.")
+}
+
+func TestMeteredCoreWrites(t *testing.T) {
+	good, logs := observer.New(DebugLevel)
+	bad := NewCore(
+		NewJSONEncoder(testEncoderConfig()),
+		AddSync(ztest.FailWriter{}),
+		ErrorLevel,
+	)
+
+	var checked, written levelCounts
+	core := NewMeteredCore(
+		NewTee(good, bad),
+		checked.observe,
+		MeteredWrites(written.observeWrite),
+	).With([]Field{makeInt64Field("k", 1)})
+
+	writeEntry(core, InfoLevel)
+	writeEntry(core, ErrorLevel)
+
+	// Checked but never written.
+	require.NotNil(t, core.Check(Entry{Level: WarnLevel}, nil), "Expected entry to be accepted.")
+
+	assert.Equal(t, map[Level]int{InfoLevel: 1, WarnLevel: 1, ErrorLevel: 1}, checked.counts, "Unexpected checked counts.")
+	assert.Equal(t, map[Level]int{InfoLevel: 1, ErrorLevel: 2}, written.counts, "Unexpected written counts.")
+	assert.Equal(t, 1, written.errors, "Expected the failed write to be reported.")
+	assert.Equal(t, 2, logs.Len(), "Unexpected number of entries written.")
+	assert.Equal(t, []Field{makeInt64Field("k", 1)}, logs.All()[0].Context, "Fields should be passed on unchanged.")
+}
+
+func TestMeteredCoreDisabledLevelAllocs(t *testing.T) {
+	var checked levelCounts
+	core := NewMeteredCore(NewCore(
+		NewJSONEncoder(testEncoderConfig()),
+		AddSync(&ztest.Discarder{}),
+		InfoLevel,
+	), checked.observe)
+
+	ent := Entry{Level: DebugLevel}
+	allocs := testing.AllocsPerRun(100, func() {
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write()
+		}
+	})
+	assert.Zero(t, allocs, "Checking a disabled level shouldn't allocate.")
+	assert.Empty(t, checked.counts, "Disabled entries shouldn't be observed.")
+}
+
+func BenchmarkMeteredCore(b *testing.B) {
+	base := NewCore(
+		NewJSONEncoder(testEncoderConfig()),
+		AddSync(&ztest.Discarder{}),
+		InfoLevel,
+	)
+	metered := NewMeteredCore(base, func(Level) {})
+
+	for _, bb := range []struct {
+		name  string
+		core  Core
+		level Level
+	}{
+		{"disabled/plain", base, DebugLevel},
+		{"disabled/metered", metered, DebugLevel},
+		{"enabled/plain", base, InfoLevel},
+		{"enabled/metered", metered, InfoLevel},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			ent := Entry{Level: bb.level, Message: "fake"}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if ce := bb.core.Check(ent, nil); ce != nil {
+					ce.Write()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,699 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+)
+
+// _msgpackTimestampExt is the MessagePack extension type for timestamps, -1.
+const _msgpackTimestampExt byte = 0xff
+
+// msgpackFrame collects the members of a MessagePack map or array. Since
+// MessagePack headers record the number of members, a frame's contents can
+// only be written out once it's complete.
+type msgpackFrame struct {
+	buf   *buffer.Buffer
+	n     int    // number of map entries or array elements in buf
+	array bool   // whether this frame is an array rather than a map
+	key   string // for namespaces, the key under which to write the frame
+	ns    bool   // whether this frame is a namespace
+}
+
+type msgpackEncoder struct {
+	*EncoderConfig
+
+	// frames[0] is the top-level map; the last frame is the innermost open
+	// namespace, object, or array.
+	frames []msgpackFrame
+	depth  int // nesting of objects and arrays added by marshalers
+
+	// for encoding generic values by reflection
+	reflectBuf *buffer.Buffer
+	reflectEnc ReflectedEncoder
+}
+
+// NewMsgpackEncoder creates an encoder whose output is a MessagePack map per
+// entry, suitable for high-throughput binary pipelines. Its keys and
+// metadata encoders are configured in the same way as the JSON encoder's;
+// for example, with ISO8601TimeEncoder times are encoded as strings, and
+// with EpochNanosTimeEncoder as integers. If EncodeTime is nil, times are
+// encoded with the MessagePack timestamp extension type. If EncodeDuration is
+// nil, durations are encoded as integer nanoseconds.
+//
+// Binary fields are encoded with the MessagePack bin type, and complex
+// numbers as strings (for example, "1+2i"). Reflected fields are first
+// serialized with the configured NewReflectedEncoder, which must produce
+// JSON, and then converted to the equivalent MessagePack value.
+//
+// Entries aren't followed by a line ending. To send entries to Fluentd, wrap
+// the destination with NewFluentForwardSyncer.
+func NewMsgpackEncoder(cfg EncoderConfig) Encoder {
+	if cfg.NewReflectedEncoder == nil {
+		cfg.NewReflectedEncoder = defaultReflectedEncoder
+	}
+	return &msgpackEncoder{
+		EncoderConfig: &cfg,
+		frames:        []msgpackFrame{{buf: bufferpool.Get()}},
+	}
+}
+
+func (enc *msgpackEncoder) cur() *msgpackFrame {
+	return &enc.frames[len(enc.frames)-1]
+}
+
+func (enc *msgpackEncoder) addKey(key string) {
+	appendMsgpackString(enc.cur().buf, key)
+}
+
+// pushFrame starts a nested map or array.
+func (enc *msgpackEncoder) pushFrame(f msgpackFrame) {
+	f.buf = bufferpool.Get()
+	enc.frames = append(enc.frames, f)
+}
+
+// popFrames closes frames until only depth remain, writing each into its
+// parent.
+func (enc *msgpackEncoder) popFrames(depth int) {
+	for len(enc.frames) > depth {
+		f := enc.frames[len(enc.frames)-1]
+		enc.frames = enc.frames[:len(enc.frames)-1]
+		parent := enc.cur()
+		if f.ns {
+			appendMsgpackString(parent.buf, f.key)
+		}
+		if f.array {
+			appendMsgpackArrayHeader(parent.buf, f.n)
+		} else {
+			appendMsgpackMapHeader(parent.buf, f.n)
+		}
+		parent.buf.Write(f.buf.Bytes())
+		parent.n++
+		f.buf.Free()
+	}
+}
+
+// dropFrames discards the frames above depth without writing them to their
+// parents.
+func (enc *msgpackEncoder) dropFrames(depth int) {
+	for _, f := range enc.frames[depth:] {
+		f.buf.Free()
+	}
+	enc.frames = enc.frames[:depth]
+}
+
+func (enc *msgpackEncoder) AddArray(key string, arr ArrayMarshaler) error {
+	enc.addKey(key)
+	return enc.AppendArray(arr)
+}
+
+func (enc *msgpackEncoder) AddObject(key string, obj ObjectMarshaler) error {
+	enc.addKey(key)
+	return enc.AppendObject(obj)
+}
+
+func (enc *msgpackEncoder) AddBinary(key string, val []byte) {
+	enc.addKey(key)
+	appendMsgpackBinary(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AddByteString(key string, val []byte) {
+	enc.addKey(key)
+	enc.AppendByteString(val)
+}
+
+func (enc *msgpackEncoder) AddBool(key string, val bool) {
+	enc.addKey(key)
+	enc.AppendBool(val)
+}
+
+func (enc *msgpackEncoder) AddComplex128(key string, val complex128) {
+	enc.addKey(key)
+	enc.AppendComplex128(val)
+}
+
+func (enc *msgpackEncoder) AddComplex64(key string, val complex64) {
+	enc.addKey(key)
+	enc.AppendComplex64(val)
+}
+
+func (enc *msgpackEncoder) AddDuration(key string, val time.Duration) {
+	enc.addKey(key)
+	enc.AppendDuration(val)
+}
+
+func (enc *msgpackEncoder) AddFloat64(key string, val float64) {
+	enc.addKey(key)
+	enc.AppendFloat64(val)
+}
+
+func (enc *msgpackEncoder) AddFloat32(key string, val float32) {
+	enc.addKey(key)
+	enc.AppendFloat32(val)
+}
+
+func (enc *msgpackEncoder) AddInt64(key string, val int64) {
+	enc.addKey(key)
+	enc.AppendInt64(val)
+}
+
+func (enc *msgpackEncoder) AddReflected(key string, obj interface{}) error {
+	valueBytes, err := enc.encodeReflected(obj)
+	if err != nil {
+		return err
+	}
+	// Convert into a scratch frame so that a conversion error doesn't leave
+	// a dangling key.
+	depth := len(enc.frames)
+	enc.pushFrame(msgpackFrame{array: true})
+	if err := enc.appendJSON(valueBytes); err != nil {
+		enc.dropFrames(depth)
+		return err
+	}
+	f := enc.frames[depth]
+	enc.frames = enc.frames[:depth]
+	enc.addKey(key)
+	enc.cur().buf.Write(f.buf.Bytes())
+	enc.cur().n++
+	f.buf.Free()
+	return nil
+}
+
+func (enc *msgpackEncoder) OpenNamespace(key string) {
+	enc.pushFrame(msgpackFrame{key: key, ns: true})
+}
+
+func (enc *msgpackEncoder) AddString(key, val string) {
+	enc.addKey(key)
+	enc.AppendString(val)
+}
+
+func (enc *msgpackEncoder) AddTime(key string, val time.Time) {
+	enc.addKey(key)
+	enc.AppendTime(val)
+}
+
+func (enc *msgpackEncoder) AddUint64(key string, val uint64) {
+	enc.addKey(key)
+	enc.AppendUint64(val)
+}
+
+func (enc *msgpackEncoder) AppendArray(arr ArrayMarshaler) error {
+	if enc.maxDepthExceeded() {
+		enc.appendMaxDepthMarker()
+		return nil
+	}
+	depth := len(enc.frames)
+	enc.depth++
+	enc.pushFrame(msgpackFrame{array: true})
+	err := arr.MarshalLogArray(enc)
+	enc.popFrames(depth)
+	enc.depth--
+	return err
+}
+
+func (enc *msgpackEncoder) AppendObject(obj ObjectMarshaler) error {
+	if enc.maxDepthExceeded() {
+		enc.appendMaxDepthMarker()
+		return nil
+	}
+	// Namespaces opened by the marshaler are closed along with the object.
+	depth := len(enc.frames)
+	enc.depth++
+	enc.pushFrame(msgpackFrame{})
+	err := obj.MarshalLogObject(enc)
+	enc.popFrames(depth)
+	enc.depth--
+	return err
+}
+
+func (enc *msgpackEncoder) maxDepthExceeded() bool {
+	return enc.MaxObjectDepth > 0 && enc.depth >= enc.MaxObjectDepth
+}
+
+// appendMaxDepthMarker appends the map that stands in for maps and arrays
+// nested beyond MaxObjectDepth.
+func (enc *msgpackEncoder) appendMaxDepthMarker() {
+	appendMsgpackMapHeader(enc.cur().buf, 1)
+	appendMsgpackString(enc.cur().buf, _maxDepthKey)
+	appendMsgpackString(enc.cur().buf, _maxDepthMessage)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AppendBool(val bool) {
+	appendMsgpackBool(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AppendByteString(val []byte) {
+	appendMsgpackStringHeader(enc.cur().buf, len(val))
+	enc.cur().buf.Write(val)
+	enc.cur().n++
+}
+
+// appendComplex appends the string form of the provided complex128 value,
+// matching the JSON encoder. precision specifies the encoding precision for
+// the real and imaginary components of the complex number.
+func (enc *msgpackEncoder) appendComplex(val complex128, precision int) {
+	r, i := float64(real(val)), float64(imag(val))
+	scratch := bufferpool.Get()
+	scratch.AppendFloat(r, precision)
+	if i >= 0 {
+		scratch.AppendByte('+')
+	}
+	scratch.AppendFloat(i, precision)
+	scratch.AppendByte('i')
+	enc.AppendByteString(scratch.Bytes())
+	scratch.Free()
+}
+
+func (enc *msgpackEncoder) AppendDuration(val time.Duration) {
+	cur := enc.cur().buf.Len()
+	if e := enc.EncodeDuration; e != nil {
+		e(val, enc)
+	}
+	if cur == enc.cur().buf.Len() {
+		// EncodeDuration is nil or a no-op. Fall back to nanoseconds.
+		enc.AppendInt64(int64(val))
+	}
+}
+
+func (enc *msgpackEncoder) AppendInt64(val int64) {
+	appendMsgpackInt(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AppendReflected(val interface{}) error {
+	valueBytes, err := enc.encodeReflected(val)
+	if err != nil {
+		return err
+	}
+	return enc.appendJSON(valueBytes)
+}
+
+func (enc *msgpackEncoder) AppendString(val string) {
+	appendMsgpackString(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AppendTimeLayout(time time.Time, layout string) {
+	enc.AppendString(time.Format(layout))
+}
+
+func (enc *msgpackEncoder) AppendTime(val time.Time) {
+	cur := enc.cur().buf.Len()
+	if e := enc.EncodeTime; e != nil {
+		e(val, enc)
+	}
+	if cur == enc.cur().buf.Len() {
+		// EncodeTime is nil or a no-op. Fall back to the timestamp extension.
+		appendMsgpackTimestamp(enc.cur().buf, val)
+		enc.cur().n++
+	}
+}
+
+func (enc *msgpackEncoder) AppendUint64(val uint64) {
+	appendMsgpackUint(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AppendFloat64(val float64) {
+	appendMsgpackFloat64(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AppendFloat32(val float32) {
+	appendMsgpackFloat32(enc.cur().buf, val)
+	enc.cur().n++
+}
+
+func (enc *msgpackEncoder) AddInt(k string, v int)         { enc.AddInt64(k, int64(v)) }
+func (enc *msgpackEncoder) AddInt32(k string, v int32)     { enc.AddInt64(k, int64(v)) }
+func (enc *msgpackEncoder) AddInt16(k string, v int16)     { enc.AddInt64(k, int64(v)) }
+func (enc *msgpackEncoder) AddInt8(k string, v int8)       { enc.AddInt64(k, int64(v)) }
+func (enc *msgpackEncoder) AddUint(k string, v uint)       { enc.AddUint64(k, uint64(v)) }
+func (enc *msgpackEncoder) AddUint32(k string, v uint32)   { enc.AddUint64(k, uint64(v)) }
+func (enc *msgpackEncoder) AddUint16(k string, v uint16)   { enc.AddUint64(k, uint64(v)) }
+func (enc *msgpackEncoder) AddUint8(k string, v uint8)     { enc.AddUint64(k, uint64(v)) }
+func (enc *msgpackEncoder) AddUintptr(k string, v uintptr) { enc.AddUint64(k, uint64(v)) }
+func (enc *msgpackEncoder) AppendComplex64(v complex64)    { enc.appendComplex(complex128(v), 32) }
+func (enc *msgpackEncoder) AppendComplex128(v complex128)  { enc.appendComplex(complex128(v), 64) }
+func (enc *msgpackEncoder) AppendInt(v int)                { enc.AppendInt64(int64(v)) }
+func (enc *msgpackEncoder) AppendInt32(v int32)            { enc.AppendInt64(int64(v)) }
+func (enc *msgpackEncoder) AppendInt16(v int16)            { enc.AppendInt64(int64(v)) }
+func (enc *msgpackEncoder) AppendInt8(v int8)              { enc.AppendInt64(int64(v)) }
+func (enc *msgpackEncoder) AppendUint(v uint)              { enc.AppendUint64(uint64(v)) }
+func (enc *msgpackEncoder) AppendUint32(v uint32)          { enc.AppendUint64(uint64(v)) }
+func (enc *msgpackEncoder) AppendUint16(v uint16)          { enc.AppendUint64(uint64(v)) }
+func (enc *msgpackEncoder) AppendUint8(v uint8)            { enc.AppendUint64(uint64(v)) }
+func (enc *msgpackEncoder) AppendUintptr(v uintptr)        { enc.AppendUint64(uint64(v)) }
+
+func (enc *msgpackEncoder) Clone() Encoder {
+	clone := &msgpackEncoder{
+		EncoderConfig: enc.EncoderConfig,
+		frames:        make([]msgpackFrame, len(enc.frames)),
+	}
+	for i, f := range enc.frames {
+		f.buf = bufferpool.Get()
+		f.buf.Write(enc.frames[i].buf.Bytes())
+		clone.frames[i] = f
+	}
+	return clone
+}
+
+func (enc *msgpackEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	final := &msgpackEncoder{
+		EncoderConfig: enc.EncoderConfig,
+		frames:        make([]msgpackFrame, 0, len(enc.frames)),
+	}
+	final.pushFrame(msgpackFrame{})
+
+	if final.LevelKey != "" && final.EncodeLevel != nil {
+		final.addKey(final.LevelKey)
+		cur := final.cur().buf.Len()
+		final.EncodeLevel(ent.Level, final)
+		if cur == final.cur().buf.Len() {
+			final.AppendString(ent.Level.String())
+		}
+	}
+	if final.TimeKey != "" && !ent.Time.IsZero() {
+		final.AddTime(final.TimeKey, ent.Time)
+	}
+	if ent.LoggerName != "" && final.NameKey != "" {
+		final.addKey(final.NameKey)
+		cur := final.cur().buf.Len()
+		nameEncoder := final.EncodeName
+		if nameEncoder == nil {
+			nameEncoder = FullNameEncoder
+		}
+		nameEncoder(ent.LoggerName, final)
+		if cur == final.cur().buf.Len() {
+			final.AppendString(ent.LoggerName)
+		}
+	}
+	if ent.Caller.Defined {
+		if final.CallerKey != "" {
+			final.addKey(final.CallerKey)
+			cur := final.cur().buf.Len()
+			if final.EncodeCaller != nil {
+				final.EncodeCaller(ent.Caller, final)
+			}
+			if cur == final.cur().buf.Len() {
+				final.AppendString(ent.Caller.String())
+			}
+		}
+		if final.FunctionKey != "" {
+			final.AddString(final.FunctionKey, ent.Caller.Function)
+		}
+	}
+	if final.MessageKey != "" {
+		final.AddString(final.MessageKey, ent.Message)
+	}
+
+	// Copy the accumulated context, including any open namespaces.
+	root := final.cur()
+	root.buf.Write(enc.frames[0].buf.Bytes())
+	root.n += enc.frames[0].n
+	for _, f := range enc.frames[1:] {
+		final.pushFrame(f)
+		final.cur().buf.Write(f.buf.Bytes())
+	}
+
+	if final.SortFields {
+		fields = sortFields(fields)
+	}
+	addFields(final, fields)
+	final.popFrames(1)
+	if ent.Stack != "" && final.StacktraceKey != "" {
+		final.AddString(final.StacktraceKey, ent.Stack)
+	}
+
+	root = final.cur()
+	out := bufferpool.Get()
+	appendMsgpackMapHeader(out, root.n)
+	out.Write(root.buf.Bytes())
+	root.buf.Free()
+	if final.reflectBuf != nil {
+		final.reflectBuf.Free()
+	}
+	return out, nil
+}
+
+func (enc *msgpackEncoder) resetReflectBuf() {
+	if enc.reflectBuf == nil {
+		enc.reflectBuf = bufferpool.Get()
+		enc.reflectEnc = enc.NewReflectedEncoder(enc.reflectBuf)
+	} else {
+		enc.reflectBuf.Reset()
+	}
+}
+
+func (enc *msgpackEncoder) encodeReflected(obj interface{}) ([]byte, error) {
+	if obj == nil {
+		return nullLiteralBytes, nil
+	}
+	enc.resetReflectBuf()
+	if err := enc.reflectEnc.Encode(obj); err != nil {
+		return nil, err
+	}
+	enc.reflectBuf.TrimNewline()
+	return enc.reflectBuf.Bytes(), nil
+}
+
+// appendJSON converts a single JSON value to MessagePack, preserving the
+// order of object members.
+func (enc *msgpackEncoder) appendJSON(bs []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	depth := len(enc.frames)
+	if err := enc.appendJSONValue(dec); err != nil {
+		// Discard the partly converted objects and arrays.
+		enc.dropFrames(depth)
+		return fmt.Errorf("msgpack encoder: can't convert reflected value: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("msgpack encoder: reflected encoder produced more than one value")
+	}
+	return nil
+}
+
+func (enc *msgpackEncoder) appendJSONValue(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		depth := len(enc.frames)
+		enc.pushFrame(msgpackFrame{array: v == '['})
+		for dec.More() {
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				enc.addKey(key.(string))
+			}
+			if err := enc.appendJSONValue(dec); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return err
+		}
+		enc.popFrames(depth)
+	case bool:
+		enc.AppendBool(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			enc.AppendInt64(i)
+		} else if f, err := v.Float64(); err == nil {
+			enc.AppendFloat64(f)
+		} else {
+			enc.AppendString(v.String())
+		}
+	case string:
+		enc.AppendString(v)
+	case nil:
+		enc.cur().buf.AppendByte(0xc0)
+		enc.cur().n++
+	}
+	return nil
+}
+
+func appendMsgpackBool(buf *buffer.Buffer, v bool) {
+	if v {
+		buf.AppendByte(0xc3)
+	} else {
+		buf.AppendByte(0xc2)
+	}
+}
+
+func appendMsgpackInt(buf *buffer.Buffer, v int64) {
+	switch {
+	case v >= 0:
+		appendMsgpackUint(buf, uint64(v))
+	case v >= -32:
+		buf.AppendByte(byte(v)) // negative fixint
+	case v >= math.MinInt8:
+		buf.AppendByte(0xd0)
+		buf.AppendByte(byte(v))
+	case v >= math.MinInt16:
+		buf.AppendByte(0xd1)
+		appendBigEndian(buf, uint64(v), 2)
+	case v >= math.MinInt32:
+		buf.AppendByte(0xd2)
+		appendBigEndian(buf, uint64(v), 4)
+	default:
+		buf.AppendByte(0xd3)
+		appendBigEndian(buf, uint64(v), 8)
+	}
+}
+
+func appendMsgpackUint(buf *buffer.Buffer, v uint64) {
+	switch {
+	case v <= math.MaxInt8:
+		buf.AppendByte(byte(v)) // positive fixint
+	case v <= math.MaxUint8:
+		buf.AppendByte(0xcc)
+		buf.AppendByte(byte(v))
+	case v <= math.MaxUint16:
+		buf.AppendByte(0xcd)
+		appendBigEndian(buf, v, 2)
+	case v <= math.MaxUint32:
+		buf.AppendByte(0xce)
+		appendBigEndian(buf, v, 4)
+	default:
+		buf.AppendByte(0xcf)
+		appendBigEndian(buf, v, 8)
+	}
+}
+
+func appendMsgpackFloat64(buf *buffer.Buffer, v float64) {
+	buf.AppendByte(0xcb)
+	appendBigEndian(buf, math.Float64bits(v), 8)
+}
+
+func appendMsgpackFloat32(buf *buffer.Buffer, v float32) {
+	buf.AppendByte(0xca)
+	appendBigEndian(buf, uint64(math.Float32bits(v)), 4)
+}
+
+func appendMsgpackStringHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n < 32:
+		buf.AppendByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.AppendByte(0xd9)
+		buf.AppendByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xda)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xdb)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+}
+
+func appendMsgpackString(buf *buffer.Buffer, s string) {
+	appendMsgpackStringHeader(buf, len(s))
+	buf.AppendString(s)
+}
+
+func appendMsgpackBinary(buf *buffer.Buffer, bs []byte) {
+	switch n := len(bs); {
+	case n <= math.MaxUint8:
+		buf.AppendByte(0xc4)
+		buf.AppendByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xc5)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xc6)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+	buf.Write(bs)
+}
+
+func appendMsgpackArrayHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.AppendByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xdc)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xdd)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+}
+
+func appendMsgpackMapHeader(buf *buffer.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.AppendByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.AppendByte(0xde)
+		appendBigEndian(buf, uint64(n), 2)
+	default:
+		buf.AppendByte(0xdf)
+		appendBigEndian(buf, uint64(n), 4)
+	}
+}
+
+// appendMsgpackTimestamp appends t using the timestamp extension type, in
+// the smallest of its 32-, 64-, and 96-bit forms that fits.
+func appendMsgpackTimestamp(buf *buffer.Buffer, t time.Time) {
+	sec, nsec := uint64(t.Unix()), uint64(t.Nanosecond())
+	switch {
+	case sec>>34 == 0 && nsec == 0 && sec <= math.MaxUint32:
+		buf.AppendByte(0xd6) // fixext 4
+		buf.AppendByte(_msgpackTimestampExt)
+		appendBigEndian(buf, sec, 4)
+	case sec>>34 == 0:
+		buf.AppendByte(0xd7) // fixext 8
+		buf.AppendByte(_msgpackTimestampExt)
+		appendBigEndian(buf, nsec<<34|sec, 8)
+	default:
+		buf.AppendByte(0xc7) // ext 8
+		buf.AppendByte(12)
+		buf.AppendByte(_msgpackTimestampExt)
+		appendBigEndian(buf, nsec, 4)
+		appendBigEndian(buf, sec, 8)
+	}
+}
+
+// appendBigEndian appends the low size bytes of v in big-endian order.
+func appendBigEndian(buf *buffer.Buffer, v uint64, size int) {
+	for i := size - 1; i >= 0; i-- {
+		buf.AppendByte(byte(v >> (8 * i)))
+	}
+}
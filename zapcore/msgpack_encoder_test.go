@@ -0,0 +1,477 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+// msgpackExt is a decoded MessagePack extension value.
+type msgpackExt struct {
+	Type int8
+	Data []byte
+}
+
+// decodeMsgpack decodes a single MessagePack value, returning it along with
+// any remaining bytes. Integers decode to int64 (or uint64 if too large),
+// floats to float64, maps to map[string]interface{}, and arrays to
+// []interface{}.
+func decodeMsgpack(bs []byte) (interface{}, []byte, error) {
+	if len(bs) == 0 {
+		return nil, nil, errors.New("unexpected end of input")
+	}
+	b, bs := bs[0], bs[1:]
+	take := func(n int) ([]byte, error) {
+		if len(bs) < n {
+			return nil, errors.New("unexpected end of input")
+		}
+		v := bs[:n]
+		bs = bs[n:]
+		return v, nil
+	}
+	uintN := func(n int) (uint64, error) {
+		v, err := take(n)
+		if err != nil {
+			return 0, err
+		}
+		var u uint64
+		for _, c := range v {
+			u = u<<8 | uint64(c)
+		}
+		return u, nil
+	}
+	str := func(n int) (interface{}, []byte, error) {
+		v, err := take(n)
+		return string(v), bs, err
+	}
+	collection := func(n int, isMap bool) (interface{}, []byte, error) {
+		if isMap {
+			m := make(map[string]interface{}, n)
+			for i := 0; i < n; i++ {
+				k, rest, err := decodeMsgpack(bs)
+				if err != nil {
+					return nil, nil, err
+				}
+				v, rest, err := decodeMsgpack(rest)
+				if err != nil {
+					return nil, nil, err
+				}
+				m[k.(string)], bs = v, rest
+			}
+			return m, bs, nil
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			v, rest, err := decodeMsgpack(bs)
+			if err != nil {
+				return nil, nil, err
+			}
+			arr[i], bs = v, rest
+		}
+		return arr, bs, nil
+	}
+	ext := func(n int) (interface{}, []byte, error) {
+		v, err := take(n + 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return msgpackExt{Type: int8(v[0]), Data: v[1:]}, bs, nil
+	}
+	sized := func(width int, f func(int) (interface{}, []byte, error)) (interface{}, []byte, error) {
+		n, err := uintN(width)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f(int(n))
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), bs, nil
+	case b >= 0xe0:
+		return int64(int8(b)), bs, nil
+	case b&0xe0 == 0xa0:
+		return str(int(b & 0x1f))
+	case b&0xf0 == 0x90:
+		return collection(int(b&0x0f), false)
+	case b&0xf0 == 0x80:
+		return collection(int(b&0x0f), true)
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, bs, nil
+	case 0xc2, 0xc3:
+		return b == 0xc3, bs, nil
+	case 0xc4, 0xc5, 0xc6:
+		return sized(1<<(b-0xc4), func(n int) (interface{}, []byte, error) {
+			v, err := take(n)
+			return append([]byte{}, v...), bs, err
+		})
+	case 0xc7:
+		return sized(1, ext)
+	case 0xca:
+		u, err := uintN(4)
+		return float64(math.Float32frombits(uint32(u))), bs, err
+	case 0xcb:
+		u, err := uintN(8)
+		return math.Float64frombits(u), bs, err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := uintN(1 << (b - 0xcc))
+		if u > math.MaxInt64 {
+			return u, bs, err
+		}
+		return int64(u), bs, err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		width := 1 << (b - 0xd0)
+		u, err := uintN(width)
+		shift := 64 - 8*width
+		return int64(u<<shift) >> shift, bs, err
+	case 0xd6:
+		return ext(4)
+	case 0xd7:
+		return ext(8)
+	case 0xd9, 0xda, 0xdb:
+		return sized(1<<(b-0xd9), str)
+	case 0xdc, 0xdd:
+		return sized(2<<(b-0xdc), func(n int) (interface{}, []byte, error) { return collection(n, false) })
+	case 0xde, 0xdf:
+		return sized(2<<(b-0xde), func(n int) (interface{}, []byte, error) { return collection(n, true) })
+	}
+	return nil, nil, fmt.Errorf("unsupported MessagePack type 0x%x", b)
+}
+
+func decodeMsgpackEntry(t *testing.T, bs []byte) map[string]interface{} {
+	v, rest, err := decodeMsgpack(bs)
+	require.NoError(t, err, "Failed to decode MessagePack.")
+	require.Empty(t, rest, "Unexpected trailing bytes.")
+	m, ok := v.(map[string]interface{})
+	require.True(t, ok, "Expected a map, got %T.", v)
+	return m
+}
+
+func TestMsgpackEncodeEntry(t *testing.T) {
+	cfg := testEncoderConfig()
+	cfg.EncodeTime = nil
+	cfg.EncodeDuration = nil
+	enc := NewMsgpackEncoder(cfg)
+	enc.AddString("context", "yes")
+	enc.OpenNamespace("ns")
+
+	ts := time.Unix(1700000000, 123).UTC()
+	entry := Entry{
+		Level:      WarnLevel,
+		Time:       ts,
+		LoggerName: "svc",
+		Message:    "hello",
+		Caller:     EntryCaller{Defined: true, File: "foo.go", Line: 12, Function: "pkg.Foo"},
+		Stack:      "stack",
+	}
+	long := string(make([]byte, 300))
+	fields := []Field{
+		{Key: "bool", Type: BoolType, Integer: 1},
+		{Key: "small", Type: Int64Type, Integer: 7},
+		{Key: "neg", Type: Int64Type, Integer: -100},
+		{Key: "min", Type: Int64Type, Integer: math.MinInt64},
+		{Key: "big", Type: Uint64Type, Integer: -1}, // math.MaxUint64
+		{Key: "float", Type: Float64Type, Integer: int64(math.Float64bits(1.5))},
+		{Key: "float32", Type: Float32Type, Integer: int64(math.Float32bits(2.5))},
+		{Key: "complex", Type: Complex128Type, Interface: complex(1, -2)},
+		{Key: "long", Type: StringType, String: long},
+		{Key: "bin", Type: BinaryType, Interface: []byte{1, 2}},
+		{Key: "bytes", Type: ByteStringType, Interface: []byte("bs")},
+		{Key: "dur", Type: DurationType, Integer: int64(time.Second)},
+		{Key: "time", Type: TimeType, Integer: ts.UnixNano(), Interface: time.UTC},
+		{Key: "obj", Type: ObjectMarshalerType, Interface: ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+			enc.AddInt("a", 1)
+			enc.OpenNamespace("inner")
+			enc.AddInt("b", 2)
+			return nil
+		})},
+		{Key: "arr", Type: ArrayMarshalerType, Interface: ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+			enc.AppendString("x")
+			return enc.AppendArray(ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+				enc.AppendInt(1)
+				return nil
+			}))
+		})},
+		{Key: "reflect", Type: ReflectType, Interface: map[string]interface{}{"k": []interface{}{1, "v", nil, 1.5, true}}},
+	}
+
+	for i := 0; i < 2; i++ {
+		buf, err := enc.EncodeEntry(entry, fields)
+		require.NoError(t, err, "Unexpected encoding error.")
+		got := decodeMsgpackEntry(t, buf.Bytes())
+		buf.Free()
+
+		assert.Equal(t, map[string]interface{}{
+			"level":      "warn",
+			"ts":         msgpackExt{Type: -1, Data: []byte{0x00, 0x00, 0x01, 0xec, 0x65, 0x53, 0xf1, 0x00}},
+			"name":       "svc",
+			"caller":     "foo.go:12",
+			"func":       "pkg.Foo",
+			"msg":        "hello",
+			"stacktrace": "stack",
+			"context":    "yes",
+			"ns": map[string]interface{}{
+				"bool":    true,
+				"small":   int64(7),
+				"neg":     int64(-100),
+				"min":     int64(math.MinInt64),
+				"big":     uint64(math.MaxUint64),
+				"float":   1.5,
+				"float32": 2.5,
+				"complex": "1-2i",
+				"long":    long,
+				"bin":     []byte{1, 2},
+				"bytes":   "bs",
+				"dur":     int64(time.Second),
+				"time":    msgpackExt{Type: -1, Data: []byte{0x00, 0x00, 0x01, 0xec, 0x65, 0x53, 0xf1, 0x00}},
+				"obj": map[string]interface{}{
+					"a":     int64(1),
+					"inner": map[string]interface{}{"b": int64(2)},
+				},
+				"arr":     []interface{}{"x", []interface{}{int64(1)}},
+				"reflect": map[string]interface{}{"k": []interface{}{int64(1), "v", nil, 1.5, true}},
+			},
+		}, got, "Unexpected decoded entry.")
+	}
+}
+
+func TestMsgpackEncoderConfiguredTimeAndDuration(t *testing.T) {
+	cfg := testEncoderConfig()
+	cfg.EncodeTime = ISO8601TimeEncoder
+	cfg.EncodeDuration = StringDurationEncoder
+	enc := NewMsgpackEncoder(cfg)
+
+	ts := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	buf, err := enc.EncodeEntry(Entry{Time: ts, Message: "m"}, []Field{
+		{Key: "dur", Type: DurationType, Integer: int64(time.Minute)},
+	})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+
+	got := decodeMsgpackEntry(t, buf.Bytes())
+	assert.Equal(t, "2024-01-02T03:04:05.000Z", got["ts"], "Expected configured time encoder to be used.")
+	assert.Equal(t, "1m0s", got["dur"], "Expected configured duration encoder to be used.")
+}
+
+func TestMsgpackEncoderClone(t *testing.T) {
+	enc := NewMsgpackEncoder(EncoderConfig{MessageKey: "msg"})
+	enc.AddString("a", "1")
+	clone := enc.Clone()
+	clone.AddString("b", "2")
+	enc.AddString("c", "3")
+
+	encode := func(enc Encoder) map[string]interface{} {
+		buf, err := enc.EncodeEntry(Entry{Message: "m"}, nil)
+		require.NoError(t, err, "Unexpected encoding error.")
+		defer buf.Free()
+		return decodeMsgpackEntry(t, buf.Bytes())
+	}
+	assert.Equal(t, map[string]interface{}{"msg": "m", "a": "1", "c": "3"}, encode(enc), "Unexpected original.")
+	assert.Equal(t, map[string]interface{}{"msg": "m", "a": "1", "b": "2"}, encode(clone), "Unexpected clone.")
+}
+
+func TestMsgpackEncoderReflectedError(t *testing.T) {
+	enc := NewMsgpackEncoder(EncoderConfig{MessageKey: "msg"})
+	assert.Error(t, enc.AddReflected("k", make(chan int)), "Expected error reflecting a channel.")
+
+	buf, err := enc.EncodeEntry(Entry{Message: "m"}, nil)
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+	assert.Equal(t, map[string]interface{}{"msg": "m"}, decodeMsgpackEntry(t, buf.Bytes()), "Expected failed field to be dropped.")
+}
+
+func TestFluentForwardSyncer(t *testing.T) {
+	sink := &ztest.Buffer{}
+	ws := NewFluentForwardSyncer(sink, "app.logs")
+	enc := NewMsgpackEncoder(EncoderConfig{MessageKey: "msg"})
+
+	record, err := enc.EncodeEntry(Entry{Message: "hello"}, nil)
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer record.Free()
+
+	before := time.Now()
+	n, err := ws.Write(record.Bytes())
+	require.NoError(t, err, "Unexpected write error.")
+	assert.Equal(t, record.Len(), n, "Unexpected number of bytes written.")
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.True(t, sink.Called(), "Expected sync to be forwarded.")
+
+	v, rest, err := decodeMsgpack(sink.Bytes())
+	require.NoError(t, err, "Failed to decode forward message.")
+	require.Empty(t, rest, "Unexpected trailing bytes.")
+	msg, ok := v.([]interface{})
+	require.True(t, ok, "Expected an array, got %T.", v)
+	require.Len(t, msg, 3, "Expected [tag, time, record].")
+
+	assert.Equal(t, "app.logs", msg[0], "Unexpected tag.")
+	eventTime, ok := msg[1].(msgpackExt)
+	require.True(t, ok, "Expected EventTime extension, got %T.", msg[1])
+	assert.Equal(t, int8(0), eventTime.Type, "Unexpected extension type.")
+	require.Len(t, eventTime.Data, 8, "Unexpected EventTime length.")
+	ts := time.Unix(
+		int64(binary.BigEndian.Uint32(eventTime.Data[:4])),
+		int64(binary.BigEndian.Uint32(eventTime.Data[4:])),
+	)
+	assert.WithinDuration(t, before, ts, time.Minute, "Unexpected event time.")
+	assert.Equal(t, map[string]interface{}{"msg": "hello"}, msg[2], "Unexpected record.")
+}
+
+func TestFluentForwardSyncerEntryTime(t *testing.T) {
+	decodeEventTime := func(t *testing.T, bs []byte) time.Time {
+		v, _, err := decodeMsgpack(bs)
+		require.NoError(t, err, "Failed to decode forward message.")
+		eventTime := v.([]interface{})[1].(msgpackExt)
+		require.Len(t, eventTime.Data, 8, "Unexpected EventTime length.")
+		return time.Unix(
+			int64(binary.BigEndian.Uint32(eventTime.Data[:4])),
+			int64(binary.BigEndian.Uint32(eventTime.Data[4:])),
+		)
+	}
+
+	tests := []struct {
+		desc string
+		ts   time.Time
+	}{
+		{"seconds", time.Unix(1700000000, 0)},
+		{"nanoseconds", time.Unix(1700000000, 123456789)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			sink := &ztest.Buffer{}
+			ws := NewFluentForwardSyncer(sink, "tag")
+			enc := NewMsgpackEncoder(EncoderConfig{TimeKey: "ts", LevelKey: "level", EncodeLevel: LowercaseLevelEncoder})
+
+			record, err := enc.EncodeEntry(Entry{Time: tt.ts, Message: "m"}, nil)
+			require.NoError(t, err, "Unexpected encoding error.")
+			defer record.Free()
+			_, err = ws.Write(record.Bytes())
+			require.NoError(t, err, "Unexpected write error.")
+
+			assert.True(t, tt.ts.Equal(decodeEventTime(t, sink.Bytes())), "Expected the entry's time.")
+		})
+	}
+
+	t.Run("after other values", func(t *testing.T) {
+		sink := &ztest.Buffer{}
+		ws := NewFluentForwardSyncer(sink, "tag")
+		ts := time.Unix(1700000000, 5)
+
+		enc := NewMsgpackEncoder(EncoderConfig{})
+		enc.AddBinary("bin", []byte{1, 2, 3})
+		enc.AddFloat64("f", 1.5)
+		enc.AddInt64("i", -1<<40)
+		require.NoError(t, enc.AddObject("o", ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+			enc.AddTime("nested", time.Unix(1, 0)) // not top-level
+			return nil
+		})), "Unexpected error adding object.")
+		require.NoError(t, enc.AddArray("a", ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+			enc.AppendString("x")
+			enc.AppendBool(true)
+			return nil
+		})), "Unexpected error adding array.")
+		enc.AddTime("t", ts)
+
+		record, err := enc.EncodeEntry(Entry{}, nil)
+		require.NoError(t, err, "Unexpected encoding error.")
+		defer record.Free()
+		_, err = ws.Write(record.Bytes())
+		require.NoError(t, err, "Unexpected write error.")
+
+		assert.True(t, ts.Equal(decodeEventTime(t, sink.Bytes())), "Expected the first top-level time.")
+	})
+}
+
+func TestMsgpackEncoderAppendReflectedError(t *testing.T) {
+	enc := NewMsgpackEncoder(EncoderConfig{
+		NewReflectedEncoder: func(w io.Writer) ReflectedEncoder {
+			return reflectedEncoderFunc(func(interface{}) error {
+				_, err := w.Write([]byte(`{"a":[1,`)) // truncated
+				return err
+			})
+		},
+	})
+	err := enc.AddArray("arr", ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+		assert.Error(t, enc.AppendReflected(struct{}{}), "Expected error converting malformed JSON.")
+		enc.AppendString("after")
+		return nil
+	}))
+	require.NoError(t, err, "Unexpected error adding array.")
+
+	buf, err := enc.EncodeEntry(Entry{}, nil)
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+	assert.Equal(t, map[string]interface{}{
+		"arr": []interface{}{"after"},
+	}, decodeMsgpackEntry(t, buf.Bytes()), "Expected the partly converted value to be discarded.")
+}
+
+type reflectedEncoderFunc func(interface{}) error
+
+func (f reflectedEncoderFunc) Encode(v interface{}) error { return f(v) }
+
+func TestFluentForwardSyncerWriteError(t *testing.T) {
+	ws := NewFluentForwardSyncer(AddSync(ztest.FailWriter{}), "tag")
+	n, err := ws.Write([]byte{0x80})
+	assert.Error(t, err, "Expected write error to be propagated.")
+	assert.Equal(t, 0, n, "Expected no bytes written.")
+}
+
+func TestMsgpackEncoderMaxObjectDepth(t *testing.T) {
+	r := ObjectMarshalerFunc(nil)
+	r = func(enc ObjectEncoder) error {
+		enc.AddString("k", "v")
+		return enc.AddObject("child", r)
+	}
+
+	enc := NewMsgpackEncoder(EncoderConfig{MaxObjectDepth: 2})
+	buf, err := enc.EncodeEntry(Entry{}, []Field{{Key: "r", Type: ObjectMarshalerType, Interface: r}})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+
+	got, rest, err := decodeMsgpack(buf.Bytes())
+	require.NoError(t, err, "Failed to decode entry.")
+	assert.Empty(t, rest, "Unexpected trailing bytes.")
+	assert.Equal(t, map[string]interface{}{
+		"r": map[string]interface{}{
+			"k": "v",
+			"child": map[string]interface{}{
+				"k":     "v",
+				"child": map[string]interface{}{"...": "max depth exceeded"},
+			},
+		},
+	}, got, "Unexpected entry.")
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "sync"
+
+type prefixSyncer struct {
+	mu     sync.Mutex
+	ws     WriteSyncer
+	prefix func() []byte
+	buf    []byte // reused across writes; guarded by mu
+}
+
+// NewPrefixSyncer wraps a WriteSyncer so that each write is preceded by the
+// bytes returned by prefix, which is called once per write. The prefix may be
+// static or computed per write, such as a timestamp or hostname.
+//
+// The prefix and the written bytes are copied into a buffer that's reused
+// across writes, and handed to the wrapped WriteSyncer in a single Write, so
+// writes don't allocate once the buffer has grown to fit them. Writes are
+// serialized, so concurrent writes never pair a prefix with the wrong entry,
+// and the wrapped WriteSyncer needn't be safe for concurrent use.
+func NewPrefixSyncer(ws WriteSyncer, prefix func() []byte) WriteSyncer {
+	return &prefixSyncer{ws: ws, prefix: prefix}
+}
+
+func (s *prefixSyncer) Write(bs []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf = append(s.buf[:0], s.prefix()...)
+	prefixLen := len(s.buf)
+	s.buf = append(s.buf, bs...)
+	n, err := s.ws.Write(s.buf)
+
+	// Report how much of bs was written, excluding the prefix.
+	n -= prefixLen
+	if n < 0 {
+		n = 0
+	}
+	return n, err
+}
+
+func (s *prefixSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ws.Sync()
+}
+
+func (s *prefixSyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
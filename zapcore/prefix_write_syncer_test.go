@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/internal/ztest"
+)
+
+func TestPrefixSyncer(t *testing.T) {
+	t.Run("static", func(t *testing.T) {
+		buf := &ztest.Buffer{}
+		ws := NewPrefixSyncer(buf, func() []byte { return []byte("[app] ") })
+
+		n, err := ws.Write([]byte("foo\n"))
+		require.NoError(t, err, "Unexpected error writing.")
+		assert.Equal(t, 4, n, "Expected the prefix to be excluded from the byte count.")
+		_, err = ws.Write([]byte("bar\n"))
+		require.NoError(t, err, "Unexpected error writing.")
+
+		assert.Equal(t, []string{"[app] foo", "[app] bar"}, buf.Lines(), "Unexpected output.")
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		buf := &ztest.Buffer{}
+		i := 0
+		ws := NewPrefixSyncer(buf, func() []byte {
+			i++
+			return []byte(strconv.Itoa(i) + ": ")
+		})
+
+		for _, s := range []string{"a\n", "b\n", "c\n"} {
+			_, err := ws.Write([]byte(s))
+			require.NoError(t, err, "Unexpected error writing.")
+		}
+		assert.Equal(t, []string{"1: a", "2: b", "3: c"}, buf.Lines(), "Unexpected output.")
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		buf := &ztest.Buffer{}
+		ws := NewPrefixSyncer(buf, func() []byte { return nil })
+		requireWriteWorks(t, ws)
+		assert.Equal(t, "foo", buf.String(), "Unexpected output.")
+	})
+}
+
+func TestPrefixSyncerErrors(t *testing.T) {
+	prefix := func() []byte { return []byte("prefix ") }
+
+	n, err := NewPrefixSyncer(&ztest.FailWriter{}, prefix).Write([]byte("foo"))
+	assert.Error(t, err, "Expected write errors to propagate.")
+	assert.Equal(t, 3, n, "Unexpected byte count.")
+
+	n, err = NewPrefixSyncer(&ztest.ShortWriter{}, prefix).Write([]byte("foo"))
+	assert.NoError(t, err, "Unexpected error on short write.")
+	assert.Equal(t, 2, n, "Expected short writes to be reported.")
+
+	spy := &ztest.Discarder{}
+	spy.SetError(errors.New("sync failed"))
+	ws := NewPrefixSyncer(spy, prefix)
+	assert.EqualError(t, ws.Sync(), "sync failed", "Expected sync errors to propagate.")
+	assert.True(t, spy.Called(), "Expected Sync to be called.")
+}
+
+func TestPrefixSyncerConcurrent(t *testing.T) {
+	const (
+		goroutines = 8
+		writes     = 100
+	)
+
+	buf := &ztest.Buffer{}
+	var seq int // guarded by the syncer's lock
+	ws := NewPrefixSyncer(buf, func() []byte {
+		seq++
+		return []byte(strconv.Itoa(seq) + " ")
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			line := []byte("goroutine-" + strconv.Itoa(g) + "\n")
+			for i := 0; i < writes; i++ {
+				_, err := ws.Write(line)
+				assert.NoError(t, err, "Unexpected error writing.")
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	lines := buf.Lines()
+	require.Len(t, lines, goroutines*writes, "Unexpected number of lines.")
+	for i, line := range lines {
+		prefix, msg, ok := strings.Cut(line, " ")
+		require.True(t, ok, "Malformed line %q.", line)
+		assert.Equal(t, strconv.Itoa(i+1), prefix, "Expected prefixes in write order.")
+		assert.True(t, strings.HasPrefix(msg, "goroutine-"), "Prefix interleaved with another write in %q.", line)
+	}
+}
+
+func TestPrefixSyncerAllocs(t *testing.T) {
+	prefix := []byte("[app] ")
+	ws := NewPrefixSyncer(&ztest.Discarder{}, func() []byte { return prefix })
+	line := []byte("some log line\n")
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = ws.Write(line)
+	})
+	assert.Zero(t, allocs, "Expected writes not to allocate.")
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"bytes"
+	"errors"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+)
+
+var errPrefixedNotObject = errors.New("prefixed encoder: wrapped encoder didn't produce a JSON object")
+
+// NewPrefixedEncoder wraps an Encoder so that every entry includes a fixed
+// set of fields, such as the service name, version, and environment.
+// staticJSON holds those fields serialized as a JSON object (for example,
+// the output of json.Marshal on a map); it's processed once, and the cached
+// bytes are spliced into the output of each entry without re-encoding.
+// staticJSON isn't validated, so callers must ensure that it's well-formed.
+//
+// Encoders built by NewJSONEncoder and NewConsoleEncoder are handled
+// natively: the static fields are added to the encoder's context, and appear
+// alongside fields added with With. For the console encoder, this means they
+// appear in the trailing JSON context.
+//
+// Any other wrapped Encoder must produce a single JSON object per entry; the
+// static fields are inserted as the last members of that object. If the
+// wrapped Encoder's output doesn't end in a JSON object, EncodeEntry returns
+// an error.
+//
+// Note that the JSON and console encoders also serialize fields added with
+// With only once, so NewPrefixedEncoder doesn't outperform With for them
+// (see BenchmarkStaticFields). It's most useful when the static fields are
+// already available in serialized form, such as from configuration.
+func NewPrefixedEncoder(enc Encoder, staticJSON []byte) Encoder {
+	static := bytes.TrimSpace(staticJSON)
+	static = bytes.TrimPrefix(static, []byte{'{'})
+	static = bytes.TrimSuffix(static, []byte{'}'})
+	static = bytes.TrimSpace(static)
+	if len(static) == 0 {
+		return enc
+	}
+
+	switch e := enc.(type) {
+	case *jsonEncoder:
+		if e.openNamespaces == 0 {
+			return withStaticJSON(e, static)
+		}
+	case consoleEncoder:
+		if e.openNamespaces == 0 {
+			return consoleEncoder{withStaticJSON(e.jsonEncoder, static)}
+		}
+	}
+	return &prefixedEncoder{
+		Encoder: enc,
+		static:  append([]byte(nil), static...),
+	}
+}
+
+// withStaticJSON returns a copy of enc with the pre-serialized members
+// appended to its context.
+func withStaticJSON(enc *jsonEncoder, static []byte) *jsonEncoder {
+	clone := enc.Clone().(*jsonEncoder)
+	clone.addElementSeparator()
+	clone.buf.Write(static)
+	return clone
+}
+
+// prefixedEncoder splices pre-serialized JSON object members into the output
+// of an arbitrary Encoder.
+type prefixedEncoder struct {
+	Encoder
+
+	static []byte // JSON object members, without braces
+}
+
+func (e *prefixedEncoder) Clone() Encoder {
+	return &prefixedEncoder{
+		Encoder: e.Encoder.Clone(),
+		static:  e.static,
+	}
+}
+
+func (e *prefixedEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return buf, err
+	}
+
+	// Find the brace closing the top-level object, skipping the line ending.
+	bs := buf.Bytes()
+	end := len(bytes.TrimRight(bs, " \t\r\n")) - 1
+	if end < 0 || bs[end] != '}' {
+		buf.Free()
+		return nil, errPrefixedNotObject
+	}
+
+	out := bufferpool.Get()
+	out.Write(bs[:end])
+	if last := bytes.TrimRight(bs[:end], " \t\r\n"); len(last) > 0 && last[len(last)-1] != '{' {
+		out.AppendByte(',')
+	}
+	out.Write(e.static)
+	out.Write(bs[end:])
+	buf.Free()
+	return out, nil
+}
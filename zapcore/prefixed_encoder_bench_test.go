@@ -0,0 +1,76 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+func BenchmarkStaticFields(b *testing.B) {
+	staticFields := []Field{
+		{Key: "service", Type: StringType, String: "api"},
+		{Key: "version", Type: StringType, String: "1.2.3"},
+		{Key: "env", Type: StringType, String: "prod"},
+	}
+	entry := Entry{Message: "hello"}
+	fields := []Field{makeInt64Field("n", 1)}
+
+	encoders := []struct {
+		name string
+		new  func() Encoder
+	}{
+		{"JSON", func() Encoder { return NewJSONEncoder(testEncoderConfig()) }},
+		{"ECS", func() Encoder { return NewECSEncoder(testEncoderConfig()) }},
+	}
+
+	for _, ee := range encoders {
+		b.Run(ee.name, func(b *testing.B) {
+			b.Run("With", func(b *testing.B) {
+				enc := ee.new()
+				for _, f := range staticFields {
+					f.AddTo(enc)
+				}
+				runEncodeEntry(b, enc, entry, fields)
+			})
+			b.Run("Prefixed", func(b *testing.B) {
+				enc := NewPrefixedEncoder(ee.new(), _staticJSON)
+				runEncodeEntry(b, enc, entry, fields)
+			})
+		})
+	}
+}
+
+func runEncodeEntry(b *testing.B, enc Encoder, entry Entry, fields []Field) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf, err := enc.EncodeEntry(entry, fields)
+			if err != nil {
+				b.Fatal(err)
+			}
+			buf.Free()
+		}
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/zap/buffer"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+var _staticJSON = []byte(`{"service":"api","version":"1.2.3","env":"prod"}`)
+
+// plainEncoder is an Encoder whose output isn't a JSON object.
+type plainEncoder struct{ *MapObjectEncoder }
+
+func (e plainEncoder) Clone() Encoder { return plainEncoder{NewMapObjectEncoder()} }
+
+func (plainEncoder) EncodeEntry(ent Entry, _ []Field) (*buffer.Buffer, error) {
+	buf := buffer.NewPool().Get()
+	buf.AppendString(ent.Message)
+	buf.AppendString("\n")
+	return buf, nil
+}
+
+func TestPrefixedEncoder(t *testing.T) {
+	fields := []Field{makeInt64Field("n", 1)}
+	entry := Entry{Message: "hello"}
+	withNamespace := func(enc Encoder) Encoder {
+		enc.OpenNamespace("ns")
+		return enc
+	}
+
+	tests := []struct {
+		desc string
+		enc  Encoder
+		want string
+	}{
+		{
+			desc: "json",
+			enc:  NewJSONEncoder(testEncoderConfig()),
+			want: `{"level":"info","msg":"hello","service":"api","version":"1.2.3","env":"prod","n":1}` + "\n",
+		},
+		{
+			desc: "console",
+			enc:  NewConsoleEncoder(testEncoderConfig()),
+			// Static fields are spliced verbatim, so they aren't spaced.
+			want: "info\thello\t" + `{"service":"api","version":"1.2.3","env":"prod", "n": 1}` + "\n",
+		},
+		{
+			desc: "json with open namespace",
+			enc:  withNamespace(NewJSONEncoder(testEncoderConfig())),
+			want: `{"level":"info","msg":"hello","ns":{"n":1},"service":"api","version":"1.2.3","env":"prod"}` + "\n",
+		},
+		{
+			desc: "ecs",
+			enc:  NewECSEncoder(EncoderConfig{MessageKey: "message", LineEnding: "\n"}),
+			want: `{"log":{},"message":"hello","ecs":{"version":"` + ECSVersion + `"},"n":1,"service":"api","version":"1.2.3","env":"prod"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			enc := NewPrefixedEncoder(tt.enc, _staticJSON)
+			for i := 0; i < 2; i++ {
+				buf, err := enc.EncodeEntry(entry, fields)
+				require.NoError(t, err, "Unexpected encoding error.")
+				assert.Equal(t, tt.want, buf.String(), "Unexpected output.")
+				buf.Free()
+			}
+
+			buf, err := enc.Clone().EncodeEntry(entry, fields)
+			require.NoError(t, err, "Unexpected encoding error from clone.")
+			assert.Equal(t, tt.want, buf.String(), "Unexpected output from clone.")
+			buf.Free()
+		})
+	}
+}
+
+func TestPrefixedEncoderEmptyObject(t *testing.T) {
+	enc := NewPrefixedEncoder(NewECSEncoder(EncoderConfig{}), _staticJSON)
+	buf, err := enc.EncodeEntry(Entry{}, nil)
+	require.NoError(t, err, "Unexpected encoding error.")
+	assert.Equal(t, `{"log":{},"ecs":{"version":"`+ECSVersion+`"},"service":"api","version":"1.2.3","env":"prod"}`+"\n", buf.String())
+
+	base := NewJSONEncoder(testEncoderConfig())
+	assert.Equal(t, base, NewPrefixedEncoder(base, []byte(` {} `)), "Expected empty static fields to be a no-op.")
+}
+
+func TestPrefixedEncoderNotObject(t *testing.T) {
+	enc := NewPrefixedEncoder(plainEncoder{NewMapObjectEncoder()}, _staticJSON)
+	_, err := enc.EncodeEntry(Entry{Message: "hello"}, nil)
+	assert.Error(t, err, "Expected error when the wrapped output isn't a JSON object.")
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+)
+
+type prettyJSONEncoder struct {
+	*jsonEncoder
+
+	indent     string
+	lineEnding string
+}
+
+// NewPrettyJSONEncoder creates an Encoder that writes each entry as JSON
+// spread over several lines, with nested objects and arrays indented by
+// indent, for reading logs locally. It's configured like the encoder
+// returned by NewJSONEncoder and writes the same keys and values. Each entry
+// is a single JSON object followed by the configured line ending, so the
+// output can still be parsed one object at a time, for example with a
+// json.Decoder.
+//
+// Indenting costs an extra pass over every entry, so production logs should
+// use NewJSONEncoder instead.
+func NewPrettyJSONEncoder(cfg EncoderConfig, indent string) Encoder {
+	lineEnding := cfg.LineEnding
+	if cfg.SkipLineEnding {
+		lineEnding = ""
+	} else if lineEnding == "" {
+		lineEnding = DefaultLineEnding
+	}
+	cfg.SkipLineEnding = true
+	return &prettyJSONEncoder{
+		jsonEncoder: newJSONEncoder(cfg, false),
+		indent:      indent,
+		lineEnding:  lineEnding,
+	}
+}
+
+func (enc *prettyJSONEncoder) Clone() Encoder {
+	return &prettyJSONEncoder{
+		jsonEncoder: enc.jsonEncoder.Clone().(*jsonEncoder),
+		indent:      enc.indent,
+		lineEnding:  enc.lineEnding,
+	}
+}
+
+func (enc *prettyJSONEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	compact, err := enc.jsonEncoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer compact.Free()
+
+	var indented bytes.Buffer
+	out := bufferpool.Get()
+	if json.Indent(&indented, compact.Bytes(), "", enc.indent) == nil {
+		_, _ = out.Write(indented.Bytes())
+	} else {
+		// Fields such as zap.Reflect with a custom ReflectedEncoder can
+		// produce output that isn't JSON; write it as-is rather than drop
+		// the entry.
+		_, _ = out.Write(compact.Bytes())
+	}
+	out.AppendString(enc.lineEnding)
+	return out, nil
+}
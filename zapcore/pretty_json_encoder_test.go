@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+func prettyTestEncoderConfig() EncoderConfig {
+	return EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		TimeKey:        "ts",
+		EncodeLevel:    LowercaseLevelEncoder,
+		EncodeTime:     EpochTimeEncoder,
+		EncodeDuration: StringDurationEncoder,
+	}
+}
+
+func TestPrettyJSONEncodeEntry(t *testing.T) {
+	enc := NewPrettyJSONEncoder(prettyTestEncoderConfig(), "  ")
+	enc.AddString("service", "api")
+
+	buf, err := enc.EncodeEntry(Entry{
+		Level:   InfoLevel,
+		Time:    time.Unix(0, 0),
+		Message: "hello",
+	}, []Field{
+		{Key: "count", Type: Int64Type, Integer: 3},
+		{Key: "nested", Type: ObjectMarshalerType, Interface: ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+			enc.AddBool("ok", true)
+			return nil
+		})},
+	})
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	defer buf.Free()
+
+	assert.Equal(t, `{
+  "level": "info",
+  "ts": 0,
+  "msg": "hello",
+  "service": "api",
+  "count": 3,
+  "nested": {
+    "ok": true
+  }
+}
+`, buf.String(), "Unexpected output.")
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "Expected output to be valid JSON.")
+	assert.Equal(t, map[string]interface{}{
+		"level":   "info",
+		"ts":      float64(0),
+		"msg":     "hello",
+		"service": "api",
+		"count":   float64(3),
+		"nested":  map[string]interface{}{"ok": true},
+	}, decoded, "Unexpected decoded entry.")
+}
+
+func TestPrettyJSONEncoderStream(t *testing.T) {
+	var out bytes.Buffer
+	core := NewCore(NewPrettyJSONEncoder(prettyTestEncoderConfig(), "\t"), AddSync(&out), DebugLevel)
+	core = core.With([]Field{{Key: "k", Type: StringType, String: "v"}})
+	for _, msg := range []string{"first", "second", "third"} {
+		require.NoError(t, core.Write(Entry{Message: msg}, nil), "Unexpected error writing.")
+	}
+
+	assert.Contains(t, out.String(), "\n\t\"msg\": \"first\",\n", "Expected tab-indented output.")
+
+	dec := json.NewDecoder(&out)
+	var msgs []string
+	for {
+		var entry map[string]interface{}
+		err := dec.Decode(&entry)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err, "Expected each entry to be a JSON object.")
+		assert.Equal(t, "v", entry["k"], "Expected context in every entry.")
+		msgs = append(msgs, entry["msg"].(string))
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, msgs, "Unexpected entries.")
+}
+
+func TestPrettyJSONEncoderLineEnding(t *testing.T) {
+	cfg := EncoderConfig{MessageKey: "msg", LineEnding: "\r\n"}
+	buf, err := NewPrettyJSONEncoder(cfg, " ").EncodeEntry(Entry{Message: "m"}, nil)
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	assert.Equal(t, "{\n \"msg\": \"m\"\n}\r\n", buf.String(), "Unexpected output.")
+	buf.Free()
+
+	cfg.SkipLineEnding = true
+	buf, err = NewPrettyJSONEncoder(cfg, " ").EncodeEntry(Entry{Message: "m"}, nil)
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	assert.Equal(t, "{\n \"msg\": \"m\"\n}", buf.String(), "Unexpected output.")
+	buf.Free()
+}
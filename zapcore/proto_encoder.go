@@ -0,0 +1,641 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+)
+
+// Protocol Buffers wire types.
+const (
+	_protoVarint  = 0
+	_protoFixed64 = 1
+	_protoBytes   = 2
+)
+
+// Field numbers of the Value, Struct, and ListValue messages in entry.proto.
+// Fields 1 through 6 match google.protobuf.Value.
+const (
+	_protoValueNull   = 1
+	_protoValueNumber = 2
+	_protoValueString = 3
+	_protoValueBool   = 4
+	_protoValueStruct = 5
+	_protoValueList   = 6
+	_protoValueInt    = 7
+	_protoValueUint   = 8
+	_protoValueBytes  = 9
+
+	_protoStructFields = 1
+	_protoListValues   = 1
+	_protoMapKey       = 1
+	_protoMapValue     = 2
+)
+
+// ProtoDescriptor describes the Protocol Buffers message that NewProtoEncoder
+// writes for each entry by assigning a field number to each part of the
+// entry. A zero field number omits that part.
+//
+// The message must declare its fields with the following types:
+//
+//	string              Message, Level, Logger, Caller, Function, Stacktrace
+//	Timestamp           Time (wire-compatible with google.protobuf.Timestamp)
+//	map<string, Value>  Fields
+//
+// Value is the message defined in entry.proto, alongside the Entry envelope
+// described by DefaultProtoDescriptor.
+type ProtoDescriptor struct {
+	Message    int
+	Level      int
+	Time       int
+	Logger     int
+	Caller     int
+	Function   int
+	Stacktrace int
+	Fields     int
+}
+
+// DefaultProtoDescriptor describes the Entry message in entry.proto.
+var DefaultProtoDescriptor = ProtoDescriptor{
+	Message:    1,
+	Level:      2,
+	Time:       3,
+	Logger:     4,
+	Caller:     5,
+	Function:   6,
+	Stacktrace: 7,
+	Fields:     8,
+}
+
+// protoFrame collects the members of a Struct or ListValue. Since Protocol
+// Buffers prefixes embedded messages with their length, a frame's contents
+// can only be written out once it's complete.
+type protoFrame struct {
+	buf   *buffer.Buffer
+	field int    // field number of the frame's map entries or list values
+	list  bool   // whether this frame is a ListValue rather than a map
+	key   string // key of the next map entry
+}
+
+type protoEncoder struct {
+	desc *ProtoDescriptor
+
+	// frames[0] holds the entry's fields; the last frame is the innermost
+	// open namespace, object, or array.
+	frames []protoFrame
+
+	// scratch holds a single encoded Value before it's added to a frame.
+	scratch *buffer.Buffer
+
+	// for encoding generic values by reflection
+	reflectBuf *buffer.Buffer
+	reflectEnc ReflectedEncoder
+}
+
+// NewProtoEncoder creates an encoder that writes each entry as a Protocol
+// Buffers message described by desc, for consumers that speak Protocol
+// Buffers natively. Each message is prefixed with its length as a varint, so
+// a stream of entries can be read back with the usual delimited readers (for
+// example, Java's parseDelimitedFrom).
+//
+// Context and fields are written as map<string, Value>, where Value mirrors
+// google.protobuf.Value. To avoid losing precision, Value also has int_value,
+// uint_value, and bytes_value members, which are used for integers and
+// binary fields. Durations are written as integer nanoseconds, times as
+// RFC 3339 strings, complex numbers as strings (for example, "1+2i"), and
+// reflected values are serialized as JSON and converted to the equivalent
+// Value. See entry.proto for the message definitions.
+func NewProtoEncoder(desc ProtoDescriptor) Encoder {
+	return &protoEncoder{
+		desc:   &desc,
+		frames: []protoFrame{{buf: bufferpool.Get(), field: desc.Fields}},
+	}
+}
+
+func (enc *protoEncoder) cur() *protoFrame {
+	return &enc.frames[len(enc.frames)-1]
+}
+
+func (enc *protoEncoder) addKey(key string) {
+	enc.cur().key = key
+}
+
+// addValue adds the Value in scratch to the current frame, either as a list
+// element or as a map entry under the pending key.
+func (enc *protoEncoder) addValue() {
+	f := enc.cur()
+	val := enc.scratch.Bytes()
+	if f.list {
+		appendProtoBytes(f.buf, f.field, val)
+		return
+	}
+	appendProtoTag(f.buf, f.field, _protoBytes)
+	appendProtoVarint(f.buf, uint64(protoBytesLen(_protoMapKey, len(f.key))+protoBytesLen(_protoMapValue, len(val))))
+	appendProtoStringField(f.buf, _protoMapKey, f.key)
+	appendProtoBytes(f.buf, _protoMapValue, val)
+}
+
+// resetScratch prepares scratch for a new Value.
+func (enc *protoEncoder) resetScratch() *buffer.Buffer {
+	if enc.scratch == nil {
+		enc.scratch = bufferpool.Get()
+	} else {
+		enc.scratch.Reset()
+	}
+	return enc.scratch
+}
+
+// pushFrame starts a nested Struct or ListValue.
+func (enc *protoEncoder) pushFrame(list bool) {
+	field := _protoStructFields
+	if list {
+		field = _protoListValues
+	}
+	enc.frames = append(enc.frames, protoFrame{
+		buf:   bufferpool.Get(),
+		field: field,
+		list:  list,
+	})
+}
+
+// popFrames closes frames until only depth remain, adding each to its parent
+// as a Value.
+func (enc *protoEncoder) popFrames(depth int) {
+	for len(enc.frames) > depth {
+		f := enc.frames[len(enc.frames)-1]
+		enc.frames = enc.frames[:len(enc.frames)-1]
+		kind := _protoValueStruct
+		if f.list {
+			kind = _protoValueList
+		}
+		appendProtoBytes(enc.resetScratch(), kind, f.buf.Bytes())
+		enc.addValue()
+		f.buf.Free()
+	}
+}
+
+func (enc *protoEncoder) AddArray(key string, arr ArrayMarshaler) error {
+	enc.addKey(key)
+	return enc.AppendArray(arr)
+}
+
+func (enc *protoEncoder) AddObject(key string, obj ObjectMarshaler) error {
+	enc.addKey(key)
+	return enc.AppendObject(obj)
+}
+
+func (enc *protoEncoder) AddBinary(key string, val []byte) {
+	enc.addKey(key)
+	appendProtoBytes(enc.resetScratch(), _protoValueBytes, val)
+	enc.addValue()
+}
+
+func (enc *protoEncoder) AddByteString(key string, val []byte) {
+	enc.addKey(key)
+	enc.AppendByteString(val)
+}
+
+func (enc *protoEncoder) AddBool(key string, val bool) {
+	enc.addKey(key)
+	enc.AppendBool(val)
+}
+
+func (enc *protoEncoder) AddComplex128(key string, val complex128) {
+	enc.addKey(key)
+	enc.AppendComplex128(val)
+}
+
+func (enc *protoEncoder) AddComplex64(key string, val complex64) {
+	enc.addKey(key)
+	enc.AppendComplex64(val)
+}
+
+func (enc *protoEncoder) AddDuration(key string, val time.Duration) {
+	enc.addKey(key)
+	enc.AppendDuration(val)
+}
+
+func (enc *protoEncoder) AddFloat64(key string, val float64) {
+	enc.addKey(key)
+	enc.AppendFloat64(val)
+}
+
+func (enc *protoEncoder) AddFloat32(key string, val float32) {
+	enc.addKey(key)
+	enc.AppendFloat32(val)
+}
+
+func (enc *protoEncoder) AddInt64(key string, val int64) {
+	enc.addKey(key)
+	enc.AppendInt64(val)
+}
+
+func (enc *protoEncoder) AddReflected(key string, obj interface{}) error {
+	enc.addKey(key)
+	return enc.AppendReflected(obj)
+}
+
+func (enc *protoEncoder) OpenNamespace(key string) {
+	enc.addKey(key)
+	enc.pushFrame(false /* list */)
+}
+
+func (enc *protoEncoder) AddString(key, val string) {
+	enc.addKey(key)
+	enc.AppendString(val)
+}
+
+func (enc *protoEncoder) AddTime(key string, val time.Time) {
+	enc.addKey(key)
+	enc.AppendTime(val)
+}
+
+func (enc *protoEncoder) AddUint64(key string, val uint64) {
+	enc.addKey(key)
+	enc.AppendUint64(val)
+}
+
+func (enc *protoEncoder) AppendArray(arr ArrayMarshaler) error {
+	depth := len(enc.frames)
+	enc.pushFrame(true /* list */)
+	err := arr.MarshalLogArray(enc)
+	enc.popFrames(depth)
+	return err
+}
+
+func (enc *protoEncoder) AppendObject(obj ObjectMarshaler) error {
+	// Namespaces opened by the marshaler are closed along with the object.
+	depth := len(enc.frames)
+	enc.pushFrame(false /* list */)
+	err := obj.MarshalLogObject(enc)
+	enc.popFrames(depth)
+	return err
+}
+
+func (enc *protoEncoder) AppendBool(val bool) {
+	var v uint64
+	if val {
+		v = 1
+	}
+	appendProtoVarintField(enc.resetScratch(), _protoValueBool, v)
+	enc.addValue()
+}
+
+func (enc *protoEncoder) AppendByteString(val []byte) {
+	appendProtoBytes(enc.resetScratch(), _protoValueString, val)
+	enc.addValue()
+}
+
+// appendComplex appends the string form of the provided complex128 value,
+// matching the JSON encoder. precision specifies the encoding precision for
+// the real and imaginary components of the complex number.
+func (enc *protoEncoder) appendComplex(val complex128, precision int) {
+	r, i := float64(real(val)), float64(imag(val))
+	str := bufferpool.Get()
+	str.AppendFloat(r, precision)
+	if i >= 0 {
+		str.AppendByte('+')
+	}
+	str.AppendFloat(i, precision)
+	str.AppendByte('i')
+	enc.AppendByteString(str.Bytes())
+	str.Free()
+}
+
+func (enc *protoEncoder) AppendDuration(val time.Duration) {
+	enc.AppendInt64(int64(val))
+}
+
+func (enc *protoEncoder) AppendInt64(val int64) {
+	appendProtoVarintField(enc.resetScratch(), _protoValueInt, uint64(val))
+	enc.addValue()
+}
+
+func (enc *protoEncoder) AppendReflected(val interface{}) error {
+	if val == nil {
+		appendProtoVarintField(enc.resetScratch(), _protoValueNull, 0)
+		enc.addValue()
+		return nil
+	}
+
+	enc.resetReflectBuf()
+	if err := enc.reflectEnc.Encode(val); err != nil {
+		return err
+	}
+	enc.reflectBuf.TrimNewline()
+
+	// Convert into a scratch frame so that a conversion error doesn't leave a
+	// partial value behind.
+	depth := len(enc.frames)
+	enc.pushFrame(true /* list */)
+	if err := enc.appendJSON(enc.reflectBuf.Bytes()); err != nil {
+		enc.frames[depth].buf.Free()
+		enc.frames = enc.frames[:depth]
+		return err
+	}
+	f := enc.frames[depth]
+	enc.frames = enc.frames[:depth]
+
+	// The scratch frame holds a single list element; unwrap it.
+	elem, err := readProtoBytesField(f.buf.Bytes())
+	if err == nil {
+		enc.resetScratch().Write(elem)
+		enc.addValue()
+	}
+	f.buf.Free()
+	return err
+}
+
+func (enc *protoEncoder) AppendString(val string) {
+	appendProtoStringField(enc.resetScratch(), _protoValueString, val)
+	enc.addValue()
+}
+
+func (enc *protoEncoder) AppendTimeLayout(time time.Time, layout string) {
+	enc.AppendString(time.Format(layout))
+}
+
+func (enc *protoEncoder) AppendTime(val time.Time) {
+	enc.AppendTimeLayout(val, time.RFC3339Nano)
+}
+
+func (enc *protoEncoder) AppendUint64(val uint64) {
+	appendProtoVarintField(enc.resetScratch(), _protoValueUint, val)
+	enc.addValue()
+}
+
+func (enc *protoEncoder) AppendFloat64(val float64) {
+	s := enc.resetScratch()
+	appendProtoTag(s, _protoValueNumber, _protoFixed64)
+	appendLittleEndian(s, math.Float64bits(val), 8)
+	enc.addValue()
+}
+
+func (enc *protoEncoder) AppendFloat32(val float32) {
+	enc.AppendFloat64(float64(val))
+}
+
+func (enc *protoEncoder) AddInt(k string, v int)         { enc.AddInt64(k, int64(v)) }
+func (enc *protoEncoder) AddInt32(k string, v int32)     { enc.AddInt64(k, int64(v)) }
+func (enc *protoEncoder) AddInt16(k string, v int16)     { enc.AddInt64(k, int64(v)) }
+func (enc *protoEncoder) AddInt8(k string, v int8)       { enc.AddInt64(k, int64(v)) }
+func (enc *protoEncoder) AddUint(k string, v uint)       { enc.AddUint64(k, uint64(v)) }
+func (enc *protoEncoder) AddUint32(k string, v uint32)   { enc.AddUint64(k, uint64(v)) }
+func (enc *protoEncoder) AddUint16(k string, v uint16)   { enc.AddUint64(k, uint64(v)) }
+func (enc *protoEncoder) AddUint8(k string, v uint8)     { enc.AddUint64(k, uint64(v)) }
+func (enc *protoEncoder) AddUintptr(k string, v uintptr) { enc.AddUint64(k, uint64(v)) }
+func (enc *protoEncoder) AppendComplex64(v complex64)    { enc.appendComplex(complex128(v), 32) }
+func (enc *protoEncoder) AppendComplex128(v complex128)  { enc.appendComplex(complex128(v), 64) }
+func (enc *protoEncoder) AppendInt(v int)                { enc.AppendInt64(int64(v)) }
+func (enc *protoEncoder) AppendInt32(v int32)            { enc.AppendInt64(int64(v)) }
+func (enc *protoEncoder) AppendInt16(v int16)            { enc.AppendInt64(int64(v)) }
+func (enc *protoEncoder) AppendInt8(v int8)              { enc.AppendInt64(int64(v)) }
+func (enc *protoEncoder) AppendUint(v uint)              { enc.AppendUint64(uint64(v)) }
+func (enc *protoEncoder) AppendUint32(v uint32)          { enc.AppendUint64(uint64(v)) }
+func (enc *protoEncoder) AppendUint16(v uint16)          { enc.AppendUint64(uint64(v)) }
+func (enc *protoEncoder) AppendUint8(v uint8)            { enc.AppendUint64(uint64(v)) }
+func (enc *protoEncoder) AppendUintptr(v uintptr)        { enc.AppendUint64(uint64(v)) }
+
+func (enc *protoEncoder) Clone() Encoder {
+	clone := &protoEncoder{
+		desc:   enc.desc,
+		frames: make([]protoFrame, len(enc.frames)),
+	}
+	for i, f := range enc.frames {
+		f.buf = bufferpool.Get()
+		f.buf.Write(enc.frames[i].buf.Bytes())
+		clone.frames[i] = f
+	}
+	return clone
+}
+
+func (enc *protoEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	desc := enc.desc
+	msg := bufferpool.Get()
+	defer msg.Free()
+
+	appendProtoString(msg, desc.Message, ent.Message)
+	appendProtoString(msg, desc.Level, ent.Level.String())
+	if desc.Time != 0 && !ent.Time.IsZero() {
+		ts := bufferpool.Get()
+		appendProtoVarintField(ts, 1, uint64(ent.Time.Unix()))
+		appendProtoVarintField(ts, 2, uint64(ent.Time.Nanosecond()))
+		appendProtoBytes(msg, desc.Time, ts.Bytes())
+		ts.Free()
+	}
+	appendProtoString(msg, desc.Logger, ent.LoggerName)
+	if ent.Caller.Defined {
+		appendProtoString(msg, desc.Caller, ent.Caller.String())
+		appendProtoString(msg, desc.Function, ent.Caller.Function)
+	}
+	appendProtoString(msg, desc.Stacktrace, ent.Stack)
+
+	if desc.Fields != 0 {
+		final := &protoEncoder{
+			desc:   desc,
+			frames: make([]protoFrame, len(enc.frames)),
+		}
+		// Copy the accumulated context, including any open namespaces.
+		for i, f := range enc.frames {
+			f.buf = bufferpool.Get()
+			f.buf.Write(enc.frames[i].buf.Bytes())
+			final.frames[i] = f
+		}
+		addFields(final, fields)
+		final.popFrames(1)
+		msg.Write(final.frames[0].buf.Bytes())
+		final.frames[0].buf.Free()
+		if final.scratch != nil {
+			final.scratch.Free()
+		}
+		if final.reflectBuf != nil {
+			final.reflectBuf.Free()
+		}
+	}
+
+	out := bufferpool.Get()
+	appendProtoVarint(out, uint64(msg.Len()))
+	out.Write(msg.Bytes())
+	return out, nil
+}
+
+func (enc *protoEncoder) resetReflectBuf() {
+	if enc.reflectBuf == nil {
+		enc.reflectBuf = bufferpool.Get()
+		enc.reflectEnc = defaultReflectedEncoder(enc.reflectBuf)
+	} else {
+		enc.reflectBuf.Reset()
+	}
+}
+
+// appendJSON converts a single JSON value to a Value, preserving the order of
+// object members.
+func (enc *protoEncoder) appendJSON(bs []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	if err := enc.appendJSONValue(dec); err != nil {
+		return fmt.Errorf("proto encoder: can't convert reflected value: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("proto encoder: reflected encoder produced more than one value")
+	}
+	return nil
+}
+
+func (enc *protoEncoder) appendJSONValue(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		depth := len(enc.frames)
+		enc.pushFrame(v == '[')
+		for dec.More() {
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				enc.addKey(key.(string))
+			}
+			if err := enc.appendJSONValue(dec); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return err
+		}
+		enc.popFrames(depth)
+	case bool:
+		enc.AppendBool(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			enc.AppendInt64(i)
+		} else if f, err := v.Float64(); err == nil {
+			enc.AppendFloat64(f)
+		} else {
+			enc.AppendString(v.String())
+		}
+	case string:
+		enc.AppendString(v)
+	case nil:
+		appendProtoVarintField(enc.resetScratch(), _protoValueNull, 0)
+		enc.addValue()
+	}
+	return nil
+}
+
+// readProtoBytesField returns the payload of the single length-delimited
+// field in bs.
+func readProtoBytesField(bs []byte) ([]byte, error) {
+	_, n := readProtoVarint(bs) // tag
+	if n <= 0 {
+		return nil, errors.New("proto encoder: malformed field")
+	}
+	size, m := readProtoVarint(bs[n:])
+	if m <= 0 || uint64(len(bs)-n-m) < size {
+		return nil, errors.New("proto encoder: malformed field")
+	}
+	return bs[n+m : n+m+int(size)], nil
+}
+
+// readProtoVarint decodes a varint from the start of bs, returning it and the
+// number of bytes read, or zero bytes if bs doesn't start with a varint.
+func readProtoVarint(bs []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(bs) && i < 10; i++ {
+		v |= uint64(bs[i]&0x7f) << (7 * i)
+		if bs[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func appendProtoVarint(buf *buffer.Buffer, v uint64) {
+	for v >= 0x80 {
+		buf.AppendByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	buf.AppendByte(byte(v))
+}
+
+func protoVarintLen(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+func appendProtoTag(buf *buffer.Buffer, field, wireType int) {
+	appendProtoVarint(buf, uint64(field)<<3|uint64(wireType))
+}
+
+func appendProtoVarintField(buf *buffer.Buffer, field int, v uint64) {
+	appendProtoTag(buf, field, _protoVarint)
+	appendProtoVarint(buf, v)
+}
+
+func appendProtoBytes(buf *buffer.Buffer, field int, bs []byte) {
+	appendProtoTag(buf, field, _protoBytes)
+	appendProtoVarint(buf, uint64(len(bs)))
+	buf.Write(bs)
+}
+
+// appendProtoString appends a string field, omitting empty strings and zero
+// field numbers.
+func appendProtoString(buf *buffer.Buffer, field int, s string) {
+	if field == 0 || s == "" {
+		return
+	}
+	appendProtoStringField(buf, field, s)
+}
+
+func appendProtoStringField(buf *buffer.Buffer, field int, s string) {
+	appendProtoTag(buf, field, _protoBytes)
+	appendProtoVarint(buf, uint64(len(s)))
+	buf.AppendString(s)
+}
+
+// protoBytesLen returns the encoded size of a length-delimited field with an
+// n-byte payload.
+func protoBytesLen(field, n int) int {
+	return protoVarintLen(uint64(field)<<3|_protoBytes) + protoVarintLen(uint64(n)) + n
+}
+
+// appendLittleEndian appends the low size bytes of v in little-endian order.
+func appendLittleEndian(buf *buffer.Buffer, v uint64, size int) {
+	for i := 0; i < size; i++ {
+		buf.AppendByte(byte(v >> (8 * i)))
+	}
+}
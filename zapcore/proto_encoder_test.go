@@ -0,0 +1,384 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+// protoField is a single decoded Protocol Buffers field.
+type protoField struct {
+	Num    int
+	Varint uint64
+	Bytes  []byte
+}
+
+// decodeProtoFields decodes the fields of a single message.
+func decodeProtoFields(bs []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(bs) > 0 {
+		tag, n := binary.Uvarint(bs)
+		if n <= 0 {
+			return nil, errors.New("malformed tag")
+		}
+		bs = bs[n:]
+		f := protoField{Num: int(tag >> 3)}
+		switch tag & 7 {
+		case 0:
+			f.Varint, n = binary.Uvarint(bs)
+			if n <= 0 {
+				return nil, errors.New("malformed varint")
+			}
+			bs = bs[n:]
+		case 1:
+			if len(bs) < 8 {
+				return nil, errors.New("unexpected end of input")
+			}
+			f.Varint, bs = binary.LittleEndian.Uint64(bs), bs[8:]
+		case 2:
+			size, n := binary.Uvarint(bs)
+			if n <= 0 || uint64(len(bs)-n) < size {
+				return nil, errors.New("malformed length-delimited field")
+			}
+			f.Bytes, bs = bs[n:n+int(size)], bs[n+int(size):]
+		default:
+			return nil, fmt.Errorf("unexpected wire type %d", tag&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// decodeProtoValue decodes a Value message into the equivalent Go value.
+func decodeProtoValue(bs []byte) (interface{}, error) {
+	fields, err := decodeProtoFields(bs)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single kind, got %d fields", len(fields))
+	}
+	f := fields[0]
+	switch f.Num {
+	case 1:
+		return nil, nil
+	case 2:
+		return math.Float64frombits(f.Varint), nil
+	case 3:
+		return string(f.Bytes), nil
+	case 4:
+		return f.Varint == 1, nil
+	case 5:
+		return decodeProtoMap(f.Bytes, 1)
+	case 6:
+		elems, err := decodeProtoFields(f.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, len(elems))
+		for i, e := range elems {
+			if list[i], err = decodeProtoValue(e.Bytes); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case 7:
+		return int64(f.Varint), nil
+	case 8:
+		return f.Varint, nil
+	case 9:
+		return append([]byte{}, f.Bytes...), nil
+	}
+	return nil, fmt.Errorf("unexpected Value field %d", f.Num)
+}
+
+// decodeProtoMap decodes the map<string, Value> with the given field number.
+func decodeProtoMap(bs []byte, num int) (map[string]interface{}, error) {
+	fields, err := decodeProtoFields(bs)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	for _, f := range fields {
+		if f.Num != num {
+			continue
+		}
+		entry, err := decodeProtoFields(f.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		var val interface{}
+		for _, e := range entry {
+			switch e.Num {
+			case 1:
+				key = string(e.Bytes)
+			case 2:
+				if val, err = decodeProtoValue(e.Bytes); err != nil {
+					return nil, err
+				}
+			}
+		}
+		m[key] = val
+	}
+	return m, nil
+}
+
+// decodeProtoEntry decodes a message written with DefaultProtoDescriptor.
+func decodeProtoEntry(bs []byte) (map[string]interface{}, error) {
+	fields, err := decodeProtoFields(bs)
+	if err != nil {
+		return nil, err
+	}
+	names := map[int]string{1: "message", 2: "level", 4: "logger", 5: "caller", 6: "function", 7: "stacktrace"}
+	entry := make(map[string]interface{})
+	for _, f := range fields {
+		if name, ok := names[f.Num]; ok {
+			entry[name] = string(f.Bytes)
+			continue
+		}
+		if f.Num == 3 {
+			ts, err := decodeProtoFields(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			var sec, nsec int64
+			for _, t := range ts {
+				if t.Num == 1 {
+					sec = int64(t.Varint)
+				} else if t.Num == 2 {
+					nsec = int64(t.Varint)
+				}
+			}
+			entry["time"] = time.Unix(sec, nsec).UTC()
+		}
+	}
+	if entry["fields"], err = decodeProtoMap(bs, 8); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// readProtoStream splits a stream of length-prefixed messages.
+func readProtoStream(bs []byte) ([][]byte, error) {
+	var msgs [][]byte
+	for len(bs) > 0 {
+		size, n := binary.Uvarint(bs)
+		if n <= 0 || uint64(len(bs)-n) < size {
+			return nil, errors.New("malformed length prefix")
+		}
+		msgs = append(msgs, bs[n:n+int(size)])
+		bs = bs[n+int(size):]
+	}
+	return msgs, nil
+}
+
+type protoTestUser struct {
+	Name  string `json:"name"`
+	Roles []string
+	Age   int `json:"age"`
+}
+
+func TestProtoEncoderRoundTrip(t *testing.T) {
+	enc := NewProtoEncoder(DefaultProtoDescriptor)
+	enc.AddString("service", "api")
+	enc.OpenNamespace("request")
+	enc.AddInt("attempt", 2)
+
+	var stream bytes.Buffer
+	entries := []Entry{
+		{
+			Level:      WarnLevel,
+			Time:       time.Unix(1700000000, 123456789).UTC(),
+			LoggerName: "db",
+			Message:    "slow query",
+			Caller:     EntryCaller{Defined: true, File: "db/query.go", Line: 42, Function: "db.Query"},
+			Stack:      "stack",
+		},
+		{Level: InfoLevel, Message: "done"},
+	}
+	fieldSets := [][]Field{
+		{
+			{Key: "took", Type: DurationType, Integer: int64(3 * time.Second)},
+			{Key: "rows", Type: Int64Type, Integer: -7},
+			{Key: "size", Type: Uint64Type, Integer: math.MaxInt64},
+			{Key: "ratio", Type: Float64Type, Integer: int64(math.Float64bits(0.5))},
+			{Key: "ok", Type: BoolType, Integer: 1},
+			{Key: "raw", Type: BinaryType, Interface: []byte{0, 1, 2}},
+			{Key: "c", Type: Complex128Type, Interface: complex128(1 + 2i)},
+			{Key: "user", Type: ReflectType, Interface: protoTestUser{Name: "jane", Roles: []string{"admin"}, Age: 30}},
+			{Key: "nothing", Type: ReflectType, Interface: nil},
+			{Key: "at", Type: TimeFullType, Interface: time.Unix(0, 0).UTC()},
+		},
+		nil,
+	}
+	for i, ent := range entries {
+		buf, err := enc.EncodeEntry(ent, fieldSets[i])
+		require.NoError(t, err, "Unexpected error encoding entry.")
+		stream.Write(buf.Bytes())
+		buf.Free()
+	}
+
+	msgs, err := readProtoStream(stream.Bytes())
+	require.NoError(t, err, "Failed to split stream.")
+	require.Len(t, msgs, 2, "Unexpected number of messages.")
+
+	first, err := decodeProtoEntry(msgs[0])
+	require.NoError(t, err, "Failed to decode first entry.")
+	assert.Equal(t, map[string]interface{}{
+		"message":    "slow query",
+		"level":      "warn",
+		"time":       time.Unix(1700000000, 123456789).UTC(),
+		"logger":     "db",
+		"caller":     "db/query.go:42",
+		"function":   "db.Query",
+		"stacktrace": "stack",
+		"fields": map[string]interface{}{
+			"service": "api",
+			"request": map[string]interface{}{
+				"attempt": int64(2),
+				"took":    int64(3 * time.Second),
+				"rows":    int64(-7),
+				"size":    uint64(math.MaxInt64),
+				"ratio":   0.5,
+				"ok":      true,
+				"raw":     []byte{0, 1, 2},
+				"c":       "1+2i",
+				"user": map[string]interface{}{
+					"name":  "jane",
+					"Roles": []interface{}{"admin"},
+					"age":   int64(30),
+				},
+				"nothing": nil,
+				"at":      "1970-01-01T00:00:00Z",
+			},
+		},
+	}, first, "Unexpected first entry.")
+
+	second, err := decodeProtoEntry(msgs[1])
+	require.NoError(t, err, "Failed to decode second entry.")
+	assert.Equal(t, map[string]interface{}{
+		"message": "done",
+		"level":   "info",
+		"fields": map[string]interface{}{
+			"service": "api",
+			"request": map[string]interface{}{"attempt": int64(2)},
+		},
+	}, second, "Unexpected second entry.")
+}
+
+func TestProtoEncoderMarshalers(t *testing.T) {
+	enc := NewProtoEncoder(DefaultProtoDescriptor)
+	buf, err := enc.EncodeEntry(Entry{Message: "m"}, []Field{
+		{Key: "obj", Type: ObjectMarshalerType, Interface: ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+			enc.AddString("in", "chicken")
+			enc.OpenNamespace("ns")
+			enc.AddBool("deep", true)
+			return nil
+		})},
+		{Key: "list", Type: ArrayMarshalerType, Interface: ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+			arr.AppendString("a")
+			arr.AppendInt(1)
+			arr.AppendFloat32(1.5)
+			return arr.AppendArray(ArrayMarshalerFunc(func(ArrayEncoder) error { return nil }))
+		})},
+	})
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	defer buf.Free()
+
+	msgs, err := readProtoStream(buf.Bytes())
+	require.NoError(t, err, "Failed to split stream.")
+	require.Len(t, msgs, 1, "Unexpected number of messages.")
+	got, err := decodeProtoEntry(msgs[0])
+	require.NoError(t, err, "Failed to decode entry.")
+
+	fields := got["fields"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"a", int64(1), 1.5, []interface{}{}}, fields["list"], "Unexpected array.")
+	assert.Equal(t, map[string]interface{}{
+		"in": "chicken",
+		"ns": map[string]interface{}{"deep": true},
+	}, fields["obj"], "Unexpected object.")
+}
+
+func TestProtoEncoderCustomDescriptor(t *testing.T) {
+	enc := NewProtoEncoder(ProtoDescriptor{Message: 3, Level: 20})
+	buf, err := enc.EncodeEntry(Entry{Level: ErrorLevel, Message: "hi", LoggerName: "ignored"}, []Field{
+		{Key: "ignored", Type: StringType, String: "too"},
+	})
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	defer buf.Free()
+
+	msgs, err := readProtoStream(buf.Bytes())
+	require.NoError(t, err, "Failed to split stream.")
+	require.Len(t, msgs, 1, "Unexpected number of messages.")
+	fields, err := decodeProtoFields(msgs[0])
+	require.NoError(t, err, "Failed to decode entry.")
+	assert.Equal(t, []protoField{
+		{Num: 3, Bytes: []byte("hi")},
+		{Num: 20, Bytes: []byte("error")},
+	}, fields, "Unexpected fields.")
+}
+
+func TestProtoEncoderReflectedError(t *testing.T) {
+	enc := NewProtoEncoder(DefaultProtoDescriptor)
+	assert.Error(t, enc.AddReflected("k", make(chan int)), "Expected error reflecting a channel.")
+
+	buf, err := enc.EncodeEntry(Entry{}, nil)
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	defer buf.Free()
+	msgs, err := readProtoStream(buf.Bytes())
+	require.NoError(t, err, "Failed to split stream.")
+	got, err := decodeProtoEntry(msgs[0])
+	require.NoError(t, err, "Failed to decode entry.")
+	assert.Empty(t, got["fields"], "Failed reflection shouldn't leave a field behind.")
+}
+
+func TestProtoEncoderLargeMessages(t *testing.T) {
+	// Exercise multi-byte length prefixes at every level of nesting.
+	long := strings.Repeat("x", 1<<14)
+	enc := NewProtoEncoder(DefaultProtoDescriptor)
+	buf, err := enc.EncodeEntry(Entry{Message: long}, []Field{
+		{Key: long, Type: StringType, String: long},
+	})
+	require.NoError(t, err, "Unexpected error encoding entry.")
+	defer buf.Free()
+
+	msgs, err := readProtoStream(buf.Bytes())
+	require.NoError(t, err, "Failed to split stream.")
+	require.Len(t, msgs, 1, "Unexpected number of messages.")
+	got, err := decodeProtoEntry(msgs[0])
+	require.NoError(t, err, "Failed to decode entry.")
+	assert.Equal(t, long, got["message"], "Unexpected message.")
+	assert.Equal(t, map[string]interface{}{long: long}, got["fields"], "Unexpected fields.")
+}
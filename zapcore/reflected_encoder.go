@@ -23,6 +23,10 @@ package zapcore
 import (
 	"encoding/json"
 	"io"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+	"go.uber.org/zap/internal/pool"
 )
 
 // ReflectedEncoder serializes log fields that can't be serialized with Zap's
@@ -39,3 +43,29 @@ func defaultReflectedEncoder(w io.Writer) ReflectedEncoder {
 	enc.SetEscapeHTML(false)
 	return enc
 }
+
+// pooledReflectedEncoder is a default ReflectedEncoder together with the
+// buffer it writes to. Encoders that use the default ReflectedEncoder take
+// one from _reflectedPool instead of building a json.Encoder for every entry.
+type pooledReflectedEncoder struct {
+	buf *buffer.Buffer
+	enc ReflectedEncoder
+}
+
+var _reflectedPool = pool.New(func() *pooledReflectedEncoder {
+	buf := bufferpool.Get()
+	return &pooledReflectedEncoder{
+		buf: buf,
+		enc: defaultReflectedEncoder(buf),
+	}
+})
+
+func getReflectedEncoder() *pooledReflectedEncoder {
+	r := _reflectedPool.Get()
+	r.buf.Reset()
+	return r
+}
+
+func putReflectedEncoder(r *pooledReflectedEncoder) {
+	_reflectedPool.Put(r)
+}
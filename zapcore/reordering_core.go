@@ -0,0 +1,269 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+const _defaultReorderingMaxEntries = 1024
+
+// ReorderingOption configures a Core built by NewReorderingCore.
+type ReorderingOption interface {
+	apply(*reorderer)
+}
+
+type reorderingOptionFunc func(*reorderer)
+
+func (f reorderingOptionFunc) apply(r *reorderer) {
+	f(r)
+}
+
+// ReorderingMaxEntries bounds the number of entries the Core buffers. When
+// the buffer is full, the earliest entry is written right away to make
+// room. The default is 1024; values below one are ignored.
+func ReorderingMaxEntries(n int) ReorderingOption {
+	return reorderingOptionFunc(func(r *reorderer) {
+		if n > 0 {
+			r.max = n
+		}
+	})
+}
+
+type reorderingCore struct {
+	Core
+
+	r *reorderer
+}
+
+var (
+	_ Core           = (*reorderingCore)(nil)
+	_ leveledEnabler = (*reorderingCore)(nil)
+)
+
+// NewReorderingCore wraps a Core so that entries logged concurrently, whose
+// timestamps can reach it slightly out of order, are written in timestamp
+// order. Each entry is held for up to window before it's written, giving
+// entries with earlier timestamps that arrive later a chance to overtake it:
+//
+//   - An entry is written once an entry whose timestamp is at least window
+//     later has been logged, or once it's been buffered for window,
+//     whichever comes first. Entries with earlier timestamps go first.
+//   - When the buffer holds the maximum number of entries (see
+//     ReorderingMaxEntries), the earliest one is written right away.
+//   - Entries above ErrorLevel, which may be followed by a panic or exit,
+//     are written right away, along with all buffered entries, before the
+//     call that logged them returns.
+//   - Sync writes all buffered entries before syncing the wrapped Core.
+//
+// Entries that arrive later than window after entries with later
+// timestamps have been written are written as soon as possible, out of
+// order. Errors from writing entries after the call that logged them has
+// returned are reported by the next Sync.
+//
+// Since entries are written after the call that logged them returns, their
+// fields are encoded late: fields that refer to mutable values, such as
+// those built with zap.Object or zap.Reflect, see the values at the time
+// of writing. Cores derived with With share the buffer, so their entries
+// are ordered together.
+func NewReorderingCore(next Core, window time.Duration, opts ...ReorderingOption) Core {
+	r := &reorderer{
+		window: window,
+		max:    _defaultReorderingMaxEntries,
+	}
+	for _, opt := range opts {
+		opt.apply(r)
+	}
+	return &reorderingCore{Core: next, r: r}
+}
+
+func (c *reorderingCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *reorderingCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *reorderingCore) With(fields []Field) Core {
+	return &reorderingCore{Core: c.Core.With(fields), r: c.r}
+}
+
+// Check registers the Cores that accept the entry behind a single
+// downstreamWriter, which buffers the entry when it's written.
+func (c *reorderingCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkDownstream(c.Core, ent, ce, downstreamWriter{write: c.r.write})
+}
+
+func (c *reorderingCore) Sync() error {
+	return multierr.Append(c.r.flushAll(), c.Core.Sync())
+}
+
+// write buffers an entry for the Cores that agreed to log it.
+func (r *reorderer) write(cores multiCore, ent Entry, fields []Field) error {
+	// The caller may reuse fields once Write returns.
+	return r.add(&bufferedEntry{
+		ent:    ent,
+		fields: append([]Field(nil), fields...),
+		cores:  cores,
+	})
+}
+
+type bufferedEntry struct {
+	ent     Entry
+	fields  []Field
+	cores   multiCore
+	seq     uint64    // breaks ties between equal timestamps
+	arrived time.Time // wall time the entry was buffered
+}
+
+// entryHeap is a min-heap of buffered entries by timestamp.
+type entryHeap []*bufferedEntry
+
+func (h entryHeap) Len() int { return len(h) }
+
+func (h entryHeap) Less(i, j int) bool {
+	if h[i].ent.Time.Equal(h[j].ent.Time) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].ent.Time.Before(h[j].ent.Time)
+}
+
+func (h entryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *entryHeap) Push(x interface{}) { *h = append(*h, x.(*bufferedEntry)) }
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+type reorderer struct {
+	window time.Duration
+	max    int
+
+	mu      sync.Mutex
+	entries entryHeap
+	seq     uint64
+	newest  time.Time   // latest timestamp seen
+	timer   *time.Timer // armed while entries are buffered
+	err     error       // from writes that no caller saw
+}
+
+// add buffers an entry and writes the entries that are due.
+func (r *reorderer) add(e *bufferedEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	e.seq = r.seq
+	e.arrived = time.Now()
+	heap.Push(&r.entries, e)
+	if e.ent.Time.After(r.newest) {
+		r.newest = e.ent.Time
+	}
+
+	var err error
+	if e.ent.Level > ErrorLevel {
+		// The caller may panic or exit once Write returns.
+		for len(r.entries) > 0 {
+			err = multierr.Append(err, r.writeNext())
+		}
+		return err
+	}
+	for len(r.entries) > r.max {
+		err = multierr.Append(err, r.writeNext())
+	}
+	cutoff := r.newest.Add(-r.window)
+	for len(r.entries) > 0 && !r.entries[0].ent.Time.After(cutoff) {
+		err = multierr.Append(err, r.writeNext())
+	}
+	r.armTimer()
+	return err
+}
+
+// writeNext writes the earliest buffered entry. It must be called with mu
+// held, so that entries are written in order.
+func (r *reorderer) writeNext() error {
+	e := heap.Pop(&r.entries).(*bufferedEntry)
+	return e.cores.Write(e.ent, e.fields)
+}
+
+// armTimer schedules flushStale for when the earliest-arriving entry has
+// been buffered for window. It must be called with mu held.
+func (r *reorderer) armTimer() {
+	if r.timer != nil || len(r.entries) == 0 {
+		return
+	}
+	first := r.entries[0].arrived
+	for _, e := range r.entries[1:] {
+		if e.arrived.Before(first) {
+			first = e.arrived
+		}
+	}
+	r.timer = time.AfterFunc(time.Until(first.Add(r.window)), r.flushStale)
+}
+
+// flushStale writes the entries that have been buffered for window, along
+// with any entries with earlier timestamps.
+func (r *reorderer) flushStale() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timer = nil
+
+	stale := time.Now().Add(-r.window)
+	var cutoff time.Time
+	found := false
+	for _, e := range r.entries {
+		if !e.arrived.After(stale) && (!found || e.ent.Time.After(cutoff)) {
+			cutoff, found = e.ent.Time, true
+		}
+	}
+	for found && len(r.entries) > 0 && !r.entries[0].ent.Time.After(cutoff) {
+		r.err = multierr.Append(r.err, r.writeNext())
+	}
+	r.armTimer()
+}
+
+// flushAll writes all buffered entries and returns any errors from writes
+// since the last flushAll.
+func (r *reorderer) flushAll() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.err
+	r.err = nil
+	for len(r.entries) > 0 {
+		err = multierr.Append(err, r.writeNext())
+	}
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+	"time"
+
+	//revive:disable:dot-imports
+	"go.uber.org/zap/internal/ztest"
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAt(t *testing.T, core Core, ts time.Time, msg string) {
+	ent := Entry{Level: InfoLevel, Time: ts, Message: msg}
+	ce := core.Check(ent, nil)
+	require.NotNil(t, ce, "Expected entry to be enabled.")
+	ce.Write()
+}
+
+func TestReorderingCoreSortsOnSync(t *testing.T) {
+	next, logs := observer.New(InfoLevel)
+	core := NewReorderingCore(next, time.Hour)
+
+	base := time.Unix(1700000000, 0)
+	writeAt(t, core, base.Add(2*time.Millisecond), "third")
+	writeAt(t, core, base, "first")
+	writeAt(t, core.With([]Field{makeInt64Field("k", 1)}), base.Add(time.Millisecond), "second")
+	writeAt(t, core, base.Add(2*time.Millisecond), "fourth")
+	assert.Zero(t, logs.Len(), "Expected entries to be buffered.")
+
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, messages(logs))
+	assert.Equal(t, map[string]interface{}{"k": int64(1)}, logs.All()[1].ContextMap(),
+		"Expected fields to be kept with their entry.")
+}
+
+func TestReorderingCoreWindow(t *testing.T) {
+	next, logs := observer.New(InfoLevel)
+	core := NewReorderingCore(next, time.Hour)
+
+	base := time.Unix(1700000000, 0)
+	writeAt(t, core, base.Add(10*time.Minute), "b")
+	writeAt(t, core, base, "a")
+	writeAt(t, core, base.Add(30*time.Minute), "c")
+	assert.Zero(t, logs.Len(), "Expected entries within the window to be buffered.")
+
+	writeAt(t, core, base.Add(75*time.Minute), "d")
+	assert.Equal(t, []string{"a", "b"}, messages(logs),
+		"Expected entries older than the window to be written in order.")
+
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{"a", "b", "c", "d"}, messages(logs))
+}
+
+func TestReorderingCoreMaxEntries(t *testing.T) {
+	next, logs := observer.New(InfoLevel)
+	core := NewReorderingCore(next, time.Hour, ReorderingMaxEntries(2))
+
+	base := time.Unix(1700000000, 0)
+	writeAt(t, core, base.Add(3*time.Second), "d")
+	writeAt(t, core, base.Add(time.Second), "b")
+	assert.Zero(t, logs.Len(), "Expected entries to be buffered.")
+
+	writeAt(t, core, base.Add(2*time.Second), "c")
+	writeAt(t, core, base, "a")
+	assert.Equal(t, []string{"b", "a"}, messages(logs),
+		"Expected the earliest buffered entry to be written when full.")
+
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, []string{"b", "a", "c", "d"}, messages(logs))
+}
+
+func TestReorderingCoreFlushesAfterWindow(t *testing.T) {
+	next, logs := observer.New(InfoLevel)
+	core := NewReorderingCore(next, 10*time.Millisecond)
+
+	base := time.Now()
+	writeAt(t, core, base.Add(time.Millisecond), "second")
+	writeAt(t, core, base, "first")
+
+	assert.Eventually(t, func() bool { return logs.Len() == 2 }, time.Second, time.Millisecond,
+		"Expected buffered entries to be written after the window.")
+	assert.Equal(t, []string{"first", "second"}, messages(logs))
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+}
+
+func TestReorderingCoreErrors(t *testing.T) {
+	failing := NewCore(
+		NewJSONEncoder(testEncoderConfig()),
+		&ztest.FailWriter{},
+		InfoLevel,
+	)
+	core := NewReorderingCore(failing, 10*time.Millisecond)
+
+	writeAt(t, core, time.Now(), "msg")
+	time.Sleep(50 * time.Millisecond)
+	assert.Error(t, core.Sync(), "Expected the timer's write error on Sync.")
+	assert.NoError(t, core.Sync(), "Expected errors to be reported once.")
+}
+
+func TestReorderingCoreLevel(t *testing.T) {
+	next, _ := observer.New(WarnLevel)
+	core := NewReorderingCore(next, time.Second)
+	assert.Equal(t, WarnLevel, LevelOf(core))
+	assert.Nil(t, core.Check(Entry{Level: InfoLevel}, nil), "Expected disabled entries to be dropped.")
+}
+
+func TestReorderingCoreTerminalEntries(t *testing.T) {
+	next, logs := observer.New(InfoLevel)
+	core := NewReorderingCore(next, time.Hour)
+
+	base := time.Unix(1700000000, 0)
+	writeAt(t, core, base.Add(time.Millisecond), "before")
+	writeAt(t, core, base, "earlier")
+
+	var written []string
+	ent := Entry{Level: FatalLevel, Time: base.Add(2 * time.Millisecond), Message: "dying"}
+	ce := core.Check(ent, nil)
+	require.NotNil(t, ce, "Expected fatal entry to be enabled.")
+	ce.After(ent, fatalHook(func() { written = messages(logs) })).Write()
+
+	assert.Equal(t, []string{"earlier", "before", "dying"}, written,
+		"Expected all entries to be written before the fatal hook runs.")
+}
+
+type fatalHook func()
+
+func (f fatalHook) OnWrite(*CheckedEntry, []Field) { f() }
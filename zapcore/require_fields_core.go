@@ -0,0 +1,193 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+// RequireFieldsOption configures a Core built by NewRequireFieldsCore.
+type RequireFieldsOption interface {
+	apply(*requireFieldsCore)
+}
+
+type requireFieldsOptionFunc func(*requireFieldsCore)
+
+func (f requireFieldsOptionFunc) apply(c *requireFieldsCore) {
+	f(c)
+}
+
+// RequireFieldsFlag makes the Core write entries that lack required fields
+// instead of dropping them, adding an array of the missing keys under the
+// given key. onViolation is still called for each such entry.
+func RequireFieldsFlag(key string) RequireFieldsOption {
+	return requireFieldsOptionFunc(func(c *requireFieldsCore) {
+		c.flagKey = key
+	})
+}
+
+type requireFieldsCore struct {
+	Core
+
+	required    []string
+	onViolation func(Entry)
+	flagKey     string // if set, flag violations rather than dropping them
+
+	// Keys added with With, qualified by namespace, and the namespace that
+	// With left open.
+	present map[string]struct{}
+	ns      string
+}
+
+var (
+	_ Core           = (*requireFieldsCore)(nil)
+	_ leveledEnabler = (*requireFieldsCore)(nil)
+)
+
+// NewRequireFieldsCore wraps a Core to enforce a logging standard: entries
+// that don't have a field for every key in required are dropped, and
+// onViolation, if non-nil, is called with each dropped entry, for example to
+// fail a test or increment a metric. Use RequireFieldsFlag to write such
+// entries with a list of the missing keys instead.
+//
+// Fields added with With count towards every entry logged afterwards. A
+// required key may be qualified by namespaces, separated by dots: the key
+// "http.request_id" requires a "request_id" field added after
+// Namespace("http"). Qualified keys don't look inside fields that carry
+// objects, such as those built with zap.Object; the fields of Inline
+// marshalers count as if they were added directly.
+//
+// onViolation is called when the entry would be written, and must be safe
+// for concurrent use.
+func NewRequireFieldsCore(next Core, required []string, onViolation func(Entry), opts ...RequireFieldsOption) Core {
+	c := &requireFieldsCore{
+		Core:        next,
+		required:    append([]string(nil), required...),
+		onViolation: onViolation,
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+func (c *requireFieldsCore) Level() Level {
+	return LevelOf(c.Core)
+}
+
+func (c *requireFieldsCore) Describe() []CoreInfo {
+	return DescribeCores(c.Core)
+}
+
+func (c *requireFieldsCore) With(fields []Field) Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.present = make(map[string]struct{}, len(c.present)+len(fields))
+	for k := range c.present {
+		clone.present[k] = struct{}{}
+	}
+	clone.ns = visitFieldKeys(c.ns, fields, func(key string) bool {
+		clone.present[key] = struct{}{}
+		return false
+	})
+	return &clone
+}
+
+// Check registers the Cores that accept the entry behind a single
+// downstreamWriter, so that the entry's fields are checked once, when
+// it's written.
+func (c *requireFieldsCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return checkDownstream(c.Core, ent, ce, downstreamWriter{write: c.write})
+}
+
+// missing returns the required keys that neither the Core's context nor the
+// given fields provide.
+func (c *requireFieldsCore) missing(fields []Field) []string {
+	var missing []string
+	for _, req := range c.required {
+		if _, ok := c.present[req]; ok {
+			continue
+		}
+		found := false
+		visitFieldKeys(c.ns, fields, func(key string) bool {
+			found = key == req
+			return found
+		})
+		if !found {
+			missing = append(missing, req)
+		}
+	}
+	return missing
+}
+
+// visitFieldKeys calls visit with the namespace-qualified key of each field,
+// starting in namespace ns, until visit returns true. It returns the
+// namespace open after the fields.
+func visitFieldKeys(ns string, fields []Field, visit func(string) bool) string {
+	for _, f := range fields {
+		switch f.Type {
+		case SkipType:
+		case NamespaceType:
+			ns += f.Key + "."
+		case InlineMarshalerType:
+			enc := NewMapObjectEncoder()
+			if m, ok := f.Interface.(ObjectMarshaler); !ok || m.MarshalLogObject(enc) != nil {
+				continue
+			}
+			for k := range enc.Fields {
+				if visit(ns + k) {
+					return ns
+				}
+			}
+		default:
+			if visit(ns + f.Key) {
+				return ns
+			}
+		}
+	}
+	return ns
+}
+
+// write writes an entry to the Cores that agreed to log it, if it has all
+// the required fields.
+func (c *requireFieldsCore) write(cores multiCore, ent Entry, fields []Field) error {
+	missing := c.missing(fields)
+	if len(missing) == 0 {
+		return cores.Write(ent, fields)
+	}
+	if c.onViolation != nil {
+		c.onViolation(ent)
+	}
+	if c.flagKey == "" {
+		return nil
+	}
+	fields = append(fields[:len(fields):len(fields)], Field{
+		Key:       c.flagKey,
+		Type:      ArrayMarshalerType,
+		Interface: missingKeys(missing),
+	})
+	return cores.Write(ent, fields)
+}
+
+type missingKeys []string
+
+func (ks missingKeys) MarshalLogArray(enc ArrayEncoder) error {
+	for _, k := range ks {
+		enc.AppendString(k)
+	}
+	return nil
+}
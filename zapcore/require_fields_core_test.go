@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"sync"
+	"testing"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// violations records the messages of entries passed to an onViolation
+// callback.
+type violations struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (v *violations) record(ent Entry) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.msgs = append(v.msgs, ent.Message)
+}
+
+func writeEntryWith(core Core, msg string, fields ...Field) {
+	if ce := core.Check(Entry{Level: InfoLevel, Message: msg}, nil); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+func TestRequireFieldsCore(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	var v violations
+	core := NewRequireFieldsCore(obs, []string{"request_id", "user"}, v.record)
+	assert.Equal(t, InfoLevel, LevelOf(core), "Unexpected level.")
+
+	writeEntryWith(core, "complete", makeInt64Field("request_id", 1), makeInt64Field("user", 2))
+	writeEntryWith(core, "missing request_id", makeInt64Field("user", 2))
+	writeEntryWith(core, "missing both")
+
+	child := core.With([]Field{makeInt64Field("request_id", 3)})
+	writeEntryWith(child, "context provides request_id", makeInt64Field("user", 2))
+	writeEntryWith(child, "still missing user")
+
+	var msgs []string
+	for _, e := range logs.AllUntimed() {
+		msgs = append(msgs, e.Message)
+	}
+	assert.Equal(t, []string{"complete", "context provides request_id"}, msgs, "Unexpected entries written.")
+	assert.Equal(t, []string{"missing request_id", "missing both", "still missing user"}, v.msgs, "Unexpected violations.")
+}
+
+func TestRequireFieldsCoreNamespaces(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	var v violations
+	core := NewRequireFieldsCore(obs, []string{"http.request_id"}, v.record)
+
+	writeEntryWith(core, "top level", makeInt64Field("request_id", 1))
+	writeEntryWith(core, "namespaced", Field{Key: "http", Type: NamespaceType}, makeInt64Field("request_id", 1))
+	writeEntryWith(core, "wrong namespace", Field{Key: "grpc", Type: NamespaceType}, makeInt64Field("request_id", 1))
+
+	// A namespace opened with With applies to later fields.
+	child := core.With([]Field{{Key: "http", Type: NamespaceType}})
+	writeEntryWith(child, "namespace from context", makeInt64Field("request_id", 1))
+
+	inline := ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddInt64("request_id", 1)
+		return nil
+	})
+	writeEntryWith(child, "inline", Field{Type: InlineMarshalerType, Interface: inline})
+
+	var msgs []string
+	for _, e := range logs.AllUntimed() {
+		msgs = append(msgs, e.Message)
+	}
+	assert.Equal(t, []string{"namespaced", "namespace from context", "inline"}, msgs, "Unexpected entries written.")
+	assert.Equal(t, []string{"top level", "wrong namespace"}, v.msgs, "Unexpected violations.")
+}
+
+func TestRequireFieldsCoreFlag(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	var v violations
+	core := NewRequireFieldsCore(obs, []string{"a", "b", "c"}, v.record, RequireFieldsFlag("missing"))
+
+	writeEntryWith(core, "flagged", makeInt64Field("b", 1))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1, "Expected flagged entry to be written.")
+	assert.Equal(t, map[string]interface{}{
+		"b":       int64(1),
+		"missing": []interface{}{"a", "c"},
+	}, entries[0].ContextMap(), "Unexpected fields.")
+	assert.Equal(t, []string{"flagged"}, v.msgs, "Expected callback for flagged entry.")
+}
+
+func TestRequireFieldsCoreTee(t *testing.T) {
+	obs1, logs1 := observer.New(InfoLevel)
+	obs2, logs2 := observer.New(InfoLevel)
+	var v violations
+	core := NewRequireFieldsCore(NewTee(obs1, obs2), []string{"request_id"}, v.record)
+
+	writeEntryWith(core, "ok", makeInt64Field("request_id", 1))
+	writeEntryWith(core, "dropped")
+	writeEntryWith(core, "also dropped")
+
+	assert.Equal(t, 1, logs1.Len(), "Unexpected entries in first core.")
+	assert.Equal(t, 1, logs2.Len(), "Unexpected entries in second core.")
+	assert.Equal(t, []string{"dropped", "also dropped"}, v.msgs, "Expected one callback per entry, not per core.")
+	assert.Nil(t, core.Check(Entry{Level: DebugLevel}, nil), "Expected disabled entries to be rejected.")
+}
@@ -23,6 +23,8 @@ package zapcore
 import (
 	"sync/atomic"
 	"time"
+
+	"go.uber.org/multierr"
 )
 
 const (
@@ -124,6 +126,16 @@ func SamplerHook(hook func(entry Entry, dec SamplingDecision)) SamplerOption {
 	})
 }
 
+// SamplerClock sets the source of time for the entries the sampler writes
+// itself, like the summary written with SamplerDropSummary. Sampling follows
+// the timestamps of the entries being sampled, so it doesn't use the clock.
+// Defaults to DefaultClock.
+func SamplerClock(clock Clock) SamplerOption {
+	return optionFunc(func(s *sampler) {
+		s.clock = clock
+	})
+}
+
 // NewSamplerWithOptions creates a Core that samples incoming entries, which
 // caps the CPU and I/O load of logging while attempting to preserve a
 // representative subset of your logs.
@@ -150,6 +162,13 @@ func SamplerHook(hook func(entry Entry, dec SamplingDecision)) SamplerOption {
 // absolute precision; under load, each tick may be slightly over- or
 // under-sampled.
 func NewSamplerWithOptions(core Core, tick time.Duration, first, thereafter int, opts ...SamplerOption) Core {
+	return newSampler(core, tick, first, thereafter, opts)
+}
+
+// newSampler builds a sampler with its own counters and applies opts. All
+// sampler constructors go through it, so that every sampler gets the same
+// defaults.
+func newSampler(core Core, tick time.Duration, first, thereafter int, opts []SamplerOption) *sampler {
 	s := &sampler{
 		Core:       core,
 		tick:       tick,
@@ -157,11 +176,11 @@ func NewSamplerWithOptions(core Core, tick time.Duration, first, thereafter int,
 		first:      uint64(first),
 		thereafter: uint64(thereafter),
 		hook:       nopSamplingHook,
+		clock:      DefaultClock,
 	}
 	for _, opt := range opts {
 		opt.apply(s)
 	}
-
 	return s
 }
 
@@ -172,6 +191,8 @@ type sampler struct {
 	tick              time.Duration
 	first, thereafter uint64
 	hook              func(Entry, SamplingDecision)
+	drops             *dropSummary // nil unless SamplerDropSummary is used
+	clock             Clock
 }
 
 var (
@@ -179,6 +200,44 @@ var (
 	_ leveledEnabler = (*sampler)(nil)
 )
 
+// SharedSampler holds sampling state that can be shared by several Cores, so
+// that they collectively honor a single sampling policy. This is useful when
+// many short-lived Loggers are built independently, for example per request,
+// and each would otherwise get its own counters.
+//
+// Cores derived with With already share their parent's counters; a
+// SharedSampler extends this to Cores that don't share a parent.
+type SharedSampler struct {
+	// template is a sampler without a Core; Wrap copies it.
+	template sampler
+}
+
+// NewSharedSampler creates a SharedSampler with the same policy and options
+// as NewSamplerWithOptions. Use Wrap to apply it to each Core that should
+// share its counters.
+//
+// Counters are keyed by level and message, as with NewSamplerWithOptions, so
+// entries from every wrapped Core count towards the same limits. A
+// SharedSampler's counters occupy a fixed amount of memory (roughly 450KiB)
+// regardless of the number of Cores wrapped, whereas each sampler built by
+// NewSamplerWithOptions allocates its own. Counters are updated atomically,
+// so wrapped Cores may be used concurrently, at the cost of contention on
+// frequently logged messages.
+//
+// Wrap Cores that receive distinct entries. If two wrapped Cores receive the
+// same entry, such as two members of the same Tee, the entry is counted twice.
+func NewSharedSampler(tick time.Duration, first, thereafter int, opts ...SamplerOption) *SharedSampler {
+	return &SharedSampler{template: *newSampler(nil, tick, first, thereafter, opts)}
+}
+
+// Wrap returns a Core that samples entries for the given Core using the
+// shared counters.
+func (s *SharedSampler) Wrap(core Core) Core {
+	smp := s.template
+	smp.Core = core
+	return &smp
+}
+
 // NewSampler creates a Core that samples incoming entries, which
 // caps the CPU and I/O load of logging while attempting to preserve a
 // representative subset of your logs.
@@ -200,6 +259,10 @@ func (s *sampler) Level() Level {
 	return LevelOf(s.Core)
 }
 
+func (s *sampler) Describe() []CoreInfo {
+	return DescribeCores(s.Core)
+}
+
 func (s *sampler) With(fields []Field) Core {
 	return &sampler{
 		Core:       s.Core.With(fields),
@@ -208,6 +271,8 @@ func (s *sampler) With(fields []Field) Core {
 		first:      s.first,
 		thereafter: s.thereafter,
 		hook:       s.hook,
+		drops:      s.drops,
+		clock:      s.clock,
 	}
 }
 
@@ -216,14 +281,34 @@ func (s *sampler) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
 		return ce
 	}
 
-	if ent.Level >= _minLevel && ent.Level <= _maxLevel {
-		counter := s.counts.get(ent.Level, ent.Message)
-		n := counter.IncCheckReset(ent.Time, s.tick)
-		if n > s.first && (s.thereafter == 0 || (n-s.first)%s.thereafter != 0) {
-			s.hook(ent, LogDropped)
-			return ce
-		}
-		s.hook(ent, LogSampled)
+	if ent.Level >= _minLevel && ent.Level <= _maxLevel && s.drop(ent, s.tick, s.first, s.thereafter) {
+		return ce
 	}
 	return s.Core.Check(ent, ce)
 }
+
+// drop counts the entry and reports whether the given policy drops it,
+// reporting the decision to the hook.
+func (s *sampler) drop(ent Entry, tick time.Duration, first, thereafter uint64) bool {
+	counter := s.counts.get(ent.Level, ent.Message)
+	n := counter.IncCheckReset(ent.Time, tick)
+	if n > first && (thereafter == 0 || (n-first)%thereafter != 0) {
+		s.hook(ent, LogDropped)
+		if s.drops != nil {
+			s.drops.record(ent)
+		}
+		return true
+	}
+	s.hook(ent, LogSampled)
+	return false
+}
+
+// Sync writes a summary of dropped entries, if SamplerDropSummary is used,
+// and syncs the wrapped Core.
+func (s *sampler) Sync() error {
+	var err error
+	if s.drops != nil {
+		err = s.drops.report(s.Core, s.clock)
+	}
+	return multierr.Append(err, s.Core.Sync())
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+const _dropSummaryMessage = "sampler dropped entries"
+
+// SamplerDropSummary makes the sampler count the entries it drops for each
+// level and message, and write a summary of the counts when it's synced, so
+// that it's possible to tell what sampling hid. Loggers sync their Core with
+// Logger.Sync, which applications typically call before exiting.
+//
+// The summary is a WarnLevel entry with the message "sampler dropped
+// entries", timestamped with the clock set by SamplerClock. It's checked
+// with the wrapped Core, bypassing sampling, so it's written wherever
+// WarnLevel entries are logged. It holds the total number of entries dropped
+// over the sampler's lifetime and, in "messages", the level, message and
+// count for each kind of dropped entry, most dropped first. It's only
+// written if entries were dropped since the last summary.
+//
+// At most maxKeys distinct level and message pairs are counted individually,
+// to bound memory use; drops of other entries only count towards the total
+// and an "untracked" count. Samplers derived with With, and Cores wrapped by
+// a SharedSampler, share their counts.
+func SamplerDropSummary(maxKeys int) SamplerOption {
+	return optionFunc(func(s *sampler) {
+		s.drops = &dropSummary{
+			maxKeys: maxKeys,
+			counts:  make(map[droppedKey]uint64),
+		}
+	})
+}
+
+type droppedKey struct {
+	level   Level
+	message string
+}
+
+type dropSummary struct {
+	maxKeys int
+
+	mu        sync.Mutex
+	counts    map[droppedKey]uint64
+	untracked uint64
+	total     uint64
+	reported  uint64 // total as of the last summary
+}
+
+func (d *dropSummary) record(ent Entry) {
+	key := droppedKey{level: ent.Level, message: ent.Message}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.total++
+	if _, ok := d.counts[key]; ok || len(d.counts) < d.maxKeys {
+		d.counts[key]++
+	} else {
+		d.untracked++
+	}
+}
+
+func (d *dropSummary) report(core Core, clock Clock) error {
+	d.mu.Lock()
+	if d.total == d.reported {
+		d.mu.Unlock()
+		return nil
+	}
+	d.reported = d.total
+	msgs := make(droppedMessages, 0, len(d.counts))
+	for k, n := range d.counts {
+		msgs = append(msgs, droppedMessage{droppedKey: k, count: n})
+	}
+	fields := []Field{{Key: "dropped", Type: Uint64Type, Integer: int64(d.total)}}
+	if d.untracked > 0 {
+		fields = append(fields, Field{Key: "untracked", Type: Uint64Type, Integer: int64(d.untracked)})
+	}
+	d.mu.Unlock()
+
+	sort.Slice(msgs, func(i, j int) bool {
+		if msgs[i].count != msgs[j].count {
+			return msgs[i].count > msgs[j].count
+		}
+		if msgs[i].level != msgs[j].level {
+			return msgs[i].level > msgs[j].level
+		}
+		return msgs[i].message < msgs[j].message
+	})
+	fields = append(fields, Field{Key: "messages", Type: ArrayMarshalerType, Interface: msgs})
+
+	ent := Entry{Level: WarnLevel, Time: clock.Now(), Message: _dropSummaryMessage}
+	ce := core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	// Write to the accepting Cores directly, rather than with ce.Write, so
+	// that errors are returned from Sync.
+	var err error
+	for _, c := range ce.cores {
+		err = multierr.Append(err, c.Write(ent, fields))
+	}
+	putCheckedEntry(ce)
+	return err
+}
+
+type droppedMessage struct {
+	droppedKey
+	count uint64
+}
+
+func (m droppedMessage) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddString("level", m.level.String())
+	enc.AddString("message", m.message)
+	enc.AddUint64("count", m.count)
+	return nil
+}
+
+type droppedMessages []droppedMessage
+
+func (ms droppedMessages) MarshalLogArray(enc ArrayEncoder) error {
+	for _, m := range ms {
+		if err := enc.AppendObject(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
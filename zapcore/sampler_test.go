@@ -327,3 +327,144 @@ func TestSamplerWithZeroThereafter(t *testing.T) {
 	assert.Equal(t, 4, int(counter.logs.Load()),
 		"Unexpected number of logs")
 }
+
+func TestSharedSampler(t *testing.T) {
+	var dropped atomic.Int64
+	shared := NewSharedSampler(time.Minute, 2, 3, SamplerHook(func(_ Entry, dec SamplingDecision) {
+		if dec&LogDropped > 0 {
+			dropped.Add(1)
+		}
+	}))
+
+	core1, logs1 := observer.New(DebugLevel)
+	core2, logs2 := observer.New(DebugLevel)
+	sampler1 := shared.Wrap(core1)
+	sampler2 := shared.Wrap(core2)
+
+	// Alternate between the two Cores: together they see entries 1-10, so
+	// only the first two and every third thereafter are logged.
+	for i := 1; i <= 10; i++ {
+		core := sampler1
+		if i%2 == 0 {
+			core = sampler2
+		}
+		writeSequence(core, i, InfoLevel)
+	}
+	assertSequence(t, logs1.TakeAll(), InfoLevel, 1, 5)
+	assertSequence(t, logs2.TakeAll(), InfoLevel, 2, 8)
+	assert.Equal(t, int64(6), dropped.Load(), "Unexpected number of dropped entries.")
+
+	// Cores wrapped separately from a plain sampler don't share counters.
+	separate, logs3 := fakeSampler(DebugLevel, time.Minute, 2, 3)
+	writeSequence(separate, 1, InfoLevel)
+	assertSequence(t, logs3.TakeAll(), InfoLevel, 1)
+}
+
+func TestSharedSamplerConcurrent(t *testing.T) {
+	const (
+		loggers    = 4
+		perLogger  = 250
+		first      = 10
+		thereafter = 100
+	)
+	shared := NewSharedSampler(time.Hour, first, thereafter)
+
+	var (
+		wg     sync.WaitGroup
+		logged atomic.Int64
+	)
+	for i := 0; i < loggers; i++ {
+		core, logs := observer.New(DebugLevel)
+		sampler := shared.Wrap(core)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perLogger; j++ {
+				writeSequence(sampler, j, InfoLevel)
+			}
+			logged.Add(int64(logs.Len()))
+		}()
+	}
+	wg.Wait()
+
+	// 1000 entries in total: the first 10, then every 100th of the rest.
+	assert.Equal(t, int64(first+(loggers*perLogger-first)/thereafter), logged.Load(),
+		"Unexpected number of entries logged across all Cores.")
+}
+
+func TestSamplerDropSummary(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	sampler := NewSamplerWithOptions(obs, time.Minute, 2, 0, SamplerDropSummary(2))
+	child := sampler.With([]Field{makeInt64Field("child", 1)})
+
+	log := func(core Core, lvl Level, msg string, n int) {
+		for i := 0; i < n; i++ {
+			if ce := core.Check(Entry{Level: lvl, Message: msg, Time: time.Unix(0, 0)}, nil); ce != nil {
+				ce.Write()
+			}
+		}
+	}
+	log(sampler, InfoLevel, "a", 5) // 3 dropped
+	log(child, ErrorLevel, "b", 3)  // 1 dropped, counted with the parent
+	log(sampler, InfoLevel, "c", 4) // 2 dropped, but only two keys are tracked
+	require.Equal(t, 6, logs.Len(), "Unexpected number of sampled entries.")
+	logs.TakeAll()
+
+	require.NoError(t, sampler.Sync(), "Unexpected sync error.")
+	summaries := logs.TakeAll()
+	require.Len(t, summaries, 1, "Expected a summary on sync.")
+	assert.Equal(t, WarnLevel, summaries[0].Level, "Unexpected summary level.")
+	assert.Equal(t, "sampler dropped entries", summaries[0].Message, "Unexpected summary message.")
+	assert.Equal(t, map[string]interface{}{
+		"dropped":   uint64(6),
+		"untracked": uint64(2),
+		"messages": []interface{}{
+			map[string]interface{}{"level": "info", "message": "a", "count": uint64(3)},
+			map[string]interface{}{"level": "error", "message": "b", "count": uint64(1)},
+		},
+	}, summaries[0].ContextMap(), "Unexpected summary fields.")
+
+	require.NoError(t, child.Sync(), "Unexpected sync error.")
+	assert.Zero(t, logs.Len(), "Expected no summary without new drops.")
+
+	log(sampler, InfoLevel, "a", 1)
+	require.NoError(t, child.Sync(), "Unexpected sync error.")
+	require.Equal(t, 1, logs.Len(), "Expected a summary after new drops.")
+	assert.Equal(t, uint64(7), logs.All()[0].ContextMap()["dropped"], "Expected lifetime drop count.")
+}
+
+func TestSamplerDropSummaryClockAndLevel(t *testing.T) {
+	clock := ztest.NewMockClock()
+	clock.Add(time.Hour)
+	warnObs, warnLogs := observer.New(InfoLevel)
+	errorObs, errorLogs := observer.New(ErrorLevel)
+	sampler := NewSamplerWithOptions(
+		NewTee(warnObs, errorObs), time.Minute, 1, 0,
+		SamplerDropSummary(10), SamplerClock(clock),
+	)
+
+	for i := 0; i < 3; i++ {
+		if ce := sampler.Check(Entry{Level: InfoLevel, Message: "a"}, nil); ce != nil {
+			ce.Write()
+		}
+	}
+	warnLogs.TakeAll()
+
+	require.NoError(t, sampler.Sync(), "Unexpected sync error.")
+	summaries := warnLogs.TakeAll()
+	require.Len(t, summaries, 1, "Expected a summary where WarnLevel is enabled.")
+	assert.Equal(t, clock.Now(), summaries[0].Time, "Expected the summary to use the sampler's clock.")
+	assert.Zero(t, errorLogs.Len(), "Expected no summary where WarnLevel is disabled.")
+}
+
+func TestSamplerWithoutDropSummary(t *testing.T) {
+	obs, logs := observer.New(InfoLevel)
+	sampler := NewSamplerWithOptions(obs, time.Minute, 1, 0)
+	for i := 0; i < 3; i++ {
+		if ce := sampler.Check(Entry{Level: InfoLevel, Message: "a"}, nil); ce != nil {
+			ce.Write()
+		}
+	}
+	require.NoError(t, sampler.Sync(), "Unexpected sync error.")
+	assert.Equal(t, 1, logs.Len(), "Expected no summary by default.")
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "go.uber.org/zap/buffer"
+
+// WithSizeObserver wraps an Encoder so that observe is called with the level
+// and size in bytes of each entry it encodes, for example to plan log storage
+// capacity. The size includes the line ending, if any.
+//
+// observe is called once per encoded entry, after the wrapped Encoder runs.
+// Since each Core encodes entries itself, an entry written to both members of
+// a Tee is observed twice if both use a wrapped Encoder. Entries that fail to
+// encode aren't observed. observe must be safe for concurrent use. If observe
+// is nil, enc is returned as-is, so there's no overhead.
+func WithSizeObserver(enc Encoder, observe func(lvl Level, bytes int)) Encoder {
+	if observe == nil {
+		return enc
+	}
+	return &sizeObservingEncoder{Encoder: enc, observe: observe}
+}
+
+type sizeObservingEncoder struct {
+	Encoder
+
+	observe func(Level, int)
+}
+
+func (e *sizeObservingEncoder) Clone() Encoder {
+	return &sizeObservingEncoder{
+		Encoder: e.Encoder.Clone(),
+		observe: e.observe,
+	}
+}
+
+func (e *sizeObservingEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err == nil {
+		e.observe(ent.Level, buf.Len())
+	}
+	return buf, err
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entrySizes records observed entry sizes.
+type entrySizes struct {
+	mu    sync.Mutex
+	sizes map[Level][]int
+}
+
+func (s *entrySizes) observe(lvl Level, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sizes == nil {
+		s.sizes = make(map[Level][]int)
+	}
+	s.sizes[lvl] = append(s.sizes[lvl], n)
+}
+
+func TestWithSizeObserver(t *testing.T) {
+	var sizes entrySizes
+	var out ztest.Buffer
+	enc := WithSizeObserver(NewJSONEncoder(EncoderConfig{MessageKey: "msg"}), sizes.observe)
+	core := NewCore(enc, &out, InfoLevel).With([]Field{makeInt64Field("ctx", 1)})
+
+	for _, ent := range []Entry{
+		{Level: InfoLevel, Message: "a"},
+		{Level: WarnLevel, Message: "longer"},
+		{Level: DebugLevel, Message: "dropped"},
+	} {
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write(makeInt64Field("n", 2))
+		}
+	}
+
+	require.Equal(t, []string{
+		`{"msg":"a","ctx":1,"n":2}`,
+		`{"msg":"longer","ctx":1,"n":2}`,
+	}, out.Lines(), "Unexpected output.")
+	assert.Equal(t, map[Level][]int{
+		InfoLevel: {len(`{"msg":"a","ctx":1,"n":2}` + "\n")},
+		WarnLevel: {len(`{"msg":"longer","ctx":1,"n":2}` + "\n")},
+	}, sizes.sizes, "Unexpected observed sizes.")
+}
+
+func TestWithSizeObserverTee(t *testing.T) {
+	var total atomic.Int64
+	observe := func(_ Level, n int) { total.Add(int64(n)) }
+	newCore := func(cfg EncoderConfig) Core {
+		return NewCore(WithSizeObserver(NewJSONEncoder(cfg), observe), &ztest.Discarder{}, DebugLevel)
+	}
+	core := NewTee(
+		newCore(EncoderConfig{MessageKey: "msg"}),
+		newCore(EncoderConfig{MessageKey: "message"}),
+	)
+
+	if ce := core.Check(Entry{Level: InfoLevel, Message: "m"}, nil); ce != nil {
+		ce.Write()
+	}
+	assert.Equal(t, int64(len(`{"msg":"m"}`+"\n")+len(`{"message":"m"}`+"\n")), total.Load(), "Expected each Core to observe the entry.")
+}
+
+// failingEncoder is an Encoder whose EncodeEntry always fails.
+type failingEncoder struct{ Encoder }
+
+func (failingEncoder) EncodeEntry(Entry, []Field) (*buffer.Buffer, error) {
+	return nil, errors.New("fail")
+}
+
+func TestWithSizeObserverErrors(t *testing.T) {
+	called := false
+	enc := WithSizeObserver(failingEncoder{NewJSONEncoder(EncoderConfig{})}, func(Level, int) { called = true })
+	_, err := enc.EncodeEntry(Entry{}, nil)
+	assert.Error(t, err, "Expected encoding errors to propagate.")
+	assert.False(t, called, "Entries that fail to encode shouldn't be observed.")
+}
+
+func TestWithSizeObserverNil(t *testing.T) {
+	enc := NewJSONEncoder(EncoderConfig{})
+	assert.Same(t, enc, WithSizeObserver(enc, nil), "Expected the Encoder to be returned as-is.")
+}
+
+func BenchmarkWithSizeObserver(b *testing.B) {
+	entry := Entry{Message: "hello"}
+	fields := []Field{makeInt64Field("n", 1)}
+	var total atomic.Int64
+
+	b.Run("Plain", func(b *testing.B) {
+		runEncodeEntry(b, NewJSONEncoder(testEncoderConfig()), entry, fields)
+	})
+	b.Run("NilObserver", func(b *testing.B) {
+		runEncodeEntry(b, WithSizeObserver(NewJSONEncoder(testEncoderConfig()), nil), entry, fields)
+	})
+	b.Run("Observer", func(b *testing.B) {
+		enc := WithSizeObserver(NewJSONEncoder(testEncoderConfig()), func(_ Level, n int) {
+			total.Add(int64(n))
+		})
+		runEncodeEntry(b, enc, entry, fields)
+	})
+}
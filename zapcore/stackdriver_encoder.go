@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"strconv"
+
+	"go.uber.org/zap/buffer"
+)
+
+// Keys of the special fields that Google Cloud Logging extracts from
+// structured log entries. Log fields with these keys, such as
+//
+//	zap.String(zapcore.StackdriverTraceKey, "projects/my-project/traces/"+traceID)
+//
+// associate entries with a trace in Cloud Trace.
+const (
+	StackdriverTraceKey        = "logging.googleapis.com/trace"
+	StackdriverSpanIDKey       = "logging.googleapis.com/spanId"
+	StackdriverTraceSampledKey = "logging.googleapis.com/trace_sampled"
+	StackdriverSourceKey       = "logging.googleapis.com/sourceLocation"
+)
+
+type stackdriverEncoder struct {
+	*jsonEncoder
+}
+
+// NewStackdriverEncoder creates a JSON encoder whose output follows the
+// structured logging format of Google Cloud Logging (formerly Stackdriver),
+// as ingested by the logging agents of GKE, Cloud Run, and Cloud Functions:
+//
+//	{
+//	  "severity": "ERROR",
+//	  "time": ...,
+//	  "logger": ...,
+//	  "message": ...,
+//	  "logging.googleapis.com/sourceLocation": {
+//	    "file": ..., "line": "42", "function": ...
+//	  },
+//	  ...
+//	  "stack_trace": ...
+//	}
+//
+// Levels are written as Cloud Logging severities: DebugLevel as "DEBUG",
+// InfoLevel as "INFO", WarnLevel as "WARNING", ErrorLevel as "ERROR",
+// DPanicLevel as "CRITICAL", PanicLevel as "ALERT", and FatalLevel as
+// "EMERGENCY". Stack traces are written under "stack_trace", where Error
+// Reporting looks for them.
+//
+// Since these field names are fixed, the keys in the supplied EncoderConfig
+// are only used to enable or disable each element, as with the ECS encoder.
+// The EncodeTime, EncodeName and EncodeDuration functions are honored;
+// EncodeLevel and EncodeCaller are not.
+//
+// Structured context is written as-is, in the same format as the JSON
+// encoder. See StackdriverTraceKey for the keys that associate entries with
+// traces.
+func NewStackdriverEncoder(cfg EncoderConfig) Encoder {
+	return stackdriverEncoder{newJSONEncoder(cfg, false)}
+}
+
+func (enc stackdriverEncoder) Clone() Encoder {
+	return stackdriverEncoder{enc.jsonEncoder.Clone().(*jsonEncoder)}
+}
+
+func (enc stackdriverEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	if enc.SortFields {
+		fields = sortFields(fields)
+	}
+
+	return enc.encodeFixedKeyEntry(fields, func(final *jsonEncoder) {
+		if final.LevelKey != "" {
+			final.AddString("severity", stackdriverSeverity(ent.Level))
+		}
+		if final.TimeKey != "" && !ent.Time.IsZero() {
+			final.AddTime("time", ent.Time)
+		}
+		if ent.LoggerName != "" && final.NameKey != "" {
+			final.addLoggerName("logger", ent.LoggerName)
+		}
+		if final.MessageKey != "" {
+			final.AddString("message", ent.Message)
+		}
+		if ent.Caller.Defined && (final.CallerKey != "" || final.FunctionKey != "") {
+			final.addKey(StackdriverSourceKey)
+			final.buf.AppendByte('{')
+			if final.CallerKey != "" {
+				final.AddString("file", ent.Caller.File)
+				final.AddString("line", strconv.Itoa(ent.Caller.Line))
+			}
+			if final.FunctionKey != "" && ent.Caller.Function != "" {
+				final.AddString("function", ent.Caller.Function)
+			}
+			final.buf.AppendByte('}')
+		}
+	}, func(final *jsonEncoder) {
+		if ent.Stack != "" && final.StacktraceKey != "" {
+			final.AddString("stack_trace", ent.Stack)
+		}
+	}), nil
+}
+
+func stackdriverSeverity(l Level) string {
+	switch l {
+	case DebugLevel:
+		return "DEBUG"
+	case InfoLevel:
+		return "INFO"
+	case WarnLevel:
+		return "WARNING"
+	case ErrorLevel:
+		return "ERROR"
+	case DPanicLevel:
+		return "CRITICAL"
+	case PanicLevel:
+		return "ALERT"
+	case FatalLevel:
+		return "EMERGENCY"
+	default:
+		return "DEFAULT"
+	}
+}
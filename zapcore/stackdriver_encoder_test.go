@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+)
+
+func TestStackdriverEncodeEntry(t *testing.T) {
+	enc := NewStackdriverEncoder(ecsTestEncoderConfig())
+	enc.AddString("service", "api")
+
+	buf, err := enc.EncodeEntry(_testEntry, []Field{
+		{Key: StackdriverTraceKey, Type: StringType, String: "projects/p/traces/abc"},
+		{Key: "count", Type: Int64Type, Integer: 3},
+	})
+	require.NoError(t, err, "Unexpected Stackdriver encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t,
+		`{"severity":"INFO","time":"1970-01-01T00:00:00Z","logger":"main","message":"hello",`+
+			`"logging.googleapis.com/sourceLocation":{"file":"foo.go","line":"42","function":"foo.Foo"},`+
+			`"service":"api","logging.googleapis.com/trace":"projects/p/traces/abc","count":3,`+
+			`"stack_trace":"fake-stack"}`+"\n",
+		buf.String(),
+		"Unexpected Stackdriver output.",
+	)
+
+	// Field names must match Cloud Logging's structured logging schema.
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "Stackdriver output must be valid JSON.")
+	for _, key := range []string{"severity", "time", "message", "stack_trace", StackdriverTraceKey} {
+		assert.Contains(t, decoded, key, "Missing special field %q.", key)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"file":     "foo.go",
+		"line":     "42",
+		"function": "foo.Foo",
+	}, decoded["logging.googleapis.com/sourceLocation"], "Unexpected source location.")
+}
+
+func TestStackdriverSeverities(t *testing.T) {
+	tests := []struct {
+		lvl  Level
+		want string
+	}{
+		{DebugLevel, "DEBUG"},
+		{InfoLevel, "INFO"},
+		{WarnLevel, "WARNING"},
+		{ErrorLevel, "ERROR"},
+		{DPanicLevel, "CRITICAL"},
+		{PanicLevel, "ALERT"},
+		{FatalLevel, "EMERGENCY"},
+		{Level(-42), "DEFAULT"},
+	}
+
+	enc := NewStackdriverEncoder(EncoderConfig{LevelKey: "severity"})
+	for _, tt := range tests {
+		buf, err := enc.EncodeEntry(Entry{Level: tt.lvl}, nil)
+		require.NoError(t, err, "Unexpected Stackdriver encoding error.")
+		assert.Equal(t, `{"severity":"`+tt.want+`"}`+"\n", buf.String(), "Unexpected severity for %v.", tt.lvl)
+		buf.Free()
+	}
+}
+
+func TestStackdriverEncoderOmitsDisabledElements(t *testing.T) {
+	tests := []struct {
+		desc     string
+		cfg      func(*EncoderConfig)
+		ent      Entry
+		expected string
+	}{
+		{
+			desc:     "no caller or stack",
+			cfg:      func(*EncoderConfig) {},
+			ent:      Entry{Level: WarnLevel, Time: _epoch, Message: "m"},
+			expected: `{"severity":"WARNING","time":"1970-01-01T00:00:00Z","message":"m"}` + "\n",
+		},
+		{
+			desc: "empty keys",
+			cfg: func(cfg *EncoderConfig) {
+				cfg.LevelKey = ""
+				cfg.TimeKey = ""
+				cfg.NameKey = ""
+				cfg.CallerKey = ""
+				cfg.FunctionKey = ""
+				cfg.StacktraceKey = ""
+			},
+			ent:      _testEntry,
+			expected: `{"message":"hello"}` + "\n",
+		},
+		{
+			desc: "function only",
+			cfg: func(cfg *EncoderConfig) {
+				cfg.CallerKey = ""
+			},
+			ent: Entry{Level: InfoLevel, Message: "m", Caller: _testEntry.Caller},
+			expected: `{"severity":"INFO","message":"m",` +
+				`"logging.googleapis.com/sourceLocation":{"function":"foo.Foo"}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := ecsTestEncoderConfig()
+			tt.cfg(&cfg)
+
+			buf, err := NewStackdriverEncoder(cfg).EncodeEntry(tt.ent, nil)
+			require.NoError(t, err, "Unexpected Stackdriver encoding error.")
+			defer buf.Free()
+
+			assert.Equal(t, tt.expected, buf.String(), "Unexpected Stackdriver output.")
+		})
+	}
+}
+
+func TestStackdriverEncoderClone(t *testing.T) {
+	parent := NewStackdriverEncoder(ecsTestEncoderConfig())
+	parent.AddString("parent", "p")
+
+	child := parent.Clone()
+	child.AddString("child", "c")
+
+	ent := Entry{Level: InfoLevel, Message: "m"}
+	parentBuf, err := parent.EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	defer parentBuf.Free()
+	childBuf, err := child.EncodeEntry(ent, nil)
+	require.NoError(t, err)
+	defer childBuf.Free()
+
+	assert.Contains(t, parentBuf.String(), `"parent":"p"}`, "Expected parent context.")
+	assert.NotContains(t, parentBuf.String(), "child", "Clone leaked context to parent.")
+	assert.Contains(t, childBuf.String(), `"parent":"p","child":"c"}`, "Expected inherited context.")
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import "sync/atomic"
+
+// SwappableCore is a Core whose underlying Core can be replaced while it's in
+// use, so that Loggers built on it pick up a new configuration without being
+// rebuilt or handed out again. Use NewSwappableCore to build one:
+//
+//	swappable := zapcore.NewSwappableCore(core)
+//	logger := zap.New(swappable)
+//	// ...
+//	old := swappable.Swap(newCore)
+//	_ = old.Sync()
+//
+// Cores derived from a SwappableCore with With share its underlying Core:
+// swapping any of them swaps it for all, and each applies its own fields to
+// the new Core. Options applied to a Logger that wrap its Core, such as
+// sampling, wrap the SwappableCore and so outlive swaps.
+//
+// Like any Core, With adds the fields to the current Core right away. Unlike
+// other Cores, though, a derived Core keeps the fields so that it can add
+// them to the Core swapped in later, which encodes them anew: fields that
+// refer to mutable values, such as those built with zap.Object, zap.Array,
+// or zap.Reflect, are logged by the new Core with the values they have when
+// the derived Core is first used after the swap, not when With was called.
+// Avoid such fields on Loggers built on a SwappableCore, or pass copies.
+//
+// Swapping is safe for concurrent use with logging. Each entry is checked
+// and written with a single Core, but an entry checked just before a swap
+// may be written to the old Core after it, so sync the old Core after
+// swapping, rather than closing its outputs straight away.
+type SwappableCore struct {
+	base   *atomic.Pointer[coreHolder] // shared by derived Cores
+	fields []Field
+	cache  atomic.Pointer[derivedCore] // base with fields, if any
+}
+
+type coreHolder struct {
+	Core
+}
+
+// derivedCore caches the result of adding fields to a base Core.
+type derivedCore struct {
+	base *coreHolder
+	core Core
+}
+
+var (
+	_ Core           = (*SwappableCore)(nil)
+	_ leveledEnabler = (*SwappableCore)(nil)
+)
+
+// NewSwappableCore builds a SwappableCore that initially writes to core. A
+// nil Core is replaced with a no-op Core.
+func NewSwappableCore(core Core) *SwappableCore {
+	c := &SwappableCore{base: new(atomic.Pointer[coreHolder])}
+	c.base.Store(newCoreHolder(core))
+	return c
+}
+
+func newCoreHolder(core Core) *coreHolder {
+	if core == nil {
+		core = NewNopCore()
+	}
+	return &coreHolder{core}
+}
+
+// Swap replaces the underlying Core and returns the one it replaced. A nil
+// Core is replaced with a no-op Core.
+func (c *SwappableCore) Swap(core Core) Core {
+	return c.base.Swap(newCoreHolder(core)).Core
+}
+
+// Current returns the Core that entries are currently written to, including
+// any fields added with With.
+func (c *SwappableCore) Current() Core {
+	return c.derive(c.base.Load())
+}
+
+// derive returns the Core h holds, with the fields added with With.
+func (c *SwappableCore) derive(h *coreHolder) Core {
+	if len(c.fields) == 0 {
+		return h.Core
+	}
+	if d := c.cache.Load(); d != nil && d.base == h {
+		return d.core
+	}
+	core := h.Core.With(c.fields)
+	c.cache.Store(&derivedCore{base: h, core: core})
+	return core
+}
+
+func (c *SwappableCore) Enabled(lvl Level) bool {
+	return c.Current().Enabled(lvl)
+}
+
+func (c *SwappableCore) Level() Level {
+	return LevelOf(c.Current())
+}
+
+// Describe describes the current Core. See DescribeCores.
+func (c *SwappableCore) Describe() []CoreInfo {
+	return DescribeCores(c.Current())
+}
+
+func (c *SwappableCore) With(fields []Field) Core {
+	all := make([]Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+	clone := &SwappableCore{base: c.base, fields: all}
+
+	// Add the fields to the current Core now, as With promises.
+	h := c.base.Load()
+	clone.cache.Store(&derivedCore{base: h, core: c.derive(h).With(fields)})
+	return clone
+}
+
+func (c *SwappableCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	return c.Current().Check(ent, ce)
+}
+
+func (c *SwappableCore) Write(ent Entry, fields []Field) error {
+	return c.Current().Write(ent, fields)
+}
+
+// Sync syncs the current Core. Cores that have been swapped out aren't
+// synced.
+func (c *SwappableCore) Sync() error {
+	return c.Current().Sync()
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwappableCore(t *testing.T) {
+	first, firstLogs := observer.New(InfoLevel)
+	second, secondLogs := observer.New(DebugLevel)
+
+	core := NewSwappableCore(first)
+	child := core.With([]Field{makeInt64Field("k", 1)})
+	assert.Equal(t, InfoLevel, LevelOf(child), "Unexpected level.")
+
+	writeEntry(child, DebugLevel)
+	writeEntry(child, InfoLevel)
+
+	old := core.Swap(second)
+	assert.Equal(t, first, old, "Expected Swap to return the replaced core.")
+	assert.Equal(t, DebugLevel, LevelOf(child), "Expected child to follow the swap.")
+
+	writeEntry(child, DebugLevel)
+	writeEntry(core, InfoLevel)
+
+	require.Equal(t, 1, firstLogs.Len(), "Unexpected entries in first core.")
+	assert.Equal(t, map[string]interface{}{"k": int64(1)}, firstLogs.AllUntimed()[0].ContextMap(), "Unexpected context.")
+
+	entries := secondLogs.AllUntimed()
+	require.Len(t, entries, 2, "Unexpected entries in second core.")
+	assert.Equal(t, map[string]interface{}{"k": int64(1)}, entries[0].ContextMap(), "Expected child's fields on the new core.")
+	assert.Empty(t, entries[1].Context, "Unexpected context on parent's entry.")
+
+	assert.Equal(t, DescribeCores(second), DescribeCores(core), "Expected description of the current core.")
+	assert.NoError(t, child.Sync(), "Unexpected sync error.")
+}
+
+func TestSwappableCoreConcurrentSwaps(t *testing.T) {
+	a, aLogs := observer.New(DebugLevel)
+	b, bLogs := observer.New(DebugLevel)
+	core := NewSwappableCore(a)
+
+	const goroutines, perGoroutine = 8, 500
+	var (
+		wg   sync.WaitGroup
+		stop atomic.Bool
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child := core.With([]Field{makeInt64Field("goroutine", i)})
+			for j := 0; j < perGoroutine; j++ {
+				writeEntry(child, InfoLevel)
+			}
+		}(i)
+	}
+
+	swaps := make(chan struct{})
+	go func() {
+		defer close(swaps)
+		cores := []Core{a, b}
+		for i := 0; !stop.Load(); i++ {
+			core.Swap(cores[i%2])
+		}
+	}()
+
+	wg.Wait()
+	stop.Store(true)
+	<-swaps
+
+	assert.Equal(t, goroutines*perGoroutine, aLogs.Len()+bLogs.Len(), "Expected every entry to be written exactly once.")
+	for _, e := range append(aLogs.AllUntimed(), bLogs.AllUntimed()...) {
+		assert.Len(t, e.Context, 1, "Expected each entry to carry its child's field once.")
+	}
+}
+
+func TestSwappableCoreWithEncodesFieldsEagerly(t *testing.T) {
+	first, second := &ztest.Buffer{}, &ztest.Buffer{}
+	newCore := func(ws WriteSyncer) Core {
+		return NewCore(NewJSONEncoder(EncoderConfig{MessageKey: "msg"}), ws, InfoLevel)
+	}
+
+	n := int64(1)
+	obj := ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+		enc.AddInt64("n", n)
+		return nil
+	})
+	core := NewSwappableCore(newCore(first))
+	child := core.With([]Field{{Key: "o", Type: ObjectMarshalerType, Interface: obj}})
+
+	n = 2
+	writeEntry(child, InfoLevel)
+	assert.Equal(t, []string{`{"msg":"","o":{"n":1}}`}, first.Lines(),
+		"Expected fields to be encoded when With is called.")
+
+	// The Core swapped in encodes the fields anew, as documented.
+	core.Swap(newCore(second))
+	writeEntry(child, InfoLevel)
+	assert.Equal(t, []string{`{"msg":"","o":{"n":2}}`}, second.Lines(),
+		"Expected the new Core to encode the fields after the swap.")
+}
+
+func TestSwappableCoreNil(t *testing.T) {
+	core := NewSwappableCore(nil)
+	assert.False(t, core.Enabled(FatalLevel), "Expected a nil Core to be replaced with a no-op Core.")
+	assert.Nil(t, core.Check(Entry{Level: FatalLevel}, nil), "Expected no-op Core to drop entries.")
+
+	obs, _ := observer.New(InfoLevel)
+	core.Swap(obs)
+	assert.Equal(t, obs, core.Swap(nil), "Expected Swap to return the replaced core.")
+	assert.False(t, core.With([]Field{makeInt64Field("k", 1)}).Enabled(FatalLevel),
+		"Expected a nil Core to be replaced with a no-op Core.")
+}
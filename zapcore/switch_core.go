@@ -0,0 +1,174 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"sort"
+
+	"go.uber.org/multierr"
+)
+
+type switchCore struct {
+	key   string
+	cases map[string]int // value to index in cores
+	cores multiCore      // the cases, in order of value, then the default
+
+	// The value of the key field added with With, if any, and whether With
+	// opened a namespace, after which fields can't carry the key.
+	value  string
+	bound  bool
+	nested bool
+}
+
+var (
+	_ Core           = (*switchCore)(nil)
+	_ leveledEnabler = (*switchCore)(nil)
+)
+
+// NewSwitchCore creates a Core that dispatches each entry to one of several
+// Cores based on the value of one of its fields. For example, entries with
+// a "component" field of "auth" can be routed to a Core with its own encoder
+// and output:
+//
+//	core := zapcore.NewSwitchCore("component", map[string]zapcore.Core{
+//		"auth": authCore,
+//	}, defaultCore)
+//
+// The entry is written to the Core in cases whose value matches the string
+// field with the given key, looking first at the fields
+// logged with the entry and then at those added with With. Entries without
+// such a field, or whose value has no case, are written to def; a nil def
+// drops them. Fields nested in a namespace don't take part in routing.
+//
+// Since the route is only known once the entry's fields are, the Core is
+// enabled at a level if any case or the default is, and the chosen Core
+// decides whether to write the entry when it's written. Any CheckWriteHook
+// that the chosen Core registers is ignored.
+func NewSwitchCore(key string, cases map[string]Core, def Core) Core {
+	if def == nil {
+		def = NewNopCore()
+	}
+	values := make([]string, 0, len(cases))
+	for v := range cases {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	c := &switchCore{
+		key:   key,
+		cases: make(map[string]int, len(cases)),
+		cores: make(multiCore, 0, len(cases)+1),
+	}
+	for i, v := range values {
+		c.cases[v] = i
+		c.cores = append(c.cores, cases[v])
+	}
+	c.cores = append(c.cores, def)
+	return c
+}
+
+func (c *switchCore) Level() Level {
+	return c.cores.Level()
+}
+
+func (c *switchCore) Enabled(lvl Level) bool {
+	return c.cores.Enabled(lvl)
+}
+
+func (c *switchCore) Describe() []CoreInfo {
+	return DescribeCores(c.cores)
+}
+
+func (c *switchCore) With(fields []Field) Core {
+	clone := *c
+	clone.cores = c.cores.With(fields).(multiCore)
+	if !c.nested {
+		if v, ok := c.find(fields); ok {
+			clone.value, clone.bound = v, true
+		}
+		for _, f := range fields {
+			if f.Type == NamespaceType {
+				clone.nested = true
+				break
+			}
+		}
+	}
+	return &clone
+}
+
+func (c *switchCore) Check(ent Entry, ce *CheckedEntry) *CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write routes the entry and writes it to the Cores that the chosen case
+// accepts it for.
+func (c *switchCore) Write(ent Entry, fields []Field) error {
+	core := c.route(fields)
+	sub := core.Check(ent, nil)
+	if sub == nil {
+		return nil
+	}
+	defer putCheckedEntry(sub)
+
+	var err error
+	for _, cc := range sub.cores {
+		err = multierr.Append(err, cc.Write(sub.Entry, fields))
+	}
+	return err
+}
+
+func (c *switchCore) Sync() error {
+	return c.cores.Sync()
+}
+
+// route returns the Core for an entry logged with the given fields.
+func (c *switchCore) route(fields []Field) Core {
+	v, ok := "", false
+	if !c.nested {
+		v, ok = c.find(fields)
+	}
+	if !ok {
+		v, ok = c.value, c.bound
+	}
+	if ok {
+		if i, ok := c.cases[v]; ok {
+			return c.cores[i]
+		}
+	}
+	return c.cores[len(c.cores)-1]
+}
+
+// find returns the value of the last field with the routing key, ignoring
+// fields nested in a namespace.
+func (c *switchCore) find(fields []Field) (value string, ok bool) {
+	for _, f := range fields {
+		if f.Type == NamespaceType {
+			break
+		}
+		if f.Key == c.key && f.Type == StringType {
+			value, ok = f.String, true
+		}
+	}
+	return value, ok
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"errors"
+	"testing"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeStringField(key, val string) Field {
+	return Field{Key: key, Type: StringType, String: val}
+}
+
+func TestSwitchCore(t *testing.T) {
+	auth, authLogs := observer.New(InfoLevel)
+	billing, billingLogs := observer.New(InfoLevel)
+	def, defLogs := observer.New(InfoLevel)
+	core := NewSwitchCore("component", map[string]Core{
+		"auth":    auth,
+		"billing": billing,
+	}, def)
+
+	writeEntryWith(core, "login", makeStringField("component", "auth"))
+	writeEntryWith(core, "charge", makeStringField("component", "billing"), makeInt64Field("cents", 100))
+	writeEntryWith(core, "unknown", makeStringField("component", "search"))
+	writeEntryWith(core, "missing")
+	writeEntryWith(core, "not a string", makeInt64Field("component", 1))
+
+	assert.Equal(t, []string{"login"}, messages(authLogs), "Unexpected entries routed to auth.")
+	assert.Equal(t, []string{"charge"}, messages(billingLogs), "Unexpected entries routed to billing.")
+	assert.Equal(t, []string{"unknown", "missing", "not a string"}, messages(defLogs), "Unexpected entries routed to the default.")
+	assert.Equal(t, map[string]interface{}{"component": "billing", "cents": int64(100)},
+		billingLogs.AllUntimed()[0].ContextMap(), "Expected fields to be written to the chosen core.")
+}
+
+func TestSwitchCoreWith(t *testing.T) {
+	auth, authLogs := observer.New(InfoLevel)
+	def, defLogs := observer.New(InfoLevel)
+	core := NewSwitchCore("component", map[string]Core{"auth": auth}, def)
+
+	child := core.With([]Field{makeStringField("component", "auth")})
+	writeEntryWith(child, "bound")
+	writeEntryWith(child, "overridden", makeStringField("component", "other"))
+	writeEntryWith(core, "parent")
+
+	nested := core.With([]Field{{Key: "http", Type: NamespaceType}})
+	writeEntryWith(nested, "nested", makeStringField("component", "auth"))
+
+	assert.Equal(t, []string{"bound"}, messages(authLogs), "Expected the With field to route entries.")
+	assert.Equal(t, []string{"overridden", "parent", "nested"}, messages(defLogs), "Unexpected entries routed to the default.")
+	assert.Equal(t, map[string]interface{}{"component": "auth"},
+		authLogs.AllUntimed()[0].ContextMap(), "Expected With fields on the chosen core.")
+}
+
+func TestSwitchCoreLevels(t *testing.T) {
+	auth, authLogs := observer.New(DebugLevel)
+	def, defLogs := observer.New(WarnLevel)
+	core := NewSwitchCore("component", map[string]Core{"auth": auth}, def)
+
+	assert.Equal(t, DebugLevel, LevelOf(core), "Expected the lowest level of any case.")
+	assert.True(t, core.Enabled(DebugLevel), "Expected enabled if any case is.")
+
+	for _, msg := range []string{"auth debug", "default debug"} {
+		ent := Entry{Level: DebugLevel, Message: msg}
+		ce := core.Check(ent, nil)
+		require.NotNil(t, ce, "Expected the entry to be checked.")
+		if msg == "auth debug" {
+			ce.Write(makeStringField("component", "auth"))
+		} else {
+			ce.Write()
+		}
+	}
+	assert.Equal(t, []string{"auth debug"}, messages(authLogs), "Unexpected auth entries.")
+	assert.Empty(t, defLogs.AllUntimed(), "The default core should still drop entries below its level.")
+
+	assert.Nil(t, NewSwitchCore("k", nil, nil).Check(Entry{Level: FatalLevel}, nil),
+		"Expected a nil default to drop entries.")
+}
+
+func TestSwitchCoreSyncAndDescribe(t *testing.T) {
+	errAuth := errors.New("auth sync failed")
+	auth := &syncErrorCore{Core: NewNopCore(), err: errAuth}
+	def, _ := observer.New(InfoLevel)
+	core := NewSwitchCore("component", map[string]Core{"auth": auth}, def)
+
+	assert.ErrorIs(t, core.Sync(), errAuth, "Expected errors from every core.")
+	assert.Len(t, DescribeCores(core), 2, "Expected the cases and default to be described.")
+}
+
+type syncErrorCore struct {
+	Core
+	err error
+}
+
+func (c *syncErrorCore) Sync() error {
+	return c.err
+}
@@ -45,6 +45,14 @@ func NewTee(cores ...Core) Core {
 	}
 }
 
+func (mc multiCore) Describe() []CoreInfo {
+	var infos []CoreInfo
+	for _, c := range mc {
+		infos = append(infos, DescribeCores(c)...)
+	}
+	return infos
+}
+
 func (mc multiCore) With(fields []Field) Core {
 	clone := make(multiCore, len(mc))
 	for i := range mc {
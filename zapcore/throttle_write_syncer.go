@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"strconv"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+// A RateLimiter decides whether an event may happen now. It's satisfied by
+// *rate.Limiter from golang.org/x/time/rate.
+type RateLimiter interface {
+	Allow() bool
+}
+
+type throttleSyncer struct {
+	ws      WriteSyncer
+	limiter RateLimiter
+
+	mu      sync.Mutex
+	dropped uint64 // lines dropped since the last note
+}
+
+// NewThrottleSyncer wraps a WriteSyncer so that lines are only written while
+// limiter allows them; the rest are dropped. This caps the output of, for
+// example, many goroutines logging the same error in a retry loop, no matter
+// which encoder or level produced the lines.
+//
+// Dropped lines are reported by a note such as "(dropped 42)", written on a
+// line of its own just before the next line the limiter allows, or when the
+// syncer is synced. Notes don't count towards the limit.
+//
+// Each line is checked against the limiter, so lines written by a Tee's
+// members are limited separately only if each member has its own syncer.
+func NewThrottleSyncer(ws WriteSyncer, limiter RateLimiter) WriteSyncer {
+	return &throttleSyncer{ws: ws, limiter: limiter}
+}
+
+// Write writes bs if the limiter allows it, and otherwise drops it. Dropped
+// lines are reported as written, since they were handled as intended.
+func (s *throttleSyncer) Write(bs []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.limiter.Allow() {
+		s.dropped++
+		return len(bs), nil
+	}
+	if err := s.writeNote(); err != nil {
+		return 0, err
+	}
+	return s.ws.Write(bs)
+}
+
+// Sync reports any dropped lines and syncs the underlying WriteSyncer.
+func (s *throttleSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return multierr.Append(s.writeNote(), s.ws.Sync())
+}
+
+func (s *throttleSyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
+
+// writeNote reports the lines dropped since the last note, if any. It must be
+// called with mu held.
+func (s *throttleSyncer) writeNote() error {
+	if s.dropped == 0 {
+		return nil
+	}
+	note := make([]byte, 0, 32)
+	note = append(note, "(dropped "...)
+	note = strconv.AppendUint(note, s.dropped, 10)
+	note = append(note, ")\n"...)
+	s.dropped = 0
+	_, err := s.ws.Write(note)
+	return err
+}
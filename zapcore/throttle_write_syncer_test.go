@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenLimiter allows a fixed number of events in total.
+type tokenLimiter struct {
+	tokens atomic.Int64
+}
+
+func newTokenLimiter(n int64) *tokenLimiter {
+	l := &tokenLimiter{}
+	l.tokens.Store(n)
+	return l
+}
+
+func (l *tokenLimiter) Allow() bool {
+	return l.tokens.Add(-1) >= 0
+}
+
+func TestThrottleSyncer(t *testing.T) {
+	sink := &ztest.Buffer{}
+	limiter := newTokenLimiter(2)
+	ws := NewThrottleSyncer(sink, limiter)
+
+	for _, line := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		n, err := ws.Write([]byte(line))
+		require.NoError(t, err, "Unexpected write error.")
+		assert.Equal(t, len(line), n, "Unexpected number of bytes written.")
+	}
+	assert.Equal(t, []string{"a", "b"}, sink.Lines(), "Expected lines beyond the limit to be dropped.")
+
+	limiter.tokens.Store(1)
+	_, err := ws.Write([]byte("e\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	_, err = ws.Write([]byte("f\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	assert.Equal(t, []string{"a", "b", "(dropped 2)", "e"}, sink.Lines(), "Expected a note before the next allowed line.")
+
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.Equal(t, []string{"a", "b", "(dropped 2)", "e", "(dropped 1)"}, sink.Lines(), "Expected a single note on sync.")
+	assert.True(t, sink.Called(), "Expected underlying syncer to be synced.")
+}
+
+func TestThrottleSyncerConcurrent(t *testing.T) {
+	const (
+		goroutines = 10
+		perG       = 100
+		allowed    = 50
+	)
+	sink := &ztest.Buffer{}
+	ws := NewThrottleSyncer(Lock(sink), newTokenLimiter(allowed))
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				_, err := ws.Write([]byte("same error\n"))
+				assert.NoError(t, err, "Unexpected write error.")
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+
+	noteRE := regexp.MustCompile(`^\(dropped (\d+)\)$`)
+	var written, dropped int
+	for _, line := range sink.Lines() {
+		if m := noteRE.FindStringSubmatch(line); m != nil {
+			n, err := strconv.Atoi(m[1])
+			require.NoError(t, err, "Unexpected note.")
+			dropped += n
+			continue
+		}
+		assert.Equal(t, "same error", line, "Unexpected line.")
+		written++
+	}
+	assert.Equal(t, allowed, written, "Expected the rate limit to be honored.")
+	assert.Equal(t, goroutines*perG-allowed, dropped, "Expected every dropped line to be reported.")
+}
+
+func TestThrottleSyncerWriteError(t *testing.T) {
+	ws := NewThrottleSyncer(&ztest.FailWriter{}, newTokenLimiter(0))
+	_, err := ws.Write([]byte("dropped\n"))
+	require.NoError(t, err, "Dropped writes shouldn't fail.")
+	assert.Error(t, ws.Sync(), "Expected error writing the note.")
+}
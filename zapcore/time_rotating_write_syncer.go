@@ -0,0 +1,287 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// TimeRotatingOption configures a TimeRotatingSyncer.
+type TimeRotatingOption interface {
+	apply(*TimeRotatingSyncer)
+}
+
+type timeRotatingOptionFunc func(*TimeRotatingSyncer)
+
+func (f timeRotatingOptionFunc) apply(s *TimeRotatingSyncer) {
+	f(s)
+}
+
+// TimeRotatingClock sets the source of time used to decide which file a
+// write belongs to. Defaults to the system clock.
+func TimeRotatingClock(clock Clock) TimeRotatingOption {
+	return timeRotatingOptionFunc(func(s *TimeRotatingSyncer) {
+		s.clock = clock
+	})
+}
+
+// TimeRotatingFooter adds a function whose output is appended to each file
+// just before the syncer closes it, either because its period ended or
+// because Close was called. Footers can carry a checksum or record count to
+// help verify rotated files. The function is called exactly once per closed
+// file, under the syncer's lock, so it may safely reset any per-file state
+// it keeps. Sync doesn't write a footer, and a nil or empty footer writes
+// nothing. Footers added more than once, including by zap.WithFileFooter,
+// are written in the order they're added.
+//
+// Since a file reopened after Close is appended to, it gets one footer for
+// each time it's closed.
+func TimeRotatingFooter(footer func() []byte) TimeRotatingOption {
+	return timeRotatingOptionFunc(func(s *TimeRotatingSyncer) {
+		s.footers = append(s.footers, footer)
+	})
+}
+
+// TimeRotatingSyncer is a WriteSyncer that writes to a new file every
+// rotation period, naming each file after the start of its period. Use
+// NewTimeRotatingSyncer to build one.
+type TimeRotatingSyncer struct {
+	pattern string
+	rotate  time.Duration
+	clock   Clock
+
+	mu      sync.Mutex
+	file    *os.File
+	name    string    // name of file
+	period  time.Time // start of the period file belongs to
+	footers []func() []byte
+	onClose []func(name string)
+}
+
+var _ WriteSyncer = (*TimeRotatingSyncer)(nil)
+
+// NewTimeRotatingSyncer builds a WriteSyncer that opens a new file at the
+// start of every rotation period, for example hourly or daily. This is
+// useful for producing predictably named files for consumption by external
+// tooling; unlike size-based rotation, the name of the file holding an entry
+// depends only on when it was written.
+//
+// The file name is derived from pattern by replacing the following
+// strftime-like tokens with the start time of the current period:
+//
+//	%Y  four-digit year
+//	%m  two-digit month (01-12)
+//	%d  two-digit day of month (01-31)
+//	%H  two-digit hour (00-23)
+//	%M  two-digit minute (00-59)
+//	%S  two-digit second (00-59)
+//	%%  a literal '%'
+//
+// For example, "/var/log/app-%Y-%m-%d.log" with a rotation period of 24 hours
+// produces one file per day. Missing parent directories are created.
+//
+// Periods are aligned to the local wall clock of the times reported by the
+// clock, so a daily period starts at midnight. Files are opened lazily and
+// appended to if they already exist. The file for a period is chosen when
+// each write happens, so a process that is idle across a boundary still
+// writes its next entry to the correctly named file.
+//
+// When a period ends, the previous file is synced to stable storage and
+// closed. Rotation happens under a lock, so each write lands entirely in a
+// single file. Call Close to release the current file.
+func NewTimeRotatingSyncer(pattern string, rotate time.Duration, opts ...TimeRotatingOption) *TimeRotatingSyncer {
+	s := &TimeRotatingSyncer{
+		pattern: pattern,
+		rotate:  rotate,
+		clock:   DefaultClock,
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	return s
+}
+
+// Write writes bs to the file for the current period, rotating first if the
+// period has changed since the last write.
+func (s *TimeRotatingSyncer) Write(bs []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	period := s.periodOf(s.clock.Now())
+	if s.file == nil || !period.Equal(s.period) {
+		if err := s.rotateTo(period); err != nil {
+			return 0, err
+		}
+	}
+	return s.file.Write(bs)
+}
+
+// Sync flushes the current file to stable storage.
+func (s *TimeRotatingSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Sync()
+}
+
+// Describe describes the syncer by its file name pattern. See
+// DescribeWriteSyncer.
+func (s *TimeRotatingSyncer) Describe() string {
+	return "time-rotating " + s.pattern
+}
+
+// Close syncs and closes the current file. A subsequent write reopens the
+// file for its period.
+func (s *TimeRotatingSyncer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeFile()
+}
+
+// OnClose registers a function that's called with a file's name after the
+// syncer closes it, either because its period ended or because Close was
+// called. This lets other code process completed files, for example by
+// compressing or uploading them. Functions are called in the order they're
+// registered, under the syncer's lock, so they must not block or use the
+// syncer; hand slow work off to another goroutine.
+//
+// A file reopened after Close is reported each time it's closed.
+func (s *TimeRotatingSyncer) OnClose(fn func(name string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onClose = append(s.onClose, fn)
+}
+
+// addFooter adds a footer after the syncer is built; see NewFooterCore.
+func (s *TimeRotatingSyncer) addFooter(footer func() []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.footers = append(s.footers, footer)
+}
+
+// periodOf returns the start of the rotation period containing t.
+func (s *TimeRotatingSyncer) periodOf(t time.Time) time.Time {
+	if s.rotate <= 0 {
+		return time.Time{}
+	}
+	// Time.Truncate works on absolute time, which would align periods to
+	// UTC. Shift by the zone offset so that periods align with wall clock.
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(s.rotate).Add(-shift)
+}
+
+// rotateTo opens the file for the given period and only then closes the
+// previous one, so that a failed open leaves the previous file in place.
+func (s *TimeRotatingSyncer) rotateTo(period time.Time) error {
+	name := formatTimePattern(s.pattern, period)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = s.closeFile()
+	s.file = f
+	s.name = name
+	s.period = period
+	return err
+}
+
+func (s *TimeRotatingSyncer) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	f := s.file
+	s.file = nil
+
+	var err error
+	for _, footer := range s.footers {
+		if footer == nil {
+			continue
+		}
+		if bs := footer(); len(bs) > 0 {
+			_, werr := f.Write(bs)
+			err = multierr.Append(err, werr)
+		}
+	}
+	err = multierr.Combine(err, f.Sync(), f.Close())
+	for _, fn := range s.onClose {
+		fn(s.name)
+	}
+	return err
+}
+
+// formatTimePattern expands the strftime-like tokens supported by
+// NewTimeRotatingSyncer. Unknown tokens are left as-is.
+func formatTimePattern(pattern string, t time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '%' || i+1 == len(pattern) {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		switch pattern[i] {
+		case 'Y':
+			sb.WriteString(strconv.Itoa(t.Year()))
+		case 'm':
+			writeTwoDigits(&sb, int(t.Month()))
+		case 'd':
+			writeTwoDigits(&sb, t.Day())
+		case 'H':
+			writeTwoDigits(&sb, t.Hour())
+		case 'M':
+			writeTwoDigits(&sb, t.Minute())
+		case 'S':
+			writeTwoDigits(&sb, t.Second())
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(pattern[i])
+		}
+	}
+	return sb.String()
+}
+
+func writeTwoDigits(sb *strings.Builder, n int) {
+	if n < 10 {
+		sb.WriteByte('0')
+	}
+	sb.WriteString(strconv.Itoa(n))
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/zap/internal/ztest"
+)
+
+func TestFormatTimePattern(t *testing.T) {
+	ts := time.Date(2024, time.March, 7, 5, 4, 9, 0, time.UTC)
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"app.log", "app.log"},
+		{"app-%Y-%m-%d.log", "app-2024-03-07.log"},
+		{"%Y/%m/%d/%H%M%S.log", "2024/03/07/050409.log"},
+		{"100%%-%Y", "100%-2024"},
+		{"%q-%Y%", "%q-2024%"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatTimePattern(tt.pattern, ts), "Unexpected expansion of %q.", tt.pattern)
+	}
+}
+
+func TestTimeRotatingSyncer(t *testing.T) {
+	dir := t.TempDir()
+	clock := ztest.NewMockClock()
+	// Move to just before the top of an hour.
+	now := clock.Now()
+	clock.Add(now.Truncate(time.Hour).Add(time.Hour - time.Second).Sub(now))
+	first := clock.Now().Truncate(time.Hour)
+
+	ws := NewTimeRotatingSyncer(
+		filepath.Join(dir, "%Y-%m-%d", "app-%H.log"),
+		time.Hour,
+		TimeRotatingClock(clock),
+	)
+	defer func() { assert.NoError(t, ws.Close(), "Unexpected error closing syncer.") }()
+
+	write := func(s string) {
+		_, err := ws.Write([]byte(s))
+		require.NoError(t, err, "Unexpected write error.")
+	}
+	fileFor := func(ts time.Time) string {
+		return filepath.Join(dir, ts.Format("2006-01-02"), ts.Format("app-15.log"))
+	}
+	read := func(ts time.Time) string {
+		bs, err := os.ReadFile(fileFor(ts))
+		require.NoError(t, err, "Failed to read log file.")
+		return string(bs)
+	}
+
+	write("a\n")
+	write("b\n")
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.Equal(t, "a\nb\n", read(first), "Unexpected contents before boundary.")
+
+	clock.Add(time.Second)
+	write("c\n")
+	assert.Equal(t, "a\nb\n", read(first), "Expected previous file to be left alone.")
+	assert.Equal(t, "c\n", read(first.Add(time.Hour)), "Expected write to open a new file.")
+
+	// Idle across several boundaries: the next write must still land in the
+	// file for the current period.
+	clock.Add(5*time.Hour + 30*time.Minute)
+	write("d\n")
+	assert.Equal(t, "d\n", read(first.Add(6*time.Hour)), "Unexpected file after idling.")
+	_, err := os.Stat(fileFor(first.Add(2 * time.Hour)))
+	assert.True(t, os.IsNotExist(err), "Expected no files for idle periods.")
+}
+
+func TestTimeRotatingSyncerAppendsAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	clock := ztest.NewMockClock()
+	ws := NewTimeRotatingSyncer(filepath.Join(dir, "app.log"), 24*time.Hour, TimeRotatingClock(clock))
+
+	assert.NoError(t, ws.Sync(), "Expected no-op sync before any writes.")
+	_, err := ws.Write([]byte("a\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+
+	_, err = ws.Write([]byte("b\n"))
+	require.NoError(t, err, "Unexpected write error after close.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+
+	bs, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err, "Failed to read log file.")
+	assert.Equal(t, "a\nb\n", string(bs), "Expected existing file to be appended to.")
+}
+
+func TestTimeRotatingSyncerFooter(t *testing.T) {
+	dir := t.TempDir()
+	clock := ztest.NewMockClock()
+	now := clock.Now()
+	clock.Add(now.Truncate(time.Hour).Add(time.Hour - time.Second).Sub(now))
+	first := clock.Now().Truncate(time.Hour)
+
+	var records int
+	ws := NewTimeRotatingSyncer(
+		filepath.Join(dir, "app-%H.log"),
+		time.Hour,
+		TimeRotatingClock(clock),
+		TimeRotatingFooter(func() []byte {
+			footer := "# records: " + strconv.Itoa(records) + "\n"
+			records = 0
+			return []byte(footer)
+		}),
+	)
+
+	write := func(s string) {
+		_, err := ws.Write([]byte(s))
+		require.NoError(t, err, "Unexpected write error.")
+		records++
+	}
+	read := func(ts time.Time) string {
+		bs, err := os.ReadFile(filepath.Join(dir, ts.Format("app-15.log")))
+		require.NoError(t, err, "Failed to read log file.")
+		return string(bs)
+	}
+
+	write("a\n")
+	write("b\n")
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.Equal(t, "a\nb\n", read(first), "Sync must not write a footer.")
+
+	clock.Add(time.Second)
+	write("c\n")
+	assert.Equal(t, "a\nb\n# records: 2\n", read(first), "Expected footer when rotating.")
+
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer twice.")
+	assert.Equal(t, "c\n# records: 1\n", read(first.Add(time.Hour)), "Expected exactly one footer on close.")
+}
+
+func TestTimeRotatingSyncerOnClose(t *testing.T) {
+	dir := t.TempDir()
+	clock := ztest.NewMockClock()
+	ws := NewTimeRotatingSyncer(filepath.Join(dir, "app-%Y%m%d%H%M%S.log"), time.Minute, TimeRotatingClock(clock))
+
+	var closed []string
+	ws.OnClose(func(name string) {
+		_, err := os.Stat(name)
+		assert.NoError(t, err, "Expected closed file to exist.")
+		closed = append(closed, filepath.Base(name))
+	})
+
+	first := ws.periodOf(clock.Now())
+	_, err := ws.Write([]byte("a\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	assert.Empty(t, closed, "Unexpected close before rotation.")
+
+	clock.Add(time.Minute)
+	second := ws.periodOf(clock.Now())
+	_, err = ws.Write([]byte("b\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer twice.")
+
+	assert.Equal(t, []string{
+		first.Format("app-20060102150405.log"),
+		second.Format("app-20060102150405.log"),
+	}, closed, "Expected each closed file to be reported once.")
+}
+
+func TestTimeRotatingSyncerFooterEmpty(t *testing.T) {
+	dir := t.TempDir()
+	ws := NewTimeRotatingSyncer(filepath.Join(dir, "app.log"), time.Hour, TimeRotatingFooter(func() []byte { return nil }))
+	_, err := ws.Write([]byte("a\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	require.NoError(t, ws.Close(), "Unexpected error closing syncer.")
+
+	bs, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err, "Failed to read log file.")
+	assert.Equal(t, "a\n", string(bs), "Expected empty footer to write nothing.")
+}
+
+func TestTimeRotatingSyncerOpenFailure(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644), "Failed to create file.")
+
+	ws := NewTimeRotatingSyncer(filepath.Join(blocker, "app.log"), time.Hour)
+	_, err := ws.Write([]byte("a\n"))
+	assert.Error(t, err, "Expected error when the directory can't be created.")
+}
+
+func TestTimeRotatingSyncerPeriodAlignment(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	s := NewTimeRotatingSyncer("", 24*time.Hour)
+	ts := time.Date(2024, time.March, 7, 1, 30, 0, 0, loc)
+	assert.Equal(t,
+		time.Date(2024, time.March, 7, 0, 0, 0, 0, loc),
+		s.periodOf(ts),
+		"Expected daily periods to start at local midnight.",
+	)
+}
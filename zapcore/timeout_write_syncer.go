@@ -0,0 +1,104 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"errors"
+	"time"
+)
+
+var errSyncerTimeout = errors.New("write syncer timed out")
+
+type timeoutSyncer struct {
+	ws      WriteSyncer
+	timeout time.Duration
+
+	// slot holds a token while a call to ws is in flight. Calls are
+	// serialized, so a hung call strands at most one goroutine.
+	slot chan struct{}
+}
+
+type timeoutResult struct {
+	n   int
+	err error
+}
+
+// NewTimeoutSyncer wraps a WriteSyncer so that no call to Write or Sync
+// blocks for longer than timeout, for example when writing to a network
+// destination that may hang. Each call to ws runs on its own goroutine; if it
+// doesn't finish in time, the call returns an error and the entry is dropped.
+//
+// Calls to ws are serialized, and the timeout includes time spent waiting for
+// earlier calls to finish. While a call to ws is hung, later calls wait for it
+// and time out too rather than starting more goroutines, so repeated timeouts
+// leave at most one goroutine blocked in ws. A timed-out write may still
+// complete later, after its caller has moved on.
+func NewTimeoutSyncer(ws WriteSyncer, timeout time.Duration) WriteSyncer {
+	return &timeoutSyncer{
+		ws:      ws,
+		timeout: timeout,
+		slot:    make(chan struct{}, 1),
+	}
+}
+
+// Write writes a copy of bs, since bs may be reused after a timeout.
+func (s *timeoutSyncer) Write(bs []byte) (int, error) {
+	line := append([]byte(nil), bs...)
+	return s.run(func() (int, error) {
+		return s.ws.Write(line)
+	})
+}
+
+func (s *timeoutSyncer) Sync() error {
+	_, err := s.run(func() (int, error) {
+		return 0, s.ws.Sync()
+	})
+	return err
+}
+
+func (s *timeoutSyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
+
+func (s *timeoutSyncer) run(call func() (int, error)) (int, error) {
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	select {
+	case s.slot <- struct{}{}:
+	case <-timer.C:
+		return 0, errSyncerTimeout
+	}
+
+	done := make(chan timeoutResult, 1)
+	go func() {
+		defer func() { <-s.slot }()
+		n, err := call()
+		done <- timeoutResult{n, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-timer.C:
+		return 0, errSyncerTimeout
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/internal/ztest"
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutSyncer(t *testing.T) {
+	sink := &ztest.Buffer{}
+	ws := NewTimeoutSyncer(sink, time.Second)
+
+	n, err := ws.Write([]byte("a\n"))
+	require.NoError(t, err, "Unexpected write error.")
+	assert.Equal(t, 2, n, "Unexpected number of bytes written.")
+	require.NoError(t, ws.Sync(), "Unexpected sync error.")
+	assert.Equal(t, "a\n", sink.String(), "Unexpected output.")
+	assert.True(t, sink.Called(), "Expected underlying syncer to be synced.")
+
+	_, err = NewTimeoutSyncer(&ztest.FailWriter{}, time.Second).Write([]byte("a\n"))
+	assert.Error(t, err, "Expected errors from the underlying syncer.")
+}
+
+func TestTimeoutSyncerSlowWrites(t *testing.T) {
+	slow := newGatedSyncer()
+	timeout := ztest.Timeout(10 * time.Millisecond)
+	ws := NewTimeoutSyncer(slow, timeout)
+
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		_, err := ws.Write([]byte("hung\n"))
+		assert.Error(t, err, "Expected write to time out.")
+		assert.Less(t, time.Since(start), timeout+ztest.Timeout(time.Second), "Expected write to return promptly.")
+	}
+	assert.Error(t, ws.Sync(), "Expected sync to time out behind the hung write.")
+	assert.Len(t, slow.started, 1, "Expected a single write to be in flight.")
+
+	// Once the destination recovers, the hung write completes and later
+	// writes succeed.
+	close(slow.gate)
+	require.Eventually(t, func() bool {
+		_, err := ws.Write([]byte("ok\n"))
+		return err == nil
+	}, ztest.Timeout(time.Second), time.Millisecond, "Expected writes to succeed after recovery.")
+	assert.Equal(t, "hung\nok\n", slow.String(), "Unexpected lines written.")
+}
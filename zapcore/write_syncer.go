@@ -22,6 +22,7 @@ package zapcore
 
 import (
 	"io"
+	"strings"
 	"sync"
 
 	"go.uber.org/multierr"
@@ -75,6 +76,10 @@ func (s *lockedWriteSyncer) Sync() error {
 	return err
 }
 
+func (s *lockedWriteSyncer) Describe() string {
+	return DescribeWriteSyncer(s.ws)
+}
+
 type writerWrapper struct {
 	io.Writer
 }
@@ -83,6 +88,10 @@ func (w writerWrapper) Sync() error {
 	return nil
 }
 
+func (w writerWrapper) Describe() string {
+	return describeWriter(w.Writer)
+}
+
 type multiWriteSyncer []WriteSyncer
 
 // NewMultiWriteSyncer creates a WriteSyncer that duplicates its writes
@@ -120,3 +129,11 @@ func (ws multiWriteSyncer) Sync() error {
 	}
 	return err
 }
+
+func (ws multiWriteSyncer) Describe() string {
+	descs := make([]string, len(ws))
+	for i, w := range ws {
+		descs[i] = DescribeWriteSyncer(w)
+	}
+	return strings.Join(descs, ", ")
+}
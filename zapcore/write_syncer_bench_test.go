@@ -99,3 +99,35 @@ func BenchmarkWriteSyncer(b *testing.B) {
 		})
 	})
 }
+
+func BenchmarkPrefixSyncer(b *testing.B) {
+	line := []byte("foobarbazbabble\n")
+	prefix := []byte("[testy] ")
+	b.Run("static", func(b *testing.B) {
+		w := NewPrefixSyncer(&ztest.Discarder{}, func() []byte { return prefix })
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := w.Write(line); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+	b.Run("concatenated", func(b *testing.B) {
+		// What NewPrefixSyncer avoids: allocating a new slice for each write.
+		w := &ztest.Discarder{}
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := make([]byte, 0, len(prefix)+len(line))
+				buf = append(append(buf, prefix...), line...)
+				if _, err := w.Write(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,566 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore
+
+import (
+	"encoding/base64"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/internal/bufferpool"
+)
+
+type xmlEncoder struct {
+	*EncoderConfig
+	buf            *buffer.Buffer
+	inArray        bool // whether values are being appended to an array
+	openNamespaces int
+	depth          int // nesting of objects and arrays added by marshalers
+
+	// for encoding generic values by reflection
+	reflectBuf *buffer.Buffer
+	reflectEnc ReflectedEncoder
+}
+
+// NewXMLEncoder creates an encoder that writes each entry as a <log>
+// element, for log sinks that ingest XML. For example:
+//
+//	<log><level>info</level><message>hello</message><field name="user"><field name="id">42</field></field></log>
+//
+// Entry metadata is written to the level, time, logger, caller, function,
+// message, and stacktrace child elements, each of which is included only if
+// the corresponding key in the EncoderConfig is non-empty; the keys
+// themselves aren't used as element names, since they may not be valid XML
+// names. Each field is written as a <field> element, with its key in the name
+// attribute. Objects and namespaces become <field> elements containing
+// nested fields, and array elements are written as <item> elements.
+//
+// All text and attribute values are escaped, and characters that can't be
+// represented in XML are replaced with U+FFFD. Reflected fields are
+// serialized with the configured NewReflectedEncoder and written as text.
+func NewXMLEncoder(cfg EncoderConfig) Encoder {
+	if cfg.SkipLineEnding {
+		cfg.LineEnding = ""
+	} else if cfg.LineEnding == "" {
+		cfg.LineEnding = DefaultLineEnding
+	}
+	if cfg.NewReflectedEncoder == nil {
+		cfg.NewReflectedEncoder = defaultReflectedEncoder
+	}
+	return &xmlEncoder{
+		EncoderConfig: &cfg,
+		buf:           bufferpool.Get(),
+	}
+}
+
+// openField opens a <field> element for the given key.
+func (enc *xmlEncoder) openField(key string) {
+	enc.buf.AppendString(`<field name="`)
+	enc.appendEscaped(key)
+	enc.buf.AppendString(`">`)
+}
+
+func (enc *xmlEncoder) closeField() {
+	enc.buf.AppendString("</field>")
+}
+
+// openValue opens an <item> element if values are being appended to an
+// array, and reports whether it did.
+func (enc *xmlEncoder) openValue() bool {
+	if enc.inArray {
+		enc.buf.AppendString("<item>")
+	}
+	return enc.inArray
+}
+
+func (enc *xmlEncoder) closeValue(opened bool) {
+	if opened {
+		enc.buf.AppendString("</item>")
+	}
+}
+
+func (enc *xmlEncoder) AddArray(key string, arr ArrayMarshaler) error {
+	enc.openField(key)
+	err := enc.appendArray(arr)
+	enc.closeField()
+	return err
+}
+
+func (enc *xmlEncoder) AddObject(key string, obj ObjectMarshaler) error {
+	enc.openField(key)
+	err := enc.appendObject(obj)
+	enc.closeField()
+	return err
+}
+
+func (enc *xmlEncoder) AddBinary(key string, val []byte) {
+	enc.AddString(key, base64.StdEncoding.EncodeToString(val))
+}
+
+func (enc *xmlEncoder) AddByteString(key string, val []byte) {
+	enc.openField(key)
+	enc.AppendByteString(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddBool(key string, val bool) {
+	enc.openField(key)
+	enc.AppendBool(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddComplex128(key string, val complex128) {
+	enc.openField(key)
+	enc.AppendComplex128(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddComplex64(key string, val complex64) {
+	enc.openField(key)
+	enc.AppendComplex64(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddDuration(key string, val time.Duration) {
+	enc.openField(key)
+	enc.AppendDuration(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddFloat64(key string, val float64) {
+	enc.openField(key)
+	enc.AppendFloat64(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddFloat32(key string, val float32) {
+	enc.openField(key)
+	enc.AppendFloat32(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddInt64(key string, val int64) {
+	enc.openField(key)
+	enc.AppendInt64(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddReflected(key string, obj interface{}) error {
+	valueBytes, err := enc.encodeReflected(obj)
+	if err != nil {
+		return err
+	}
+	enc.openField(key)
+	enc.appendEscapedBytes(valueBytes)
+	enc.closeField()
+	return nil
+}
+
+func (enc *xmlEncoder) OpenNamespace(key string) {
+	enc.openField(key)
+	enc.openNamespaces++
+}
+
+func (enc *xmlEncoder) AddString(key, val string) {
+	enc.openField(key)
+	enc.AppendString(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddTime(key string, val time.Time) {
+	enc.openField(key)
+	enc.AppendTime(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AddUint64(key string, val uint64) {
+	enc.openField(key)
+	enc.AppendUint64(val)
+	enc.closeField()
+}
+
+func (enc *xmlEncoder) AppendArray(arr ArrayMarshaler) error {
+	opened := enc.openValue()
+	err := enc.appendArray(arr)
+	enc.closeValue(opened)
+	return err
+}
+
+func (enc *xmlEncoder) AppendObject(obj ObjectMarshaler) error {
+	opened := enc.openValue()
+	err := enc.appendObject(obj)
+	enc.closeValue(opened)
+	return err
+}
+
+// appendArray writes the elements of arr into the current element.
+func (enc *xmlEncoder) appendArray(arr ArrayMarshaler) error {
+	if enc.maxDepthExceeded() {
+		enc.appendMaxDepthMarker()
+		return nil
+	}
+	inArray := enc.inArray
+	enc.inArray = true
+	enc.depth++
+	err := arr.MarshalLogArray(enc)
+	enc.depth--
+	enc.inArray = inArray
+	return err
+}
+
+// appendObject writes the fields of obj into the current element.
+func (enc *xmlEncoder) appendObject(obj ObjectMarshaler) error {
+	if enc.maxDepthExceeded() {
+		enc.appendMaxDepthMarker()
+		return nil
+	}
+	// Close ONLY new openNamespaces that are created during
+	// appendObject().
+	inArray, namespaces := enc.inArray, enc.openNamespaces
+	enc.inArray, enc.openNamespaces = false, 0
+	enc.depth++
+	err := obj.MarshalLogObject(enc)
+	enc.closeOpenNamespaces()
+	enc.depth--
+	enc.inArray, enc.openNamespaces = inArray, namespaces
+	return err
+}
+
+func (enc *xmlEncoder) maxDepthExceeded() bool {
+	return enc.MaxObjectDepth > 0 && enc.depth >= enc.MaxObjectDepth
+}
+
+// appendMaxDepthMarker writes the field that stands in for the contents of
+// objects and arrays nested beyond MaxObjectDepth.
+func (enc *xmlEncoder) appendMaxDepthMarker() {
+	inArray := enc.inArray
+	enc.inArray = false
+	enc.AddString(_maxDepthKey, _maxDepthMessage)
+	enc.inArray = inArray
+}
+
+func (enc *xmlEncoder) AppendBool(val bool) {
+	opened := enc.openValue()
+	enc.buf.AppendBool(val)
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) AppendByteString(val []byte) {
+	opened := enc.openValue()
+	enc.appendEscapedBytes(val)
+	enc.closeValue(opened)
+}
+
+// appendComplex appends the string form of the provided complex128 value,
+// matching the JSON encoder. precision specifies the encoding precision for
+// the real and imaginary components of the complex number.
+func (enc *xmlEncoder) appendComplex(val complex128, precision int) {
+	opened := enc.openValue()
+	r, i := float64(real(val)), float64(imag(val))
+	enc.buf.AppendFloat(r, precision)
+	if i >= 0 {
+		enc.buf.AppendByte('+')
+	}
+	enc.buf.AppendFloat(i, precision)
+	enc.buf.AppendByte('i')
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) AppendDuration(val time.Duration) {
+	cur := enc.buf.Len()
+	if e := enc.EncodeDuration; e != nil {
+		e(val, enc)
+	}
+	if cur == enc.buf.Len() {
+		// User-supplied EncodeDuration is a no-op. Fall back to nanoseconds.
+		enc.AppendInt64(int64(val))
+	}
+}
+
+func (enc *xmlEncoder) AppendInt64(val int64) {
+	opened := enc.openValue()
+	enc.buf.AppendInt(val)
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) AppendReflected(val interface{}) error {
+	valueBytes, err := enc.encodeReflected(val)
+	if err != nil {
+		return err
+	}
+	opened := enc.openValue()
+	enc.appendEscapedBytes(valueBytes)
+	enc.closeValue(opened)
+	return nil
+}
+
+func (enc *xmlEncoder) AppendString(val string) {
+	opened := enc.openValue()
+	enc.appendEscaped(val)
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) AppendTimeLayout(time time.Time, layout string) {
+	opened := enc.openValue()
+	enc.buf.AppendTime(time, layout)
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) AppendTime(val time.Time) {
+	cur := enc.buf.Len()
+	if e := enc.EncodeTime; e != nil {
+		e(val, enc)
+	}
+	if cur == enc.buf.Len() {
+		// User-supplied EncodeTime is a no-op. Fall back to nanos since epoch
+		// to keep output consistent with the JSON encoder.
+		enc.AppendInt64(val.UnixNano())
+	}
+}
+
+func (enc *xmlEncoder) AppendUint64(val uint64) {
+	opened := enc.openValue()
+	enc.buf.AppendUint(val)
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) appendFloat(val float64, bitSize int) {
+	opened := enc.openValue()
+	switch {
+	case math.IsNaN(val):
+		enc.buf.AppendString("NaN")
+	case math.IsInf(val, 1):
+		enc.buf.AppendString("+Inf")
+	case math.IsInf(val, -1):
+		enc.buf.AppendString("-Inf")
+	default:
+		enc.buf.AppendFloat(val, bitSize)
+	}
+	enc.closeValue(opened)
+}
+
+func (enc *xmlEncoder) AddInt(k string, v int)         { enc.AddInt64(k, int64(v)) }
+func (enc *xmlEncoder) AddInt32(k string, v int32)     { enc.AddInt64(k, int64(v)) }
+func (enc *xmlEncoder) AddInt16(k string, v int16)     { enc.AddInt64(k, int64(v)) }
+func (enc *xmlEncoder) AddInt8(k string, v int8)       { enc.AddInt64(k, int64(v)) }
+func (enc *xmlEncoder) AddUint(k string, v uint)       { enc.AddUint64(k, uint64(v)) }
+func (enc *xmlEncoder) AddUint32(k string, v uint32)   { enc.AddUint64(k, uint64(v)) }
+func (enc *xmlEncoder) AddUint16(k string, v uint16)   { enc.AddUint64(k, uint64(v)) }
+func (enc *xmlEncoder) AddUint8(k string, v uint8)     { enc.AddUint64(k, uint64(v)) }
+func (enc *xmlEncoder) AddUintptr(k string, v uintptr) { enc.AddUint64(k, uint64(v)) }
+func (enc *xmlEncoder) AppendComplex64(v complex64)    { enc.appendComplex(complex128(v), 32) }
+func (enc *xmlEncoder) AppendComplex128(v complex128)  { enc.appendComplex(complex128(v), 64) }
+func (enc *xmlEncoder) AppendFloat64(v float64)        { enc.appendFloat(v, 64) }
+func (enc *xmlEncoder) AppendFloat32(v float32)        { enc.appendFloat(float64(v), 32) }
+func (enc *xmlEncoder) AppendInt(v int)                { enc.AppendInt64(int64(v)) }
+func (enc *xmlEncoder) AppendInt32(v int32)            { enc.AppendInt64(int64(v)) }
+func (enc *xmlEncoder) AppendInt16(v int16)            { enc.AppendInt64(int64(v)) }
+func (enc *xmlEncoder) AppendInt8(v int8)              { enc.AppendInt64(int64(v)) }
+func (enc *xmlEncoder) AppendUint(v uint)              { enc.AppendUint64(uint64(v)) }
+func (enc *xmlEncoder) AppendUint32(v uint32)          { enc.AppendUint64(uint64(v)) }
+func (enc *xmlEncoder) AppendUint16(v uint16)          { enc.AppendUint64(uint64(v)) }
+func (enc *xmlEncoder) AppendUint8(v uint8)            { enc.AppendUint64(uint64(v)) }
+func (enc *xmlEncoder) AppendUintptr(v uintptr)        { enc.AppendUint64(uint64(v)) }
+
+func (enc *xmlEncoder) Clone() Encoder {
+	clone := enc.clone()
+	clone.buf.Write(enc.buf.Bytes())
+	return clone
+}
+
+func (enc *xmlEncoder) clone() *xmlEncoder {
+	return &xmlEncoder{
+		EncoderConfig:  enc.EncoderConfig,
+		buf:            bufferpool.Get(),
+		openNamespaces: enc.openNamespaces,
+	}
+}
+
+func (enc *xmlEncoder) EncodeEntry(ent Entry, fields []Field) (*buffer.Buffer, error) {
+	final := enc.clone()
+	final.buf.AppendString("<log>")
+
+	if final.LevelKey != "" && final.EncodeLevel != nil {
+		final.buf.AppendString("<level>")
+		cur := final.buf.Len()
+		final.EncodeLevel(ent.Level, final)
+		if cur == final.buf.Len() {
+			// User-supplied EncodeLevel was a no-op. Fall back to strings to
+			// keep output consistent.
+			final.AppendString(ent.Level.String())
+		}
+		final.buf.AppendString("</level>")
+	}
+	if final.TimeKey != "" && !ent.Time.IsZero() {
+		final.buf.AppendString("<time>")
+		final.AppendTime(ent.Time)
+		final.buf.AppendString("</time>")
+	}
+	if ent.LoggerName != "" && final.NameKey != "" {
+		final.buf.AppendString("<logger>")
+		cur := final.buf.Len()
+		nameEncoder := final.EncodeName
+		if nameEncoder == nil {
+			// Fall back to FullNameEncoder for backward compatibility.
+			nameEncoder = FullNameEncoder
+		}
+		nameEncoder(ent.LoggerName, final)
+		if cur == final.buf.Len() {
+			// User-supplied EncodeName was a no-op. Fall back to strings to
+			// keep output consistent.
+			final.AppendString(ent.LoggerName)
+		}
+		final.buf.AppendString("</logger>")
+	}
+	if ent.Caller.Defined {
+		if final.CallerKey != "" {
+			final.buf.AppendString("<caller>")
+			cur := final.buf.Len()
+			if final.EncodeCaller != nil {
+				final.EncodeCaller(ent.Caller, final)
+			}
+			if cur == final.buf.Len() {
+				// User-supplied EncodeCaller was a no-op. Fall back to strings
+				// to keep output consistent.
+				final.AppendString(ent.Caller.String())
+			}
+			final.buf.AppendString("</caller>")
+		}
+		if final.FunctionKey != "" {
+			final.buf.AppendString("<function>")
+			final.AppendString(ent.Caller.Function)
+			final.buf.AppendString("</function>")
+		}
+	}
+	if final.MessageKey != "" {
+		final.buf.AppendString("<message>")
+		final.AppendString(ent.Message)
+		final.buf.AppendString("</message>")
+	}
+	final.buf.Write(enc.buf.Bytes())
+	if final.SortFields {
+		fields = sortFields(fields)
+	}
+	addFields(final, fields)
+	final.closeOpenNamespaces()
+	if ent.Stack != "" && final.StacktraceKey != "" {
+		final.buf.AppendString("<stacktrace>")
+		final.AppendString(ent.Stack)
+		final.buf.AppendString("</stacktrace>")
+	}
+	final.buf.AppendString("</log>")
+	final.buf.AppendString(final.LineEnding)
+
+	ret := final.buf
+	if final.reflectBuf != nil {
+		final.reflectBuf.Free()
+	}
+	return ret, nil
+}
+
+func (enc *xmlEncoder) closeOpenNamespaces() {
+	for i := 0; i < enc.openNamespaces; i++ {
+		enc.closeField()
+	}
+	enc.openNamespaces = 0
+}
+
+func (enc *xmlEncoder) resetReflectBuf() {
+	if enc.reflectBuf == nil {
+		enc.reflectBuf = bufferpool.Get()
+		enc.reflectEnc = enc.NewReflectedEncoder(enc.reflectBuf)
+	} else {
+		enc.reflectBuf.Reset()
+	}
+}
+
+func (enc *xmlEncoder) encodeReflected(obj interface{}) ([]byte, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	enc.resetReflectBuf()
+	if err := enc.reflectEnc.Encode(obj); err != nil {
+		return nil, err
+	}
+	enc.reflectBuf.TrimNewline()
+	return enc.reflectBuf.Bytes(), nil
+}
+
+// appendEscaped appends s, escaped for use in both text and attribute values.
+func (enc *xmlEncoder) appendEscaped(s string) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:]) // RuneError if invalid
+		enc.appendEscapedRune(r)
+		i += size
+	}
+}
+
+// appendEscapedBytes is like appendEscaped, but for byte slices.
+func (enc *xmlEncoder) appendEscapedBytes(bs []byte) {
+	for i := 0; i < len(bs); {
+		r, size := utf8.DecodeRune(bs[i:])
+		enc.appendEscapedRune(r)
+		i += size
+	}
+}
+
+func (enc *xmlEncoder) appendEscapedRune(r rune) {
+	switch r {
+	case '&':
+		enc.buf.AppendString("&amp;")
+	case '<':
+		enc.buf.AppendString("&lt;")
+	case '>':
+		enc.buf.AppendString("&gt;")
+	case '"':
+		enc.buf.AppendString("&quot;")
+	case '\'':
+		enc.buf.AppendString("&apos;")
+	case '\t':
+		enc.buf.AppendString("&#x9;")
+	case '\n':
+		enc.buf.AppendString("&#xA;")
+	case '\r':
+		enc.buf.AppendString("&#xD;")
+	default:
+		if !isXMLChar(r) {
+			r = utf8.RuneError
+		}
+		if r < utf8.RuneSelf {
+			enc.buf.AppendByte(byte(r))
+			return
+		}
+		var scratch [utf8.UTFMax]byte
+		n := utf8.EncodeRune(scratch[:], r)
+		enc.buf.Write(scratch[:n])
+	}
+}
+
+// isXMLChar reports whether r is in the Char production of the XML
+// specification.
+func isXMLChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
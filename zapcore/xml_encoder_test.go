@@ -0,0 +1,256 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapcore_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	//revive:disable:dot-imports
+	. "go.uber.org/zap/zapcore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xmlNode is a generic XML element, for parsing the XML encoder's output.
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []xmlNode  `xml:",any"`
+}
+
+// toValue converts an element into its text or, for elements with children,
+// a map keyed by child name (or, for fields, the name attribute). Runs of
+// <item> children are collected into slices.
+func (n xmlNode) toValue() interface{} {
+	if len(n.Children) == 0 {
+		return n.Text
+	}
+	if n.Children[0].XMLName.Local == "item" {
+		items := make([]interface{}, len(n.Children))
+		for i, c := range n.Children {
+			items[i] = c.toValue()
+		}
+		return items
+	}
+	m := make(map[string]interface{}, len(n.Children))
+	for _, c := range n.Children {
+		key := c.XMLName.Local
+		if key == "field" {
+			key = "field:" + c.attr("name")
+		}
+		m[key] = c.toValue()
+	}
+	return m
+}
+
+func (n xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseXMLEntry(t testing.TB, bs []byte) map[string]interface{} {
+	var n xmlNode
+	require.NoError(t, xml.Unmarshal(bs, &n), "Failed to parse %q.", bs)
+	require.Equal(t, "log", n.XMLName.Local, "Unexpected root element.")
+	return n.toValue().(map[string]interface{})
+}
+
+type xmlTestUser struct {
+	Name string `json:"name"`
+}
+
+func TestXMLEncoderRoundTrip(t *testing.T) {
+	enc := NewXMLEncoder(EncoderConfig{
+		MessageKey:     "M",
+		LevelKey:       "L",
+		TimeKey:        "T",
+		NameKey:        "N",
+		CallerKey:      "C",
+		FunctionKey:    "F",
+		StacktraceKey:  "S",
+		EncodeLevel:    LowercaseLevelEncoder,
+		EncodeTime:     ISO8601TimeEncoder,
+		EncodeDuration: StringDurationEncoder,
+		EncodeCaller:   ShortCallerEncoder,
+	})
+	enc.AddString("service", `a<b>&"c"`)
+	enc.OpenNamespace("request")
+	enc.AddInt("attempt", 2)
+
+	buf, err := enc.EncodeEntry(Entry{
+		Level:      WarnLevel,
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		LoggerName: "db",
+		Message:    "line one\nline two\ttabbed\r",
+		Caller:     EntryCaller{Defined: true, File: "a/b/c.go", Line: 42, Function: "b.C"},
+		Stack:      "stack",
+	}, []Field{
+		{Key: "took", Type: DurationType, Integer: int64(1500 * time.Millisecond)},
+		{Key: "ratio", Type: Float64Type, Integer: int64(math.Float64bits(0.25))},
+		{Key: "inf", Type: Float64Type, Integer: int64(math.Float64bits(math.Inf(1)))},
+		{Key: "ok", Type: BoolType, Integer: 1},
+		{Key: "raw", Type: BinaryType, Interface: []byte("hi")},
+		{Key: "c", Type: Complex128Type, Interface: complex128(1 + 2i)},
+		{Key: "user", Type: ReflectType, Interface: xmlTestUser{Name: "<jane>"}},
+		{Key: "nothing", Type: ReflectType},
+		{Key: "list", Type: ArrayMarshalerType, Interface: ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+			arr.AppendString("x")
+			arr.AppendInt(1)
+			arr.AppendDuration(time.Second)
+			return arr.AppendObject(ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+				enc.AddString("in", "array")
+				return nil
+			}))
+		})},
+		{Key: "obj", Type: ObjectMarshalerType, Interface: ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+			enc.AddString("k", "v")
+			enc.OpenNamespace("inner")
+			enc.AddBool("deep", false)
+			return nil
+		})},
+		{Key: `we"ird <key> & 'more'`, Type: StringType, String: "escaped attribute"},
+		{Key: "control", Type: StringType, String: "bell\a and invalid \xff"},
+	})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t, map[string]interface{}{
+		"level":         "warn",
+		"time":          "2024-01-02T03:04:05.000Z",
+		"logger":        "db",
+		"caller":        "b/c.go:42",
+		"function":      "b.C",
+		"message":       "line one\nline two\ttabbed\r",
+		"field:service": `a<b>&"c"`,
+		"field:request": map[string]interface{}{
+			"field:attempt": "2",
+			"field:took":    "1.5s",
+			"field:ratio":   "0.25",
+			"field:inf":     "+Inf",
+			"field:ok":      "true",
+			"field:raw":     "aGk=",
+			"field:c":       "1+2i",
+			"field:user":    `{"name":"<jane>"}`,
+			"field:nothing": "",
+			"field:list": []interface{}{
+				"x",
+				"1",
+				"1s",
+				map[string]interface{}{"field:in": "array"},
+			},
+			"field:obj": map[string]interface{}{
+				"field:k": "v",
+				"field:inner": map[string]interface{}{
+					"field:deep": "false",
+				},
+			},
+			`field:we"ird <key> & 'more'`: "escaped attribute",
+			"field:control":               "bell� and invalid �",
+		},
+		"stacktrace": "stack",
+	}, parseXMLEntry(t, buf.Bytes()), "Unexpected entry.")
+	assert.Equal(t, byte('\n'), buf.Bytes()[buf.Len()-1], "Expected a line ending.")
+}
+
+func TestXMLEncoderExactOutput(t *testing.T) {
+	enc := NewXMLEncoder(EncoderConfig{
+		MessageKey:     "msg",
+		SkipLineEnding: true,
+	})
+	enc.AddString("k", "v")
+	buf, err := enc.EncodeEntry(Entry{Message: "hi"}, []Field{
+		{Key: "n", Type: Int64Type, Integer: 1},
+		{Key: "a", Type: ArrayMarshalerType, Interface: ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+			arr.AppendBool(true)
+			return nil
+		})},
+	})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t,
+		`<log><message>hi</message><field name="k">v</field><field name="n">1</field>`+
+			`<field name="a"><item>true</item></field></log>`,
+		buf.String(), "Unexpected output.")
+}
+
+func TestXMLEncoderClone(t *testing.T) {
+	parent := NewXMLEncoder(EncoderConfig{MessageKey: "msg"})
+	parent.OpenNamespace("ns")
+	child := parent.Clone()
+	child.AddString("child", "only")
+	parent.AddString("parent", "only")
+
+	for _, tt := range []struct {
+		enc  Encoder
+		want map[string]interface{}
+	}{
+		{parent, map[string]interface{}{"field:parent": "only", "field:f": "1"}},
+		{child, map[string]interface{}{"field:child": "only", "field:f": "1"}},
+	} {
+		buf, err := tt.enc.EncodeEntry(Entry{Message: "m"}, []Field{{Key: "f", Type: Int64Type, Integer: 1}})
+		require.NoError(t, err, "Unexpected encoding error.")
+		assert.Equal(t, map[string]interface{}{
+			"message":  "m",
+			"field:ns": tt.want,
+		}, parseXMLEntry(t, buf.Bytes()), "Unexpected entry.")
+		buf.Free()
+	}
+}
+
+func TestXMLEncoderMaxObjectDepth(t *testing.T) {
+	enc := NewXMLEncoder(EncoderConfig{MaxObjectDepth: 2})
+	var nest ObjectMarshalerFunc
+	nest = func(enc ObjectEncoder) error {
+		return enc.AddObject("next", nest)
+	}
+	buf, err := enc.EncodeEntry(Entry{}, []Field{{Key: "o", Type: ObjectMarshalerType, Interface: nest}})
+	require.NoError(t, err, "Unexpected encoding error.")
+	defer buf.Free()
+
+	assert.Equal(t, map[string]interface{}{
+		"field:o": map[string]interface{}{
+			"field:next": map[string]interface{}{
+				"field:next": map[string]interface{}{
+					"field:...": "max depth exceeded",
+				},
+			},
+		},
+	}, parseXMLEntry(t, buf.Bytes()), "Unexpected entry.")
+}
+
+func TestXMLEncoderErrors(t *testing.T) {
+	enc := NewXMLEncoder(EncoderConfig{})
+	assert.Error(t, enc.AddReflected("k", make(chan int)), "Expected error reflecting a channel.")
+	assert.Error(t, enc.AddArray("k", ArrayMarshalerFunc(func(ArrayEncoder) error {
+		return errors.New("fail")
+	})), "Expected marshaler error.")
+}
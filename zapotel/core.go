@@ -0,0 +1,241 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package zapotel records zap log entries as events on tracing spans, and
+// bridges them to the OpenTelemetry logs signal with NewLogBridgeCore.
+//
+// The package doesn't depend on OpenTelemetry directly. Instead, it describes
+// the small part of a tracer it needs with the Tracer and Span interfaces,
+// and the part of a log pipeline it needs with the LoggerProvider and Logger
+// interfaces. These are straightforward to satisfy with the OpenTelemetry
+// API:
+//
+//	type otelTracer struct{}
+//
+//	func (otelTracer) SpanFromContext(ctx context.Context) zapotel.Span {
+//		span := trace.SpanFromContext(ctx)
+//		if !span.IsRecording() {
+//			return nil
+//		}
+//		return otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) AddEvent(name string, attrs map[string]interface{}) {
+//		kvs := make([]attribute.KeyValue, 0, len(attrs))
+//		for k, v := range attrs {
+//			kvs = append(kvs, attribute.String(k, fmt.Sprint(v)))
+//		}
+//		s.Span.AddEvent(name, trace.WithAttributes(kvs...))
+//	}
+package zapotel // import "go.uber.org/zap/zapotel"
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const _contextKey = "zapotel.context"
+
+// A Span is the part of a tracing span that log entries are recorded on.
+type Span interface {
+	AddEvent(name string, attrs map[string]interface{})
+}
+
+// A Tracer finds the span active in a context. It returns nil if there is
+// no span, or if the span isn't recording.
+type Tracer interface {
+	SpanFromContext(context.Context) Span
+}
+
+// Context binds a context to a log entry so that NewSpanEventCore can find
+// the span active in it. Encoders ignore the field.
+//
+//	logger.Error("payment failed", zapotel.Context(ctx), zap.Error(err))
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: _contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// ContextFields is a context extractor that binds the context of the
+// Logger's *Context methods to each entry. Install it with
+// zap.WithContextExtractor:
+//
+//	logger := zap.New(core, zap.WithContextExtractor(zapotel.ContextFields))
+//	logger.ErrorContext(ctx, "payment failed", zap.Error(err))
+func ContextFields(ctx context.Context) []zap.Field {
+	return []zap.Field{Context(ctx)}
+}
+
+// An Option configures a core built by NewSpanEventCore.
+type Option interface {
+	apply(*spanRecorder)
+}
+
+type optionFunc func(*spanRecorder)
+
+func (f optionFunc) apply(r *spanRecorder) {
+	f(r)
+}
+
+// WithEventLevel selects the entries recorded as span events. By default,
+// only entries at ErrorLevel and above are recorded.
+func WithEventLevel(enab zapcore.LevelEnabler) Option {
+	return optionFunc(func(r *spanRecorder) {
+		r.events = enab
+	})
+}
+
+// NewSpanEventCore wraps a Core so that, in addition to being written to the
+// wrapped Core, selected entries are recorded as events on the span active
+// in the entry's bound context. The event is named after the entry's
+// message, and its attributes hold the entry's level, logger name, and
+// fields.
+//
+// Entries without a bound context (see Context and ContextFields), or whose
+// context has no span, are only written to the wrapped Core.
+func NewSpanEventCore(next zapcore.Core, tracer Tracer, opts ...Option) zapcore.Core {
+	r := &spanRecorder{
+		tracer: tracer,
+		events: zapcore.ErrorLevel,
+	}
+	for _, opt := range opts {
+		opt.apply(r)
+	}
+	return &spanEventCore{Core: next, recorder: r}
+}
+
+type spanEventCore struct {
+	zapcore.Core
+
+	recorder *spanRecorder
+}
+
+var (
+	_ zapcore.Core                       = (*spanEventCore)(nil)
+	_ interface{ Level() zapcore.Level } = (*spanEventCore)(nil)
+)
+
+func (c *spanEventCore) Level() zapcore.Level {
+	lvl := zapcore.LevelOf(c.Core)
+	if evLvl := zapcore.LevelOf(c.recorder.events); evLvl < lvl {
+		return evLvl
+	}
+	return lvl
+}
+
+func (c *spanEventCore) Enabled(lvl zapcore.Level) bool {
+	return c.Core.Enabled(lvl) || c.recorder.events.Enabled(lvl)
+}
+
+func (c *spanEventCore) Describe() []zapcore.CoreInfo {
+	return zapcore.DescribeCores(c.Core)
+}
+
+func (c *spanEventCore) With(fields []zapcore.Field) zapcore.Core {
+	return &spanEventCore{
+		Core:     c.Core.With(fields),
+		recorder: c.recorder.with(fields),
+	}
+}
+
+func (c *spanEventCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	ce = c.Core.Check(ent, ce)
+	return c.recorder.Check(ent, ce)
+}
+
+// spanRecorder is a Core that records entries as span events instead of
+// writing them. spanEventCore adds it to checked entries alongside the
+// wrapped Core.
+type spanRecorder struct {
+	tracer Tracer
+	events zapcore.LevelEnabler
+	fields []zapcore.Field
+}
+
+func (r *spanRecorder) with(fields []zapcore.Field) *spanRecorder {
+	clone := *r
+	clone.fields = make([]zapcore.Field, 0, len(r.fields)+len(fields))
+	clone.fields = append(clone.fields, r.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return &clone
+}
+
+func (r *spanRecorder) Enabled(lvl zapcore.Level) bool {
+	return r.events.Enabled(lvl)
+}
+
+func (r *spanRecorder) With(fields []zapcore.Field) zapcore.Core {
+	return r.with(fields)
+}
+
+func (r *spanRecorder) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if r.Enabled(ent.Level) {
+		return ce.AddCore(ent, r)
+	}
+	return ce
+}
+
+func (r *spanRecorder) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ctx, ok := findContext(fields)
+	if !ok {
+		if ctx, ok = findContext(r.fields); !ok {
+			return nil
+		}
+	}
+	span := r.tracer.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range r.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	enc.Fields["level"] = ent.Level.String()
+	if ent.LoggerName != "" {
+		enc.Fields["logger"] = ent.LoggerName
+	}
+	span.AddEvent(ent.Message, enc.Fields)
+	return nil
+}
+
+func (r *spanRecorder) Sync() error {
+	return nil
+}
+
+// findContext returns the most recently bound context in fields.
+func findContext(fields []zapcore.Field) (context.Context, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if f.Type != zapcore.SkipType || f.Key != _contextKey {
+			continue
+		}
+		if ctx, ok := f.Interface.(context.Context); ok {
+			return ctx, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type spanKey struct{}
+
+type event struct {
+	Name  string
+	Attrs map[string]interface{}
+}
+
+type mockSpan struct{ events []event }
+
+func (s *mockSpan) AddEvent(name string, attrs map[string]interface{}) {
+	s.events = append(s.events, event{name, attrs})
+}
+
+type mockTracer struct{}
+
+func (mockTracer) SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(*mockSpan); ok {
+		return span
+	}
+	return nil
+}
+
+func withSpan(opts ...Option) (*zap.Logger, *mockSpan, context.Context, *observer.ObservedLogs) {
+	obs, logs := observer.New(zapcore.InfoLevel)
+	span := &mockSpan{}
+	ctx := context.WithValue(context.Background(), spanKey{}, span)
+	logger := zap.New(
+		NewSpanEventCore(obs, mockTracer{}, opts...),
+		zap.WithContextExtractor(ContextFields),
+	)
+	return logger, span, ctx, logs
+}
+
+func TestSpanEventCore(t *testing.T) {
+	logger, span, ctx, logs := withSpan()
+
+	logger.Named("payments").InfoContext(ctx, "charging card")
+	logger.Named("payments").ErrorContext(ctx, "charge failed", zap.Error(errors.New("declined")))
+
+	assert.Equal(t, []event{{
+		Name: "charge failed",
+		Attrs: map[string]interface{}{
+			"level":  "error",
+			"logger": "payments",
+			"error":  "declined",
+		},
+	}}, span.events, "Unexpected span events.")
+
+	require.Equal(t, 2, logs.Len(), "Entries must still be written to the wrapped core.")
+	for _, e := range logs.AllUntimed() {
+		assert.NotContains(t, e.ContextMap(), _contextKey, "Bound context must not be encoded.")
+	}
+}
+
+func TestSpanEventCoreContextSources(t *testing.T) {
+	t.Run("field", func(t *testing.T) {
+		logger, span, ctx, _ := withSpan()
+		logger.Error("failed", Context(ctx))
+		assert.Len(t, span.events, 1, "Expected an event from a context field.")
+	})
+
+	t.Run("with", func(t *testing.T) {
+		logger, span, ctx, _ := withSpan()
+		logger.With(Context(ctx), zap.String("user", "alice")).Error("failed")
+		require.Len(t, span.events, 1, "Expected an event from a context bound with With.")
+		assert.Equal(t, "alice", span.events[0].Attrs["user"], "Expected With fields in attributes.")
+	})
+
+	t.Run("no context", func(t *testing.T) {
+		logger, span, _, logs := withSpan()
+		logger.Error("failed")
+		assert.Empty(t, span.events, "Unexpected event without a bound context.")
+		assert.Equal(t, 1, logs.Len(), "Entry must still be written.")
+	})
+
+	t.Run("no span", func(t *testing.T) {
+		logger, span, _, _ := withSpan()
+		logger.ErrorContext(context.Background(), "failed")
+		assert.Empty(t, span.events, "Unexpected event without an active span.")
+	})
+}
+
+func TestSpanEventCoreLevels(t *testing.T) {
+	logger, span, ctx, logs := withSpan(WithEventLevel(zapcore.DebugLevel))
+
+	logger.DebugContext(ctx, "debug")
+	logger.InfoContext(ctx, "info")
+	logger.WarnContext(ctx, "warn")
+
+	var names []string
+	for _, e := range span.events {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"debug", "info", "warn"}, names, "Unexpected span events.")
+	assert.Equal(t, 2, logs.Len(), "Wrapped core's level must still apply.")
+
+	assert.Equal(t, zapcore.DebugLevel, zapcore.LevelOf(logger.Core()), "Unexpected core level.")
+}
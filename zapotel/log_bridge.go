@@ -0,0 +1,293 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapotel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// _bridgeScope is the instrumentation scope of the OpenTelemetry Logger that
+// NewLogBridgeCore emits records with.
+const _bridgeScope = "go.uber.org/zap/zapotel"
+
+// A LoggerProvider provides the Loggers that NewLogBridgeCore emits records
+// with. It mirrors the LoggerProvider of the OpenTelemetry Logs Bridge API.
+type LoggerProvider interface {
+	Logger(name string) Logger
+}
+
+// A Logger emits log records. It mirrors the Logger of the OpenTelemetry
+// Logs Bridge API; ctx carries the trace context the record belongs to.
+type Logger interface {
+	Emit(ctx context.Context, record Record)
+}
+
+// Severity is the severity of a log record, numbered as in the OpenTelemetry
+// log data model.
+type Severity int
+
+// Severities that zap levels map to.
+const (
+	SeverityDebug  Severity = 5
+	SeverityInfo   Severity = 9
+	SeverityWarn   Severity = 13
+	SeverityError  Severity = 17
+	SeverityError2 Severity = 18
+	SeverityError3 Severity = 19
+	SeverityFatal  Severity = 21
+)
+
+// A Record is a log record in the OpenTelemetry log data model.
+type Record struct {
+	Timestamp    time.Time
+	Severity     Severity
+	SeverityText string
+	Body         string
+	Attributes   []Attribute
+}
+
+// An Attribute is a key-value pair attached to a Record. Values are bool,
+// int64, float64, string, []interface{} of values, or map[string]interface{}
+// of values, matching the value types of the OpenTelemetry data model.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// A BridgeOption configures a core built by NewLogBridgeCore.
+type BridgeOption interface {
+	apply(*bridgeCore)
+}
+
+type bridgeOptionFunc func(*bridgeCore)
+
+func (f bridgeOptionFunc) apply(c *bridgeCore) {
+	f(c)
+}
+
+// WithBridgeLevel selects the entries emitted as log records. By default,
+// entries at every level are emitted.
+func WithBridgeLevel(enab zapcore.LevelEnabler) BridgeOption {
+	return bridgeOptionFunc(func(c *bridgeCore) {
+		c.LevelEnabler = enab
+	})
+}
+
+// NewLogBridgeCore builds a Core that emits each entry as an OpenTelemetry
+// log record, so that zap logs flow into an OpenTelemetry log pipeline. Use
+// it alone, or alongside other Cores with zapcore.NewTee.
+//
+// Records are built as follows:
+//
+//   - The entry's message is the body, and its time is the timestamp.
+//   - Levels map to severities: DebugLevel to SeverityDebug, InfoLevel to
+//     SeverityInfo, WarnLevel to SeverityWarn, ErrorLevel to SeverityError,
+//     DPanicLevel to SeverityError2, PanicLevel to SeverityError3, and
+//     FatalLevel to SeverityFatal. The severity text is the level's
+//     capitalized name.
+//   - Fields become attributes, sorted by key. Integers, floats, bools, and
+//     strings keep their types; objects and arrays become maps and slices;
+//     other values, such as durations and times, are formatted as strings.
+//   - The logger name, caller, and stack trace, if any, become the
+//     "logger.name", "code.filepath", "code.lineno", "code.function", and
+//     "code.stacktrace" attributes.
+//
+// Records are emitted with the context bound to the entry with Context or
+// ContextFields, so that they're associated with the active trace; entries
+// without one are emitted with context.Background().
+func NewLogBridgeCore(provider LoggerProvider, opts ...BridgeOption) zapcore.Core {
+	c := &bridgeCore{
+		LevelEnabler: zapcore.DebugLevel,
+		logger:       provider.Logger(_bridgeScope),
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+type bridgeCore struct {
+	zapcore.LevelEnabler
+
+	logger Logger
+	fields []zapcore.Field
+}
+
+var (
+	_ zapcore.Core                       = (*bridgeCore)(nil)
+	_ interface{ Level() zapcore.Level } = (*bridgeCore)(nil)
+)
+
+func (c *bridgeCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+func (c *bridgeCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return &clone
+}
+
+func (c *bridgeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *bridgeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ctx, ok := findContext(fields)
+	if !ok {
+		if ctx, ok = findContext(c.fields); !ok {
+			ctx = context.Background()
+		}
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	if ent.LoggerName != "" {
+		enc.Fields["logger.name"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		enc.Fields["code.filepath"] = ent.Caller.File
+		enc.Fields["code.lineno"] = ent.Caller.Line
+		if ent.Caller.Function != "" {
+			enc.Fields["code.function"] = ent.Caller.Function
+		}
+	}
+	if ent.Stack != "" {
+		enc.Fields["code.stacktrace"] = ent.Stack
+	}
+
+	attrs := make([]Attribute, 0, len(enc.Fields))
+	for k, v := range enc.Fields {
+		attrs = append(attrs, Attribute{Key: k, Value: attributeValue(v)})
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+
+	c.logger.Emit(ctx, Record{
+		Timestamp:    ent.Time,
+		Severity:     severityOf(ent.Level),
+		SeverityText: ent.Level.CapitalString(),
+		Body:         ent.Message,
+		Attributes:   attrs,
+	})
+	return nil
+}
+
+func (c *bridgeCore) Sync() error {
+	return nil
+}
+
+func severityOf(lvl zapcore.Level) Severity {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return SeverityDebug
+	case zapcore.InfoLevel:
+		return SeverityInfo
+	case zapcore.WarnLevel:
+		return SeverityWarn
+	case zapcore.ErrorLevel:
+		return SeverityError
+	case zapcore.DPanicLevel:
+		return SeverityError2
+	case zapcore.PanicLevel:
+		return SeverityError3
+	case zapcore.FatalLevel:
+		return SeverityFatal
+	}
+	if lvl < zapcore.DebugLevel {
+		return SeverityDebug
+	}
+	return SeverityFatal
+}
+
+// attributeValue converts a value produced by zapcore.MapObjectEncoder to
+// one of the types of the OpenTelemetry data model.
+func attributeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case bool, int64, float64, string:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case uint:
+		return uintValue(uint64(v))
+	case uint64:
+		return uintValue(v)
+	case uint32:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uintptr:
+		return uintValue(uint64(v))
+	case float32:
+		return float64(v)
+	case []byte:
+		return string(v)
+	case []interface{}:
+		vals := make([]interface{}, len(v))
+		for i, elem := range v {
+			vals[i] = attributeValue(elem)
+		}
+		return vals
+	case map[string]interface{}:
+		vals := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			vals[k] = attributeValue(elem)
+		}
+		return vals
+	case fmt.Stringer:
+		return v.String()
+	case nil:
+		return nil
+	}
+	return fmt.Sprint(v)
+}
+
+// uintValue converts an unsigned integer to an int64 attribute value, or to
+// a string if it doesn't fit.
+func uintValue(v uint64) interface{} {
+	if v > math.MaxInt64 {
+		return fmt.Sprint(v)
+	}
+	return int64(v)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapotel
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type emitted struct {
+	ctx    context.Context
+	record Record
+}
+
+type mockProvider struct {
+	scopes  []string
+	records []emitted
+}
+
+func (p *mockProvider) Logger(name string) Logger {
+	p.scopes = append(p.scopes, name)
+	return p
+}
+
+func (p *mockProvider) Emit(ctx context.Context, record Record) {
+	p.records = append(p.records, emitted{ctx, record})
+}
+
+func attrMap(r Record) map[string]interface{} {
+	m := make(map[string]interface{}, len(r.Attributes))
+	for _, a := range r.Attributes {
+		m[a.Key] = a.Value
+	}
+	return m
+}
+
+func TestLogBridgeCore(t *testing.T) {
+	provider := &mockProvider{}
+	logger := zap.New(NewLogBridgeCore(provider), zap.WithContextExtractor(ContextFields)).
+		Named("svc").
+		With(zap.String("region", "us"))
+	assert.Equal(t, []string{_bridgeScope}, provider.scopes, "Unexpected instrumentation scope.")
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "span")
+	logger.InfoContext(ctx, "hello",
+		zap.Int("int", 42),
+		zap.Uint64("big", math.MaxUint64),
+		zap.Float32("float", 1.5),
+		zap.Bool("bool", true),
+		zap.Duration("dur", time.Second),
+		zap.Error(errors.New("fail")),
+		zap.Ints("ints", []int{1, 2}),
+		zap.Dict("obj", zap.Int8("small", 3)),
+	)
+
+	require.Len(t, provider.records, 1, "Expected one record.")
+	got := provider.records[0]
+	assert.Equal(t, "span", got.ctx.Value(spanKey{}), "Expected the bound context to be emitted.")
+	assert.Equal(t, "hello", got.record.Body, "Unexpected body.")
+	assert.Equal(t, SeverityInfo, got.record.Severity, "Unexpected severity.")
+	assert.Equal(t, "INFO", got.record.SeverityText, "Unexpected severity text.")
+	assert.False(t, got.record.Timestamp.IsZero(), "Expected a timestamp.")
+	assert.Equal(t, map[string]interface{}{
+		"region":      "us",
+		"logger.name": "svc",
+		"int":         int64(42),
+		"big":         "18446744073709551615",
+		"float":       float64(1.5),
+		"bool":        true,
+		"dur":         "1s",
+		"error":       "fail",
+		"ints":        []interface{}{int64(1), int64(2)},
+		"obj":         map[string]interface{}{"small": int64(3)},
+	}, attrMap(got.record), "Unexpected attributes.")
+
+	keys := make([]string, len(got.record.Attributes))
+	for i, a := range got.record.Attributes {
+		keys[i] = a.Key
+	}
+	assert.IsIncreasing(t, keys, "Expected attributes sorted by key.")
+}
+
+func TestLogBridgeCoreContextSources(t *testing.T) {
+	provider := &mockProvider{}
+	logger := zap.New(NewLogBridgeCore(provider))
+
+	logger.Info("no context")
+	bound := context.WithValue(context.Background(), spanKey{}, "bound")
+	logger.With(Context(bound)).Info("bound")
+
+	require.Len(t, provider.records, 2, "Expected two records.")
+	assert.Equal(t, context.Background(), provider.records[0].ctx, "Expected the background context.")
+	assert.Equal(t, "bound", provider.records[1].ctx.Value(spanKey{}), "Expected the context bound with With.")
+	assert.Empty(t, provider.records[1].record.Attributes, "Context field shouldn't become an attribute.")
+}
+
+func TestLogBridgeCoreCaller(t *testing.T) {
+	provider := &mockProvider{}
+	logger := zap.New(NewLogBridgeCore(provider), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+
+	logger.Error("oops")
+
+	require.Len(t, provider.records, 1, "Expected one record.")
+	attrs := attrMap(provider.records[0].record)
+	assert.Contains(t, attrs["code.filepath"], "log_bridge_test.go", "Unexpected caller file.")
+	assert.IsType(t, int64(0), attrs["code.lineno"], "Expected an integer line number.")
+	assert.Contains(t, attrs["code.function"], "TestLogBridgeCoreCaller", "Unexpected caller function.")
+	assert.NotEmpty(t, attrs["code.stacktrace"], "Expected a stack trace.")
+}
+
+func TestLogBridgeCoreLevels(t *testing.T) {
+	tests := []struct {
+		lvl  zapcore.Level
+		want Severity
+	}{
+		{zapcore.DebugLevel, SeverityDebug},
+		{zapcore.InfoLevel, SeverityInfo},
+		{zapcore.WarnLevel, SeverityWarn},
+		{zapcore.ErrorLevel, SeverityError},
+		{zapcore.DPanicLevel, SeverityError2},
+		{zapcore.PanicLevel, SeverityError3},
+		{zapcore.FatalLevel, SeverityFatal},
+		{zapcore.DebugLevel - 1, SeverityDebug},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, severityOf(tt.lvl), "Unexpected severity for %v.", tt.lvl)
+	}
+
+	provider := &mockProvider{}
+	core := NewLogBridgeCore(provider, WithBridgeLevel(zapcore.WarnLevel))
+	assert.Equal(t, zapcore.WarnLevel, zapcore.LevelOf(core), "Unexpected core level.")
+
+	logger := zap.New(core)
+	logger.Info("dropped")
+	logger.Warn("kept")
+	require.Len(t, provider.records, 1, "Expected only the warning.")
+	assert.Equal(t, "kept", provider.records[0].record.Body, "Unexpected body.")
+	assert.NoError(t, core.Sync(), "Unexpected error syncing.")
+}
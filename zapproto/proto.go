@@ -0,0 +1,204 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package zapproto logs protobuf messages as structured fields.
+//
+// To avoid a dependency on the protobuf runtime, the package doesn't marshal
+// messages itself. Instead, callers supply a MarshalFunc, which is usually a
+// thin wrapper around protojson:
+//
+//	var marshalProto zapproto.MarshalFunc = func(msg interface{}) ([]byte, error) {
+//		return protojson.Marshal(msg.(proto.Message))
+//	}
+//
+//	logger.Info("received request", zapproto.Proto("req", req, marshalProto))
+//
+// Because protojson honors the message's json_name options, so do the logged
+// fields.
+package zapproto // import "go.uber.org/zap/zapproto"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// A MarshalFunc renders a protobuf message as JSON.
+type MarshalFunc func(msg interface{}) ([]byte, error)
+
+// Proto constructs a field that logs a protobuf message as a nested object.
+// The message is marshaled with marshal only when the entry is written. Nil
+// messages are logged as null.
+func Proto(key string, msg interface{}, marshal MarshalFunc) zap.Field {
+	if isNil(msg) {
+		return zap.Reflect(key, nil)
+	}
+	return zap.Object(key, protoMessage{msg: msg, marshal: marshal})
+}
+
+func isNil(msg interface{}) bool {
+	if msg == nil {
+		return true
+	}
+	v := reflect.ValueOf(msg)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+type protoMessage struct {
+	msg     interface{}
+	marshal MarshalFunc
+}
+
+func (m protoMessage) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	b, err := m.marshal(m.msg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return err
+	}
+	obj, ok := v.(jsonObject)
+	if !ok {
+		return errors.New("zapproto: message didn't marshal to a JSON object")
+	}
+	return obj.MarshalLogObject(enc)
+}
+
+// jsonObject is a decoded JSON object that retains its field order.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	key   string
+	value interface{}
+}
+
+func (o jsonObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, m := range o {
+		var err error
+		switch v := m.value.(type) {
+		case jsonObject:
+			err = enc.AddObject(m.key, v)
+		case jsonArray:
+			err = enc.AddArray(m.key, v)
+		case string:
+			enc.AddString(m.key, v)
+		case bool:
+			enc.AddBool(m.key, v)
+		case json.Number:
+			if i, ierr := v.Int64(); ierr == nil {
+				enc.AddInt64(m.key, i)
+			} else if f, ferr := v.Float64(); ferr == nil {
+				enc.AddFloat64(m.key, f)
+			} else {
+				enc.AddString(m.key, v.String())
+			}
+		default:
+			err = enc.AddReflected(m.key, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonArray is a decoded JSON array.
+type jsonArray []interface{}
+
+func (a jsonArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, elem := range a {
+		var err error
+		switch v := elem.(type) {
+		case jsonObject:
+			err = enc.AppendObject(v)
+		case jsonArray:
+			err = enc.AppendArray(v)
+		case string:
+			enc.AppendString(v)
+		case bool:
+			enc.AppendBool(v)
+		case json.Number:
+			if i, ierr := v.Int64(); ierr == nil {
+				enc.AppendInt64(i)
+			} else if f, ferr := v.Float64(); ferr == nil {
+				enc.AppendFloat64(f)
+			} else {
+				enc.AppendString(v.String())
+			}
+		default:
+			err = enc.AppendReflected(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeValue decodes the next JSON value from dec, preserving the order of
+// object members.
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	switch delim {
+	case '{':
+		obj := jsonObject{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, jsonMember{key: key, value: v})
+		}
+		_, err = dec.Token() // closing '}'
+		return obj, err
+	case '[':
+		arr := jsonArray{}
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token() // closing ']'
+		return arr, err
+	default:
+		return nil, errors.New("zapproto: unexpected JSON delimiter")
+	}
+}
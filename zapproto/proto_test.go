@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapproto
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// address and person stand in for generated messages. Their JSON tags play
+// the part of json_name options, and json.Marshal plays the part of protojson.
+type address struct {
+	Street string `json:"street_line"`
+	Zip    string `json:"zip"`
+}
+
+type person struct {
+	Name      string     `json:"fullName"`
+	Age       int        `json:"age"`
+	Score     float64    `json:"score"`
+	Active    bool       `json:"active"`
+	Home      *address   `json:"home"`
+	Previous  []*address `json:"previousHomes"`
+	Nicknames []string   `json:"nicknames"`
+	Matrix    [][]int    `json:"matrix"`
+}
+
+func marshalJSON(msg interface{}) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func encode(t *testing.T, f zap.Field) string {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{f})
+	require.NoError(t, err, "Failed to encode entry.")
+	defer buf.Free()
+	return buf.String()
+}
+
+func TestProto(t *testing.T) {
+	msg := &person{
+		Name:   "Alice",
+		Age:    42,
+		Score:  9.5,
+		Active: true,
+		Home:   &address{Street: "1 Main St", Zip: "12345"},
+		Previous: []*address{
+			{Street: "2 Oak Ave", Zip: "54321"},
+			{Street: "3 Elm Rd", Zip: "67890"},
+		},
+		Nicknames: []string{"al", "ally"},
+		Matrix:    [][]int{{1, 2}, {3}},
+	}
+
+	assert.Equal(t,
+		`{"person":{"fullName":"Alice","age":42,"score":9.5,"active":true,`+
+			`"home":{"street_line":"1 Main St","zip":"12345"},`+
+			`"previousHomes":[{"street_line":"2 Oak Ave","zip":"54321"},{"street_line":"3 Elm Rd","zip":"67890"}],`+
+			`"nicknames":["al","ally"],"matrix":[[1,2],[3]]}}`+"\n",
+		encode(t, Proto("person", msg, marshalJSON)),
+		"Unexpected encoded message.",
+	)
+}
+
+func TestProtoNil(t *testing.T) {
+	var msg *person
+	assert.Equal(t, `{"person":null}`+"\n", encode(t, Proto("person", msg, marshalJSON)), "Expected typed nil message as null.")
+	assert.Equal(t, `{"person":null}`+"\n", encode(t, Proto("person", nil, marshalJSON)), "Expected nil message as null.")
+}
+
+func TestProtoErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		marshal MarshalFunc
+	}{
+		{
+			desc:    "marshal error",
+			marshal: func(interface{}) ([]byte, error) { return nil, errors.New("fail") },
+		},
+		{
+			desc:    "not an object",
+			marshal: func(interface{}) ([]byte, error) { return []byte(`[1]`), nil },
+		},
+		{
+			desc:    "invalid JSON",
+			marshal: func(interface{}) ([]byte, error) { return []byte(`{"a":`), nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Contains(t, encode(t, Proto("person", &person{}, tt.marshal)), `"personError":`,
+				"Expected marshaling failure to be reported.")
+		})
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package zapsql writes log entries to a database table, for applications
+// that want queryable logs without running a separate log pipeline.
+//
+// It works with any database/sql driver. The table needs a column for each
+// part of an entry:
+//
+//	CREATE TABLE logs (
+//		time    TIMESTAMP,
+//		level   TEXT,
+//		message TEXT,
+//		fields  TEXT
+//	)
+package zapsql // import "go.uber.org/zap/zapsql"
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	_defaultBatchSize     = 100
+	_defaultFlushInterval = time.Second
+)
+
+// Option configures a Core.
+type Option interface {
+	apply(*batcher)
+}
+
+type optionFunc func(*batcher)
+
+func (f optionFunc) apply(b *batcher) {
+	f(b)
+}
+
+// BatchSize sets the number of entries inserted together. Once this many
+// entries are buffered, the entry that fills the batch inserts it before it
+// returns. The default is 100; sizes below one are ignored.
+func BatchSize(n int) Option {
+	return optionFunc(func(b *batcher) {
+		if n > 0 {
+			b.size = n
+		}
+	})
+}
+
+// FlushInterval sets how often buffered entries are inserted in the
+// background, so that entries don't wait indefinitely for a batch to fill.
+// The default is one second; a non-positive interval disables background
+// flushing, leaving it to full batches and Sync.
+func FlushInterval(d time.Duration) Option {
+	return optionFunc(func(b *batcher) {
+		b.interval = d
+	})
+}
+
+// Placeholders sets the function that returns the query placeholder for the
+// n-th argument, counting from one. The default, "?", suits SQLite and
+// MySQL; for PostgreSQL, use
+//
+//	zapsql.Placeholders(func(n int) string { return "$" + strconv.Itoa(n) })
+func Placeholders(placeholder func(n int) string) Option {
+	return optionFunc(func(b *batcher) {
+		b.placeholder = placeholder
+	})
+}
+
+// OnError sets a function that's called when a background flush fails.
+// Entries in a batch that fails to insert are dropped, and the error says
+// how many; the batches after it stay buffered for the next flush. By
+// default, errors are written to standard error. Flushes triggered by
+// writing an entry or by Sync return their errors instead.
+func OnError(fn func(error)) Option {
+	return optionFunc(func(b *batcher) {
+		b.onError = fn
+	})
+}
+
+// Core is a zapcore.Core that inserts entries into a database table. Use
+// NewCore to build one.
+type Core struct {
+	zapcore.LevelEnabler
+
+	enc zapcore.Encoder
+	b   *batcher
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// NewCore builds a Core that inserts the entries enabled by enab into table
+// as rows with the entry's time, level, message, and fields, which are
+// encoded as a JSON object. The logger name, caller, and stack trace, if
+// any, are included in the fields under "logger", "caller", and
+// "stacktrace". table is inserted into queries verbatim, so it must come
+// from a trusted source.
+//
+// Entries are buffered and inserted in batches: when a batch fills up, at
+// every flush interval, on Sync, and right after entries above ErrorLevel,
+// which may precede the process exiting. Close flushes the remaining entries
+// and stops the background flushing; Cores derived with With share the
+// buffer, so closing any of them closes all.
+func NewCore(db *sql.DB, table string, enab zapcore.LevelEnabler, opts ...Option) *Core {
+	b := &batcher{
+		db:          db,
+		table:       table,
+		size:        _defaultBatchSize,
+		interval:    _defaultFlushInterval,
+		placeholder: func(int) string { return "?" },
+		onError:     reportError,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(b)
+	}
+	if b.interval > 0 {
+		go b.run()
+	} else {
+		close(b.done)
+	}
+
+	return &Core{
+		LevelEnabler: enab,
+		enc: zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			NameKey:        "logger",
+			CallerKey:      "caller",
+			StacktraceKey:  "stacktrace",
+			EncodeName:     zapcore.FullNameEncoder,
+			EncodeCaller:   zapcore.ShortCallerEncoder,
+			EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+			EncodeDuration: zapcore.StringDurationEncoder,
+		}),
+		b: b,
+	}
+}
+
+func reportError(err error) {
+	fmt.Fprintf(os.Stderr, "%v zapsql: failed to insert log entries: %v\n", time.Now().UTC(), err)
+}
+
+// Level returns the minimum enabled level.
+func (c *Core) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+// With adds structured context to the Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(clone.enc)
+	}
+	return &clone
+}
+
+// Check adds the Core to the CheckedEntry if the entry's level is enabled.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write buffers the entry, inserting the batch if it's full.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(zapcore.Entry{
+		LoggerName: ent.LoggerName,
+		Caller:     ent.Caller,
+		Stack:      ent.Stack,
+	}, fields)
+	if err != nil {
+		return err
+	}
+	r := row{
+		time:    ent.Time,
+		level:   ent.Level.String(),
+		message: ent.Message,
+		fields:  string(bytes.TrimSuffix(buf.Bytes(), []byte(zapcore.DefaultLineEnding))),
+	}
+	buf.Free()
+
+	if c.b.add(r) || ent.Level > zapcore.ErrorLevel {
+		return c.b.flush()
+	}
+	return nil
+}
+
+// Sync inserts all buffered entries.
+func (c *Core) Sync() error {
+	return c.b.flush()
+}
+
+// Close stops background flushing and inserts all buffered entries. It's
+// safe to call more than once.
+func (c *Core) Close() error {
+	c.b.closeOnce.Do(func() {
+		close(c.b.stop)
+	})
+	<-c.b.done
+	return c.b.flush()
+}
+
+type row struct {
+	time    time.Time
+	level   string
+	message string
+	fields  string
+}
+
+type batcher struct {
+	db          *sql.DB
+	table       string
+	size        int
+	interval    time.Duration
+	placeholder func(n int) string
+	onError     func(error)
+
+	mu   sync.Mutex
+	rows []row
+
+	flushMu sync.Mutex // held while inserting, to keep batches in order
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{} // closed when the background flusher exits
+}
+
+// add buffers a row and reports whether the batch is full.
+func (b *batcher) add(r row) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rows = append(b.rows, r)
+	return len(b.rows) >= b.size
+}
+
+func (b *batcher) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			if err := b.flush(); err != nil && b.onError != nil {
+				b.onError(err)
+			}
+		}
+	}
+}
+
+// flush inserts the buffered rows, in batches of at most b.size.
+func (b *batcher) flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	rows := b.rows
+	b.rows = nil
+	b.mu.Unlock()
+
+	for len(rows) > 0 {
+		n := len(rows)
+		if n > b.size {
+			n = b.size
+		}
+		if err := b.insert(rows[:n]); err != nil {
+			// Keep the rows after the failed batch for the next flush,
+			// ahead of any added since.
+			b.mu.Lock()
+			b.rows = append(rows[n:], b.rows...)
+			b.mu.Unlock()
+			return err
+		}
+		rows = rows[n:]
+	}
+	return nil
+}
+
+func (b *batcher) insert(rows []row) error {
+	var query strings.Builder
+	query.WriteString("INSERT INTO ")
+	query.WriteString(b.table)
+	query.WriteString(" (time, level, message, fields) VALUES ")
+
+	args := make([]interface{}, 0, len(rows)*4)
+	for i, r := range rows {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteByte('(')
+		for j := 1; j <= 4; j++ {
+			if j > 1 {
+				query.WriteString(", ")
+			}
+			query.WriteString(b.placeholder(len(args) + j))
+		}
+		query.WriteByte(')')
+		args = append(args, r.time, r.level, r.message, r.fields)
+	}
+
+	if _, err := b.db.Exec(query.String(), args...); err != nil {
+		return fmt.Errorf("inserting %v entries into %v: %w", len(rows), b.table, err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zapsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingDB is a database/sql driver that records the statements it
+// executes.
+type recordingDB struct {
+	mu      sync.Mutex
+	queries []string
+	args    [][]driver.Value
+	err     error
+}
+
+func (d *recordingDB) open(t *testing.T) *sql.DB {
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func (d *recordingDB) Connect(context.Context) (driver.Conn, error) { return recordingConn{d}, nil }
+func (d *recordingDB) Driver() driver.Driver                        { return nil }
+
+// rows returns the rows inserted so far, as [time, level, message, fields].
+func (d *recordingDB) rows() [][]driver.Value {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var rows [][]driver.Value
+	for _, args := range d.args {
+		for i := 0; i < len(args); i += 4 {
+			rows = append(rows, args[i:i+4])
+		}
+	}
+	return rows
+}
+
+func (d *recordingDB) batches() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.queries)
+}
+
+type recordingConn struct{ d *recordingDB }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{c.d, query}, nil
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type recordingStmt struct {
+	d     *recordingDB
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	s.d.queries = append(s.d.queries, s.query)
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(len(args) / 4), nil
+}
+
+func (s recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestCoreInsertsEntries(t *testing.T) {
+	rec := &recordingDB{}
+	core := NewCore(rec.open(t), "logs", zapcore.InfoLevel, FlushInterval(0))
+	defer core.Close()
+	logger := zap.New(core).Named("api").With(zap.String("region", "eu"))
+
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	logger.WithTime(ts).Info("hello", zap.Int("n", 1))
+	logger.Debug("disabled")
+	assert.Empty(t, rec.rows(), "Entries should be buffered until a flush.")
+
+	require.NoError(t, logger.Sync(), "Unexpected error syncing.")
+	assert.Equal(t, [][]driver.Value{
+		{ts, "info", "hello", `{"logger":"api","region":"eu","n":1}`},
+	}, rec.rows(), "Unexpected rows.")
+	assert.Equal(t, []string{"INSERT INTO logs (time, level, message, fields) VALUES (?, ?, ?, ?)"},
+		rec.queries, "Unexpected query.")
+}
+
+func TestCoreBatches(t *testing.T) {
+	rec := &recordingDB{}
+	core := NewCore(rec.open(t), "logs", zapcore.DebugLevel,
+		BatchSize(3),
+		FlushInterval(0),
+		Placeholders(func(n int) string { return "$" + strconv.Itoa(n) }),
+	)
+	defer core.Close()
+	logger := zap.New(core)
+
+	for i := 0; i < 7; i++ {
+		logger.Info(strconv.Itoa(i))
+	}
+	assert.Equal(t, 2, rec.batches(), "Expected a batch each time the buffer fills.")
+	assert.Len(t, rec.rows(), 6, "Unexpected number of inserted rows.")
+	assert.True(t, strings.HasSuffix(rec.queries[0], "VALUES ($1, $2, $3, $4), ($5, $6, $7, $8), ($9, $10, $11, $12)"),
+		"Unexpected query: %v", rec.queries[0])
+
+	require.NoError(t, core.Close(), "Unexpected error closing.")
+	require.NoError(t, core.Close(), "Closing twice should be safe.")
+	rows := rec.rows()
+	require.Len(t, rows, 7, "Expected Close to flush the remaining entry.")
+	assert.Equal(t, "6", rows[6][2], "Unexpected last message.")
+}
+
+func TestCoreFlushesOnInterval(t *testing.T) {
+	rec := &recordingDB{}
+	core := NewCore(rec.open(t), "logs", zapcore.DebugLevel, FlushInterval(time.Millisecond))
+	defer core.Close()
+
+	zap.New(core).Info("eventually")
+	assert.Eventually(t, func() bool { return len(rec.rows()) == 1 }, time.Second, time.Millisecond,
+		"Expected the background flush to insert the entry.")
+}
+
+func TestCoreFlushesSevereEntries(t *testing.T) {
+	rec := &recordingDB{}
+	core := NewCore(rec.open(t), "logs", zapcore.DebugLevel, FlushInterval(0))
+	defer core.Close()
+
+	ce := core.Check(zapcore.Entry{Level: zapcore.DPanicLevel, Message: "severe"}, nil)
+	ce.Write()
+	assert.Len(t, rec.rows(), 1, "Expected entries above ErrorLevel to be inserted immediately.")
+}
+
+func TestCoreErrors(t *testing.T) {
+	rec := &recordingDB{err: errors.New("db down")}
+	errs := make(chan error, 1)
+	core := NewCore(rec.open(t), "logs", zapcore.DebugLevel,
+		BatchSize(1),
+		FlushInterval(time.Millisecond),
+		OnError(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}),
+	)
+	defer core.Close()
+
+	err := core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "lost"}, nil)
+	assert.ErrorContains(t, err, "inserting 1 entries into logs: db down", "Expected the insert error from Write.")
+
+	core.b.add(row{message: "background"})
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "db down", "Unexpected background error.")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the background flush to report its error.")
+	}
+	assert.Empty(t, rec.rows(), "Nothing should be inserted.")
+	assert.Equal(t, zapcore.DebugLevel, core.Level(), "Unexpected level.")
+}
+
+func TestCoreKeepsRowsAfterFailedBatch(t *testing.T) {
+	rec := &recordingDB{err: errors.New("db down")}
+	core := NewCore(rec.open(t), "logs", zapcore.DebugLevel, BatchSize(2), FlushInterval(0))
+	defer core.Close()
+
+	for i := 0; i < 5; i++ {
+		core.b.add(row{message: strconv.Itoa(i)})
+	}
+	assert.ErrorContains(t, core.Sync(), "inserting 2 entries into logs: db down", "Expected the insert error.")
+	core.b.add(row{message: "5"})
+
+	rec.mu.Lock()
+	rec.err = nil
+	rec.mu.Unlock()
+	require.NoError(t, core.Sync(), "Unexpected error syncing.")
+
+	var messages []driver.Value
+	for _, r := range rec.rows() {
+		messages = append(messages, r[2])
+	}
+	assert.Equal(t, []driver.Value{"2", "3", "4", "5"}, messages,
+		"Expected only the failed batch to be dropped, and the rest kept in order.")
+}
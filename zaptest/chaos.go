@@ -0,0 +1,136 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zaptest
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ChaosOption configures a Core built by NewChaosCore.
+type ChaosOption interface {
+	apply(*chaosState)
+}
+
+type chaosOptionFunc func(*chaosState)
+
+func (f chaosOptionFunc) apply(s *chaosState) {
+	f(s)
+}
+
+// ChaosSeed seeds the random number generator that decides which entries
+// are dropped and how long each is delayed. Cores with the same seed make
+// the same decisions for the same sequence of entries, which makes tests
+// reproducible. By default, the generator is seeded from the current time.
+func ChaosSeed(seed int64) ChaosOption {
+	return chaosOptionFunc(func(s *chaosState) {
+		s.rng = rand.New(rand.NewSource(seed))
+	})
+}
+
+// chaosState is shared by a chaosCore and the Cores derived from it with
+// With, so that they draw from a single sequence of random decisions.
+type chaosState struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	drop  float64
+	delay time.Duration
+	sleep func(time.Duration) // for tests
+}
+
+// roll decides whether to drop the next entry and, if not, how long to
+// delay it.
+func (s *chaosState) roll() (drop bool, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.drop > 0 && s.rng.Float64() < s.drop {
+		return true, 0
+	}
+	if s.delay > 0 {
+		delay = time.Duration(s.rng.Int63n(int64(s.delay) + 1))
+	}
+	return false, delay
+}
+
+type chaosCore struct {
+	zapcore.Core
+
+	state *chaosState
+}
+
+var _ zapcore.CoreDescriber = (*chaosCore)(nil)
+
+// NewChaosCore wraps a Core to simulate an unreliable logging pipeline, for
+// testing that an application doesn't depend on its logs being written, or
+// written promptly.
+//
+// Each enabled entry is dropped with probability dropProbability. Entries
+// that aren't dropped are delayed by a random duration between zero and
+// delay before they're passed to the wrapped Core; the delay happens in
+// Check, so it blocks the logging caller. Use ChaosSeed for reproducible
+// decisions.
+func NewChaosCore(next zapcore.Core, dropProbability float64, delay time.Duration, opts ...ChaosOption) zapcore.Core {
+	s := &chaosState{
+		drop:  dropProbability,
+		delay: delay,
+		sleep: time.Sleep,
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	if s.rng == nil {
+		s.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &chaosCore{Core: next, state: s}
+}
+
+func (c *chaosCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.Core)
+}
+
+func (c *chaosCore) Describe() []zapcore.CoreInfo {
+	return zapcore.DescribeCores(c.Core)
+}
+
+func (c *chaosCore) With(fields []zapcore.Field) zapcore.Core {
+	return &chaosCore{
+		Core:  c.Core.With(fields),
+		state: c.state,
+	}
+}
+
+func (c *chaosCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+	drop, delay := c.state.roll()
+	if drop {
+		return ce
+	}
+	if delay > 0 {
+		c.state.sleep(delay)
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zaptest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// countingCore counts the entries it's asked to check.
+type countingCore struct {
+	zapcore.LevelEnabler
+
+	n int
+}
+
+func (c *countingCore) With([]zapcore.Field) zapcore.Core          { return c }
+func (c *countingCore) Write(zapcore.Entry, []zapcore.Field) error { return nil }
+func (c *countingCore) Sync() error                                { return nil }
+
+func (c *countingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		c.n++
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func TestChaosCoreDropProbability(t *testing.T) {
+	const n = 10000
+	for _, p := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		next := &countingCore{LevelEnabler: zapcore.InfoLevel}
+		core := NewChaosCore(next, p, 0, ChaosSeed(42))
+		for i := 0; i < n; i++ {
+			core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil)
+		}
+		kept := float64(next.n) / n
+		assert.InDelta(t, 1-p, kept, 0.02, "Unexpected fraction of entries kept with drop probability %v.", p)
+	}
+}
+
+func TestChaosCoreDeterministic(t *testing.T) {
+	decisions := func(core zapcore.Core) []bool {
+		var kept []bool
+		for i := 0; i < 100; i++ {
+			kept = append(kept, core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil) != nil)
+		}
+		return kept
+	}
+
+	a := decisions(NewChaosCore(&countingCore{LevelEnabler: zapcore.InfoLevel}, 0.5, 0, ChaosSeed(7)))
+	b := decisions(NewChaosCore(&countingCore{LevelEnabler: zapcore.InfoLevel}, 0.5, 0, ChaosSeed(7)))
+	assert.Equal(t, a, b, "Cores with the same seed should make the same decisions.")
+	assert.Contains(t, a, true, "Expected some entries to be kept.")
+	assert.Contains(t, a, false, "Expected some entries to be dropped.")
+}
+
+func TestChaosCoreDelay(t *testing.T) {
+	const delay = time.Second
+	next := &countingCore{LevelEnabler: zapcore.InfoLevel}
+	core := NewChaosCore(next, 0, delay, ChaosSeed(1)).(*chaosCore)
+	var slept []time.Duration
+	core.state.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	for i := 0; i < 100; i++ {
+		require.NotNil(t, core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil), "Entries shouldn't be dropped.")
+	}
+	assert.Equal(t, 100, next.n, "Expected every entry to reach the wrapped core.")
+	require.NotEmpty(t, slept, "Expected entries to be delayed.")
+	for _, d := range slept {
+		assert.True(t, d > 0 && d <= delay, "Delay %v out of range.", d)
+	}
+}
+
+func TestChaosCoreDisabledLevels(t *testing.T) {
+	next := &countingCore{LevelEnabler: zapcore.WarnLevel}
+	core := NewChaosCore(next, 0, time.Second)
+	core.(*chaosCore).state.sleep = func(time.Duration) {
+		t.Fatal("Entries at disabled levels shouldn't be delayed.")
+	}
+
+	assert.Nil(t, core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil), "Expected disabled entry to be dropped.")
+	assert.Equal(t, zapcore.WarnLevel, zapcore.LevelOf(core), "Unexpected level.")
+	assert.Equal(t, 0, next.n, "Disabled entries shouldn't reach the wrapped core.")
+}
+
+func TestChaosCoreWithSharesState(t *testing.T) {
+	next := &countingCore{LevelEnabler: zapcore.InfoLevel}
+	parent := NewChaosCore(next, 0.5, 0, ChaosSeed(3))
+	child := parent.With([]zapcore.Field{{Key: "k", Type: zapcore.StringType, String: "v"}})
+
+	want := NewChaosCore(&countingCore{LevelEnabler: zapcore.InfoLevel}, 0.5, 0, ChaosSeed(3))
+	for i := 0; i < 50; i++ {
+		c := parent
+		if i%2 == 1 {
+			c = child
+		}
+		assert.Equal(
+			t,
+			want.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil) != nil,
+			c.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil) != nil,
+			"Derived cores should share the parent's random sequence.",
+		)
+	}
+}
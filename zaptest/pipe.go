@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zaptest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// _maxPipeLine is the length of the longest line a PipeReader reads.
+const _maxPipeLine = 1 << 20
+
+// A PipeSyncer is a zapcore.WriteSyncer that writes to the write end of an
+// operating system pipe, whose read end is a PipeReader. Since the output
+// goes through a real file descriptor, it's useful for integration tests
+// that exercise the same write path as logging to a file or standard error.
+type PipeSyncer struct {
+	w *os.File
+}
+
+// A PipeReader reads the lines written to a PipeSyncer.
+type PipeReader struct {
+	r       *os.File
+	scanner *bufio.Scanner
+}
+
+// NewPipeSyncer creates a connected PipeSyncer and PipeReader:
+//
+//	ws, r, err := zaptest.NewPipeSyncer()
+//	require.NoError(t, err)
+//	defer r.Close()
+//
+//	logger := zap.New(zapcore.NewCore(enc, ws, zap.DebugLevel))
+//	logger.Info("hello")
+//	ws.Close()
+//
+//	entry, err := r.NextEntry()
+//	require.NoError(t, err)
+//	assert.Equal(t, "hello", entry["msg"])
+//
+// Pipes have a limited capacity, typically 64KiB, so writes block if the
+// PipeReader falls behind. Tests that log more than that must read
+// concurrently with logging.
+func NewPipeSyncer() (*PipeSyncer, *PipeReader, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), _maxPipeLine)
+	return &PipeSyncer{w: w}, &PipeReader{r: r, scanner: scanner}, nil
+}
+
+// Write writes p to the pipe.
+func (s *PipeSyncer) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+// Sync is a no-op: writes to a pipe aren't buffered.
+func (s *PipeSyncer) Sync() error {
+	return nil
+}
+
+// Close closes the write end of the pipe. Once the PipeReader has read
+// everything written before, it reports io.EOF.
+func (s *PipeSyncer) Close() error {
+	return s.w.Close()
+}
+
+// NextLine blocks until a full line has been written to the pipe, and
+// returns it without the trailing newline. It returns io.EOF once the
+// PipeSyncer is closed and every line has been read.
+func (r *PipeReader) NextLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// NextEntry reads the next line, as NextLine does, and decodes it as a JSON
+// object, as written by the JSON encoder.
+func (r *PipeReader) NextEntry() (map[string]interface{}, error) {
+	line, err := r.NextLine()
+	if err != nil {
+		return nil, err
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return nil, fmt.Errorf("decoding %q: %w", line, err)
+	}
+	return entry, nil
+}
+
+// Close closes the read end of the pipe.
+func (r *PipeReader) Close() error {
+	return r.r.Close()
+}
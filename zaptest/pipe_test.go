@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package zaptest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPipeSyncer(t *testing.T) {
+	ws, r, err := NewPipeSyncer()
+	require.NoError(t, err, "Unexpected error creating pipe.")
+	defer r.Close()
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey:  "msg",
+		LevelKey:    "level",
+		EncodeLevel: zapcore.LowercaseLevelEncoder,
+	})
+	logger := zap.New(zapcore.NewCore(enc, ws, zapcore.DebugLevel))
+	logger.Info("first", zap.Int("n", 1))
+	logger.Warn("second", zap.String("s", "two"), zap.Bools("b", []bool{true}))
+	require.NoError(t, logger.Sync(), "Unexpected error syncing.")
+	require.NoError(t, ws.Close(), "Unexpected error closing the write end.")
+
+	entry, err := r.NextEntry()
+	require.NoError(t, err, "Unexpected error reading the first entry.")
+	assert.Equal(t, map[string]interface{}{"level": "info", "msg": "first", "n": float64(1)}, entry, "Unexpected first entry.")
+
+	entry, err = r.NextEntry()
+	require.NoError(t, err, "Unexpected error reading the second entry.")
+	assert.Equal(t, map[string]interface{}{
+		"level": "warn",
+		"msg":   "second",
+		"s":     "two",
+		"b":     []interface{}{true},
+	}, entry, "Unexpected second entry.")
+
+	_, err = r.NextEntry()
+	assert.Equal(t, io.EOF, err, "Expected EOF after the write end is closed.")
+}
+
+func TestPipeReaderErrors(t *testing.T) {
+	ws, r, err := NewPipeSyncer()
+	require.NoError(t, err, "Unexpected error creating pipe.")
+	defer r.Close()
+
+	go func() {
+		_, _ = ws.Write([]byte("not json\n"))
+		_, _ = ws.Write([]byte(strings.Repeat("x", _maxPipeLine+1) + "\n"))
+		_ = ws.Close()
+	}()
+
+	_, err = r.NextEntry()
+	assert.ErrorContains(t, err, `decoding "not json"`, "Expected an error for a line that isn't JSON.")
+
+	_, err = r.NextLine()
+	assert.ErrorIs(t, err, bufio.ErrTooLong, "Expected an error for an overlong line.")
+}